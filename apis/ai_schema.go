@@ -1,30 +1,63 @@
 package apis
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
 )
 
+// AIRequestIdHeader is the response header with the unique id of an AI api request.
+//
+// The same id is also stored as "aiRequestId" in the request activity log meta
+// so that the client errors could be correlated with the server logs.
+const AIRequestIdHeader = "x-ai-request-id"
+
+const requestEventKeyAIRequestId = "aiRequestId"
+
 // bindAIApi registers the AI API endpoints.
 func bindAIApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
-	subGroup := rg.Group("/ai").Bind(RequireSuperuserAuth())
+	subGroup := rg.Group("/ai").Bind(aiRequestId(), RequireSuperuserAuth())
 	subGroup.POST("/generate-schema", aiGenerateSchema)
 	subGroup.POST("/test-connection", aiTestConnection)
 	subGroup.POST("/generate-seed-data", aiGenerateSeedData)
+	subGroup.POST("/generate-seed-data/stream", aiGenerateSeedDataStream)
+	subGroup.DELETE("/seed-runs/{runId}", aiCleanupSeedRun)
 	subGroup.POST("/generate-embeddings", aiGenerateEmbeddings)
+	subGroup.POST("/generate-embeddings-pending", aiGenerateEmbeddingsPending)
+	subGroup.POST("/regenerate-embeddings", aiRegenerateEmbeddings)
+	subGroup.GET("/embedding-config", aiGetEmbeddingConfig)
+	subGroup.PUT("/embedding-config", aiSaveEmbeddingConfig)
+	subGroup.POST("/embed-collection", aiEmbedCollection)
 	subGroup.POST("/find-similar", aiFindSimilar)
+	subGroup.POST("/find-similar-batch", aiFindSimilarBatch)
+	subGroup.POST("/find-similar-global", aiFindSimilarGlobal)
+	subGroup.POST("/build-knn", aiBuildKNN)
 	subGroup.GET("/embedding-stats", aiGetEmbeddingStats)
+	subGroup.GET("/embedding-quality", aiGetEmbeddingQuality)
+	subGroup.GET("/embedding-text", aiGetEmbeddingText)
 	subGroup.GET("/embedding-cache-stats", aiGetEmbeddingCacheStats)
+	subGroup.GET("/archetype-cache-stats", aiGetArchetypeCacheStats)
+	subGroup.GET("/embedding-cache-dump", aiGetEmbeddingCacheDump)
 	subGroup.POST("/clear-embedding-cache", aiClearEmbeddingCache)
 	subGroup.GET("/pending-embeddings", aiGetPendingEmbeddings)
 }
 
+// collectionNameAffixRegex validates the generated collection name prefix and suffix.
+var collectionNameAffixRegex = regexp.MustCompile(`^\w*$`)
+
 func aiGenerateSchema(e *core.RequestEvent) error {
 	var req core.GenerateSchemaRequest
 
@@ -34,8 +67,10 @@ func aiGenerateSchema(e *core.RequestEvent) error {
 
 	// Validate request
 	if err := validation.ValidateStruct(&req,
-		validation.Field(&req.Prompt, validation.Required, validation.Length(1, 2000)),
+		validation.Field(&req.Prompt, validation.Required, validation.Length(1, core.MaxSchemaPromptLength)),
 		validation.Field(&req.CollectionType, validation.In("base", "auth", "view")),
+		validation.Field(&req.NamePrefix, validation.Length(0, 50), validation.Match(collectionNameAffixRegex)),
+		validation.Field(&req.NameSuffix, validation.Length(0, 50), validation.Match(collectionNameAffixRegex)),
 	); err != nil {
 		return e.BadRequestError("Invalid request data.", err)
 	}
@@ -46,7 +81,7 @@ func aiGenerateSchema(e *core.RequestEvent) error {
 	}
 
 	// Generate schema using AI service
-	collection, err := core.GenerateSchemaFromPrompt(e.App, req)
+	collection, dropped, err := core.GenerateSchemaFromPromptWithReport(e.Request.Context(), e.App, req)
 	if err != nil {
 		// Check if it's a validation error
 		var validationErrors validation.Errors
@@ -58,17 +93,90 @@ func aiGenerateSchema(e *core.RequestEvent) error {
 		return e.BadRequestError("Failed to generate schema. "+err.Error(), nil)
 	}
 
+	extra := map[string]any{}
+
+	// editing an existing collection - include the generated fields diff
+	if len(req.ExistingFields) > 0 {
+		extra["fieldsDiff"] = core.DiffSchemaFields(req.ExistingFields, collection)
+	}
+
+	// lenient mode - include the dropped invalid fields (if any)
+	if req.Lenient {
+		if dropped == nil {
+			dropped = []core.DroppedSchemaField{}
+		}
+		extra["droppedFields"] = dropped
+	}
+
+	var response any = collection
+
+	if len(extra) > 0 {
+		response, err = withSchemaExtraData(collection, extra)
+		if err != nil {
+			return e.InternalServerError("Failed to serialize the generated schema.", err)
+		}
+	}
+
 	return execAfterSuccessTx(true, e.App, func() error {
-		return e.JSON(http.StatusOK, collection)
+		return e.JSON(http.StatusOK, response)
 	})
 }
 
+// withSchemaExtraData returns the serialized collection data extended
+// with the extra keys (ex. "fieldsDiff", "droppedFields").
+//
+// Note that the collection is serialized to a map because embedding it
+// in a struct would promote its custom MarshalJSON and drop the extra data.
+func withSchemaExtraData(collection *core.Collection, extra map[string]any) (map[string]any, error) {
+	raw, err := json.Marshal(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	for k, v := range extra {
+		data[k] = v
+	}
+
+	return data, nil
+}
+
+// aiRequestId middleware generates a unique AI request id and attaches it
+// to the response headers and to the request activity log meta.
+func aiRequestId() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Func: func(e *core.RequestEvent) error {
+			id := security.RandomString(20)
+
+			e.Set(requestEventKeyAIRequestId, id)
+			e.Response.Header().Set(AIRequestIdHeader, id)
+
+			meta, _ := e.Get(RequestEventKeyLogMeta).(map[string]any)
+			if meta == nil {
+				meta = map[string]any{}
+			}
+			meta[requestEventKeyAIRequestId] = id
+			e.Set(RequestEventKeyLogMeta, meta)
+
+			return e.Next()
+		},
+	}
+}
+
 // aiTestConnection tests the AI connection using provided credentials.
 func aiTestConnection(e *core.RequestEvent) error {
 	var req struct {
-		Provider string `json:"provider"`
-		Model    string `json:"model"`
-		APIKey   string `json:"apiKey"`
+		Provider   string `json:"provider"`
+		Model      string `json:"model"`
+		APIKey     string `json:"apiKey"`
+		BaseURL    string `json:"baseURL"`
+		ChatPath   string `json:"chatPath"`
+		APIVersion string `json:"apiVersion"`
+		AuthHeader string `json:"authHeader"`
 	}
 
 	if err := e.BindBody(&req); err != nil {
@@ -77,15 +185,26 @@ func aiTestConnection(e *core.RequestEvent) error {
 
 	// Validate request
 	if err := validation.ValidateStruct(&req,
-		validation.Field(&req.Provider, validation.Required, validation.In("openai")),
+		validation.Field(&req.Provider, validation.Required, validation.In(core.AIProviderOpenAI, core.AIProviderAnthropic)),
 		validation.Field(&req.Model, validation.Required),
 		validation.Field(&req.APIKey, validation.Required),
+		validation.Field(&req.BaseURL, is.URL),
+		validation.Field(&req.ChatPath, validation.By(core.ValidateAIEndpointPath)),
+		validation.Field(&req.AuthHeader, validation.In(core.AIAuthHeaderAPIKey)),
 	); err != nil {
 		return e.BadRequestError("Invalid request data.", err)
 	}
 
 	// Test the connection using the provided credentials
-	err := core.TestAIConnection(req.Provider, req.Model, req.APIKey)
+	err := core.TestAIConnection(core.AIConfig{
+		Provider:   req.Provider,
+		Model:      req.Model,
+		APIKey:     req.APIKey,
+		BaseURL:    req.BaseURL,
+		ChatPath:   req.ChatPath,
+		APIVersion: req.APIVersion,
+		AuthHeader: req.AuthHeader,
+	})
 	if err != nil {
 		return e.BadRequestError("Connection test failed: "+err.Error(), nil)
 	}
@@ -100,64 +219,226 @@ func aiTestConnection(e *core.RequestEvent) error {
 // For counts <= 20: Uses pure AI generation
 // For counts > 20: Uses hybrid AI archetypes + gofakeit multiplexing for speed
 func aiGenerateSeedData(e *core.RequestEvent) error {
-	var req struct {
-		CollectionId string `json:"collectionId"`
-		Count        int    `json:"count"`
-		Description  string `json:"description"`
+	req, collection, err := loadSeedDataRequest(e)
+	if err != nil {
+		return err
 	}
 
+	response, err := insertSeedData(e, collection, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// aiGenerateSeedDataStream is similar to [aiGenerateSeedData] but reports
+// the insert progress as server-sent events:
+//   - "progress" after each inserted batch (with the created, skipped and total counts)
+//   - "summary" with the same data as the [aiGenerateSeedData] response
+//   - "error" if the generation fails after the stream has started
+//
+// The request errors (invalid data, missing collection, etc.) are returned
+// as regular JSON error responses.
+func aiGenerateSeedDataStream(e *core.RequestEvent) error {
+	req, collection, err := loadSeedDataRequest(e)
+	if err != nil {
+		return err
+	}
+
+	// disable global write deadline for the (potentially long) SSE response
+	rc := http.NewResponseController(e.Response)
+	writeDeadlineErr := rc.SetWriteDeadline(time.Time{})
+	if writeDeadlineErr != nil {
+		if !errors.Is(writeDeadlineErr, http.ErrNotSupported) {
+			return e.InternalServerError("Failed to initialize SSE connection.", writeDeadlineErr)
+		}
+
+		// only log since there are valid cases where it may not be implement (e.g. httptest.ResponseRecorder)
+		e.App.Logger().Warn("SetWriteDeadline is not supported, fallback to the default server WriteTimeout")
+	}
+
+	e.Response.Header().Set("Content-Type", "text/event-stream")
+	e.Response.Header().Set("Cache-Control", "no-store")
+	e.Response.Header().Set("X-Accel-Buffering", "no")
+
+	var eventId int
+
+	writeEvent := func(name string, data any) error {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+
+		eventId++
+		msg := subscriptions.Message{Name: name, Data: raw}
+		if err := msg.WriteSSE(e.Response, strconv.Itoa(eventId)); err != nil {
+			return err
+		}
+
+		return e.Flush()
+	}
+
+	response, err := insertSeedData(e, collection, req, func(progress seedDataProgress) {
+		// a disconnected client is handled by the next batch request context check
+		_ = writeEvent("progress", progress)
+	})
+	if err != nil {
+		return writeEvent("error", router.ToApiError(err))
+	}
+
+	return writeEvent("summary", response)
+}
+
+// seedDataProgress represents the seed data insert progress after each batch.
+type seedDataProgress struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Total   int `json:"total"`
+}
+
+// loadSeedDataRequest loads and validates the seed data request body
+// and returns it together with its resolved collection.
+func loadSeedDataRequest(e *core.RequestEvent) (core.GenerateSeedDataRequest, *core.Collection, error) {
+	var req core.GenerateSeedDataRequest
+
 	if err := e.BindBody(&req); err != nil {
-		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+		return req, nil, e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 	}
 
 	// Validate request - now supports up to 1,000,000 records
 	if err := validation.ValidateStruct(&req,
 		validation.Field(&req.CollectionId, validation.Required),
-		validation.Field(&req.Count, validation.Required, validation.Min(1), validation.Max(1000000)),
+		validation.Field(
+			&req.Count,
+			validation.When(req.RecordsPerArchetype <= 0 && req.Tree == nil, validation.Required),
+			validation.Min(1),
+			validation.Max(1000000),
+			// the dry run records are returned with the response so keep them reasonably small
+			validation.When(req.DryRun, validation.Max(maxSeedDryRunCount)),
+		),
+		validation.Field(
+			&req.RecordsPerArchetype,
+			validation.Min(0),
+			validation.Max(1000000/core.ArchetypeCount),
+			validation.When(req.DryRun, validation.Max(maxSeedDryRunCount/core.ArchetypeCount)),
+		),
+		validation.Field(&req.Tree, validation.When(req.DryRun, validation.By(func(value any) error {
+			if tree, _ := value.(*core.SeedTree); tree != nil && tree.Size() > maxSeedDryRunCount {
+				return validation.NewError(
+					"validation_tree_too_large",
+					fmt.Sprintf("The dry run tree must have at most %d records.", maxSeedDryRunCount),
+				)
+			}
+			return nil
+		}))),
+		validation.Field(&req.RunId, validation.Length(1, 100), validation.Match(core.DefaultIdRegex)),
+		validation.Field(&req.Locale, validation.Length(0, 20)),
+		validation.Field(&req.Description, validation.Length(0, core.MaxSeedDescriptionLength)),
 	); err != nil {
-		return e.BadRequestError("Invalid request data.", err)
+		return req, nil, e.BadRequestError("Invalid request data.", err)
 	}
 
 	// Find the collection
 	collection, err := e.App.FindCollectionByNameOrId(req.CollectionId)
 	if err != nil {
-		return e.NotFoundError("Collection not found.", err)
+		return req, nil, e.NotFoundError("Collection not found.", err)
 	}
 
 	// Don't allow seed data for view collections
 	if collection.IsView() {
-		return e.BadRequestError("Cannot generate seed data for view collections.", nil)
+		return req, nil, e.BadRequestError("Cannot generate seed data for view collections.", nil)
 	}
 
-	// Generate seed data using hybrid AI service (auto-switches based on count)
-	records, err := core.GenerateSeedDataHybrid(e.App, collection, req.Count, req.Description)
-	if err != nil {
-		return e.BadRequestError("Failed to generate seed data: "+err.Error(), nil)
-	}
+	return req, collection, nil
+}
+
+// maxSeedDryRunCount is the max number of the generated records of a dry run seed data request.
+const maxSeedDryRunCount = 1000
 
+// insertSeedData generates and inserts the seed records of the loaded request
+// and returns the summary response data.
+//
+// If req.DryRun is set, the generated records are returned in the response
+// "records" array without being inserted.
+//
+// onProgress is optional and it is called after each inserted batch.
+func insertSeedData(
+	e *core.RequestEvent,
+	collection *core.Collection,
+	req core.GenerateSeedDataRequest,
+	onProgress func(progress seedDataProgress),
+) (map[string]any, error) {
 	// Determine which mode was used
 	mode := "pure_ai"
-	if req.Count > core.HybridThreshold {
+	if req.Count > core.HybridThreshold || req.RecordsPerArchetype > 0 || req.Tree != nil {
 		mode = "hybrid"
 	}
 
 	// Create the records in the database using transaction for better performance
 	created := 0
 	skipped := 0
+	total := 0
 	var creationErrors []string
 
+	// number of the skipped records per offending field
+	fieldFailures := map[string]int{}
+
 	// Use batched transaction for large counts
 	batchSize := 100
-	if req.Count > 1000 {
+	if req.Count > 1000 || req.RecordsPerArchetype*core.ArchetypeCount > 1000 || (req.Tree != nil && req.Tree.Size() > 1000) {
 		batchSize = 500
 	}
 
-	for i := 0; i < len(records); i += batchSize {
-		end := i + batchSize
-		if end > len(records) {
-			end = len(records)
+	var dryRunRecords []map[string]any
+	if req.DryRun {
+		dryRunRecords = []map[string]any{}
+	}
+
+	if req.RunId != "" && !req.DryRun {
+		if _, err := core.EnsureSeedRunsCollection(e.App); err != nil {
+			return nil, e.InternalServerError("Failed to initialize the seed runs tracking.", err)
+		}
+	}
+
+	var skippedRelations []string
+	if req.PopulateRelations {
+		var err error
+		skippedRelations, err = core.SkippedSeedRelationFields(e.App, collection, req)
+		if err != nil {
+			return nil, e.InternalServerError("Failed to load the seed relations.", err)
+		}
+	}
+
+	var cancelled bool
+
+	// Generate (using the hybrid AI service that auto-switches based on count)
+	// and insert the records one batch at a time so that only a single batch is held in memory
+	err := core.StreamSeedData(e.App, collection, req, batchSize, func(batch []map[string]any) error {
+		// stop generating and inserting if the client has disconnected
+		// (the already inserted records could be removed with the run cleanup)
+		if e.Request.Context().Err() != nil {
+			return errSeedCancelled
+		}
+
+		offset := total
+		total += len(batch)
+
+		if req.DryRun {
+			dryRunRecords = append(dryRunRecords, batch...)
+
+			if onProgress != nil {
+				onProgress(seedDataProgress{Created: created, Skipped: skipped, Total: total})
+			}
+
+			return nil
 		}
-		batch := records[i:end]
+
+		// the batch counters are added to the totals only after the transaction
+		// commit because a failed transaction rolls back all batch records
+		var batchCreated, batchSkipped int
+		var batchErrors []string
+		batchFailures := map[string]int{}
 
 		err := e.App.RunInTransaction(func(txApp core.App) error {
 			for j, recordData := range batch {
@@ -167,39 +448,129 @@ func aiGenerateSeedData(e *core.RequestEvent) error {
 				form.Load(recordData)
 
 				if err := form.Submit(); err != nil {
-					skipped++
-					if len(creationErrors) < 10 {
-						creationErrors = append(creationErrors,
-							fmt.Sprintf("Record %d: %s", i+j+1, err.Error()))
+					batchSkipped++
+					for _, name := range seedErrorFields(err) {
+						batchFailures[name]++
+					}
+					if len(creationErrors)+len(batchErrors) < 10 {
+						batchErrors = append(batchErrors,
+							fmt.Sprintf("Record %d: %s", offset+j+1, err.Error()))
 					}
 					continue
 				}
-				created++
+
+				if req.RunId != "" {
+					if err := core.TrackSeedRunRecord(txApp, req.RunId, record); err != nil {
+						return err
+					}
+				}
+
+				batchCreated++
 			}
 			return nil
 		})
 
 		if err != nil {
 			// Log transaction error but continue with other batches
+			skipped += len(batch)
 			creationErrors = append(creationErrors,
-				fmt.Sprintf("Batch %d-%d transaction error: %s", i+1, end, err.Error()))
+				fmt.Sprintf("Batch %d-%d transaction error: %s", offset+1, total, err.Error()))
+		} else {
+			created += batchCreated
+			skipped += batchSkipped
+			for name, n := range batchFailures {
+				fieldFailures[name] += n
+			}
+			creationErrors = append(creationErrors, batchErrors...)
 		}
+
+		if onProgress != nil {
+			onProgress(seedDataProgress{Created: created, Skipped: skipped, Total: total})
+		}
+
+		return nil
+	})
+	if errors.Is(err, errSeedCancelled) {
+		cancelled = true
+	} else if err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return nil, e.BadRequestError("Failed to generate seed data.", validationErrors)
+		}
+
+		return nil, e.BadRequestError("Failed to generate seed data: "+err.Error(), nil)
 	}
 
 	response := map[string]interface{}{
 		"created": created,
 		"skipped": skipped,
-		"total":   len(records),
+		"total":   total,
 		"mode":    mode,
 	}
 
+	if req.RunId != "" {
+		response["runId"] = req.RunId
+	}
+
+	if req.DryRun {
+		response["dryRun"] = true
+		response["records"] = dryRunRecords
+	}
+
+	if len(fieldFailures) > 0 {
+		response["fieldFailures"] = fieldFailures
+	}
+
+	// the relation fields left blank because of their empty referenced collection
+	if len(skippedRelations) > 0 {
+		response["skippedRelations"] = skippedRelations
+	}
+
+	if cancelled {
+		response["cancelled"] = true
+	}
+
 	if len(creationErrors) > 0 && len(creationErrors) <= 5 {
 		response["errors"] = creationErrors
 	} else if len(creationErrors) > 5 {
 		response["errors"] = append(creationErrors[:5], fmt.Sprintf("... and %d more", len(creationErrors)-5))
 	}
 
-	return e.JSON(http.StatusOK, response)
+	return response, nil
+}
+
+// errSeedCancelled stops the seed data streaming when the client has disconnected.
+var errSeedCancelled = errors.New("seed data generation cancelled")
+
+// seedErrorFields returns the names of the fields that caused the
+// seed record insert error (if it is a fields validation error).
+func seedErrorFields(err error) []string {
+	var validationErrors validation.Errors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	names := make([]string, 0, len(validationErrors))
+	for name := range validationErrors {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// aiCleanupSeedRun deletes all records inserted as part of a tagged seed run.
+func aiCleanupSeedRun(e *core.RequestEvent) error {
+	runId := e.Request.PathValue("runId")
+
+	deleted, err := core.CleanupSeedRun(e.App, runId)
+	if err != nil {
+		return e.BadRequestError("Failed to cleanup the seed run: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"runId":   runId,
+		"deleted": deleted,
+	})
 }
 
 // aiGenerateEmbeddings generates vector embeddings for records in a collection.
@@ -210,6 +581,114 @@ func aiGenerateEmbeddings(e *core.RequestEvent) error {
 		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 	}
 
+	if err := validateAIEmbeddingRequest(e, req); err != nil {
+		return err
+	}
+
+	// Generate embeddings
+	response, err := core.GenerateEmbeddingsWithContext(e.Request.Context(), e.App, req)
+	if err != nil {
+		return e.BadRequestError("Failed to generate embeddings: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// aiGenerateEmbeddingsPending generates embeddings only for the
+// records that don't have embeddings yet (see [core.GetPendingEmbeddingRecordIds]).
+func aiGenerateEmbeddingsPending(e *core.RequestEvent) error {
+	var req core.EmbeddingRequest
+
+	if err := e.BindBody(&req); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := validateAIEmbeddingRequest(e, req); err != nil {
+		return err
+	}
+
+	if len(req.RecordIds) > 0 {
+		return e.BadRequestError("recordIds are not supported for the pending embeddings generation.", nil)
+	}
+
+	if req.EmbeddingsCollection != "" && req.EmbeddingsCollection != core.EmbeddingsCollectionName {
+		return e.BadRequestError("Custom embeddings collections are not supported for the pending embeddings generation.", nil)
+	}
+
+	// the field name under which the embeddings of the request mode are stored
+	fieldName := req.FieldName
+	switch req.Mode {
+	case core.EmbeddingModeRecord:
+		fieldName = core.RecordLevelFieldName
+	case core.EmbeddingModeFields:
+		fieldName = core.CombinedFieldName(req.Fields)
+	default:
+		if req.JSONPath != "" {
+			fieldName = core.JSONPathFieldName(req.FieldName, req.JSONPath)
+		}
+	}
+
+	pendingIds, err := core.GetPendingEmbeddingRecordIds(e.App, req.CollectionId, fieldName)
+	if err != nil {
+		return e.BadRequestError("Failed to get pending embeddings: "+err.Error(), nil)
+	}
+
+	response := struct {
+		*core.EmbeddingResponse
+		Pending int `json:"pending"`
+	}{
+		EmbeddingResponse: &core.EmbeddingResponse{},
+		Pending:           len(pendingIds),
+	}
+
+	// nothing to generate
+	// (note that an empty RecordIds list means all records)
+	if len(pendingIds) == 0 {
+		return e.JSON(http.StatusOK, response)
+	}
+
+	req.RecordIds = pendingIds
+
+	response.EmbeddingResponse, err = core.GenerateEmbeddingsWithContext(e.Request.Context(), e.App, req)
+	if err != nil {
+		return e.BadRequestError("Failed to generate embeddings: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// aiRegenerateEmbeddings regenerates the field-level embeddings of multiple
+// collection fields with the target model (see [core.RegenerateEmbeddings]).
+//
+// Resending the same request resumes an interrupted regeneration.
+func aiRegenerateEmbeddings(e *core.RequestEvent) error {
+	var req core.ReembedRequest
+
+	if err := e.BindBody(&req); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.CollectionId, validation.Required),
+		validation.Field(&req.Fields, validation.Required),
+		validation.Field(&req.BatchSize, validation.Min(0), validation.Max(10000)),
+		validation.Field(&req.Concurrency, validation.Min(0), validation.Max(core.MaxReembedConcurrency)),
+		validation.Field(&req.RunId, validation.Length(1, 100), validation.Match(core.DefaultIdRegex)),
+	); err != nil {
+		return e.BadRequestError("Invalid request data.", err)
+	}
+
+	response, err := core.RegenerateEmbeddingsWithContext(e.Request.Context(), e.App, req)
+	if err != nil {
+		return e.BadRequestError("Failed to regenerate embeddings: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// validateAIEmbeddingRequest validates the common embeddings generation request fields
+// and returns the related bad request error (if any).
+func validateAIEmbeddingRequest(e *core.RequestEvent, req core.EmbeddingRequest) error {
 	// Validate request - CollectionId is always required
 	// FieldName is only required for field-level mode (not record mode)
 	if req.CollectionId == "" {
@@ -217,14 +696,76 @@ func aiGenerateEmbeddings(e *core.RequestEvent) error {
 	}
 
 	// For field mode (default), fieldName is required
-	if req.Mode != core.EmbeddingModeRecord && req.FieldName == "" {
+	if req.Mode != core.EmbeddingModeRecord && req.Mode != core.EmbeddingModeFields && req.FieldName == "" {
 		return e.BadRequestError("fieldName is required for field-level embedding mode.", nil)
 	}
 
-	// Generate embeddings
-	response, err := core.GenerateEmbeddings(e.App, req)
+	// For multi-field mode, fields are required
+	if req.Mode == core.EmbeddingModeFields && len(req.Fields) == 0 {
+		return e.BadRequestError("fields are required for multi-field mode.", nil)
+	}
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.RunId, validation.Length(1, 100), validation.Match(core.DefaultIdRegex)),
+	); err != nil {
+		return e.BadRequestError("Invalid request data.", err)
+	}
+
+	return nil
+}
+
+// aiGetEmbeddingConfig returns the stored embedding configuration of a collection.
+func aiGetEmbeddingConfig(e *core.RequestEvent) error {
+	collectionId := e.Request.URL.Query().Get("collectionId")
+	if collectionId == "" {
+		return e.BadRequestError("The 'collectionId' query parameter is required.", nil)
+	}
+
+	config, err := core.FindEmbeddingConfig(e.App, collectionId)
 	if err != nil {
-		return e.BadRequestError("Failed to generate embeddings: "+err.Error(), nil)
+		return e.NotFoundError("Failed to get the embedding config: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, config)
+}
+
+// aiSaveEmbeddingConfig creates or replaces the stored embedding configuration of a collection.
+func aiSaveEmbeddingConfig(e *core.RequestEvent) error {
+	var config core.EmbeddingConfig
+
+	if err := e.BindBody(&config); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if config.CollectionId == "" {
+		return e.BadRequestError("collectionId is required.", nil)
+	}
+
+	saved, err := core.SaveEmbeddingConfig(e.App, config)
+	if err != nil {
+		return e.BadRequestError("Failed to save the embedding config: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, saved)
+}
+
+// aiEmbedCollection generates the embeddings of a collection using its stored embedding configuration.
+func aiEmbedCollection(e *core.RequestEvent) error {
+	var req struct {
+		CollectionId string `json:"collectionId"`
+	}
+
+	if err := e.BindBody(&req); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if req.CollectionId == "" {
+		return e.BadRequestError("collectionId is required.", nil)
+	}
+
+	response, err := core.EmbedCollectionWithContext(e.Request.Context(), e.App, req.CollectionId)
+	if err != nil {
+		return e.BadRequestError("Failed to embed the collection: "+err.Error(), nil)
 	}
 
 	return e.JSON(http.StatusOK, response)
@@ -244,13 +785,33 @@ func aiFindSimilar(e *core.RequestEvent) error {
 	}
 
 	// For field mode (default), fieldName is required
-	if req.Mode != core.EmbeddingModeRecord && req.FieldName == "" {
+	if req.Mode != core.EmbeddingModeRecord && req.Mode != core.EmbeddingModeFields && req.FieldName == "" {
 		return e.BadRequestError("fieldName is required for field-level search mode.", nil)
 	}
 
+	// For multi-field mode, fields are required
+	if req.Mode == core.EmbeddingModeFields && len(req.Fields) == 0 {
+		return e.BadRequestError("fields are required for multi-field mode.", nil)
+	}
+
 	// Validate limit
-	if req.Limit < 0 || req.Limit > 100 {
-		return e.BadRequestError("limit must be between 1 and 100.", nil)
+	if maxLimit := e.App.Settings().AI.SimilarityMaxLimitOrDefault(); req.Limit < 0 || req.Limit > maxLimit {
+		return e.BadRequestError(fmt.Sprintf("limit must be between 1 and %d.", maxLimit), nil)
+	}
+
+	// Validate per group limit
+	if maxLimit := e.App.Settings().AI.SimilarityMaxLimitOrDefault(); req.PerGroupLimit < 0 || req.PerGroupLimit > maxLimit {
+		return e.BadRequestError(fmt.Sprintf("perGroupLimit must be between 1 and %d.", maxLimit), nil)
+	}
+
+	// Validate score scale
+	if req.ScoreScale != "" && req.ScoreScale != core.SimilarityScoreScaleRaw && req.ScoreScale != core.SimilarityScoreScalePercent {
+		return e.BadRequestError("scoreScale must be either 'raw' or 'percent'.", nil)
+	}
+
+	// Validate metric
+	if req.Metric != "" && req.Metric != core.SimilarityMetricCosine && req.Metric != core.SimilarityMetricDot && req.Metric != core.SimilarityMetricEuclidean {
+		return e.BadRequestError("metric must be one of 'cosine', 'dot' or 'euclidean'.", nil)
 	}
 
 	// Require either text or recordId
@@ -264,7 +825,75 @@ func aiFindSimilar(e *core.RequestEvent) error {
 	}
 
 	// Find similar records
-	response, err := core.FindSimilarRecords(e.App, req)
+	response, err := core.FindSimilarRecordsWithContext(e.Request.Context(), e.App, req)
+	if err != nil {
+		return e.BadRequestError("Failed to find similar records: "+err.Error(), nil)
+	}
+
+	// Enrich the expanded records (relations expand, emails visibility, etc.)
+	if len(response.Expand) > 0 {
+		records := make([]*core.Record, 0, len(response.Expand))
+		for _, record := range response.Expand {
+			records = append(records, record)
+		}
+
+		if err := EnrichRecords(e, records); err != nil {
+			return firstApiError(err, e.InternalServerError("Failed to enrich the expanded records.", err))
+		}
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// aiFindSimilarBatch finds the records similar to each of multiple query texts.
+func aiFindSimilarBatch(e *core.RequestEvent) error {
+	var req core.FindSimilarBatchRequest
+
+	if err := e.BindBody(&req); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if req.CollectionId == "" {
+		return e.BadRequestError("collectionId is required.", nil)
+	}
+
+	// For field mode (default), fieldName is required
+	if req.Mode != core.EmbeddingModeRecord && req.Mode != core.EmbeddingModeFields && req.FieldName == "" {
+		return e.BadRequestError("fieldName is required for field-level search mode.", nil)
+	}
+
+	// For multi-field mode, fields are required
+	if req.Mode == core.EmbeddingModeFields && len(req.Fields) == 0 {
+		return e.BadRequestError("fields are required for multi-field mode.", nil)
+	}
+
+	if len(req.Queries) == 0 || len(req.Queries) > core.MaxSimilarityBatchQueries {
+		return e.BadRequestError(fmt.Sprintf("queries must have between 1 and %d items.", core.MaxSimilarityBatchQueries), nil)
+	}
+
+	// Validate the queries
+	maxLimit := e.App.Settings().AI.SimilarityMaxLimitOrDefault()
+	for i, query := range req.Queries {
+		if query.Text == "" {
+			return e.BadRequestError(fmt.Sprintf("queries.%d.text is required.", i), nil)
+		}
+
+		if query.Limit < 0 || query.Limit > maxLimit {
+			return e.BadRequestError(fmt.Sprintf("queries.%d.limit must be between 1 and %d.", i, maxLimit), nil)
+		}
+	}
+
+	// Validate score scale
+	if req.ScoreScale != "" && req.ScoreScale != core.SimilarityScoreScaleRaw && req.ScoreScale != core.SimilarityScoreScalePercent {
+		return e.BadRequestError("scoreScale must be either 'raw' or 'percent'.", nil)
+	}
+
+	// Validate metric
+	if req.Metric != "" && req.Metric != core.SimilarityMetricCosine && req.Metric != core.SimilarityMetricDot && req.Metric != core.SimilarityMetricEuclidean {
+		return e.BadRequestError("metric must be one of 'cosine', 'dot' or 'euclidean'.", nil)
+	}
+
+	response, err := core.FindSimilarRecordsBatch(e.Request.Context(), e.App, req)
 	if err != nil {
 		return e.BadRequestError("Failed to find similar records: "+err.Error(), nil)
 	}
@@ -272,13 +901,86 @@ func aiFindSimilar(e *core.RequestEvent) error {
 	return e.JSON(http.StatusOK, response)
 }
 
-// aiGetEmbeddingStats returns embedding statistics for a collection/field.
+// aiFindSimilarGlobal finds records similar to a given text across multiple collections.
+func aiFindSimilarGlobal(e *core.RequestEvent) error {
+	var req core.FindSimilarGlobalRequest
+
+	if err := e.BindBody(&req); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if req.Text == "" {
+		return e.BadRequestError("text is required.", nil)
+	}
+
+	if len(req.Targets) == 0 {
+		return e.BadRequestError("At least one target is required.", nil)
+	}
+
+	// Validate limit
+	if maxLimit := e.App.Settings().AI.SimilarityMaxLimitOrDefault(); req.Limit < 0 || req.Limit > maxLimit {
+		return e.BadRequestError(fmt.Sprintf("limit must be between 1 and %d.", maxLimit), nil)
+	}
+
+	response, err := core.FindSimilarGlobalWithContext(e.Request.Context(), e.App, req)
+	if err != nil {
+		return e.BadRequestError("Failed to find similar records: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// aiBuildKNN computes (and optionally stores) the nearest neighbors of every embedded record in a collection.
+func aiBuildKNN(e *core.RequestEvent) error {
+	var req core.BuildKNNRequest
+
+	if err := e.BindBody(&req); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if req.CollectionId == "" {
+		return e.BadRequestError("collectionId is required.", nil)
+	}
+
+	// For field mode (default), fieldName is required
+	if req.Mode != core.EmbeddingModeRecord && req.Mode != core.EmbeddingModeFields && req.FieldName == "" {
+		return e.BadRequestError("fieldName is required for field-level mode.", nil)
+	}
+
+	// For multi-field mode, fields are required
+	if req.Mode == core.EmbeddingModeFields && len(req.Fields) == 0 {
+		return e.BadRequestError("fields are required for multi-field mode.", nil)
+	}
+
+	if req.K < 0 || req.K > core.MaxKNNNeighbors {
+		return e.BadRequestError(fmt.Sprintf("k must be between 1 and %d.", core.MaxKNNNeighbors), nil)
+	}
+
+	response, err := core.BuildKNNWithContext(e.Request.Context(), e.App, req)
+	if err != nil {
+		return e.BadRequestError("Failed to build the nearest neighbors: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+// aiGetEmbeddingStats returns embedding statistics for a collection/field
+// (or the aggregated statistics of all embeddable collection fields if fieldName is not set).
 func aiGetEmbeddingStats(e *core.RequestEvent) error {
 	collectionId := e.Request.URL.Query().Get("collectionId")
 	fieldName := e.Request.URL.Query().Get("fieldName")
 
-	if collectionId == "" || fieldName == "" {
-		return e.BadRequestError("Both 'collectionId' and 'fieldName' query parameters are required.", nil)
+	if collectionId == "" {
+		return e.BadRequestError("The 'collectionId' query parameter is required.", nil)
+	}
+
+	if fieldName == "" {
+		stats, err := core.GetEmbeddingStatsForCollection(e.App, collectionId)
+		if err != nil {
+			return e.BadRequestError("Failed to get embedding stats: "+err.Error(), nil)
+		}
+
+		return e.JSON(http.StatusOK, stats)
 	}
 
 	stats, err := core.GetEmbeddingStatsForField(e.App, collectionId, fieldName)
@@ -289,12 +991,61 @@ func aiGetEmbeddingStats(e *core.RequestEvent) error {
 	return e.JSON(http.StatusOK, stats)
 }
 
+// aiGetEmbeddingQuality returns aggregate quality metrics for the embeddings of a collection/field.
+func aiGetEmbeddingQuality(e *core.RequestEvent) error {
+	collectionId := e.Request.URL.Query().Get("collectionId")
+	fieldName := e.Request.URL.Query().Get("fieldName")
+
+	if collectionId == "" || fieldName == "" {
+		return e.BadRequestError("Both 'collectionId' and 'fieldName' query parameters are required.", nil)
+	}
+
+	stats, err := core.GetEmbeddingQualityForField(e.App, collectionId, fieldName)
+	if err != nil {
+		return e.BadRequestError("Failed to get embedding quality: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, stats)
+}
+
+// aiGetEmbeddingText returns the stored embedded source text of a record.
+func aiGetEmbeddingText(e *core.RequestEvent) error {
+	recordId := e.Request.URL.Query().Get("recordId")
+	fieldName := e.Request.URL.Query().Get("fieldName")
+
+	if recordId == "" || fieldName == "" {
+		return e.BadRequestError("Both 'recordId' and 'fieldName' query parameters are required.", nil)
+	}
+
+	text, err := core.GetEmbeddingText(e.App, recordId, fieldName)
+	if err != nil {
+		return e.NotFoundError("Failed to get the embedding text: "+err.Error(), nil)
+	}
+
+	return e.JSON(http.StatusOK, text)
+}
+
 // aiGetEmbeddingCacheStats returns statistics about the embedding cache.
 func aiGetEmbeddingCacheStats(e *core.RequestEvent) error {
 	stats := core.GetEmbeddingCacheStats()
 	return e.JSON(http.StatusOK, stats)
 }
 
+// aiGetArchetypeCacheStats returns statistics about the seed data archetype cache.
+func aiGetArchetypeCacheStats(e *core.RequestEvent) error {
+	stats := core.GetArchetypeCacheStats()
+	return e.JSON(http.StatusOK, stats)
+}
+
+// aiGetEmbeddingCacheDump returns the embedding cache internal state (without the raw vectors).
+func aiGetEmbeddingCacheDump(e *core.RequestEvent) error {
+	if !e.App.Settings().AI.EmbeddingCacheDebug {
+		return e.ForbiddenError("The embedding cache dump is disabled (see the AI embeddingCacheDebug setting).", nil)
+	}
+
+	return e.JSON(http.StatusOK, core.DumpEmbeddingCache())
+}
+
 // aiClearEmbeddingCache clears the embedding cache.
 func aiClearEmbeddingCache(e *core.RequestEvent) error {
 	core.ClearEmbeddingCache()
@@ -0,0 +1,356 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported AI providers.
+const (
+	AIProviderOpenAI    = "openai"
+	AIProviderAnthropic = "anthropic"
+)
+
+const (
+	// DefaultOpenAIBaseURL is the default OpenAI API base url
+	// (could be changed with the AIConfig.BaseURL setting).
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+	// AIAuthHeaderAPIKey is the Azure OpenAI "api-key" auth header style
+	// (see AIConfig.AuthHeader).
+	AIAuthHeaderAPIKey = "api-key"
+
+	// DefaultOpenAIChatPath is the default OpenAI chat completions endpoint path
+	// (could be changed with the AIConfig.ChatPath setting).
+	DefaultOpenAIChatPath = "/chat/completions"
+
+	// DefaultOpenAIEmbeddingsPath is the default OpenAI embeddings endpoint path
+	// (could be changed with the AIConfig.EmbeddingsPath setting).
+	DefaultOpenAIEmbeddingsPath = "/embeddings"
+
+	openAIModelsPath = "/models/"
+)
+
+const (
+	anthropicMessagesURL = "https://api.anthropic.com/v1/messages"
+	anthropicModelsURL   = "https://api.anthropic.com/v1/models/"
+	anthropicAPIVersion  = "2023-06-01"
+
+	// DefaultAnthropicMaxTokens is the default max tokens of a single
+	// Anthropic response (the messages API requires an explicit limit).
+	DefaultAnthropicMaxTokens = 8192
+)
+
+// ErrAIEmbeddingsNotSupported is returned when the configured AI provider
+// doesn't have an embeddings API (ex. Anthropic).
+var ErrAIEmbeddingsNotSupported = errors.New("the configured AI provider doesn't support embeddings")
+
+// ChatMessage is a single chat completion message.
+type ChatMessage struct {
+	Role    string `json:"role"` // "system", "user" or "assistant"
+	Content string `json:"content"`
+}
+
+// ChatOptions are the options of a single chat completion request.
+type ChatOptions struct {
+	Model       string
+	Temperature float64
+
+	// JSON requests the response content to be a single JSON object.
+	JSON bool
+
+	// Timeout is the whole request timeout (including the response read).
+	Timeout time.Duration
+}
+
+// ChatProvider defines a chat completion AI provider.
+type ChatProvider interface {
+	// Complete submits the messages to the provider and returns the response content.
+	//
+	// Returns [ErrAIResponseTruncated] or [ErrAIContentFiltered]
+	// if the response content is incomplete and [ErrAIRequestCanceled] if ctx is done.
+	Complete(ctx context.Context, messages []ChatMessage, opts ChatOptions) (string, error)
+}
+
+// NewChatProvider returns the chat provider of the app AI settings.
+func NewChatProvider(app App) (ChatProvider, error) {
+	settings := app.Settings()
+
+	switch settings.AI.Provider {
+	case AIProviderOpenAI:
+		return &openAIChatProvider{app: app}, nil
+	case AIProviderAnthropic:
+		return &anthropicChatProvider{app: app}, nil
+	default:
+		return nil, fmt.Errorf("unsupported AI provider: %s", settings.AI.Provider)
+	}
+}
+
+// -------------------------------------------------------------------
+
+var _ ChatProvider = (*openAIChatProvider)(nil)
+
+// openAIChatProvider is a [ChatProvider] implementation for the OpenAI chat completions API.
+type openAIChatProvider struct {
+	app App
+}
+
+// Complete implements [ChatProvider.Complete] interface method.
+func (p *openAIChatProvider) Complete(ctx context.Context, messages []ChatMessage, opts ChatOptions) (string, error) {
+	settings := p.app.Settings()
+
+	if err := checkAIContext(ctx); err != nil {
+		return "", err
+	}
+
+	openAIReq := map[string]any{
+		"model":       opts.Model,
+		"messages":    messages,
+		"temperature": opts.Temperature,
+	}
+	if opts.JSON {
+		openAIReq["response_format"] = map[string]string{
+			"type": "json_object",
+		}
+	}
+
+	reqBody, err := json.Marshal(openAIReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal OpenAI request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", openAIURL(settings.AI, settings.AI.ChatPathOrDefault()), bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	setOpenAIAuthHeader(httpReq, settings.AI)
+
+	client := newAIHTTPClient(p.app, opts.Timeout)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctxErr := checkAIContext(ctx); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("failed to call OpenAI API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readAIResponseBody(resp.Body, settings.AI.MaxResponseSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to read OpenAI response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var openAIResp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+	}
+
+	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
+		return "", fmt.Errorf("failed to parse OpenAI response: %w", err)
+	}
+
+	if len(openAIResp.Choices) == 0 {
+		return "", fmt.Errorf("no response from OpenAI")
+	}
+
+	if err := checkAIFinishReason(openAIResp.Choices[0].FinishReason); err != nil {
+		return "", err
+	}
+
+	return openAIResp.Choices[0].Message.Content, nil
+}
+
+// openAIURL returns the OpenAI API url of the specified endpoint path
+// (ex. "/embeddings") respecting the AIConfig.BaseURL and AIConfig.APIVersion settings.
+func openAIURL(config AIConfig, endpointPath string) string {
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+
+	if config.APIVersion == "" {
+		return baseURL + endpointPath
+	}
+
+	u, err := url.Parse(baseURL + endpointPath)
+	if err != nil {
+		return baseURL + endpointPath // the base url is validated on settings save
+	}
+
+	query := u.Query()
+	query.Set("api-version", config.APIVersion)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// setOpenAIAuthHeader sets the OpenAI request auth header
+// in the style of the AIConfig.AuthHeader setting.
+func setOpenAIAuthHeader(req *http.Request, config AIConfig) {
+	if config.AuthHeader == AIAuthHeaderAPIKey {
+		req.Header.Set("api-key", config.APIKey)
+		return
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.APIKey))
+}
+
+// -------------------------------------------------------------------
+
+var _ ChatProvider = (*anthropicChatProvider)(nil)
+
+// anthropicChatProvider is a [ChatProvider] implementation for the Anthropic messages API.
+type anthropicChatProvider struct {
+	app App
+}
+
+// Complete implements [ChatProvider.Complete] interface method.
+//
+// The system messages are joined into the top level "system" request param
+// because the Anthropic messages API doesn't accept a "system" role.
+func (p *anthropicChatProvider) Complete(ctx context.Context, messages []ChatMessage, opts ChatOptions) (string, error) {
+	settings := p.app.Settings()
+
+	if err := checkAIContext(ctx); err != nil {
+		return "", err
+	}
+
+	var system []string
+	conversation := make([]ChatMessage, 0, len(messages))
+	for _, m := range messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+		} else {
+			conversation = append(conversation, m)
+		}
+	}
+
+	// there is no dedicated JSON response mode so it is enforced with an extra instruction
+	if opts.JSON {
+		system = append(system, "Respond ONLY with a single valid JSON object, without markdown code fences or any other text.")
+	}
+
+	// the Anthropic temperature range is 0-1
+	temperature := opts.Temperature
+	if temperature > 1 {
+		temperature = 1
+	}
+
+	anthropicReq := map[string]any{
+		"model":       opts.Model,
+		"max_tokens":  DefaultAnthropicMaxTokens,
+		"messages":    conversation,
+		"temperature": temperature,
+	}
+	if len(system) > 0 {
+		anthropicReq["system"] = strings.Join(system, "\n\n")
+	}
+
+	reqBody, err := json.Marshal(anthropicReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Anthropic request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicMessagesURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	setAnthropicHeaders(httpReq, settings.AI.APIKey)
+
+	client := newAIHTTPClient(p.app, opts.Timeout)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctxErr := checkAIContext(ctx); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("failed to call Anthropic API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readAIResponseBody(resp.Body, settings.AI.MaxResponseSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Anthropic response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var anthropicResp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+	}
+
+	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
+		return "", fmt.Errorf("failed to parse Anthropic response: %w", err)
+	}
+
+	// map the stop reasons to their chat completion finish reasons equivalent
+	switch anthropicResp.StopReason {
+	case "max_tokens":
+		return "", ErrAIResponseTruncated
+	case "refusal":
+		return "", ErrAIContentFiltered
+	}
+
+	var content strings.Builder
+	for _, block := range anthropicResp.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+
+	if content.Len() == 0 {
+		return "", fmt.Errorf("no response from Anthropic")
+	}
+
+	if opts.JSON {
+		return extractJSONObject(content.String()), nil
+	}
+
+	return content.String(), nil
+}
+
+// setAnthropicHeaders sets the common Anthropic API request headers.
+func setAnthropicHeaders(req *http.Request, apiKey string) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+}
+
+// extractJSONObject returns the outermost JSON object of the provided
+// text content (ex. stripping surrounding markdown code fences).
+//
+// Returns the content as it is if it doesn't contain an object.
+func extractJSONObject(content string) string {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return content
+	}
+
+	return content[start : end+1]
+}
@@ -0,0 +1,488 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestNewChatProvider(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		provider    string
+		expectError bool
+	}{
+		{"", true},
+		{"invalid", true},
+		{core.AIProviderOpenAI, false},
+		{core.AIProviderAnthropic, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.provider, func(t *testing.T) {
+			app.Settings().AI.Provider = s.provider
+
+			provider, err := core.NewChatProvider(app)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && provider == nil {
+				t.Fatal("Expected non-nil provider")
+			}
+		})
+	}
+}
+
+func TestAnthropicChatProvider(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+	app.Settings().AI.Provider = core.AIProviderAnthropic
+	app.Settings().AI.APIKey = "test_key"
+	app.Settings().AI.Model = "claude-test"
+
+	collection := core.NewBaseCollection("test_anthropic_provider")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		transport := &fakeAnthropicTransport{
+			content: []string{"```json\n", `{"name":"test_anthropic_schema","fields":[{"name":"title","type":"text"}]}`, "\n```"},
+		}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		generated, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "blog posts"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if generated.Name != "test_anthropic_schema" || generated.Fields.GetByName("title") == nil {
+			t.Fatalf("Expected the parsed content blocks schema, got %s %v", generated.Name, generated.Fields.FieldNames())
+		}
+
+		requests := transport.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("Expected 1 request, got %d", len(requests))
+		}
+		req := requests[0]
+
+		if req.url != "https://api.anthropic.com/v1/messages" {
+			t.Fatalf("Expected the messages API url, got %q", req.url)
+		}
+
+		if req.apiKey != "test_key" || req.version == "" {
+			t.Fatalf("Expected the Anthropic auth headers, got key %q and version %q", req.apiKey, req.version)
+		}
+
+		if req.body.Model != "claude-test" || req.body.MaxTokens <= 0 {
+			t.Fatalf("Expected model claude-test with positive max_tokens, got %q %d", req.body.Model, req.body.MaxTokens)
+		}
+
+		if !strings.Contains(req.body.System, "PocketBase schema designer") {
+			t.Fatalf("Expected the system prompt to be sent as system param, got %q", req.body.System)
+		}
+
+		if len(req.body.Messages) != 1 || req.body.Messages[0].Role != "user" || !strings.Contains(req.body.Messages[0].Content, "blog posts") {
+			t.Fatalf("Expected a single user message, got %+v", req.body.Messages)
+		}
+	})
+
+	t.Run("seed data", func(t *testing.T) {
+		transport := &fakeAnthropicTransport{
+			content: []string{`{"records":[{"title":"a"},{"title":"b"}],"archetypes":[{"title":"a"}]}`},
+		}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		records, err := core.GenerateSeedDataFromSchema(app, collection, 2, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(records))
+		}
+
+		// the archetypes temperature should be clamped to the Anthropic range
+		_, err = core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:                core.HybridThreshold + 1,
+			ArchetypeTemperature: func() *float64 { v := 1.8; return &v }(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		requests := transport.Requests()
+		if last := requests[len(requests)-1]; last.body.Temperature != 1 {
+			t.Fatalf("Expected temperature 1, got %v", last.body.Temperature)
+		}
+	})
+
+	t.Run("stop reasons", func(t *testing.T) {
+		scenarios := []struct {
+			stopReason  string
+			expectedErr error
+		}{
+			{"end_turn", nil},
+			{"max_tokens", core.ErrAIResponseTruncated},
+			{"refusal", core.ErrAIContentFiltered},
+		}
+
+		for _, s := range scenarios {
+			app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeAnthropicTransport{
+				content:    []string{`{"records":[{"title":"a"}]}`},
+				stopReason: s.stopReason,
+			})
+
+			_, err := core.GenerateSeedDataFromSchema(app, collection, 1, "")
+			if !errors.Is(err, s.expectedErr) {
+				t.Fatalf("[%s] Expected error %v, got %v", s.stopReason, s.expectedErr, err)
+			}
+		}
+	})
+
+	t.Run("embeddings", func(t *testing.T) {
+		app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeAnthropicTransport{})
+
+		_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Text:         "test",
+		})
+		if !errors.Is(err, core.ErrAIEmbeddingsNotSupported) {
+			t.Fatalf("Expected ErrAIEmbeddingsNotSupported, got %v", err)
+		}
+	})
+}
+
+func TestAIConnectionProviders(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		provider       string
+		status         int
+		expectedURL    string
+		expectedHeader string
+		expectedError  string
+	}{
+		{"invalid", http.StatusOK, "", "", "unsupported AI provider"},
+		{core.AIProviderOpenAI, http.StatusOK, "https://api.openai.com/v1/models/test_model", "Authorization", ""},
+		{core.AIProviderAnthropic, http.StatusOK, "https://api.anthropic.com/v1/models/test_model", "X-Api-Key", ""},
+		{core.AIProviderAnthropic, http.StatusUnauthorized, "https://api.anthropic.com/v1/models/test_model", "X-Api-Key", "invalid API key"},
+		{core.AIProviderAnthropic, http.StatusNotFound, "https://api.anthropic.com/v1/models/test_model", "X-Api-Key", "not found"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s_%d", i, s.provider, s.status), func(t *testing.T) {
+			var calledURL string
+			var calledHeaders http.Header
+
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calledURL = req.URL.String()
+				calledHeaders = req.Header
+				return &http.Response{
+					StatusCode: s.status,
+					Body:       io.NopCloser(strings.NewReader("{}")),
+					Request:    req,
+				}, nil
+			})
+
+			err := core.CheckAIConnection(transport, core.AIConfig{
+				Provider: s.provider,
+				Model:    "test_model",
+				APIKey:   "test_key",
+			})
+
+			if s.expectedError == "" && err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+			if s.expectedError != "" && (err == nil || !strings.Contains(err.Error(), s.expectedError)) {
+				t.Fatalf("Expected error %q, got %v", s.expectedError, err)
+			}
+
+			if calledURL != s.expectedURL {
+				t.Fatalf("Expected url %q, got %q", s.expectedURL, calledURL)
+			}
+
+			if s.expectedHeader != "" && !strings.Contains(calledHeaders.Get(s.expectedHeader), "test_key") {
+				t.Fatalf("Expected the api key in the %s header, got %v", s.expectedHeader, calledHeaders)
+			}
+		})
+	}
+}
+
+func TestOpenAIBaseURL(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name                  string
+		baseURL               string
+		chatPath              string
+		embeddingsPath        string
+		apiVersion            string
+		authHeader            string
+		expectedChatURL       string
+		expectedEmbeddingsURL string
+		expectedAPIKeyHeader  bool
+	}{
+		{
+			"default",
+			"",
+			"",
+			"",
+			"",
+			"",
+			"https://api.openai.com/v1/chat/completions",
+			"https://api.openai.com/v1/embeddings",
+			false,
+		},
+		{
+			"gateway",
+			"https://gateway.example.com/openai/v1/",
+			"",
+			"",
+			"",
+			"",
+			"https://gateway.example.com/openai/v1/chat/completions",
+			"https://gateway.example.com/openai/v1/embeddings",
+			false,
+		},
+		{
+			"azure",
+			"https://test.openai.azure.com/openai/deployments/test",
+			"",
+			"",
+			"2024-06-01",
+			core.AIAuthHeaderAPIKey,
+			"https://test.openai.azure.com/openai/deployments/test/chat/completions?api-version=2024-06-01",
+			"https://test.openai.azure.com/openai/deployments/test/embeddings?api-version=2024-06-01",
+			true,
+		},
+		{
+			"custom paths",
+			"https://gateway.example.com",
+			"/api/chat",
+			"/openai/v1/embed",
+			"2024-06-01",
+			"",
+			"https://gateway.example.com/api/chat?api-version=2024-06-01",
+			"https://gateway.example.com/openai/v1/embed?api-version=2024-06-01",
+			false,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			app.Settings().AI.Enabled = true
+			app.Settings().AI.APIKey = "test_key"
+			app.Settings().AI.BaseURL = s.baseURL
+			app.Settings().AI.ChatPath = s.chatPath
+			app.Settings().AI.EmbeddingsPath = s.embeddingsPath
+			app.Settings().AI.APIVersion = s.apiVersion
+			app.Settings().AI.AuthHeader = s.authHeader
+
+			var mu sync.Mutex
+			calls := map[string]http.Header{}
+
+			chat := &fakeChatTransport{content: `{"name":"test","fields":[{"name":"title","type":"text"}]}`}
+			embeddings := &fakeEmbeddingsTransport{}
+
+			app.Store().Set(core.StoreKeyAIHTTPTransport, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls[req.URL.String()] = req.Header.Clone()
+				mu.Unlock()
+
+				if req.URL.String() == s.expectedEmbeddingsURL {
+					return embeddings.RoundTrip(req)
+				}
+				return chat.RoundTrip(req)
+			}))
+
+			if _, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "test"}); err != nil {
+				t.Fatal(err)
+			}
+
+			collection := createTestEmbeddingsSourceCollection(t, app, fmt.Sprintf("test_base_url_%d", i))
+			record := core.NewRecord(collection)
+			record.Set("title", "test")
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Generated != 1 {
+				t.Fatalf("Expected 1 generated embedding, got %+v", result)
+			}
+
+			for _, expectedURL := range []string{s.expectedChatURL, s.expectedEmbeddingsURL} {
+				headers, ok := calls[expectedURL]
+				if !ok {
+					t.Fatalf("Expected a request to %q, got %v", expectedURL, calls)
+				}
+
+				if s.expectedAPIKeyHeader {
+					if headers.Get("api-key") != "test_key" || headers.Get("Authorization") != "" {
+						t.Fatalf("Expected only the api-key auth header, got %v", headers)
+					}
+				} else if headers.Get("Authorization") != "Bearer test_key" || headers.Get("api-key") != "" {
+					t.Fatalf("Expected only the Authorization auth header, got %v", headers)
+				}
+			}
+		})
+	}
+
+	t.Run("connection test", func(t *testing.T) {
+		var calledMethod, calledURL string
+
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calledMethod = req.Method
+			calledURL = req.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+				Request:    req,
+			}, nil
+		})
+
+		err := core.CheckAIConnection(transport, core.AIConfig{
+			Provider: core.AIProviderOpenAI,
+			Model:    "test_model",
+			APIKey:   "test_key",
+			BaseURL:  "https://gateway.example.com/v1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calledMethod != http.MethodGet || calledURL != "https://gateway.example.com/v1/models/test_model" {
+			t.Fatalf("Expected the gateway models endpoint, got %s %s", calledMethod, calledURL)
+		}
+
+		// Azure deployments are tested with a minimal chat completion
+		err = core.CheckAIConnection(transport, core.AIConfig{
+			Provider:   core.AIProviderOpenAI,
+			Model:      "test_model",
+			APIKey:     "test_key",
+			BaseURL:    "https://test.openai.azure.com/openai/deployments/test",
+			APIVersion: "2024-06-01",
+			AuthHeader: core.AIAuthHeaderAPIKey,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calledMethod != http.MethodPost || calledURL != "https://test.openai.azure.com/openai/deployments/test/chat/completions?api-version=2024-06-01" {
+			t.Fatalf("Expected the Azure chat completions endpoint, got %s %s", calledMethod, calledURL)
+		}
+	})
+}
+
+// -------------------------------------------------------------------
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type fakeAnthropicRequest struct {
+	url     string
+	apiKey  string
+	version string
+	body    struct {
+		Model       string  `json:"model"`
+		MaxTokens   int     `json:"max_tokens"`
+		Temperature float64 `json:"temperature"`
+		System      string  `json:"system"`
+		Messages    []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+}
+
+// fakeAnthropicTransport is a fake Anthropic messages API that
+// responds with the configured text content blocks.
+type fakeAnthropicTransport struct {
+	mu         sync.Mutex
+	requests   []fakeAnthropicRequest
+	content    []string
+	stopReason string
+}
+
+// Requests returns all submitted messages requests.
+func (f *fakeAnthropicTransport) Requests() []fakeAnthropicRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]fakeAnthropicRequest(nil), f.requests...)
+}
+
+func (f *fakeAnthropicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := fakeAnthropicRequest{
+		url:     req.URL.String(),
+		apiKey:  req.Header.Get("x-api-key"),
+		version: req.Header.Get("anthropic-version"),
+	}
+	if err := json.NewDecoder(req.Body).Decode(&r.body); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	f.mu.Unlock()
+
+	blocks := make([]map[string]any, 0, len(f.content))
+	for _, text := range f.content {
+		blocks = append(blocks, map[string]any{"type": "text", "text": text})
+	}
+
+	stopReason := f.stopReason
+	if stopReason == "" {
+		stopReason = "end_turn"
+	}
+
+	raw, err := json.Marshal(map[string]any{
+		"type":        "message",
+		"role":        "assistant",
+		"content":     blocks,
+		"stop_reason": stopReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(raw)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// DroppedSchemaField describes an invalid AI generated field
+// dropped from the schema in [GenerateSchemaRequest.Lenient] mode.
+type DroppedSchemaField struct {
+	// Index is the position of the field in the generated fields list.
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// filterGeneratedSchemaFields validates each of the AI generated collectionData
+// fields individually and removes the invalid ones from collectionData.
+//
+// Returns the dropped fields and their errors as [validation.Errors]
+// keyed by the field index (nil if all fields are valid).
+//
+// Note that the generated relation fields are allowed to be without
+// collectionId since the prompt asks for them to be filled by the user.
+func filterGeneratedSchemaFields(ctx context.Context, app App, collectionType string, collectionData map[string]any) ([]DroppedSchemaField, error) {
+	rawFields, ok := collectionData["fields"].([]any)
+	if !ok {
+		return nil, nil
+	}
+
+	// used only as validation context
+	collection := NewCollection(collectionType, "")
+
+	var dropped []DroppedSchemaField
+	fieldsErrs := validation.Errors{}
+	names := make(map[string]struct{}, len(rawFields))
+	valid := make([]any, 0, len(rawFields))
+
+	for i, raw := range rawFields {
+		data, _ := raw.(map[string]any)
+		name, _ := data["name"].(string)
+		fieldType, _ := data["type"].(string)
+
+		err := validateGeneratedSchemaField(ctx, app, collection, raw, names)
+		if err != nil {
+			dropped = append(dropped, DroppedSchemaField{
+				Index: i,
+				Name:  name,
+				Type:  fieldType,
+				Error: err.Error(),
+			})
+			fieldsErrs[strconv.Itoa(i)] = err
+			continue
+		}
+
+		names[strings.ToLower(name)] = struct{}{}
+		valid = append(valid, raw)
+	}
+
+	if len(dropped) == 0 {
+		return nil, nil
+	}
+
+	collectionData["fields"] = valid
+
+	return dropped, validation.Errors{"fields": fieldsErrs}
+}
+
+// validateGeneratedSchemaField validates a single raw generated field
+// against the collection and the already validated field names.
+//
+// The valid field is added to the collection fields (so that it is
+// taken into account when validating the next fields).
+func validateGeneratedSchemaField(ctx context.Context, app App, collection *Collection, raw any, names map[string]struct{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return validation.NewError("validation_invalid_generated_field", "Invalid field data.")
+	}
+
+	fwt := fieldWithType{}
+	if err := json.Unmarshal(data, &fwt); err != nil {
+		return validation.NewError("validation_invalid_generated_field", "Missing or unknown field type.")
+	}
+	field := fwt.Field
+
+	name := strings.ToLower(field.GetName())
+	if _, ok := names[name]; ok {
+		return validation.NewError("validation_duplicated_generated_field", "Duplicated field name.")
+	}
+
+	// the generated fields with the same name replace the collection default ones (ex. "email")
+	if existing := collection.Fields.GetByName(field.GetName()); existing != nil {
+		collection.Fields.RemoveById(existing.GetId())
+	}
+
+	collection.Fields.Add(field)
+
+	err = field.ValidateSettings(ctx, app, collection)
+
+	if relation, ok := field.(*RelationField); ok && relation.CollectionId == "" {
+		if errs, ok := err.(validation.Errors); ok {
+			delete(errs, "collectionId")
+			if len(errs) == 0 {
+				err = nil
+			}
+		}
+	}
+
+	if err != nil {
+		collection.Fields.RemoveById(field.GetId())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestGenerateSchemaLenient(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, &fakeChatTransport{content: `{"name":"products","fields":[
+		{"name":"title","type":"text"},
+		{"name":"price","type":"number"},
+		{"name":"currency","type":"money"},
+		{"name":"Title","type":"text"},
+		{"name":"bad name!","type":"text"},
+		{"name":"status","type":"select"},
+		{"name":"author","type":"relation","collectionId":"","maxSelect":1},
+		{"name":"owner","type":"relation","collectionId":"missing","maxSelect":1}
+	]}`})
+
+	expectedDropped := []int{2, 3, 4, 5, 7}
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "products"})
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		fieldsErrs, ok := errs["fields"].(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected fields validation errors, got %v", errs)
+		}
+
+		if len(fieldsErrs) != len(expectedDropped) {
+			t.Fatalf("Expected %d fields errors, got %v", len(expectedDropped), fieldsErrs)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		collection, dropped, err := core.GenerateSchemaFromPromptWithReport(context.Background(), app, core.GenerateSchemaRequest{
+			Prompt:  "products",
+			Lenient: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := collection.Fields.FieldNames()
+		expectedNames := []string{"title", "price", "author"}
+		if !slices.Equal(names, expectedNames) {
+			t.Fatalf("Expected fields %v, got %v", expectedNames, names)
+		}
+
+		if len(dropped) != len(expectedDropped) {
+			t.Fatalf("Expected %d dropped fields, got %v", len(expectedDropped), dropped)
+		}
+
+		for i, index := range expectedDropped {
+			if dropped[i].Index != index {
+				t.Fatalf("[%d] Expected dropped field index %d, got %d", i, index, dropped[i].Index)
+			}
+
+			if dropped[i].Error == "" {
+				t.Fatalf("[%d] Expected the dropped field error to be set", i)
+			}
+		}
+
+		if dropped[0].Name != "currency" || dropped[0].Type != "money" {
+			t.Fatalf("Expected the first dropped field to be currency (money), got %v", dropped[0])
+		}
+	})
+
+	t.Run("lenient with valid fields only", func(t *testing.T) {
+		app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeChatTransport{content: `{"fields":[{"name":"title","type":"text"}]}`})
+
+		_, dropped, err := core.GenerateSchemaFromPromptWithReport(context.Background(), app, core.GenerateSchemaRequest{
+			Prompt:  "posts",
+			Lenient: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(dropped) != 0 {
+			t.Fatalf("Expected no dropped fields, got %v", dropped)
+		}
+	})
+}
@@ -1,32 +1,113 @@
 package core
 
 import (
-	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/brianvoe/gofakeit/v7"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/dbutils"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
 )
 
 const (
-	openAIAPIURL = "https://api.openai.com/v1/chat/completions"
-
 	// HybridThreshold is the count above which we switch to hybrid generation
 	HybridThreshold = 20
 
 	// ArchetypeCount is the number of AI-generated archetypes for hybrid mode
 	ArchetypeCount = 12
+
+	// DefaultArchetypeTemperature is the default archetypes generation temperature
+	// (higher temperature for more diverse archetypes)
+	DefaultArchetypeTemperature = 0.8
+
+	// MaxArchetypeTemperature is the max allowed archetypes generation temperature
+	MaxArchetypeTemperature = 2
+
+	// ArchetypeSelectionRandom picks a random archetype for each generated record (default)
+	ArchetypeSelectionRandom = "random"
+
+	// ArchetypeSelectionRoundRobin cycles through the archetypes in order
+	// (the i-th generated record is derived from the i%len(archetypes) archetype).
+	//
+	// Each archetype produces count/len(archetypes) records with the remainder
+	// going to the first count%len(archetypes) archetypes (one extra record each).
+	ArchetypeSelectionRoundRobin = "roundRobin"
+
+	// DefaultAIMaxResponseSize is the default max size (in bytes) of a single AI provider response
+	// (could be changed with the AIConfig.MaxResponseSize setting)
+	DefaultAIMaxResponseSize = 32 << 20
+
+	// DefaultAIGenerationParseRetries is the default max number of regenerations
+	// of an AI response that couldn't be parsed as valid JSON
+	// (could be changed with the AIConfig.GenerationParseRetries setting)
+	DefaultAIGenerationParseRetries = 1
+
+	// MaxAIGenerationParseRetries is the max allowed value of the AIConfig.GenerationParseRetries setting
+	// (each retry is a new completion request so it is bounded to avoid runaway costs).
+	MaxAIGenerationParseRetries = 5
+
+	// StoreKeyAIHTTPTransport is the app store key of an optional [http.RoundTripper]
+	// used for the AI provider requests (ex. a custom proxy transport or a mock in tests).
+	StoreKeyAIHTTPTransport = "@aiHTTPTransport"
 )
 
+// readAIResponseBody reads the AI provider response body up to maxSize bytes
+// (non-positive maxSize fallbacks to [DefaultAIMaxResponseSize]).
+//
+// Returns an error if the response body is larger than maxSize.
+func readAIResponseBody(body io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultAIMaxResponseSize
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("the response exceeds the max allowed size of %d bytes", maxSize)
+	}
+
+	return data, nil
+}
+
+// newAIHTTPClient creates a new http client for the AI provider requests.
+//
+// If the app store has a [StoreKeyAIHTTPTransport] entry, it is used as client transport.
+func newAIHTTPClient(app App, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+
+	if transport, ok := app.Store().Get(StoreKeyAIHTTPTransport).(http.RoundTripper); ok {
+		client.Transport = transport
+	}
+
+	return client
+}
+
 // =====================================================
 // ARCHETYPE CACHE - In-memory cache for AI archetypes
 // =====================================================
@@ -43,13 +124,22 @@ type CachedArchetypes struct {
 type ArchetypeCache struct {
 	mu    sync.RWMutex
 	cache map[string]*CachedArchetypes
+
+	// lookup counters (see [ArchetypeCache.Stats])
+	hits   atomic.Int64
+	misses atomic.Int64
 }
 
-// Global archetype cache instance
-var globalArchetypeCache = &ArchetypeCache{
-	cache: make(map[string]*CachedArchetypes),
+// NewArchetypeCache creates a new empty archetype cache.
+func NewArchetypeCache() *ArchetypeCache {
+	return &ArchetypeCache{
+		cache: make(map[string]*CachedArchetypes),
+	}
 }
 
+// Global archetype cache instance
+var globalArchetypeCache = NewArchetypeCache()
+
 // Get retrieves cached archetypes if the schema hash matches
 func (c *ArchetypeCache) Get(collectionID, schemaHash string) (*CachedArchetypes, bool) {
 	c.mu.RLock()
@@ -57,8 +147,10 @@ func (c *ArchetypeCache) Get(collectionID, schemaHash string) (*CachedArchetypes
 
 	cached, exists := c.cache[collectionID]
 	if !exists || cached.SchemaHash != schemaHash {
+		c.misses.Add(1)
 		return nil, false
 	}
+	c.hits.Add(1)
 	return cached, true
 }
 
@@ -76,6 +168,34 @@ func (c *ArchetypeCache) Invalidate(collectionID string) {
 	delete(c.cache, collectionID)
 }
 
+// Stats returns the cache entries count and the Get lookups hit/miss counters
+// (the hit rate is the ratio of the hits to all lookups).
+func (c *ArchetypeCache) Stats() map[string]any {
+	c.mu.RLock()
+	entriesCount := len(c.cache)
+	c.mu.RUnlock()
+
+	hits := c.hits.Load()
+	misses := c.misses.Load()
+
+	var hitRate float64
+	if total := hits + misses; total > 0 {
+		hitRate = float64(hits) / float64(total)
+	}
+
+	return map[string]any{
+		"entriesCount": entriesCount,
+		"hits":         hits,
+		"misses":       misses,
+		"hitRate":      hitRate,
+	}
+}
+
+// GetArchetypeCacheStats returns statistics about the archetype cache
+func GetArchetypeCacheStats() map[string]any {
+	return globalArchetypeCache.Stats()
+}
+
 // computeSchemaHash generates a hash of the collection's field schema
 // This is used to invalidate cache when schema changes
 func computeSchemaHash(fields []SeedFieldInfo) string {
@@ -98,6 +218,106 @@ func computeSchemaHash(fields []SeedFieldInfo) string {
 	return hex.EncodeToString(hash[:8]) // First 8 bytes is enough
 }
 
+// =====================================================
+// CUSTOM SEED GENERATORS - Host application registered value generators
+// =====================================================
+
+// SeedValueGenerator defines a custom seed value generator function.
+//
+// The provided rand source is local to the current generation worker
+// and it is safe to use without additional locking.
+type SeedValueGenerator func(fieldInfo SeedFieldInfo, r *rand.Rand) any
+
+// SeedGeneratorTypePrefix is the pattern prefix used to register
+// a custom seed generator for a field type instead of a field name
+// (ex. "type:email").
+const SeedGeneratorTypePrefix = "type:"
+
+type seedGeneratorEntry struct {
+	pattern   string
+	generator SeedValueGenerator
+}
+
+// seedGenerators holds the registered custom seed generators in registration order
+var seedGenerators = struct {
+	mu      sync.RWMutex
+	entries []seedGeneratorEntry
+}{}
+
+// RegisterSeedGenerator registers a custom seed value generator that is
+// consulted in the archetypes mutation path before the built-in heuristics.
+//
+// The pattern could be either:
+//   - a case-insensitive field name pattern with optional "*" and "?" wildcards (ex. "iban", "*_iban")
+//   - a field type tag prefixed with [SeedGeneratorTypePrefix] (ex. "type:email")
+//
+// Registering a generator for an already registered pattern replaces it.
+// When multiple patterns match the same field, the first registered one is used.
+func RegisterSeedGenerator(pattern string, generator SeedValueGenerator) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" || generator == nil {
+		return
+	}
+
+	seedGenerators.mu.Lock()
+	defer seedGenerators.mu.Unlock()
+
+	for i, entry := range seedGenerators.entries {
+		if entry.pattern == pattern {
+			seedGenerators.entries[i].generator = generator
+			return
+		}
+	}
+
+	seedGenerators.entries = append(seedGenerators.entries, seedGeneratorEntry{
+		pattern:   pattern,
+		generator: generator,
+	})
+}
+
+// UnregisterSeedGenerator removes the custom seed generator registered for the specified pattern.
+func UnregisterSeedGenerator(pattern string) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+	seedGenerators.mu.Lock()
+	defer seedGenerators.mu.Unlock()
+
+	for i, entry := range seedGenerators.entries {
+		if entry.pattern == pattern {
+			seedGenerators.entries = append(seedGenerators.entries[:i], seedGenerators.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// findSeedGenerator returns the first registered custom seed generator matching the field (if any).
+func findSeedGenerator(fieldInfo SeedFieldInfo) SeedValueGenerator {
+	seedGenerators.mu.RLock()
+	defer seedGenerators.mu.RUnlock()
+
+	if len(seedGenerators.entries) == 0 {
+		return nil
+	}
+
+	lowerName := strings.ToLower(fieldInfo.Name)
+	lowerType := strings.ToLower(fieldInfo.Type)
+
+	for _, entry := range seedGenerators.entries {
+		if typeTag, ok := strings.CutPrefix(entry.pattern, SeedGeneratorTypePrefix); ok {
+			if lowerType != "" && typeTag == lowerType {
+				return entry.generator
+			}
+			continue
+		}
+
+		if matched, _ := path.Match(entry.pattern, lowerName); matched {
+			return entry.generator
+		}
+	}
+
+	return nil
+}
+
 // ExistingField represents a simplified field for context.
 type ExistingField struct {
 	Name string `json:"name"`
@@ -110,28 +330,261 @@ type GenerateSchemaRequest struct {
 	CollectionType    string          `json:"collectionType"` // "base", "auth", or "view"
 	CurrentCollection string          `json:"currentCollection,omitempty"`
 	ExistingFields    []ExistingField `json:"existingFields,omitempty"`
+
+	// IncludeExistingCollections indicates whether to include a summary of the existing
+	// app collections in the prompt so that the generated schema follows their naming
+	// and field conventions (see [MaxSchemaContextCollections]).
+	IncludeExistingCollections bool `json:"includeExistingCollections,omitempty"`
+
+	// NamePrefix and NameSuffix are optional prefix and suffix of the collection
+	// name generated from the prompt when the AI response doesn't have one
+	// (ex. "app_" and "_v2" -> "app_blog_posts_v2").
+	NamePrefix string `json:"namePrefix,omitempty"`
+	NameSuffix string `json:"nameSuffix,omitempty"`
+
+	// Lenient indicates whether to drop the invalid generated fields
+	// (see [GenerateSchemaFromPromptWithReport]) instead of failing the whole generation.
+	Lenient bool `json:"lenient,omitempty"`
 }
 
+// MaxSchemaContextCollections is the max number of existing collections
+// summarized in the schema generation prompt.
+const MaxSchemaContextCollections = 30
+
+// MaxSchemaExistingFields is the max number of the edited collection
+// existing fields listed in the schema generation prompt.
+const MaxSchemaExistingFields = 50
+
+const (
+	// DefaultGeneratedCollectionName is the collection name fallback when
+	// the schema generation prompt doesn't have any transliterable characters.
+	DefaultGeneratedCollectionName = "collection"
+
+	// MaxGeneratedCollectionNameLength is the max length of the collection name
+	// slug generated from the prompt (excluding the prefix and suffix).
+	MaxGeneratedCollectionNameLength = 50
+)
+
 // GenerateSchemaResponse represents the response from schema generation.
 type GenerateSchemaResponse struct {
 	Collection *Collection `json:"collection"`
 	Error      string      `json:"error,omitempty"`
 }
 
-// GenerateSchemaFromPrompt uses OpenAI to generate a PocketBase collection schema from natural language.
+// summarizeExistingCollections returns a short, one line per collection, summary
+// of the non-system app collections and their fields (excluding the specified one).
+func summarizeExistingCollections(app App, excludeName string) (string, error) {
+	collections, err := app.FindAllCollections()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the existing collections: %w", err)
+	}
+
+	var sb strings.Builder
+	var count int
+
+	for _, collection := range collections {
+		if collection.System || strings.EqualFold(collection.Name, excludeName) {
+			continue
+		}
+
+		if count >= MaxSchemaContextCollections {
+			break
+		}
+		count++
+
+		fields := make([]string, 0, len(collection.Fields))
+		for _, field := range collection.Fields {
+			if field.GetSystem() || field.GetHidden() {
+				continue
+			}
+			fields = append(fields, fmt.Sprintf("%s (%s)", field.GetName(), field.Type()))
+		}
+
+		sb.WriteString(fmt.Sprintf("- %s [%s]: %s\n", collection.Name, collection.Type, strings.Join(fields, ", ")))
+	}
+
+	return sb.String(), nil
+}
+
+// selectSchemaExistingFields returns up to limit of the existing fields to list
+// in the schema generation prompt and the number of the omitted ones.
+//
+// The fields mentioned in the prompt are selected first and the rest of
+// the limit is filled with the fields in their original order
+// (the selected fields are also returned in their original order).
+func selectSchemaExistingFields(fields []ExistingField, prompt string, limit int) ([]ExistingField, int) {
+	if len(fields) <= limit {
+		return fields, 0
+	}
+
+	prompt = strings.ToLower(prompt)
+
+	selected := make([]bool, len(fields))
+	var total int
+
+	// the fields mentioned in the prompt (ex. "rename the created_by field" or "split the full name")
+	for i, f := range fields {
+		if total >= limit {
+			break
+		}
+
+		name := strings.ToLower(f.Name)
+		if strings.Contains(prompt, name) || strings.Contains(prompt, strings.ReplaceAll(name, "_", " ")) {
+			selected[i] = true
+			total++
+		}
+	}
+
+	// the first N
+	for i := range fields {
+		if total >= limit {
+			break
+		}
+
+		if !selected[i] {
+			selected[i] = true
+			total++
+		}
+	}
+
+	result := make([]ExistingField, 0, total)
+	for i, f := range fields {
+		if selected[i] {
+			result = append(result, f)
+		}
+	}
+
+	return result, len(fields) - total
+}
+
+// ErrAIResponseTruncated is returned when the AI response was cut off because
+// it reached the max tokens limit (finish_reason "length").
+var ErrAIResponseTruncated = errors.New("the AI response was truncated because it reached the max tokens limit (finish_reason \"length\"), try raising max_tokens or requesting less data")
+
+// ErrAIContentFiltered is returned when the AI response was blocked
+// by the provider content filter (finish_reason "content_filter").
+var ErrAIContentFiltered = errors.New("the AI response was blocked by the provider content filter (finish_reason \"content_filter\"), try revising the prompt")
+
+const (
+	// MaxSchemaPromptLength is the max number of characters of the schema generation prompt.
+	MaxSchemaPromptLength = 2000
+
+	// MaxSeedDescriptionLength is the max number of characters of the seed data description.
+	MaxSeedDescriptionLength = 2000
+)
+
+// ErrAITextTooLong is returned when a text input of an AI operation
+// exceeds its max allowed length (ex. [MaxSeedDescriptionLength]).
+var ErrAITextTooLong = errors.New("the text input is too long")
+
+// checkAITextLength returns [ErrAITextTooLong] if text has more than maxLength characters.
+func checkAITextLength(name string, text string, maxLength int) error {
+	if length := utf8.RuneCountInString(text); length > maxLength {
+		return fmt.Errorf("%w: %s must be at most %d characters (got %d)", ErrAITextTooLong, name, maxLength, length)
+	}
+
+	return nil
+}
+
+// ErrAIRequestCanceled is returned when the context of an AI operation
+// is canceled before its completion (ex. the client closed the connection).
+var ErrAIRequestCanceled = errors.New("the AI operation was canceled")
+
+// checkAIContext returns [ErrAIRequestCanceled] if the context is already done.
+func checkAIContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrAIRequestCanceled, err)
+	}
+
+	return nil
+}
+
+// checkAIFinishReason returns a descriptive error for the chat completion
+// finish reasons that result in incomplete content.
+func checkAIFinishReason(reason string) error {
+	switch reason {
+	case "length":
+		return ErrAIResponseTruncated
+	case "content_filter":
+		return ErrAIContentFiltered
+	}
+
+	return nil
+}
+
+// completeAIJSON sends the messages to the chat provider and decodes
+// the JSON response content with the provided parse function.
+//
+// If parse fails (ex. truncated or malformed JSON), the whole completion is
+// regenerated up to AIConfig.GenerationParseRetries times and the last parse
+// error is returned. The provider request errors are not retried.
+func completeAIJSON(
+	ctx context.Context,
+	app App,
+	provider ChatProvider,
+	messages []ChatMessage,
+	opts ChatOptions,
+	parse func(content string) error,
+) error {
+	retries := app.Settings().AI.GenerationParseRetriesOrDefault()
+
+	var parseErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := checkAIContext(ctx); err != nil {
+				return err
+			}
+
+			app.Logger().Debug(
+				"Regenerating the unparsable AI response",
+				"attempt", attempt,
+				"error", parseErr.Error(),
+			)
+		}
+
+		content, err := provider.Complete(ctx, messages, opts)
+		if err != nil {
+			return err
+		}
+
+		parseErr = parse(content)
+		if parseErr == nil {
+			return nil
+		}
+	}
+
+	return parseErr
+}
+
+// GenerateSchemaFromPrompt uses the configured AI provider to generate a PocketBase collection schema from natural language.
 func GenerateSchemaFromPrompt(app App, req GenerateSchemaRequest) (*Collection, error) {
+	return GenerateSchemaFromPromptWithContext(context.Background(), app, req)
+}
+
+// GenerateSchemaFromPromptWithContext is the same as [GenerateSchemaFromPrompt]
+// but the AI provider request is canceled when ctx is done.
+func GenerateSchemaFromPromptWithContext(ctx context.Context, app App, req GenerateSchemaRequest) (*Collection, error) {
+	collection, _, err := GenerateSchemaFromPromptWithReport(ctx, app, req)
+	return collection, err
+}
+
+// GenerateSchemaFromPromptWithReport is the same as [GenerateSchemaFromPromptWithContext]
+// but also returns the invalid generated fields dropped in [GenerateSchemaRequest.Lenient] mode.
+//
+// In non-lenient mode the generation fails with [validation.Errors] if any of the generated fields is invalid.
+func GenerateSchemaFromPromptWithReport(ctx context.Context, app App, req GenerateSchemaRequest) (*Collection, []DroppedSchemaField, error) {
 	settings := app.Settings()
 	
 	if !settings.AI.Enabled {
-		return nil, fmt.Errorf("AI features are not enabled")
+		return nil, nil, fmt.Errorf("AI features are not enabled")
 	}
 
 	if settings.AI.APIKey == "" {
-		return nil, fmt.Errorf("AI API key is not configured")
+		return nil, nil, fmt.Errorf("AI API key is not configured")
 	}
 
-	if settings.AI.Provider != "openai" {
-		return nil, fmt.Errorf("unsupported AI provider: %s", settings.AI.Provider)
+	if err := checkAITextLength("prompt", req.Prompt, MaxSchemaPromptLength); err != nil {
+		return nil, nil, err
 	}
 
 	// Build the system prompt with context about PocketBase field types
@@ -141,10 +594,14 @@ func GenerateSchemaFromPrompt(app App, req GenerateSchemaRequest) (*Collection,
 	var userPrompt string
 	if req.CurrentCollection != "" && len(req.ExistingFields) > 0 {
 		// User is editing an existing collection - provide context
-		existingFieldsStr := make([]string, len(req.ExistingFields))
-		for i, f := range req.ExistingFields {
+		existingFields, omitted := selectSchemaExistingFields(req.ExistingFields, req.Prompt, MaxSchemaExistingFields)
+		existingFieldsStr := make([]string, len(existingFields))
+		for i, f := range existingFields {
 			existingFieldsStr[i] = fmt.Sprintf("%s (%s)", f.Name, f.Type)
 		}
+		if omitted > 0 {
+			existingFieldsStr = append(existingFieldsStr, fmt.Sprintf("and %d more fields not listed here", omitted))
+		}
 		userPrompt = fmt.Sprintf(
 			`I'm editing a collection named '%s' which already has these fields: %s.
 
@@ -174,82 +631,42 @@ CRITICAL INSTRUCTIONS:
 		userPrompt = fmt.Sprintf("Create a PocketBase %s collection schema for: %s", req.CollectionType, req.Prompt)
 	}
 
-	// Prepare OpenAI API request
-	openAIReq := map[string]interface{}{
-		"model": settings.AI.Model,
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": systemPrompt,
-			},
-			{
-				"role":    "user",
-				"content": userPrompt,
-			},
-		},
-		"temperature": 0.3,
-		"response_format": map[string]string{
-			"type": "json_object",
-		},
-	}
-
-	reqBody, err := json.Marshal(openAIReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal OpenAI request: %w", err)
-	}
-
-	// Create HTTP request
-	httpReq, err := http.NewRequest("POST", openAIAPIURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", settings.AI.APIKey))
-
-	// Make the request
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	if req.IncludeExistingCollections {
+		summary, err := summarizeExistingCollections(app, req.CurrentCollection)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call OpenAI API: %w", err)
+		if summary != "" {
+			userPrompt += "\n\nThe app already has the following collections:\n" + summary +
+				"\nFollow their naming and field conventions (ex. reuse the same field names for the same concepts)."
+		}
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	provider, err := NewChatProvider(app)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read OpenAI response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
-
-	// Parse OpenAI response
-	var openAIResp struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+		return nil, nil, err
 	}
 
-	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
-	}
-
-	if len(openAIResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
-	}
-
-	content := openAIResp.Choices[0].Message.Content
-
 	// Parse the collection JSON from the response
 	var collectionData map[string]interface{}
-	if err := json.Unmarshal([]byte(content), &collectionData); err != nil {
-		return nil, fmt.Errorf("failed to parse collection JSON: %w", err)
+	err = completeAIJSON(ctx, app, provider, []ChatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	}, ChatOptions{
+		Model:       settings.AI.SchemaModelOrDefault(),
+		Temperature: 0.3,
+		JSON:        true,
+		Timeout:     30 * time.Second,
+	}, func(content string) error {
+		collectionData = nil
+		if err := json.Unmarshal([]byte(content), &collectionData); err != nil {
+			return fmt.Errorf("failed to parse collection JSON: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Ensure collection type is set
@@ -258,65 +675,167 @@ CRITICAL INSTRUCTIONS:
 	}
 	collectionData["type"] = req.CollectionType
 
+	// Validate the generated fields individually
+	dropped, fieldsErr := filterGeneratedSchemaFields(ctx, app, req.CollectionType, collectionData)
+	if fieldsErr != nil && !req.Lenient {
+		return nil, nil, fieldsErr
+	}
+
 	// Create collection from JSON
 	collectionJSON, err := json.Marshal(collectionData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal collection data: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal collection data: %w", err)
 	}
 
 	collection := NewCollection(req.CollectionType, "")
 	if err := json.Unmarshal(collectionJSON, collection); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal collection: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal collection: %w", err)
 	}
 
 	// Ensure collection has a name
 	if collection.Name == "" {
-		// Generate a name from the prompt (simple slugify)
-		name := strings.ToLower(req.Prompt)
-		name = strings.ReplaceAll(name, " ", "_")
-		name = strings.ReplaceAll(name, "-", "_")
-		// Remove special characters
-		var builder strings.Builder
-		for _, r := range name {
-			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
-				builder.WriteRune(r)
-			}
+		collection.Name = generateCollectionName(req.Prompt, req.NamePrefix, req.NameSuffix)
+	}
+
+	return collection, dropped, nil
+}
+
+// SchemaFieldsDiff describes the generated fields of an edited collection
+// compared to its existing fields (see [DiffSchemaFields]).
+type SchemaFieldsDiff struct {
+	// Added are the generated fields that don't exist in the collection.
+	Added []ExistingField `json:"added"`
+
+	// Collisions are the generated fields with the same name as an existing field.
+	Collisions []SchemaFieldCollision `json:"collisions"`
+}
+
+// SchemaFieldCollision describes a generated field colliding with an existing one.
+type SchemaFieldCollision struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	ExistingName string `json:"existingName"`
+	ExistingType string `json:"existingType"`
+	Warning      string `json:"warning"`
+}
+
+// DiffSchemaFields compares the non-system fields of the generated collection
+// against the existing fields of the edited collection.
+//
+// The field names are compared case-insensitively.
+func DiffSchemaFields(existingFields []ExistingField, collection *Collection) SchemaFieldsDiff {
+	diff := SchemaFieldsDiff{
+		Added:      []ExistingField{},
+		Collisions: []SchemaFieldCollision{},
+	}
+
+	for _, field := range collection.Fields {
+		if field.GetSystem() {
+			continue
 		}
-		collection.Name = builder.String()
-		if collection.Name == "" {
-			collection.Name = "collection"
+
+		name := field.GetName()
+
+		idx := slices.IndexFunc(existingFields, func(f ExistingField) bool {
+			return strings.EqualFold(f.Name, name)
+		})
+		if idx == -1 {
+			diff.Added = append(diff.Added, ExistingField{Name: name, Type: field.Type()})
+			continue
 		}
+
+		existing := existingFields[idx]
+
+		warning := fmt.Sprintf("Field %q already exists in the collection.", existing.Name)
+		if existing.Type != field.Type() {
+			warning = fmt.Sprintf("Field %q already exists in the collection with a different type (%s instead of %s).", existing.Name, existing.Type, field.Type())
+		}
+
+		diff.Collisions = append(diff.Collisions, SchemaFieldCollision{
+			Name:         name,
+			Type:         field.Type(),
+			ExistingName: existing.Name,
+			ExistingType: existing.Type,
+			Warning:      warning,
+		})
 	}
 
-	return collection, nil
+	return diff
 }
 
-// TestAIConnection tests the AI connection with the provided credentials.
-func TestAIConnection(provider, model, apiKey string) error {
-	if provider != "openai" {
-		return fmt.Errorf("unsupported AI provider: %s", provider)
+// generateCollectionName generates a collection name from the transliterated
+// prompt slug (see [inflector.Slugify]) wrapped with the specified prefix and suffix.
+//
+// The slug is truncated to [MaxGeneratedCollectionNameLength] characters at a word
+// boundary and fallbacks to [DefaultGeneratedCollectionName] if empty.
+func generateCollectionName(prompt string, prefix string, suffix string) string {
+	slug := inflector.Slugify(prompt, "_")
+
+	if len(slug) > MaxGeneratedCollectionNameLength {
+		slug = slug[:MaxGeneratedCollectionNameLength]
+		if i := strings.LastIndexByte(slug, '_'); i > 0 {
+			slug = slug[:i]
+		}
 	}
 
-	if apiKey == "" {
-		return fmt.Errorf("API key is required")
+	if slug == "" {
+		slug = DefaultGeneratedCollectionName
 	}
 
-	// Make a simple API call to test the connection
-	// Using a minimal request to the models endpoint
-	httpReq, err := http.NewRequest("GET", "https://api.openai.com/v1/models/"+model, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+	return prefix + slug + suffix
+}
+
+// TestAIConnection tests the AI connection with the provided config credentials
+// (respecting the OpenAI BaseURL, ChatPath, APIVersion and AuthHeader options).
+func TestAIConnection(config AIConfig) error {
+	return testAIConnection(&http.Client{Timeout: 10 * time.Second}, config)
+}
+
+// testAIConnection tests the AI connection with the provided config credentials using the specified client.
+func testAIConnection(client *http.Client, config AIConfig) error {
+	if config.Provider != AIProviderOpenAI && config.Provider != AIProviderAnthropic {
+		return fmt.Errorf("unsupported AI provider: %s", config.Provider)
+	}
+
+	if config.APIKey == "" {
+		return fmt.Errorf("API key is required")
 	}
 
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	var httpReq *http.Request
+	var err error
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	providerName := "OpenAI"
+	switch {
+	case config.Provider == AIProviderAnthropic:
+		providerName = "Anthropic"
+		httpReq, err = http.NewRequest("GET", anthropicModelsURL+config.Model, nil)
+		if err == nil {
+			setAnthropicHeaders(httpReq, config.APIKey)
+		}
+	case config.AuthHeader == AIAuthHeaderAPIKey:
+		// Azure OpenAI deployments don't have a models endpoint
+		// so we submit a minimal chat completion instead
+		body := `{"model":` + strconv.Quote(config.Model) + `,"messages":[{"role":"user","content":"ping"}],"max_tokens":1}`
+		httpReq, err = http.NewRequest("POST", openAIURL(config, config.ChatPathOrDefault()), strings.NewReader(body))
+		if err == nil {
+			httpReq.Header.Set("Content-Type", "application/json")
+			setOpenAIAuthHeader(httpReq, config)
+		}
+	default:
+		// Make a simple API call to test the connection
+		// Using a minimal request to the models endpoint
+		httpReq, err = http.NewRequest("GET", openAIURL(config, openAIModelsPath+config.Model), nil)
+		if err == nil {
+			setOpenAIAuthHeader(httpReq, config)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("failed to connect to OpenAI API: %w", err)
+		return fmt.Errorf("failed to connect to %s API: %w", providerName, err)
 	}
 	defer resp.Body.Close()
 
@@ -325,12 +844,12 @@ func TestAIConnection(provider, model, apiKey string) error {
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("model '%s' not found or not accessible with this API key", model)
+		return fmt.Errorf("model '%s' not found or not accessible with this API key", config.Model)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+		body, _ := readAIResponseBody(resp.Body, DefaultAIMaxResponseSize)
+		return fmt.Errorf("%s API error (status %d): %s", providerName, resp.StatusCode, string(body))
 	}
 
 	return nil
@@ -408,6 +927,97 @@ type GenerateSeedDataRequest struct {
 	CollectionId string `json:"collectionId"`
 	Count        int    `json:"count"`
 	Description  string `json:"description,omitempty"` // Optional context for data generation
+
+	// FixedFields are set identically on every generated record (overriding the generated values)
+	FixedFields map[string]any `json:"fixedFields,omitempty"`
+
+	// ArchetypeTemperature is an optional archetypes generation temperature override (0-2).
+	//
+	// When set, the archetypes are always regenerated (and the cached ones are left untouched).
+	ArchetypeTemperature *float64 `json:"archetypeTemperature,omitempty"`
+
+	// ArchetypeSelection is the archetypes selection mode of the hybrid generation
+	// ("random" or "roundRobin"; default to "random").
+	//
+	// Use "roundRobin" for reproducible and evenly distributed records.
+	ArchetypeSelection string `json:"archetypeSelection,omitempty"`
+
+	// RecordsPerArchetype is an optional exact number of records to derive from each
+	// of the archetypes in round-robin order (aka. len(archetypes)*RecordsPerArchetype records in total).
+	//
+	// When set, the archetypes are always used (regardless of the count)
+	// and the Count is ignored.
+	RecordsPerArchetype int `json:"recordsPerArchetype,omitempty"`
+
+	// NumberDistributions is an optional map with the values distribution of specific
+	// number fields (the generated values of the listed fields are replaced by samples of the distribution).
+	NumberDistributions map[string]SeedNumberDistribution `json:"numberDistributions,omitempty"`
+
+	// SelectCountDistributions is an optional map with the selections count distribution of specific
+	// multi-select fields (the generated values of the listed fields are replaced by values with a sampled count).
+	SelectCountDistributions map[string]SeedSelectCountDistribution `json:"selectCountDistributions,omitempty"`
+
+	// Distributions is an optional map with the target values proportions of specific
+	// select fields (ex. {"status": {"active": 0.7, "pending": 0.2, "closed": 0.1}}).
+	//
+	// The proportions of each field must sum to 1 and the values not listed are never picked.
+	Distributions map[string]map[string]float64 `json:"distributions,omitempty"`
+
+	// SelectWeights is an optional map with the relative weights of specific
+	// select field values (ex. {"status": {"published": 8, "archived": 0.5}}).
+	//
+	// Unlike the Distributions, the weights don't need to sum to 1
+	// and the values not listed default to weight 1.
+	SelectWeights map[string]map[string]float64 `json:"selectWeights,omitempty"`
+
+	// DateRanges is an optional map with the values range of specific date fields
+	// (ex. {"published_at": {"min": "2024-01-01 00:00:00.000Z"}}).
+	//
+	// The ranges are combined with the field own min/max constraints (if any).
+	DateRanges map[string]SeedDateRange `json:"dateRanges,omitempty"`
+
+	// Constraints is an optional list of cross-field ordering constraints
+	// (ex. "end_date > start_date") enforced on the generated records.
+	Constraints []SeedConstraint `json:"constraints,omitempty"`
+
+	// TimeSeries is an optional option to generate the values of a date field
+	// as a sequence following a specific distribution over a date range.
+	TimeSeries *SeedTimeSeries `json:"timeSeries,omitempty"`
+
+	// RunId is an optional seed run identifier used to tag the inserted records
+	// so that they could be removed later with [CleanupSeedRun].
+	RunId string `json:"runId,omitempty"`
+
+	// Tree is an optional option to generate hierarchical records linked
+	// with a self-referencing parent relation (see [SeedTree]).
+	//
+	// When set, the records count is determined by the tree options and the Count is ignored.
+	Tree *SeedTree `json:"tree,omitempty"`
+
+	// PopulateRelations indicates whether to fill the relation fields with randomly
+	// sampled existing record ids of their referenced collection
+	// (up to [MaxSeedRelationCandidates] candidates per field).
+	//
+	// The relation fields with empty referenced collection are left blank
+	// (see [SkippedSeedRelationFields]).
+	PopulateRelations bool `json:"populateRelations,omitempty"`
+
+	// Locale is an optional locale of the generated identity values
+	// (names, cities, countries and phones; ex. "de" or "ja-JP").
+	//
+	// Unknown locales fallback to the default (English) values.
+	Locale string `json:"locale,omitempty"`
+
+	// Seed is an optional random seed of the hybrid generation.
+	//
+	// When set, the same request (with the same cached archetypes and existing records)
+	// always produces identical records (the pure AI generation of the small counts
+	// and the AI generated archetypes are not affected by it).
+	Seed *int64 `json:"seed,omitempty"`
+
+	// DryRun indicates whether to only return the generated records
+	// without inserting them (used by the seed data API endpoints).
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // GenerateSeedDataResponse represents the response from seed data generation.
@@ -426,10 +1036,40 @@ type SeedFieldInfo struct {
 	Max       float64  `json:"max,omitempty"`
 	Values    []string `json:"values,omitempty"` // For select fields
 	MaxSelect int      `json:"maxSelect,omitempty"`
+
+	// OnlyInt indicates that the number field allows only integer values.
+	OnlyInt bool `json:"onlyInt,omitempty"`
+
+	// Unique indicates that the field has a single column unique index
+	// (the generated values are deduplicated, see [seedUniqueValues]).
+	Unique bool `json:"unique,omitempty"`
+
+	// Distribution is an optional number field values distribution (default to uniform)
+	Distribution *SeedNumberDistribution `json:"distribution,omitempty"`
+
+	// SelectCountDistribution is an optional multi-select field selections count distribution (default to uniform)
+	SelectCountDistribution *SeedSelectCountDistribution `json:"selectCountDistribution,omitempty"`
+
+	// DateRange is an optional date field values range (default to the last 2 years)
+	DateRange *SeedDateRange `json:"dateRange,omitempty"`
+
+	// Proportions is an optional select field values target proportions
+	// or relative weights (default to uniform)
+	Proportions map[string]float64 `json:"proportions,omitempty"`
 }
 
-// GenerateSeedDataFromSchema uses OpenAI to generate realistic sample records for a collection.
+// GenerateSeedDataFromSchema uses the configured AI provider to generate realistic sample records for a collection.
 func GenerateSeedDataFromSchema(app App, collection *Collection, count int, description string) ([]map[string]any, error) {
+	if err := checkAITextLength("description", description, MaxSeedDescriptionLength); err != nil {
+		return nil, err
+	}
+
+	return generateSeedDataFromSchema(app, collection, count, description)
+}
+
+// generateSeedDataFromSchema is the same as [GenerateSeedDataFromSchema]
+// but without the description length check (ex. for the locale described one).
+func generateSeedDataFromSchema(app App, collection *Collection, count int, description string) ([]map[string]any, error) {
 	settings := app.Settings()
 
 	if !settings.AI.Enabled {
@@ -440,10 +1080,6 @@ func GenerateSeedDataFromSchema(app App, collection *Collection, count int, desc
 		return nil, fmt.Errorf("AI API key is not configured")
 	}
 
-	if settings.AI.Provider != "openai" {
-		return nil, fmt.Errorf("unsupported AI provider: %s", settings.AI.Provider)
-	}
-
 	if count <= 0 {
 		return nil, fmt.Errorf("count must be greater than 0")
 	}
@@ -465,87 +1101,107 @@ func GenerateSeedDataFromSchema(app App, collection *Collection, count int, desc
 	// Build the user prompt
 	userPrompt := buildSeedDataUserPrompt(collection.Name, fields, count, description)
 
-	// Prepare OpenAI API request
-	openAIReq := map[string]interface{}{
-		"model": settings.AI.Model,
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": systemPrompt,
-			},
-			{
-				"role":    "user",
-				"content": userPrompt,
-			},
-		},
-		"temperature": 0.7, // Slightly higher for more varied data
-		"response_format": map[string]string{
-			"type": "json_object",
-		},
-	}
-
-	reqBody, err := json.Marshal(openAIReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal OpenAI request: %w", err)
-	}
-
-	// Create HTTP request
-	httpReq, err := http.NewRequest("POST", openAIAPIURL, bytes.NewBuffer(reqBody))
+	provider, err := NewChatProvider(app)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", settings.AI.APIKey))
-
-	// Make the request with longer timeout for larger data generation
-	client := &http.Client{
-		Timeout: 120 * time.Second,
+	// Parse the records JSON from the response
+	var result struct {
+		Records []map[string]any `json:"records"`
 	}
 
-	resp, err := client.Do(httpReq)
+	// Longer timeout and slightly higher temperature for larger and more varied data
+	err = completeAIJSON(context.Background(), app, provider, []ChatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	}, ChatOptions{
+		Model:       settings.AI.SeedModelOrDefault(),
+		Temperature: 0.7,
+		JSON:        true,
+		Timeout:     120 * time.Second,
+	}, func(content string) error {
+		result.Records = nil
+		if err := json.Unmarshal([]byte(content), &result); err != nil {
+			return fmt.Errorf("failed to parse records JSON: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to call OpenAI API: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read OpenAI response: %w", err)
-	}
+	sanitizeAISeedRecords(app, collection, result.Records, fields)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
+	return result.Records, nil
+}
 
-	// Parse OpenAI response
-	var openAIResp struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
+// SeedFieldsReport describes the field names mismatches between
+// the AI generated records and the collection schema.
+type SeedFieldsReport struct {
+	// UnknownFields are the returned field names that don't exist in the schema.
+	UnknownFields []string `json:"unknownFields,omitempty"`
+
+	// MissingFields are the schema field names that were omitted in all returned records.
+	MissingFields []string `json:"missingFields,omitempty"`
+}
+
+// IsEmpty reports whether there are no field names mismatches.
+func (r SeedFieldsReport) IsEmpty() bool {
+	return len(r.UnknownFields) == 0 && len(r.MissingFields) == 0
+}
 
-	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
+// SanitizeSeedRecords strips the unknown (non-schema) fields from the
+// AI generated records and reports them together with the omitted schema fields.
+func SanitizeSeedRecords(records []map[string]any, fields []SeedFieldInfo) SeedFieldsReport {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Name] = false
 	}
 
-	if len(openAIResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+	unknown := map[string]struct{}{}
+
+	for _, record := range records {
+		for name := range record {
+			if _, ok := known[name]; !ok {
+				unknown[name] = struct{}{}
+				delete(record, name)
+				continue
+			}
+			known[name] = true
+		}
 	}
 
-	content := openAIResp.Choices[0].Message.Content
+	report := SeedFieldsReport{}
 
-	// Parse the records JSON from the response
-	var result struct {
-		Records []map[string]any `json:"records"`
+	for name := range unknown {
+		report.UnknownFields = append(report.UnknownFields, name)
 	}
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse records JSON: %w", err)
+	sort.Strings(report.UnknownFields)
+
+	for _, f := range fields {
+		if !known[f.Name] {
+			report.MissingFields = append(report.MissingFields, f.Name)
+		}
 	}
 
-	return result.Records, nil
+	return report
+}
+
+// sanitizeAISeedRecords strips the unknown fields of the AI generated records
+// and logs a warning with the field names mismatches (if any).
+func sanitizeAISeedRecords(app App, collection *Collection, records []map[string]any, fields []SeedFieldInfo) {
+	report := SanitizeSeedRecords(records, fields)
+	if report.IsEmpty() {
+		return
+	}
+
+	app.Logger().Warn(
+		"AI generated seed records with mismatched field names",
+		"collection", collection.Name,
+		"unknownFields", report.UnknownFields,
+		"missingFields", report.MissingFields,
+	)
 }
 
 // extractSeedFieldsInfo extracts field information suitable for seed data generation.
@@ -580,8 +1236,9 @@ func extractSeedFieldsInfo(collection *Collection) []SeedFieldInfo {
 		}
 
 		info := SeedFieldInfo{
-			Name: fieldName,
-			Type: fieldType,
+			Name:   fieldName,
+			Type:   fieldType,
+			Unique: dbutils.HasSingleColumnUniqueIndex(fieldName, collection.Indexes),
 		}
 
 		// Extract type-specific options
@@ -593,12 +1250,17 @@ func extractSeedFieldsInfo(collection *Collection) []SeedFieldInfo {
 			if f.Max != nil {
 				info.Max = *f.Max
 			}
+			info.OnlyInt = f.OnlyInt
 		case *TextField:
 			info.Min = float64(f.Min)
 			info.Max = float64(f.Max)
 		case *SelectField:
 			info.Values = f.Values
 			info.MaxSelect = f.MaxSelect
+		case *DateField:
+			if !f.Min.IsZero() || !f.Max.IsZero() {
+				info.DateRange = &SeedDateRange{Min: f.Min, Max: f.Max}
+			}
 		}
 
 		fields = append(fields, info)
@@ -617,7 +1279,7 @@ RULES:
 3. DO NOT include "id", "created", or "updated" fields - they are auto-generated
 4. Match data types exactly:
    - text: strings appropriate to the field name (e.g., "title" → article titles, "description" → paragraphs)
-   - number: numbers within min/max constraints if provided
+   - number: numbers within min/max constraints if provided (integers if onlyInt is true)
    - bool: true or false
    - email: valid email addresses (use example.com domain)
    - url: valid URLs (use example.com domain)
@@ -671,477 +1333,1865 @@ Return a JSON object with a "records" array containing EXACTLY %d generated reco
 // HYBRID SEED DATA GENERATION
 // =====================================================
 
-// GenerateSeedDataHybrid generates seed data using the optimal strategy based on count.
-// For count <= HybridThreshold (20): Uses pure AI generation
-// For count > HybridThreshold: Uses AI archetypes + gofakeit multiplexing
-func GenerateSeedDataHybrid(app App, collection *Collection, count int, description string) ([]map[string]any, error) {
-	if count <= 0 {
-		return nil, fmt.Errorf("count must be greater than 0")
-	}
+// GenerateSeedData generates seed data for a collection using the optimal
+// strategy based on the requested count (see [GenerateSeedDataHybrid])
+// and applies the extra request options to the generated records.
+func GenerateSeedData(app App, collection *Collection, req GenerateSeedDataRequest) ([]map[string]any, error) {
+	var records []map[string]any
 
-	// For small counts, use pure AI (existing behavior)
-	if count <= HybridThreshold {
-		return GenerateSeedDataFromSchema(app, collection, count, description)
+	err := StreamSeedData(app, collection, req, req.Count, func(batch []map[string]any) error {
+		records = append(records, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// For larger counts, use hybrid approach
-	return generateSeedDataHybridInternal(app, collection, count, description)
+	return records, nil
 }
 
-// generateSeedDataHybridInternal implements the hybrid AI + gofakeit approach
-func generateSeedDataHybridInternal(app App, collection *Collection, count int, description string) ([]map[string]any, error) {
-	// Extract field information
-	fields := extractSeedFieldsInfo(collection)
-	if len(fields) == 0 {
-		return nil, fmt.Errorf("collection has no fields suitable for seed data generation")
+// DefaultSeedStreamBatchSize is the default number of records per batch of [StreamSeedData].
+const DefaultSeedStreamBatchSize = 500
+
+// StreamSeedData is the same as [GenerateSeedData] but passes the generated
+// records to fn in batches of up to batchSize records (default to [DefaultSeedStreamBatchSize])
+// instead of returning all of them at once.
+//
+// The hybrid generation records are produced lazily one batch at a time so that
+// the peak memory usage is a single batch rather than the whole set
+// (only the already generated email/url values and the time series dates
+// are kept for the entire run).
+//
+// The generation stops on the first fn error, returning it as it is.
+func StreamSeedData(app App, collection *Collection, req GenerateSeedDataRequest, batchSize int, fn func(batch []map[string]any) error) error {
+	if err := validateSeedDataRequest(app, collection, req); err != nil {
+		return err
 	}
 
-	// Compute schema hash for cache validation
-	schemaHash := computeSchemaHash(fields)
+	if req.Count <= 0 && req.RecordsPerArchetype <= 0 && req.Tree == nil {
+		return fmt.Errorf("count must be greater than 0")
+	}
 
-	// Try to get cached archetypes
-	var archetypes []map[string]any
-	cached, found := globalArchetypeCache.Get(collection.Id, schemaHash)
+	if batchSize <= 0 {
+		batchSize = DefaultSeedStreamBatchSize
+	}
 
-	if found {
-		archetypes = cached.Archetypes
-	} else {
-		// Generate new archetypes using AI
+	localRand := newSeedRand(req.Seed)
+	fields := extractSeedFieldsInfo(collection)
+	applySeedDateRangeOptions(fields, req.DateRanges)
+	applySeedSelectWeightOptions(fields, req.SelectWeights)
+
+	var relations []seedRelation
+	if req.PopulateRelations {
+		var skipped []string
 		var err error
-		archetypes, err = generateArchetypes(app, collection, fields, description)
+		relations, skipped, err = loadSeedRelations(app, collection, req, MaxSeedRelationCandidates)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate archetypes: %w", err)
+			return fmt.Errorf("failed to load the seed relations: %w", err)
+		}
+		if len(skipped) > 0 {
+			app.Logger().Warn(
+				"Skipped seed relation fields with empty referenced collection",
+				"collection", collection.Name,
+				"fields", skipped,
+			)
 		}
+	}
 
-		// Cache the archetypes
-		globalArchetypeCache.Set(collection.Id, &CachedArchetypes{
-			SchemaHash: schemaHash,
-			Archetypes: archetypes,
-			Fields:     fields,
-			CreatedAt:  time.Now(),
-		})
+	locale := findSeedLocale(req.Locale)
+	description := locale.describe(req.Description)
+
+	// deduplicate the unique fields values (including toward the existing records)
+	unique := newSeedUniqueValues(fields)
+	if err := unique.loadExisting(app, collection); err != nil {
+		return fmt.Errorf("failed to load the existing unique values: %w", err)
 	}
 
-	// Multiply archetypes using gofakeit
-	records := multiplyArchetypes(archetypes, fields, count)
+	// for small counts, use pure AI (the records are already in memory)
+	if req.Count <= HybridThreshold && req.RecordsPerArchetype <= 0 && req.Tree == nil {
+		records, err := generateSeedDataFromSchema(app, collection, req.Count, description)
+		if err != nil {
+			return err
+		}
 
-	return records, nil
-}
+		unique.apply(records)
 
-// generateArchetypes uses AI to generate diverse archetype records
-func generateArchetypes(app App, collection *Collection, fields []SeedFieldInfo, description string) ([]map[string]any, error) {
-	settings := app.Settings()
+		if len(req.SelectWeights) > 0 {
+			applySeedSelectWeights(records, fields, localRand)
+		}
 
-	if !settings.AI.Enabled {
-		return nil, fmt.Errorf("AI features are not enabled")
-	}
+		var dates []time.Time
+		if req.TimeSeries != nil {
+			dates = sampleSeedTimeSeriesDates(*req.TimeSeries, len(records), localRand)
+		}
 
-	if settings.AI.APIKey == "" {
-		return nil, fmt.Errorf("AI API key is not configured")
-	}
+		for offset := 0; offset < len(records); offset += batchSize {
+			end := min(offset+batchSize, len(records))
+			batch := records[offset:end]
 
-	// Build specialized prompt for archetypes
-	systemPrompt := buildArchetypeSystemPrompt()
-	userPrompt := buildArchetypeUserPrompt(collection.Name, fields, description)
+			finalizeSeedRecords(app, batch, collection, fields, relations, req, seedDatesRange(dates, offset, end), localRand)
+
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
 
-	// Prepare OpenAI API request
-	openAIReq := map[string]interface{}{
-		"model": settings.AI.Model,
-		"messages": []map[string]string{
-			{"role": "system", "content": systemPrompt},
-			{"role": "user", "content": userPrompt},
-		},
-		"temperature": 0.8, // Higher temperature for more diverse archetypes
-		"response_format": map[string]string{
-			"type": "json_object",
-		},
+		return nil
 	}
 
-	reqBody, err := json.Marshal(openAIReq)
+	archetypes, err := loadSeedArchetypes(app, collection, fields, description, req.ArchetypeTemperature)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal OpenAI request: %w", err)
+		return err
 	}
 
-	httpReq, err := http.NewRequest("POST", openAIAPIURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	count := req.Count
+	selection := req.ArchetypeSelection
+	if req.RecordsPerArchetype > 0 {
+		count = len(archetypes) * req.RecordsPerArchetype
+		selection = ArchetypeSelectionRoundRobin
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", settings.AI.APIKey))
+	var treeIds []string
+	if req.Tree != nil {
+		count = req.Tree.Size()
+		treeIds = generateSeedTreeIds(count)
+	}
 
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call OpenAI API: %w", err)
+	var dates []time.Time
+	if req.TimeSeries != nil {
+		dates = sampleSeedTimeSeriesDates(*req.TimeSeries, count, localRand)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read OpenAI response: %w", err)
+	fieldTypes := seedFieldTypes(fields)
+
+	for offset := 0; offset < count; offset += batchSize {
+		end := min(offset+batchSize, count)
+
+		batch := multiplyArchetypesRange(archetypes, fieldTypes, unique, locale, offset, end-offset, localRand, selection)
+
+		finalizeSeedRecords(app, batch, collection, fields, relations, req, seedDatesRange(dates, offset, end), localRand)
+
+		if req.Tree != nil {
+			applySeedTree(batch, *req.Tree, treeIds, offset)
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
+	return nil
+}
+
+// validateSeedDataRequest validates the seed data request options against the collection schema.
+func validateSeedDataRequest(app App, collection *Collection, req GenerateSeedDataRequest) error {
+	if err := checkAITextLength("description", req.Description, MaxSeedDescriptionLength); err != nil {
+		return err
 	}
 
-	var openAIResp struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+	if err := validateSeedFixedFields(app, collection, req.FixedFields); err != nil {
+		return err
 	}
 
-	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	if err := validateSeedTimeSeries(collection, req.TimeSeries); err != nil {
+		return err
 	}
 
-	if len(openAIResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+	if err := validateSeedTree(collection, req.Tree, req.FixedFields); err != nil {
+		return err
 	}
 
-	var result struct {
-		Archetypes []map[string]any `json:"archetypes"`
+	if err := validateSeedDateRanges(collection, req.DateRanges); err != nil {
+		return err
 	}
-	if err := json.Unmarshal([]byte(openAIResp.Choices[0].Message.Content), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse archetypes JSON: %w", err)
+
+	if err := validateSeedNumberDistributions(collection, req.NumberDistributions); err != nil {
+		return err
 	}
 
-	if len(result.Archetypes) == 0 {
-		return nil, fmt.Errorf("AI returned no archetypes")
+	if err := validateSeedSelectCountDistributions(collection, req.SelectCountDistributions); err != nil {
+		return err
 	}
 
-	return result.Archetypes, nil
-}
+	if err := validateSeedSelectDistributions(collection, req.Distributions); err != nil {
+		return err
+	}
 
-// buildArchetypeSystemPrompt creates the system prompt for archetype generation
-func buildArchetypeSystemPrompt() string {
-	return `You are a data archetype generator for PocketBase. Generate DIVERSE archetype records that represent different "personas" or "categories" of data.
+	if err := validateSeedSelectWeights(collection, req.SelectWeights, req.Distributions); err != nil {
+		return err
+	}
 
-RULES:
-1. Return a JSON object with an "archetypes" array containing exactly 12 DIVERSE records
-2. Each archetype should represent a DISTINCT category, persona, or style
-3. For text fields that appear to be creative content (bio, description, content, summary, etc.):
-   - Write full, realistic, varied content in different styles and tones
-   - Some formal, some casual, some brief, some detailed
-4. For name-like fields (name, title, username, etc.):
-   - Provide placeholder patterns like "{{NAME}}", "{{TITLE}}", "{{USERNAME}}"
-   - These will be replaced with generated values
-5. For email fields: use "{{EMAIL}}" as placeholder
-6. For URL fields: use "{{URL}}" as placeholder
-7. DO NOT include "id", "created", or "updated" fields
-8. Match data types exactly:
-   - text: strings (use {{PLACEHOLDER}} for unique fields, real content for creative fields)
-   - number: numbers within constraints
-   - bool: true or false
-   - email: "{{EMAIL}}"
-   - url: "{{URL}}"
-   - editor: HTML content with basic formatting
-   - date: ISO 8601 datetime strings
-   - select: values ONLY from the provided "values" array
-   - json: appropriate JSON objects
+	if err := validateSeedConstraints(collection, req.Constraints, req.FixedFields); err != nil {
+		return err
+	}
 
-DIVERSITY IS KEY:
-- Vary the tone: professional, casual, enthusiastic, minimalist
-- Vary the length: some brief, some detailed
-- Vary the content: different topics, perspectives, styles
-- Each archetype should feel like a different "person" wrote it
+	if req.ArchetypeTemperature != nil {
+		if t := *req.ArchetypeTemperature; t < 0 || t > MaxArchetypeTemperature {
+			return validation.Errors{"archetypeTemperature": validation.NewError(
+				"validation_invalid_temperature",
+				fmt.Sprintf("Must be between 0 and %d.", MaxArchetypeTemperature),
+			)}
+		}
+	}
 
-OUTPUT FORMAT:
-{
-  "archetypes": [
-    { "field1": "value or {{PLACEHOLDER}}", ... },
-    ...12 total archetypes...
-  ]
-}`
-}
+	switch req.ArchetypeSelection {
+	case "", ArchetypeSelectionRandom, ArchetypeSelectionRoundRobin:
+	default:
+		return validation.Errors{"archetypeSelection": validation.NewError(
+			"validation_invalid_archetype_selection",
+			fmt.Sprintf("Must be %q or %q.", ArchetypeSelectionRandom, ArchetypeSelectionRoundRobin),
+		)}
+	}
 
-// buildArchetypeUserPrompt creates the user prompt for archetype generation
-func buildArchetypeUserPrompt(collectionName string, fields []SeedFieldInfo, description string) string {
-	fieldsJSON, _ := json.MarshalIndent(fields, "", "  ")
+	if req.RecordsPerArchetype < 0 {
+		return validation.Errors{"recordsPerArchetype": validation.NewError(
+			"validation_invalid_records_per_archetype",
+			"Must be a positive number.",
+		)}
+	}
 
-	prompt := fmt.Sprintf(`Generate 12 DIVERSE archetype records for a "%s" collection.
+	if req.RecordsPerArchetype > 0 && req.Tree != nil {
+		return validation.Errors{"recordsPerArchetype": validation.NewError(
+			"validation_records_per_archetype_tree",
+			"Cannot be combined with the tree generation.",
+		)}
+	}
 
-These archetypes will be used as templates to generate thousands of records, so make them:
-- DIVERSE in style, tone, and content
-- REPRESENTATIVE of different user personas or data categories
-- HIGH QUALITY with realistic, contextual content
+	if req.RecordsPerArchetype > 0 && req.ArchetypeSelection == ArchetypeSelectionRandom {
+		return validation.Errors{"recordsPerArchetype": validation.NewError(
+			"validation_records_per_archetype_random_selection",
+			fmt.Sprintf("Cannot be combined with the %q archetypes selection.", ArchetypeSelectionRandom),
+		)}
+	}
 
-Field Schema:
-%s`, collectionName, string(fieldsJSON))
+	return nil
+}
 
-	if description != "" {
-		prompt += fmt.Sprintf(`
+// finalizeSeedRecords applies the request distributions, time series dates,
+// relations, fixed fields and constraints to the generated records
+// (and regenerates the invalid email and url values).
+func finalizeSeedRecords(app App, records []map[string]any, collection *Collection, fields []SeedFieldInfo, relations []seedRelation, req GenerateSeedDataRequest, dates []time.Time, localRand *rand.Rand) {
+	applySeedDateRanges(records, fields, localRand)
 
-Context/Description: %s
+	applySeedFormatValidation(app, records, collection, localRand)
 
-Use this context to make archetypes more relevant and realistic.`, description)
+	if len(req.NumberDistributions) > 0 {
+		applySeedNumberDistributions(records, fields, req.NumberDistributions, localRand)
 	}
 
-	prompt += `
-
-Remember:
-- Use {{NAME}}, {{EMAIL}}, {{URL}}, {{USERNAME}}, {{TITLE}} placeholders for fields that should be unique per record
-- Write actual content for creative fields (bio, description, content, etc.)
-- Return exactly 12 diverse archetypes in the "archetypes" array`
-
-	return prompt
-}
+	if len(req.SelectCountDistributions) > 0 || len(req.Distributions) > 0 {
+		applySeedSelectDistributions(records, fields, req.SelectCountDistributions, req.Distributions, localRand)
+	}
 
-// multiplyArchetypes generates records by mutating archetypes with gofakeit
-// Uses parallel workers for large counts to maximize throughput
-func multiplyArchetypes(archetypes []map[string]any, fields []SeedFieldInfo, count int) []map[string]any {
-	// Build a field type map for quick lookup
-	fieldTypes := make(map[string]SeedFieldInfo)
-	for _, f := range fields {
-		fieldTypes[f.Name] = f
+	if req.TimeSeries != nil {
+		setSeedTimeSeriesDates(records, req.TimeSeries.Field, dates)
 	}
 
-	// For small counts, use simple sequential generation
-	if count <= 1000 {
-		records := make([]map[string]any, 0, count)
-		for i := 0; i < count; i++ {
-			archetype := archetypes[rand.Intn(len(archetypes))]
-			record := mutateArchetype(archetype, fieldTypes)
-			records = append(records, record)
-		}
-		return records
+	if len(relations) > 0 {
+		applySeedRelations(records, relations, localRand)
 	}
 
-	// For large counts, use parallel generation with worker pool
-	return multiplyArchetypesParallel(archetypes, fieldTypes, count)
+	applySeedFixedFields(records, req.FixedFields)
+
+	if len(req.Constraints) > 0 {
+		applySeedConstraints(records, collection, req.Constraints)
+	}
 }
 
-// multiplyArchetypesParallel generates records using multiple goroutines
-func multiplyArchetypesParallel(archetypes []map[string]any, fieldTypes map[string]SeedFieldInfo, count int) []map[string]any {
-	// Determine number of workers (use available CPUs, cap at 8)
-	numWorkers := 8
-	
-	// Pre-allocate result slice
-	records := make([]map[string]any, count)
-	
-	// Calculate records per worker
-	chunkSize := count / numWorkers
-	remainder := count % numWorkers
+// maxSeedFormatAttempts is the max number of attempts to regenerate
+// a single invalid seed email or url value.
+const maxSeedFormatAttempts = 10
 
-	var wg sync.WaitGroup
-	wg.Add(numWorkers)
+// applySeedFormatValidation regenerates the email and url values of the records
+// that would fail the field validation on insert (ex. unusual TLDs or not allowed domains).
+//
+// The values that couldn't be regenerated within [maxSeedFormatAttempts] are left as they are.
+func applySeedFormatValidation(app App, records []map[string]any, collection *Collection, localRand *rand.Rand) {
+	// reused only to run the field validators
+	record := NewRecord(collection)
 
-	startIdx := 0
-	for w := 0; w < numWorkers; w++ {
-		// Distribute remainder among first workers
-		workerCount := chunkSize
-		if w < remainder {
-			workerCount++
-		}
-		
-		endIdx := startIdx + workerCount
-		
-		// Launch worker goroutine
-		go func(start, end int) {
-			defer wg.Done()
-			
-			// Each worker has its own random source for thread safety
-			localRand := rand.New(rand.NewSource(time.Now().UnixNano() + int64(start)))
-			
-			for i := start; i < end; i++ {
-				// Pick a random archetype
-				archetype := archetypes[localRand.Intn(len(archetypes))]
-				// Generate record (mutateArchetype is thread-safe with local rand)
-				records[i] = mutateArchetypeWithRand(archetype, fieldTypes, localRand)
-			}
-		}(startIdx, endIdx)
-		
-		startIdx = endIdx
+	validate := func(field Field, value any) error {
+		record.Set(field.GetName(), value)
+		return field.ValidateValue(context.Background(), app, record)
 	}
 
-	wg.Wait()
-	return records
-}
-
-// mutateArchetypeWithRand is a thread-safe version using a local random source
-func mutateArchetypeWithRand(archetype map[string]any, fieldTypes map[string]SeedFieldInfo, localRand *rand.Rand) map[string]any {
-	record := make(map[string]any)
+	for _, field := range collection.Fields {
+		switch field.(type) {
+		case *EmailField, *URLField:
+		default:
+			continue
+		}
 
-	for fieldName, value := range archetype {
-		fieldInfo, hasInfo := fieldTypes[fieldName]
+		name := field.GetName()
 
-		switch v := value.(type) {
-		case string:
-			record[fieldName] = mutateStringFieldWithRand(v, fieldName, fieldInfo, hasInfo, localRand)
-		case float64:
-			if hasInfo && fieldInfo.Type == FieldTypeNumber {
-				record[fieldName] = mutateNumberFieldWithRand(fieldInfo, localRand)
-			} else {
-				record[fieldName] = v
-			}
-		case bool:
-			if localRand.Float32() < 0.3 {
-				record[fieldName] = !v
-			} else {
-				record[fieldName] = v
+		for _, data := range records {
+			value, ok := data[name]
+			if !ok || validate(field, value) == nil {
+				continue
 			}
-		case []interface{}:
-			if hasInfo && fieldInfo.Type == FieldTypeSelect && len(fieldInfo.Values) > 0 {
-				record[fieldName] = mutateSelectFieldWithRand(fieldInfo, localRand)
-			} else {
-				record[fieldName] = v
+
+			for i := 0; i < maxSeedFormatAttempts; i++ {
+				candidate := generateSeedFormatValue(field, localRand)
+				if validate(field, candidate) == nil {
+					data[name] = candidate
+					break
+				}
 			}
-		default:
-			record[fieldName] = value
 		}
 	}
-
-	return record
 }
 
-// mutateStringFieldWithRand is thread-safe string mutation
-func mutateStringFieldWithRand(value, fieldName string, fieldInfo SeedFieldInfo, hasInfo bool, localRand *rand.Rand) string {
-	result := value
+// generateSeedFormatValue generates a new email or url value for the field
+// (within the field allowed domains if any).
+func generateSeedFormatValue(field Field, localRand *rand.Rand) string {
+	faker := seedFaker(localRand)
 
-	// Replace placeholders using gofakeit (which is thread-safe)
-	if strings.Contains(result, "{{NAME}}") {
-		result = strings.ReplaceAll(result, "{{NAME}}", gofakeit.Name())
-	}
-	if strings.Contains(result, "{{FIRSTNAME}}") {
-		result = strings.ReplaceAll(result, "{{FIRSTNAME}}", gofakeit.FirstName())
-	}
-	if strings.Contains(result, "{{LASTNAME}}") {
-		result = strings.ReplaceAll(result, "{{LASTNAME}}", gofakeit.LastName())
-	}
-	if strings.Contains(result, "{{EMAIL}}") {
-		result = strings.ReplaceAll(result, "{{EMAIL}}", gofakeit.Email())
+	domain := func(onlyDomains []string) string {
+		if len(onlyDomains) > 0 {
+			return randomSeedItem(onlyDomains, localRand)
+		}
+		return faker.DomainName()
 	}
-	if strings.Contains(result, "{{URL}}") {
-		result = strings.ReplaceAll(result, "{{URL}}", gofakeit.URL())
+
+	switch f := field.(type) {
+	case *EmailField:
+		return strings.ToLower(faker.Username()) + "@" + domain(f.OnlyDomains)
+	case *URLField:
+		return "https://" + domain(f.OnlyDomains)
 	}
-	if strings.Contains(result, "{{USERNAME}}") {
-		result = strings.ReplaceAll(result, "{{USERNAME}}", gofakeit.Username())
+
+	return ""
+}
+
+// seedDatesRange returns the [start, end) dates subslice (or nil if there are no dates).
+func seedDatesRange(dates []time.Time, start, end int) []time.Time {
+	if dates == nil {
+		return nil
 	}
-	if strings.Contains(result, "{{TITLE}}") {
-		result = strings.ReplaceAll(result, "{{TITLE}}", gofakeit.Sentence(localRand.Intn(5)+3))
+
+	return dates[start:end]
+}
+
+// Supported seed number distributions.
+const (
+	SeedNumberDistributionUniform     = "uniform"
+	SeedNumberDistributionNormal      = "normal"
+	SeedNumberDistributionExponential = "exponential"
+	SeedNumberDistributionLogNormal   = "lognormal"
+)
+
+// SeedNumberDistribution defines the values distribution of a seed number field.
+//
+// The generated values are clamped to the field min/max constraints (if any).
+type SeedNumberDistribution struct {
+	// Type is the distribution type (uniform, normal, exponential or lognormal).
+	Type string `json:"type"`
+
+	// Mean is the distribution mean for the normal and exponential distributions
+	// and the mean of the underlying normal distribution for the lognormal one.
+	Mean float64 `json:"mean,omitempty"`
+
+	// StdDev is the standard deviation for the normal distribution and the standard
+	// deviation of the underlying normal distribution for the lognormal one.
+	StdDev float64 `json:"stdDev,omitempty"`
+}
+
+// validateSeedNumberDistributions validates the number distributions against the collection schema.
+func validateSeedNumberDistributions(collection *Collection, distributions map[string]SeedNumberDistribution) error {
+	errs := validation.Errors{}
+
+	for name, dist := range distributions {
+		field := collection.Fields.GetByName(name)
+		if field == nil {
+			errs[name] = validation.NewError("validation_unknown_field", "Unknown collection field.")
+			continue
+		}
+
+		if field.Type() != FieldTypeNumber {
+			errs[name] = validation.NewError("validation_invalid_field_type", "The distribution field must be a number field.")
+			continue
+		}
+
+		switch dist.Type {
+		case SeedNumberDistributionUniform:
+		case SeedNumberDistributionNormal, SeedNumberDistributionLogNormal:
+			if dist.StdDev <= 0 {
+				errs[name] = validation.NewError("validation_invalid_std_dev", "The stdDev must be a positive number.")
+			}
+		case SeedNumberDistributionExponential:
+			if dist.Mean <= 0 {
+				errs[name] = validation.NewError("validation_invalid_mean", "The mean must be a positive number.")
+			}
+		default:
+			errs[name] = validation.NewError("validation_invalid_distribution", "Must be uniform, normal, exponential or lognormal.")
+		}
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"numberDistributions": errs}
+	}
+
+	return nil
+}
+
+// applySeedNumberDistributions replaces the values of the distribution
+// number fields of the records with samples of their distribution.
+func applySeedNumberDistributions(records []map[string]any, fields []SeedFieldInfo, distributions map[string]SeedNumberDistribution, localRand *rand.Rand) {
+	for _, fieldInfo := range fields {
+		dist, ok := distributions[fieldInfo.Name]
+		if !ok {
+			continue
+		}
+		fieldInfo.Distribution = &dist
+
+		for _, record := range records {
+			record[fieldInfo.Name] = mutateNumberFieldWithRand(fieldInfo, localRand)
+		}
+	}
+}
+
+// Supported seed select count distributions.
+const (
+	// SeedSelectCountDistributionUniform picks evenly any count between 1 and maxSelect.
+	SeedSelectCountDistributionUniform = "uniform"
+
+	// SeedSelectCountDistributionGeometric favors fewer selections, with each extra
+	// selection being less likely by the distribution Probability factor.
+	SeedSelectCountDistributionGeometric = "geometric"
+
+	// SeedSelectCountDistributionWeighted picks the count using the distribution
+	// Weights (the first weight is for 1 selection, the second for 2, etc.).
+	SeedSelectCountDistributionWeighted = "weighted"
+)
+
+// SeedSelectCountDistribution defines the distribution of the number
+// of selected values of a seed multi-select field.
+//
+// The sampled counts are always between 1 and the field maxSelect (or the number of allowed values).
+type SeedSelectCountDistribution struct {
+	// Type is the distribution type (uniform, geometric or weighted).
+	Type string `json:"type"`
+
+	// Probability is the geometric distribution success probability (0-1),
+	// aka. the chance to stop at each count (ex. 0.6 -> ~60% of the records have 1 selection).
+	Probability float64 `json:"probability,omitempty"`
+
+	// Weights are the relative weights of each count for the weighted distribution
+	// (ex. [5, 3, 1] -> 1 selection is 5 times more likely than 3 selections).
+	Weights []float64 `json:"weights,omitempty"`
+}
+
+// weights returns the relative weights of the counts between 1 and maxCount.
+func (dist SeedSelectCountDistribution) weights(maxCount int) []float64 {
+	weights := make([]float64, maxCount)
+
+	for i := range weights {
+		switch dist.Type {
+		case SeedSelectCountDistributionGeometric:
+			weights[i] = dist.Probability * math.Pow(1-dist.Probability, float64(i))
+		case SeedSelectCountDistributionWeighted:
+			if i < len(dist.Weights) {
+				weights[i] = dist.Weights[i]
+			}
+		default:
+			weights[i] = 1
+		}
+	}
+
+	return weights
+}
+
+// validateSeedSelectCountDistributions validates the select count distributions against the collection schema.
+func validateSeedSelectCountDistributions(collection *Collection, distributions map[string]SeedSelectCountDistribution) error {
+	errs := validation.Errors{}
+
+	for name, dist := range distributions {
+		field, ok := collection.Fields.GetByName(name).(*SelectField)
+		if !ok {
+			errs[name] = validation.NewError("validation_invalid_field", "The distribution field must be an existing select field.")
+			continue
+		}
+
+		if !field.IsMultiple() {
+			errs[name] = validation.NewError("validation_invalid_field_max_select", "The distribution field must be a multi-select field (maxSelect > 1).")
+			continue
+		}
+
+		switch dist.Type {
+		case SeedSelectCountDistributionUniform:
+		case SeedSelectCountDistributionGeometric:
+			if dist.Probability <= 0 || dist.Probability > 1 {
+				errs[name] = validation.NewError("validation_invalid_probability", "The probability must be a number between 0 (exclusive) and 1.")
+			}
+		case SeedSelectCountDistributionWeighted:
+			var sum float64
+			for _, w := range dist.Weights {
+				if w < 0 {
+					sum = -1
+					break
+				}
+				sum += w
+			}
+			if sum <= 0 {
+				errs[name] = validation.NewError("validation_invalid_weights", "The weights must be non-negative numbers with at least one positive.")
+			}
+		default:
+			errs[name] = validation.NewError("validation_invalid_distribution", "Must be uniform, geometric or weighted.")
+		}
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"selectCountDistributions": errs}
+	}
+
+	return nil
+}
+
+// seedDistributionSumTolerance is the allowed deviation from 1
+// of the sum of a seed select field target proportions.
+const seedDistributionSumTolerance = 0.01
+
+// validateSeedSelectDistributions validates the select values proportions against the collection schema.
+func validateSeedSelectDistributions(collection *Collection, distributions map[string]map[string]float64) error {
+	errs := validation.Errors{}
+
+	for name, proportions := range distributions {
+		field, ok := collection.Fields.GetByName(name).(*SelectField)
+		if !ok {
+			errs[name] = validation.NewError("validation_invalid_field", "The distribution field must be an existing select field.")
+			continue
+		}
+
+		var sum float64
+		for value, p := range proportions {
+			if !slices.Contains(field.Values, value) {
+				errs[name] = validation.NewError(
+					"validation_invalid_value",
+					fmt.Sprintf("Invalid value %q (must be one of the field values).", value),
+				)
+				break
+			}
+			if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+				errs[name] = validation.NewError("validation_invalid_proportion", "The proportions must be non-negative numbers.")
+				break
+			}
+			sum += p
+		}
+		if _, ok := errs[name]; ok {
+			continue
+		}
+
+		if math.Abs(sum-1) > seedDistributionSumTolerance {
+			errs[name] = validation.NewError(
+				"validation_invalid_proportions_sum",
+				fmt.Sprintf("The proportions must sum to 1 (got %v).", sum),
+			)
+		}
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"distributions": errs}
+	}
+
+	return nil
+}
+
+// validateSeedSelectWeights validates the select values weights against the collection schema.
+func validateSeedSelectWeights(collection *Collection, weights map[string]map[string]float64, distributions map[string]map[string]float64) error {
+	errs := validation.Errors{}
+
+	for name, fieldWeights := range weights {
+		field, ok := collection.Fields.GetByName(name).(*SelectField)
+		if !ok {
+			errs[name] = validation.NewError("validation_invalid_field", "The weights field must be an existing select field.")
+			continue
+		}
+
+		if _, ok := distributions[name]; ok {
+			errs[name] = validation.NewError("validation_weights_with_distribution", "Cannot be combined with a distribution for the same field.")
+			continue
+		}
+
+		for value, w := range fieldWeights {
+			if !slices.Contains(field.Values, value) {
+				errs[name] = validation.NewError(
+					"validation_invalid_value",
+					fmt.Sprintf("Invalid value %q (must be one of the field values).", value),
+				)
+				break
+			}
+			if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				errs[name] = validation.NewError("validation_invalid_weight", "The weights must be non-negative numbers.")
+				break
+			}
+		}
+		if _, ok := errs[name]; ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(field.Values, func(v string) bool { return seedSelectWeight(fieldWeights, v) > 0 }) {
+			errs[name] = validation.NewError("validation_invalid_weights", "At least one of the field values must have a positive weight.")
+		}
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"selectWeights": errs}
+	}
+
+	return nil
+}
+
+// seedSelectWeight returns the weight of the select value (default to 1 if not listed).
+func seedSelectWeight(weights map[string]float64, value string) float64 {
+	if w, ok := weights[value]; ok {
+		return w
+	}
+
+	return 1
+}
+
+// applySeedSelectWeightOptions sets the values weights of the
+// select fields info from the request select weights.
+func applySeedSelectWeightOptions(fields []SeedFieldInfo, weights map[string]map[string]float64) {
+	for i, fieldInfo := range fields {
+		fieldWeights, ok := weights[fieldInfo.Name]
+		if !ok || fieldInfo.Type != FieldTypeSelect {
+			continue
+		}
+
+		proportions := make(map[string]float64, len(fieldInfo.Values))
+		for _, v := range fieldInfo.Values {
+			proportions[v] = seedSelectWeight(fieldWeights, v)
+		}
+		fields[i].Proportions = proportions
+	}
+}
+
+// applySeedSelectWeights replaces the values of the weighted select fields
+// of the records with weighted random values (ex. for the pure AI generated records).
+func applySeedSelectWeights(records []map[string]any, fields []SeedFieldInfo, localRand *rand.Rand) {
+	for _, fieldInfo := range fields {
+		if fieldInfo.Type != FieldTypeSelect || len(fieldInfo.Proportions) == 0 {
+			continue
+		}
+
+		for _, record := range records {
+			record[fieldInfo.Name] = mutateSelectFieldWithRand(fieldInfo, localRand)
+		}
+	}
+}
+
+// applySeedSelectDistributions replaces the values of the distribution
+// select fields of the records with random values following the
+// field selections count distribution and/or values proportions.
+func applySeedSelectDistributions(
+	records []map[string]any,
+	fields []SeedFieldInfo,
+	countDistributions map[string]SeedSelectCountDistribution,
+	distributions map[string]map[string]float64,
+	localRand *rand.Rand,
+) {
+	for _, fieldInfo := range fields {
+		countDist, hasCount := countDistributions[fieldInfo.Name]
+		proportions, hasProportions := distributions[fieldInfo.Name]
+		if !hasCount && !hasProportions {
+			continue
+		}
+
+		if hasCount {
+			fieldInfo.SelectCountDistribution = &countDist
+		}
+		if hasProportions {
+			fieldInfo.Proportions = proportions
+		}
+
+		for _, record := range records {
+			record[fieldInfo.Name] = mutateSelectFieldWithRand(fieldInfo, localRand)
+		}
+	}
+}
+
+// Supported seed time series distributions.
+const (
+	// SeedDistributionUniform spreads the dates evenly over the range.
+	SeedDistributionUniform = "uniform"
+
+	// SeedDistributionIncreasing linearly increases the dates density toward the range end (aka. growth).
+	SeedDistributionIncreasing = "increasing"
+
+	// SeedDistributionSeasonal follows a weekly pattern with lower weekend volume.
+	SeedDistributionSeasonal = "seasonal"
+)
+
+// seedWeekendWeight is the relative volume of the weekend days for the seasonal distribution.
+const seedWeekendWeight = 0.4
+
+// SeedTimeSeries defines the time series generation options of a seed date field.
+type SeedTimeSeries struct {
+	// Field is the name of the date field to generate.
+	Field string `json:"field"`
+
+	// Start and End define the dates range.
+	Start types.DateTime `json:"start"`
+	End   types.DateTime `json:"end"`
+
+	// Distribution is the dates distribution shape over the range
+	// (uniform, increasing or seasonal; default to uniform).
+	Distribution string `json:"distribution,omitempty"`
+}
+
+// validateSeedTimeSeries validates the time series options against the collection schema.
+func validateSeedTimeSeries(collection *Collection, ts *SeedTimeSeries) error {
+	if ts == nil {
+		return nil
+	}
+
+	errs := validation.Errors{}
+
+	if ts.Field == "" {
+		errs["field"] = validation.ErrRequired
+	} else if field := collection.Fields.GetByName(ts.Field); field == nil {
+		errs["field"] = validation.NewError("validation_unknown_field", "Unknown collection field.")
+	} else if field.Type() != FieldTypeDate {
+		errs["field"] = validation.NewError("validation_invalid_field_type", "The time series field must be a date field.")
+	}
+
+	if ts.Start.IsZero() {
+		errs["start"] = validation.ErrRequired
+	}
+
+	if ts.End.IsZero() {
+		errs["end"] = validation.ErrRequired
+	} else if !ts.Start.IsZero() && !ts.End.Time().After(ts.Start.Time()) {
+		errs["end"] = validation.NewError("validation_invalid_range", "The end date must be after the start date.")
+	}
+
+	switch ts.Distribution {
+	case "", SeedDistributionUniform, SeedDistributionIncreasing, SeedDistributionSeasonal:
+	default:
+		errs["distribution"] = validation.NewError("validation_invalid_distribution", "Must be uniform, increasing or seasonal.")
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"timeSeries": errs}
+	}
+
+	return nil
+}
+
+// applySeedTimeSeries sets the time series field of the records to an
+// ascending sequence of dates following the time series distribution.
+func applySeedTimeSeries(records []map[string]any, ts SeedTimeSeries, localRand *rand.Rand) {
+	setSeedTimeSeriesDates(records, ts.Field, sampleSeedTimeSeriesDates(ts, len(records), localRand))
+}
+
+// sampleSeedTimeSeriesDates returns count ascending dates following the time series distribution.
+func sampleSeedTimeSeriesDates(ts SeedTimeSeries, count int, localRand *rand.Rand) []time.Time {
+	start := ts.Start.Time()
+	span := float64(ts.End.Time().Sub(start))
+
+	dates := make([]time.Time, count)
+	for i := range dates {
+		dates[i] = start.Add(time.Duration(span * seedDistributionSample(ts, start, span, localRand)))
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+
+	return dates
+}
+
+// setSeedTimeSeriesDates sets the field of each record to the date with the same index.
+func setSeedTimeSeriesDates(records []map[string]any, field string, dates []time.Time) {
+	for i, record := range records {
+		if i >= len(dates) {
+			break
+		}
+
+		dt, _ := types.ParseDateTime(dates[i])
+		record[field] = dt.String()
+	}
+}
+
+// SeedDateRange defines the values range of a seed date field.
+//
+// A zero Min defaults to 2 years before Max and a zero Max
+// defaults to the current date (or 2 years after Min if Min is in the future).
+type SeedDateRange struct {
+	Min types.DateTime `json:"min"`
+	Max types.DateTime `json:"max"`
+}
+
+// contains checks whether the date is within the range bounds.
+func (r SeedDateRange) contains(date types.DateTime) bool {
+	if !r.Min.IsZero() && date.Before(r.Min) {
+		return false
+	}
+
+	if !r.Max.IsZero() && date.After(r.Max) {
+		return false
+	}
+
+	return true
+}
+
+// intersect returns the range overlapping both r and other.
+//
+// The returned range could be empty (aka. Max before Min).
+func (r SeedDateRange) intersect(other SeedDateRange) SeedDateRange {
+	result := r
+
+	if !other.Min.IsZero() && (result.Min.IsZero() || other.Min.After(result.Min)) {
+		result.Min = other.Min
+	}
+
+	if !other.Max.IsZero() && (result.Max.IsZero() || other.Max.Before(result.Max)) {
+		result.Max = other.Max
+	}
+
+	return result
+}
+
+// validateSeedDateRanges validates the date ranges against the collection schema.
+func validateSeedDateRanges(collection *Collection, ranges map[string]SeedDateRange) error {
+	errs := validation.Errors{}
+
+	for name, r := range ranges {
+		field, ok := collection.Fields.GetByName(name).(*DateField)
+		if !ok {
+			if collection.Fields.GetByName(name) == nil {
+				errs[name] = validation.NewError("validation_unknown_field", "Unknown collection field.")
+			} else {
+				errs[name] = validation.NewError("validation_invalid_field_type", "The date range field must be a date field.")
+			}
+			continue
+		}
+
+		if r.Min.IsZero() && r.Max.IsZero() {
+			errs[name] = validation.NewError("validation_required_date_range", "At least one of min or max is required.")
+			continue
+		}
+
+		if !r.Min.IsZero() && !r.Max.IsZero() && r.Max.Before(r.Min) {
+			errs[name] = validation.NewError("validation_invalid_range", "The max date must not be before the min date.")
+			continue
+		}
+
+		combined := r.intersect(SeedDateRange{Min: field.Min, Max: field.Max})
+		if !combined.Min.IsZero() && !combined.Max.IsZero() && combined.Max.Before(combined.Min) {
+			errs[name] = validation.NewError("validation_date_range_out_of_field_bounds", "The date range must overlap the field min/max constraints.")
+		}
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"dateRanges": errs}
+	}
+
+	return nil
+}
+
+// applySeedDateRangeOptions combines the date ranges of the fields
+// info with the request ones.
+func applySeedDateRangeOptions(fields []SeedFieldInfo, ranges map[string]SeedDateRange) {
+	for i, fieldInfo := range fields {
+		r, ok := ranges[fieldInfo.Name]
+		if !ok {
+			continue
+		}
+
+		if fieldInfo.DateRange != nil {
+			r = fieldInfo.DateRange.intersect(r)
+		}
+		fields[i].DateRange = &r
+	}
+}
+
+// applySeedDateRanges replaces the missing or out of range date values
+// of the ranged date fields with random dates within the field range
+// (ex. for the pure AI generated records).
+func applySeedDateRanges(records []map[string]any, fields []SeedFieldInfo, localRand *rand.Rand) {
+	for _, fieldInfo := range fields {
+		if fieldInfo.Type != FieldTypeDate || fieldInfo.DateRange == nil {
+			continue
+		}
+
+		for _, record := range records {
+			value, ok := record[fieldInfo.Name]
+			if !ok {
+				continue
+			}
+
+			date, err := types.ParseDateTime(value)
+			if err != nil || date.IsZero() || !fieldInfo.DateRange.contains(date) {
+				record[fieldInfo.Name] = mutateDateFieldWithRand(fieldInfo, localRand)
+			}
+		}
+	}
+}
+
+// seedDistributionSample returns a random range offset (0..1) following the time series distribution.
+func seedDistributionSample(ts SeedTimeSeries, start time.Time, span float64, localRand *rand.Rand) float64 {
+	switch ts.Distribution {
+	case SeedDistributionIncreasing:
+		// inverse CDF of a linearly increasing density
+		return math.Sqrt(localRand.Float64())
+	case SeedDistributionSeasonal:
+		// rejection sampling with lower weekend acceptance
+		for {
+			offset := localRand.Float64()
+			day := start.Add(time.Duration(span * offset)).Weekday()
+			if day != time.Saturday && day != time.Sunday {
+				return offset
+			}
+			if localRand.Float64() < seedWeekendWeight {
+				return offset
+			}
+		}
+	default:
+		return localRand.Float64()
+	}
+}
+
+// Supported seed constraint operators.
+const (
+	SeedConstraintGt  = ">"
+	SeedConstraintGte = ">="
+	SeedConstraintLt  = "<"
+	SeedConstraintLte = "<="
+)
+
+// SeedConstraint defines a cross-field ordering constraint between
+// two date or two number fields (ex. {Field: "end_date", Op: ">", Other: "start_date"}).
+//
+// The violating records are fixed by adjusting only the Field value.
+type SeedConstraint struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Other string `json:"other"`
+}
+
+// validateSeedConstraints validates the seed constraints against the collection schema.
+func validateSeedConstraints(collection *Collection, constraints []SeedConstraint, fixedFields map[string]any) error {
+	errs := validation.Errors{}
+
+	for i, c := range constraints {
+		key := strconv.Itoa(i)
+
+		field := collection.Fields.GetByName(c.Field)
+		other := collection.Fields.GetByName(c.Other)
+
+		switch {
+		case field == nil:
+			errs[key] = validation.NewError("validation_unknown_field", fmt.Sprintf("Unknown collection field %q.", c.Field))
+		case other == nil:
+			errs[key] = validation.NewError("validation_unknown_field", fmt.Sprintf("Unknown collection field %q.", c.Other))
+		case c.Field == c.Other:
+			errs[key] = validation.NewError("validation_same_fields", "The constraint fields must be different.")
+		case field.Type() != other.Type() || (field.Type() != FieldTypeDate && field.Type() != FieldTypeNumber):
+			errs[key] = validation.NewError("validation_invalid_field_type", "The constraint fields must be both date or both number fields.")
+		case !slices.Contains([]string{SeedConstraintGt, SeedConstraintGte, SeedConstraintLt, SeedConstraintLte}, c.Op):
+			errs[key] = validation.NewError("validation_invalid_operator", "Must be >, >=, < or <=.")
+		default:
+			if _, ok := fixedFields[c.Field]; ok {
+				errs[key] = validation.NewError("validation_fixed_field", "The constrained field cannot be a fixed field.")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"constraints": errs}
+	}
+
+	return nil
+}
+
+// applySeedConstraints adjusts the records that violate the seed constraints
+// (the constraints are applied in order in a single pass).
+//
+// The violating Field value is mirrored around the Other value (aka. keeping the same distance)
+// or, if both values are equal and the constraint is strict, shifted with 1 unit (1 day for the dates).
+// The adjusted value is clamped to the field min/max constraints (if any).
+//
+// Records with missing or invalid values (or that cannot be fixed within the field limits) are left untouched.
+func applySeedConstraints(records []map[string]any, collection *Collection, constraints []SeedConstraint) {
+	for _, c := range constraints {
+		field := collection.Fields.GetByName(c.Field)
+		isDate := field.Type() == FieldTypeDate
+		lo, hi := seedConstraintBounds(field)
+
+		for _, record := range records {
+			a, okA := seedConstraintValue(record[c.Field], isDate)
+			b, okB := seedConstraintValue(record[c.Other], isDate)
+			if !okA || !okB {
+				continue
+			}
+
+			fixed, changed := fixSeedConstraintValue(a, b, c.Op, isDate)
+			if !changed {
+				continue
+			}
+
+			fixed = math.Min(math.Max(fixed, lo), hi)
+			if _, changed := fixSeedConstraintValue(fixed, b, c.Op, isDate); changed {
+				continue // not satisfiable within the field limits
+			}
+
+			if isDate {
+				dt, _ := types.ParseDateTime(time.Unix(0, int64(fixed)).UTC())
+				record[c.Field] = dt.String()
+			} else {
+				record[c.Field] = fixed
+			}
+		}
+	}
+}
+
+// seedConstraintBounds returns the min/max limits of a seed constraint field
+// (in the same units as [seedConstraintValue]).
+func seedConstraintBounds(field Field) (float64, float64) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+
+	switch f := field.(type) {
+	case *NumberField:
+		if f.Min != nil {
+			lo = *f.Min
+		}
+		if f.Max != nil {
+			hi = *f.Max
+		}
+	case *DateField:
+		if !f.Min.IsZero() {
+			lo = float64(f.Min.Time().UnixNano())
+		}
+		if !f.Max.IsZero() {
+			hi = float64(f.Max.Time().UnixNano())
+		}
+	}
+
+	return lo, hi
+}
+
+// seedConstraintValue returns the comparable numeric value of a seed
+// record value (unix nanoseconds for the dates).
+func seedConstraintValue(value any, isDate bool) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+
+	if isDate {
+		dt, err := types.ParseDateTime(value)
+		if err != nil || dt.IsZero() {
+			return 0, false
+		}
+		return float64(dt.Time().UnixNano()), true
+	}
+
+	n, err := cast.ToFloat64E(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// fixSeedConstraintValue returns the adjusted a value so that "a op b" holds
+// and whether an adjustment was necessary.
+func fixSeedConstraintValue(a, b float64, op string, isDate bool) (float64, bool) {
+	unit := 1.0
+	if isDate {
+		unit = float64(24 * time.Hour)
+	}
+
+	switch op {
+	case SeedConstraintGt, SeedConstraintGte:
+		if a > b || (op == SeedConstraintGte && a == b) {
+			return a, false
+		}
+		if a == b {
+			return b + unit, true
+		}
+		return 2*b - a, true
+	default:
+		if a < b || (op == SeedConstraintLte && a == b) {
+			return a, false
+		}
+		if a == b {
+			return b - unit, true
+		}
+		return 2*b - a, true
 	}
-	if strings.Contains(result, "{{COMPANY}}") {
-		result = strings.ReplaceAll(result, "{{COMPANY}}", gofakeit.Company())
+}
+
+// validateSeedFixedFields validates the fixed seed values against the collection schema.
+func validateSeedFixedFields(app App, collection *Collection, fixedFields map[string]any) error {
+	if len(fixedFields) == 0 {
+		return nil
+	}
+
+	record := NewRecord(collection)
+
+	fieldsErrors := validation.Errors{}
+	for name, value := range fixedFields {
+		field := collection.Fields.GetByName(name)
+		if field == nil {
+			fieldsErrors[name] = validation.NewError("validation_unknown_field", "Unknown collection field.")
+			continue
+		}
+
+		if name == FieldNameId || field.Type() == FieldTypeAutodate {
+			fieldsErrors[name] = validation.NewError("validation_auto_generated_field", "Auto generated fields cannot be fixed.")
+			continue
+		}
+
+		record.Set(name, value)
+		if err := field.ValidateValue(context.Background(), app, record); err != nil {
+			fieldsErrors[name] = err
+		}
+	}
+
+	if len(fieldsErrors) > 0 {
+		return validation.Errors{"fixedFields": fieldsErrors}
+	}
+
+	return nil
+}
+
+// applySeedFixedFields sets the fixed values on every record (overriding the generated ones).
+func applySeedFixedFields(records []map[string]any, fixedFields map[string]any) {
+	if len(fixedFields) == 0 {
+		return
+	}
+
+	for _, record := range records {
+		for name, value := range fixedFields {
+			record[name] = value
+		}
+	}
+}
+
+// GenerateSeedDataHybrid generates seed data using the optimal strategy based on count.
+// For count <= HybridThreshold (20): Uses pure AI generation
+// For count > HybridThreshold: Uses AI archetypes + gofakeit multiplexing
+func GenerateSeedDataHybrid(app App, collection *Collection, count int, description string) ([]map[string]any, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be greater than 0")
+	}
+
+	if err := checkAITextLength("description", description, MaxSeedDescriptionLength); err != nil {
+		return nil, err
+	}
+
+	// For small counts, use pure AI (existing behavior)
+	if count <= HybridThreshold {
+		return GenerateSeedDataFromSchema(app, collection, count, description)
+	}
+
+	// For larger counts, use hybrid approach
+	return generateSeedDataHybridInternal(app, collection, count, description)
+}
+
+// generateSeedDataHybridInternal implements the hybrid AI + gofakeit approach.
+//
+// For the generation options (archetypes temperature and selection,
+// locale, random seed, etc.) use [GenerateSeedData] instead.
+func generateSeedDataHybridInternal(app App, collection *Collection, count int, description string) ([]map[string]any, error) {
+	// Extract field information
+	fields := extractSeedFieldsInfo(collection)
+
+	archetypes, err := loadSeedArchetypes(app, collection, fields, description, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multiply archetypes using gofakeit
+	return multiplyArchetypes(archetypes, fields, count), nil
+}
+
+// newSeedRand creates a new seed data random source from the optional seed
+// (or from the current time if seed is nil).
+//
+// All seed values (including the gofakeit ones and the parallel workers
+// random sources) are derived from it so that a fixed seed makes the generation reproducible.
+func newSeedRand(seed *int64) *rand.Rand {
+	if seed != nil {
+		return rand.New(rand.NewSource(*seed))
+	}
+
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// loadSeedArchetypes returns the cached (or newly generated) archetypes of the collection.
+//
+// If temperature is set, the archetypes are always regenerated with it
+// (and the cached ones are left untouched).
+func loadSeedArchetypes(app App, collection *Collection, fields []SeedFieldInfo, description string, temperature *float64) ([]map[string]any, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("collection has no fields suitable for seed data generation")
+	}
+
+	if temperature != nil {
+		archetypes, err := generateArchetypes(app, collection, fields, description, *temperature)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate archetypes: %w", err)
+		}
+		return archetypes, nil
+	}
+
+	return getOrGenerateArchetypes(app, collection, fields, description)
+}
+
+// getOrGenerateArchetypes returns the cached archetypes of the collection
+// or generates (and caches) new ones if missing or the schema has changed.
+func getOrGenerateArchetypes(app App, collection *Collection, fields []SeedFieldInfo, description string) ([]map[string]any, error) {
+	// Compute schema hash for cache validation
+	schemaHash := computeSchemaHash(fields)
+
+	// Try to get cached archetypes
+	cached, found := globalArchetypeCache.Get(collection.Id, schemaHash)
+	if found {
+		return cached.Archetypes, nil
+	}
+
+	// Generate new archetypes using AI
+	archetypes, err := generateArchetypes(app, collection, fields, description, DefaultArchetypeTemperature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate archetypes: %w", err)
+	}
+
+	// Cache the archetypes
+	globalArchetypeCache.Set(collection.Id, &CachedArchetypes{
+		SchemaHash: schemaHash,
+		Archetypes: archetypes,
+		Fields:     fields,
+		CreatedAt:  time.Now(),
+	})
+
+	return archetypes, nil
+}
+
+// =====================================================
+// SHARED PERSONAS - Correlated seed data across collections
+// =====================================================
+
+// SeedPersona represents a shared set of identity values keyed by
+// their archetype placeholder key (ex. "NAME", "EMAIL", "USERNAME").
+//
+// It is used to generate correlated records across multiple collections,
+// aka. the same persona produces the same identity values in each collection.
+type SeedPersona map[string]string
+
+// seedPersonaKeys lists the placeholder keys that are part of a persona identity
+var seedPersonaKeys = []string{
+	"NAME",
+	"FIRSTNAME",
+	"LASTNAME",
+	"EMAIL",
+	"URL",
+	"USERNAME",
+	"COMPANY",
+	"CITY",
+	"COUNTRY",
+	"JOBTITLE",
+	"PHONE",
+}
+
+// GenerateSeedPersonas generates count new shared seed personas.
+//
+// The optional seed makes the generated personas reproducible
+// (see [GenerateSeedDataRequest.Seed]).
+func GenerateSeedPersonas(count int, seed *int64) []SeedPersona {
+	personas := make([]SeedPersona, 0, max(count, 0))
+
+	localRand := newSeedRand(seed)
+
+	for i := 0; i < count; i++ {
+		faker := seedFaker(localRand)
+
+		firstName := faker.FirstName()
+		lastName := faker.LastName()
+
+		persona := SeedPersona{
+			"NAME":      firstName + " " + lastName,
+			"FIRSTNAME": firstName,
+			"LASTNAME":  lastName,
+			"EMAIL":     strings.ToLower(firstName+"."+lastName) + "@" + faker.DomainName(),
+		}
+
+		// fill the remaining keys with generated values
+		for _, key := range seedPersonaKeys {
+			if persona[key] == "" {
+				persona[key] = seedPlaceholderValue(key, nil, nil, localRand)
+			}
+		}
+
+		personas = append(personas, persona)
+	}
+
+	return personas
+}
+
+// GenerateSeedDataForPersonas generates one record per persona for each of the provided collections.
+//
+// The records of each collection are derived from the collection archetypes (cached or AI generated),
+// but with the identity placeholders and fields (name, email, username, etc.) filled
+// from the shared personas, aka. result[collectionId][i] is derived from personas[i].
+//
+// The optional seed makes the non-persona values of the records reproducible
+// (see [GenerateSeedDataRequest.Seed]).
+func GenerateSeedDataForPersonas(app App, collections []*Collection, personas []SeedPersona, description string, seed *int64) (map[string][]map[string]any, error) {
+	if err := checkAITextLength("description", description, MaxSeedDescriptionLength); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]map[string]any, len(collections))
+
+	localRand := newSeedRand(seed)
+
+	for _, collection := range collections {
+		if collection.IsView() {
+			return nil, fmt.Errorf("cannot generate seed data for view collection %q", collection.Name)
+		}
+
+		fields := extractSeedFieldsInfo(collection)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("collection %q has no fields suitable for seed data generation", collection.Name)
+		}
+
+		archetypes, err := getOrGenerateArchetypes(app, collection, fields, description)
+		if err != nil {
+			return nil, fmt.Errorf("collection %q: %w", collection.Name, err)
+		}
+
+		fieldTypes := make(map[string]SeedFieldInfo, len(fields))
+		for _, f := range fields {
+			fieldTypes[f.Name] = f
+		}
+
+		if len(archetypes) == 0 {
+			return nil, fmt.Errorf("collection %q has no archetypes", collection.Name)
+		}
+
+		records := make([]map[string]any, len(personas))
+		for i, persona := range personas {
+			records[i] = mutateArchetypeWithRand(archetypes[i%len(archetypes)], fieldTypes, localRand, persona, nil, nil)
+		}
+
+		result[collection.Id] = records
+	}
+
+	return result, nil
+}
+
+// generateArchetypes uses AI to generate diverse archetype records
+func generateArchetypes(app App, collection *Collection, fields []SeedFieldInfo, description string, temperature float64) ([]map[string]any, error) {
+	settings := app.Settings()
+
+	if !settings.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	if settings.AI.APIKey == "" {
+		return nil, fmt.Errorf("AI API key is not configured")
+	}
+
+	// Build specialized prompt for archetypes
+	systemPrompt := buildArchetypeSystemPrompt()
+	userPrompt := buildArchetypeUserPrompt(collection.Name, fields, description)
+
+	provider, err := NewChatProvider(app)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Archetypes []map[string]any `json:"archetypes"`
+	}
+	err = completeAIJSON(context.Background(), app, provider, []ChatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	}, ChatOptions{
+		Model:       settings.AI.SeedModelOrDefault(),
+		Temperature: temperature,
+		JSON:        true,
+		Timeout:     60 * time.Second,
+	}, func(content string) error {
+		result.Archetypes = nil
+		if err := json.Unmarshal([]byte(content), &result); err != nil {
+			return fmt.Errorf("failed to parse archetypes JSON: %w", err)
+		}
+		if len(result.Archetypes) == 0 {
+			return fmt.Errorf("AI returned no archetypes")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sanitizeAISeedRecords(app, collection, result.Archetypes, fields)
+
+	return result.Archetypes, nil
+}
+
+// buildArchetypeSystemPrompt creates the system prompt for archetype generation
+func buildArchetypeSystemPrompt() string {
+	return `You are a data archetype generator for PocketBase. Generate DIVERSE archetype records that represent different "personas" or "categories" of data.
+
+RULES:
+1. Return a JSON object with an "archetypes" array containing exactly 12 DIVERSE records
+2. Each archetype should represent a DISTINCT category, persona, or style
+3. For text fields that appear to be creative content (bio, description, content, summary, etc.):
+   - Write full, realistic, varied content in different styles and tones
+   - Some formal, some casual, some brief, some detailed
+4. For name-like fields (name, title, username, etc.):
+   - Provide placeholder patterns like "{{NAME}}", "{{TITLE}}", "{{USERNAME}}"
+   - These will be replaced with generated values
+5. For email fields: use "{{EMAIL}}" as placeholder
+6. For URL fields: use "{{URL}}" as placeholder
+7. DO NOT include "id", "created", or "updated" fields
+8. Match data types exactly:
+   - text: strings (use {{PLACEHOLDER}} for unique fields, real content for creative fields)
+   - number: numbers within constraints
+   - bool: true or false
+   - email: "{{EMAIL}}"
+   - url: "{{URL}}"
+   - editor: HTML content with basic formatting
+   - date: ISO 8601 datetime strings
+   - select: values ONLY from the provided "values" array
+   - json: appropriate JSON objects
+
+DIVERSITY IS KEY:
+- Vary the tone: professional, casual, enthusiastic, minimalist
+- Vary the length: some brief, some detailed
+- Vary the content: different topics, perspectives, styles
+- Each archetype should feel like a different "person" wrote it
+
+OUTPUT FORMAT:
+{
+  "archetypes": [
+    { "field1": "value or {{PLACEHOLDER}}", ... },
+    ...12 total archetypes...
+  ]
+}`
+}
+
+// buildArchetypeUserPrompt creates the user prompt for archetype generation
+func buildArchetypeUserPrompt(collectionName string, fields []SeedFieldInfo, description string) string {
+	fieldsJSON, _ := json.MarshalIndent(fields, "", "  ")
+
+	prompt := fmt.Sprintf(`Generate 12 DIVERSE archetype records for a "%s" collection.
+
+These archetypes will be used as templates to generate thousands of records, so make them:
+- DIVERSE in style, tone, and content
+- REPRESENTATIVE of different user personas or data categories
+- HIGH QUALITY with realistic, contextual content
+
+Field Schema:
+%s`, collectionName, string(fieldsJSON))
+
+	if description != "" {
+		prompt += fmt.Sprintf(`
+
+Context/Description: %s
+
+Use this context to make archetypes more relevant and realistic.`, description)
+	}
+
+	prompt += `
+
+Remember:
+- Use {{NAME}}, {{EMAIL}}, {{URL}}, {{USERNAME}}, {{TITLE}} placeholders for fields that should be unique per record
+- Write actual content for creative fields (bio, description, content, etc.)
+- Return exactly 12 diverse archetypes in the "archetypes" array`
+
+	return prompt
+}
+
+// multiplyArchetypes generates records by mutating archetypes with gofakeit
+// Uses parallel workers for large counts to maximize throughput
+func multiplyArchetypes(archetypes []map[string]any, fields []SeedFieldInfo, count int) []map[string]any {
+	return multiplyArchetypesWithRand(archetypes, fields, count, rand.New(rand.NewSource(time.Now().UnixNano())), ArchetypeSelectionRandom, nil)
+}
+
+// multiplyArchetypesWithRand is the same as multiplyArchetypes but uses the
+// provided (per request) random source instead of the global one
+// and the specified archetypes selection mode and locale (nil for the default one).
+//
+// The parallel workers random sources are derived from localRand.
+func multiplyArchetypesWithRand(archetypes []map[string]any, fields []SeedFieldInfo, count int, localRand *rand.Rand, selection string, locale *seedLocale) []map[string]any {
+	return multiplyArchetypesRange(archetypes, seedFieldTypes(fields), newSeedUniqueValues(fields), locale, 0, count, localRand, selection)
+}
+
+// seedFieldTypes returns a field name -> field info map for quick lookup.
+func seedFieldTypes(fields []SeedFieldInfo) map[string]SeedFieldInfo {
+	fieldTypes := make(map[string]SeedFieldInfo, len(fields))
+	for _, f := range fields {
+		fieldTypes[f.Name] = f
+	}
+	return fieldTypes
+}
+
+// multiplyArchetypesRange generates count records starting from the offset
+// record index (used for the round-robin archetypes selection).
+//
+// The generated values of the unique fields are deduplicated with
+// the unique tracker (if not nil) and the identity values are localized
+// with locale (if not nil).
+func multiplyArchetypesRange(archetypes []map[string]any, fieldTypes map[string]SeedFieldInfo, unique *seedUniqueValues, locale *seedLocale, offset int, count int, localRand *rand.Rand, selection string) []map[string]any {
+	if count > 1000 {
+		// For large counts, use parallel generation with worker pool
+		return multiplyArchetypesParallel(archetypes, fieldTypes, unique, locale, offset, count, localRand, selection)
+	}
+
+	// For small counts, use simple sequential generation
+	records := make([]map[string]any, 0, count)
+	for i := 0; i < count; i++ {
+		archetype := selectArchetype(archetypes, offset+i, selection, localRand)
+		record := mutateArchetypeWithRand(archetype, fieldTypes, localRand, nil, locale, unique)
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// maxSeedUniqueRegenerations is the max number of attempts to regenerate
+// a fresh unique value before falling back to a counter suffix.
+const maxSeedUniqueRegenerations = 5
+
+// seedUniqueValues keeps track of the already generated values of the email, url
+// and unique indexed fields so that they could be made unique across multiple record batches.
+type seedUniqueValues struct {
+	fields []SeedFieldInfo
+	sets   map[string]*seedUniqueSet
+}
+
+// newSeedUniqueValues creates a new seedUniqueValues tracker for the email, url and unique indexed fields.
+func newSeedUniqueValues(fields []SeedFieldInfo) *seedUniqueValues {
+	u := &seedUniqueValues{
+		sets: map[string]*seedUniqueSet{},
+	}
+
+	for _, field := range fields {
+		if field.Unique || field.Type == FieldTypeEmail || field.Type == FieldTypeURL {
+			u.fields = append(u.fields, field)
+			u.sets[field.Name] = newSeedUniqueSet(field)
+		}
+	}
+
+	return u
+}
+
+// set returns the taken values set of the specified field
+// (or nil if the field is not tracked or u is nil).
+func (u *seedUniqueValues) set(fieldName string) *seedUniqueSet {
+	if u == nil {
+		return nil
 	}
-	if strings.Contains(result, "{{CITY}}") {
-		result = strings.ReplaceAll(result, "{{CITY}}", gofakeit.City())
+
+	return u.sets[fieldName]
+}
+
+// loadExisting marks the values of the existing collection records as taken.
+func (u *seedUniqueValues) loadExisting(app App, collection *Collection) error {
+	for _, field := range u.fields {
+		var values []string
+		err := app.DB().
+			Select(field.Name).
+			From(collection.Name).
+			AndWhere(dbx.NewExp("[[" + field.Name + "]] != ''")).
+			Column(&values)
+		if err != nil {
+			return err
+		}
+
+		set := u.sets[field.Name]
+		for _, v := range values {
+			set.values[strings.ToLower(v)] = struct{}{}
+		}
 	}
-	if strings.Contains(result, "{{COUNTRY}}") {
-		result = strings.ReplaceAll(result, "{{COUNTRY}}", gofakeit.Country())
+
+	return nil
+}
+
+// apply makes the records tracked fields values unique
+// (including toward the values of the previously applied records)
+// by appending a counter suffix to the colliding values.
+func (u *seedUniqueValues) apply(records []map[string]any) {
+	for _, field := range u.fields {
+		set := u.sets[field.Name]
+
+		for _, record := range records {
+			value, ok := record[field.Name].(string)
+			if !ok || value == "" {
+				continue
+			}
+
+			record[field.Name] = set.reserve(value, nil)
+		}
 	}
-	if strings.Contains(result, "{{JOBTITLE}}") {
-		result = strings.ReplaceAll(result, "{{JOBTITLE}}", gofakeit.JobTitle())
+}
+
+// seedUniqueSet is a concurrent safe set of the taken (lowercased) values of a single seed field.
+type seedUniqueSet struct {
+	mu sync.Mutex
+
+	makeUnique func(value string, n int) string
+
+	values map[string]struct{}
+
+	// the next suffix counter of each colliding value
+	// (to avoid rechecking the already taken suffixes)
+	counters map[string]int
+}
+
+// newSeedUniqueSet creates a new empty seedUniqueSet for the specified field.
+func newSeedUniqueSet(field SeedFieldInfo) *seedUniqueSet {
+	makeUnique := uniqueSeedText
+	switch field.Type {
+	case FieldTypeEmail:
+		makeUnique = uniqueSeedEmail
+	case FieldTypeURL:
+		makeUnique = uniqueSeedURL
 	}
-	if strings.Contains(result, "{{PHONE}}") {
-		result = strings.ReplaceAll(result, "{{PHONE}}", gofakeit.Phone())
+
+	return &seedUniqueSet{
+		makeUnique: makeUnique,
+		values:     map[string]struct{}{},
+		counters:   map[string]int{},
 	}
+}
 
-	if hasInfo {
-		switch fieldInfo.Type {
-		case FieldTypeEmail:
-			if result == "{{EMAIL}}" || result == "" {
-				return gofakeit.Email()
-			}
-		case FieldTypeURL:
-			if result == "{{URL}}" || result == "" {
-				return gofakeit.URL()
+// reserve marks value as taken and returns it if it wasn't already taken.
+//
+// Otherwise the value is replaced with a fresh one from regenerate (if not nil,
+// up to [maxSeedUniqueRegenerations] attempts) and as last resort with
+// the value suffixed with a counter (ex. "john@example.com" -> "john2@example.com").
+func (s *seedUniqueSet) reserve(value string, regenerate func() string) string {
+	if value == "" || s.tryAdd(value) {
+		return value
+	}
+
+	if regenerate != nil {
+		prev := value
+		for i := 0; i < maxSeedUniqueRegenerations; i++ {
+			fresh := regenerate()
+			if fresh == prev {
+				break // not random (ex. a value without placeholders)
 			}
-		case FieldTypeSelect:
-			if len(fieldInfo.Values) > 0 && fieldInfo.MaxSelect <= 1 {
-				return fieldInfo.Values[localRand.Intn(len(fieldInfo.Values))]
+			if fresh != "" && s.tryAdd(fresh) {
+				return fresh
 			}
+			prev = fresh
 		}
 	}
 
-	lowerName := strings.ToLower(fieldName)
-	if result == value {
-		if strings.Contains(lowerName, "email") {
-			return gofakeit.Email()
-		}
-		if strings.Contains(lowerName, "phone") {
-			return gofakeit.Phone()
-		}
-		if strings.Contains(lowerName, "username") || lowerName == "user" {
-			return gofakeit.Username()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(value)
+
+	n := max(s.counters[key], 2)
+	unique := value
+	for {
+		unique = s.makeUnique(value, n)
+		n++
+		if _, exists := s.values[strings.ToLower(unique)]; !exists {
+			break
 		}
 	}
+	s.counters[key] = n
+	s.values[strings.ToLower(unique)] = struct{}{}
 
-	return result
+	return unique
 }
 
-// mutateNumberFieldWithRand generates a random number with local rand
-func mutateNumberFieldWithRand(fieldInfo SeedFieldInfo, localRand *rand.Rand) float64 {
-	min := fieldInfo.Min
-	max := fieldInfo.Max
-	if min == 0 && max == 0 {
-		min = 0
-		max = 1000
-	} else if max == 0 {
-		max = min + 1000
+// tryAdd adds value to the set and reports whether it wasn't already taken.
+func (s *seedUniqueSet) tryAdd(value string) bool {
+	key := strings.ToLower(value)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.values[key]; exists {
+		return false
 	}
-	return min + localRand.Float64()*(max-min)
+	s.values[key] = struct{}{}
+
+	return true
 }
 
-// mutateSelectFieldWithRand picks random values with local rand
-func mutateSelectFieldWithRand(fieldInfo SeedFieldInfo, localRand *rand.Rand) interface{} {
-	if len(fieldInfo.Values) == 0 {
-		return ""
+// uniqueSeedText appends n to the text value (ex. "my-post" -> "my-post-2").
+func uniqueSeedText(value string, n int) string {
+	return value + "-" + strconv.Itoa(n)
+}
+
+// uniqueSeedEmail appends n to the local part of the email address
+// (ex. "john@example.com" -> "john2@example.com").
+func uniqueSeedEmail(email string, n int) string {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return email + strconv.Itoa(n)
 	}
 
-	if fieldInfo.MaxSelect <= 1 {
-		return fieldInfo.Values[localRand.Intn(len(fieldInfo.Values))]
+	return email[:at] + strconv.Itoa(n) + email[at:]
+}
+
+// uniqueSeedURL appends n to the path of the url
+// (ex. "https://example.com/about" -> "https://example.com/about-2").
+func uniqueSeedURL(rawURL string, n int) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL + "-" + strconv.Itoa(n)
 	}
 
-	numSelections := localRand.Intn(fieldInfo.MaxSelect) + 1
-	if numSelections > len(fieldInfo.Values) {
-		numSelections = len(fieldInfo.Values)
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = "/" + strconv.Itoa(n)
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "-" + strconv.Itoa(n)
 	}
+	u.RawPath = ""
 
-	shuffled := make([]string, len(fieldInfo.Values))
-	copy(shuffled, fieldInfo.Values)
-	localRand.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
+	return u.String()
+}
 
-	return shuffled[:numSelections]
+// multiplyArchetypesParallel generates records using multiple goroutines
+//
+// The unique fields values are deduplicated after the workers completion
+// in the records order so that the result doesn't depend on the workers scheduling.
+func multiplyArchetypesParallel(archetypes []map[string]any, fieldTypes map[string]SeedFieldInfo, unique *seedUniqueValues, locale *seedLocale, offset int, count int, localRand *rand.Rand, selection string) []map[string]any {
+	// Determine number of workers (use available CPUs, cap at 8)
+	numWorkers := 8
+	
+	// Pre-allocate result slice
+	records := make([]map[string]any, count)
+	
+	// Calculate records per worker
+	chunkSize := count / numWorkers
+	remainder := count % numWorkers
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+
+	startIdx := 0
+	for w := 0; w < numWorkers; w++ {
+		// Distribute remainder among first workers
+		workerCount := chunkSize
+		if w < remainder {
+			workerCount++
+		}
+		
+		endIdx := startIdx + workerCount
+		
+		// Each worker has its own random source for thread safety
+		workerRand := rand.New(rand.NewSource(localRand.Int63()))
+
+		// Launch worker goroutine
+		go func(start, end int, workerRand *rand.Rand) {
+			defer wg.Done()
+			
+			for i := start; i < end; i++ {
+				// Pick an archetype (the round-robin index is global so that the order doesn't depend on the workers)
+				archetype := selectArchetype(archetypes, offset+i, selection, workerRand)
+				// Generate record (mutateArchetypeWithRand is thread-safe with local rand)
+				records[i] = mutateArchetypeWithRand(archetype, fieldTypes, workerRand, nil, locale, nil)
+			}
+		}(startIdx, endIdx, workerRand)
+		
+		startIdx = endIdx
+	}
+
+	wg.Wait()
+
+	if unique != nil {
+		unique.apply(records)
+	}
+
+	return records
 }
 
-// mutateArchetype creates a new record by replacing placeholders and randomizing fields
-func mutateArchetype(archetype map[string]any, fieldTypes map[string]SeedFieldInfo) map[string]any {
-	record := make(map[string]any)
+// selectArchetype returns the archetype of the i-th generated record based on the selection mode.
+func selectArchetype(archetypes []map[string]any, i int, selection string, localRand *rand.Rand) map[string]any {
+	if selection == ArchetypeSelectionRoundRobin {
+		return archetypes[i%len(archetypes)]
+	}
+
+	return archetypes[localRand.Intn(len(archetypes))]
+}
 
-	for fieldName, value := range archetype {
+// mutateArchetypeWithRand is a thread-safe version using a local random source
+//
+// If persona is not nil, its values are used for the identity placeholders and fields.
+//
+// If locale is not nil, the localized identity values are generated.
+//
+// If unique is not nil, the string values of its tracked fields are deduplicated.
+func mutateArchetypeWithRand(archetype map[string]any, fieldTypes map[string]SeedFieldInfo, localRand *rand.Rand, persona SeedPersona, locale *seedLocale, unique *seedUniqueValues) map[string]any {
+	record := make(map[string]any, len(archetype))
+
+	// iterate in a stable order so that the generated values depend only on the random source
+	for _, fieldName := range slices.Sorted(maps.Keys(archetype)) {
+		value := archetype[fieldName]
 		fieldInfo, hasInfo := fieldTypes[fieldName]
+		if !hasInfo {
+			fieldInfo.Name = fieldName
+		}
+
+		taken := unique.set(fieldName)
+
+		// Custom generators take precedence over the built-in heuristics
+		if generator := findSeedGenerator(fieldInfo); generator != nil {
+			generated := generator(fieldInfo, localRand)
+			if str, ok := generated.(string); ok && taken != nil {
+				generated = taken.reserve(str, func() string {
+					str, _ := generator(fieldInfo, localRand).(string)
+					return str
+				})
+			}
+			record[fieldName] = generated
+			continue
+		}
+
+		// the weighted select values are always sampled
+		// (the archetype values would skew the weights)
+		if hasInfo && fieldInfo.Type == FieldTypeSelect && len(fieldInfo.Proportions) > 0 {
+			record[fieldName] = mutateSelectFieldWithRand(fieldInfo, localRand)
+			continue
+		}
 
-		// Process based on field type and value
 		switch v := value.(type) {
 		case string:
-			record[fieldName] = mutateStringField(v, fieldName, fieldInfo, hasInfo)
+			if hasInfo && fieldInfo.Type == FieldTypeDate {
+				generate := func() string {
+					return mutateDateFieldWithRand(fieldInfo, localRand)
+				}
+				if taken != nil {
+					record[fieldName] = taken.reserve(generate(), generate)
+				} else {
+					record[fieldName] = generate()
+				}
+				continue
+			}
+			record[fieldName] = mutateStringFieldWithRand(v, fieldName, fieldInfo, hasInfo, localRand, persona, locale, taken)
 		case float64:
 			if hasInfo && fieldInfo.Type == FieldTypeNumber {
-				record[fieldName] = mutateNumberField(fieldInfo)
+				record[fieldName] = mutateNumberFieldWithRand(fieldInfo, localRand)
 			} else {
 				record[fieldName] = v
 			}
 		case bool:
-			// Randomly flip booleans for variety
-			if rand.Float32() < 0.3 {
+			if localRand.Float32() < 0.3 {
 				record[fieldName] = !v
 			} else {
 				record[fieldName] = v
 			}
 		case []interface{}:
-			// Handle select fields with multiple values
 			if hasInfo && fieldInfo.Type == FieldTypeSelect && len(fieldInfo.Values) > 0 {
-				record[fieldName] = mutateSelectField(fieldInfo)
+				record[fieldName] = normalizeSeedSelectValuesWithRand(fieldInfo, v, localRand)
 			} else {
 				record[fieldName] = v
 			}
 		default:
-			// Keep JSON and other complex types as-is
 			record[fieldName] = value
 		}
 	}
@@ -1149,92 +3199,174 @@ func mutateArchetype(archetype map[string]any, fieldTypes map[string]SeedFieldIn
 	return record
 }
 
-// mutateStringField handles string field mutation with placeholder replacement
-func mutateStringField(value, fieldName string, fieldInfo SeedFieldInfo, hasInfo bool) string {
-	// Replace placeholders
-	result := value
+// seedPlaceholders lists the supported archetype placeholder keys (ex. "NAME" for "{{NAME}}")
+// in the order of their replacement.
+var seedPlaceholders = []string{
+	"NAME",
+	"FIRSTNAME",
+	"LASTNAME",
+	"EMAIL",
+	"URL",
+	"USERNAME",
+	"TITLE",
+	"COMPANY",
+	"CITY",
+	"COUNTRY",
+	"JOBTITLE",
+	"PHONE",
+}
 
-	// Common placeholder replacements
-	if strings.Contains(result, "{{NAME}}") {
-		result = strings.ReplaceAll(result, "{{NAME}}", gofakeit.Name())
-	}
-	if strings.Contains(result, "{{FIRSTNAME}}") {
-		result = strings.ReplaceAll(result, "{{FIRSTNAME}}", gofakeit.FirstName())
-	}
-	if strings.Contains(result, "{{LASTNAME}}") {
-		result = strings.ReplaceAll(result, "{{LASTNAME}}", gofakeit.LastName())
+// seedPlaceholderValue returns the value for the specified placeholder key.
+//
+// Persona values (if any) take precedence over the localized (if locale is not nil)
+// and the generated gofakeit values.
+func seedPlaceholderValue(key string, persona SeedPersona, locale *seedLocale, localRand *rand.Rand) string {
+	if v, ok := persona[key]; ok {
+		return v
 	}
-	if strings.Contains(result, "{{EMAIL}}") {
-		result = strings.ReplaceAll(result, "{{EMAIL}}", gofakeit.Email())
-	}
-	if strings.Contains(result, "{{URL}}") {
-		result = strings.ReplaceAll(result, "{{URL}}", gofakeit.URL())
-	}
-	if strings.Contains(result, "{{USERNAME}}") {
-		result = strings.ReplaceAll(result, "{{USERNAME}}", gofakeit.Username())
-	}
-	if strings.Contains(result, "{{TITLE}}") {
-		result = strings.ReplaceAll(result, "{{TITLE}}", gofakeit.Sentence(rand.Intn(5)+3))
-	}
-	if strings.Contains(result, "{{COMPANY}}") {
-		result = strings.ReplaceAll(result, "{{COMPANY}}", gofakeit.Company())
+
+	if locale != nil {
+		if v, ok := locale.value(key, localRand); ok {
+			return v
+		}
 	}
-	if strings.Contains(result, "{{CITY}}") {
-		result = strings.ReplaceAll(result, "{{CITY}}", gofakeit.City())
+
+	faker := seedFaker(localRand)
+
+	switch key {
+	case "NAME":
+		return faker.Name()
+	case "FIRSTNAME":
+		return faker.FirstName()
+	case "LASTNAME":
+		return faker.LastName()
+	case "EMAIL":
+		return faker.Email()
+	case "URL":
+		return faker.URL()
+	case "USERNAME":
+		return faker.Username()
+	case "TITLE":
+		return faker.Sentence(faker.Number(3, 7))
+	case "COMPANY":
+		return faker.Company()
+	case "CITY":
+		return faker.City()
+	case "COUNTRY":
+		return faker.Country()
+	case "JOBTITLE":
+		return faker.JobTitle()
+	case "PHONE":
+		return faker.Phone()
+	}
+
+	return ""
+}
+
+// seedFaker returns a gofakeit faker seeded from localRand
+// (or the global one if localRand is nil).
+func seedFaker(localRand *rand.Rand) *gofakeit.Faker {
+	if localRand == nil {
+		return gofakeit.GlobalFaker
 	}
-	if strings.Contains(result, "{{COUNTRY}}") {
-		result = strings.ReplaceAll(result, "{{COUNTRY}}", gofakeit.Country())
+
+	// the 0 seed is avoided because it is replaced with a crypto random one
+	return gofakeit.New(uint64(localRand.Int63()) | 1)
+}
+
+// mutateStringFieldWithRand is thread-safe string mutation
+//
+// If persona is not nil, its values are used instead of the generated ones.
+//
+// If locale is not nil, the localized values are generated (see [seedPlaceholderValue]).
+//
+// If taken is not nil, the result is regenerated (or suffixed) until
+// it is not part of the set (see [seedUniqueSet.reserve]).
+func mutateStringFieldWithRand(value, fieldName string, fieldInfo SeedFieldInfo, hasInfo bool, localRand *rand.Rand, persona SeedPersona, locale *seedLocale, taken *seedUniqueSet) string {
+	generate := func() string {
+		return generateSeedStringWithRand(value, fieldName, fieldInfo, hasInfo, localRand, persona, locale)
 	}
-	if strings.Contains(result, "{{JOBTITLE}}") {
-		result = strings.ReplaceAll(result, "{{JOBTITLE}}", gofakeit.JobTitle())
+
+	if taken == nil {
+		return generate()
 	}
-	if strings.Contains(result, "{{PHONE}}") {
-		result = strings.ReplaceAll(result, "{{PHONE}}", gofakeit.Phone())
+
+	return taken.reserve(generate(), generate)
+}
+
+// generateSeedStringWithRand replaces the placeholders of the archetype string value
+// (and generates the heuristic values for the email, url, select, etc. fields).
+func generateSeedStringWithRand(value, fieldName string, fieldInfo SeedFieldInfo, hasInfo bool, localRand *rand.Rand, persona SeedPersona, locale *seedLocale) string {
+	result := value
+
+	// Replace placeholders using gofakeit (which is thread-safe)
+	for _, key := range seedPlaceholders {
+		placeholder := "{{" + key + "}}"
+		if strings.Contains(result, placeholder) {
+			result = strings.ReplaceAll(result, placeholder, seedPlaceholderValue(key, persona, locale, localRand))
+		}
 	}
 
-	// If still has placeholders or is a known unique field type, generate fresh
 	if hasInfo {
 		switch fieldInfo.Type {
 		case FieldTypeEmail:
 			if result == "{{EMAIL}}" || result == "" {
-				return gofakeit.Email()
+				return seedPlaceholderValue("EMAIL", persona, locale, localRand)
 			}
 		case FieldTypeURL:
 			if result == "{{URL}}" || result == "" {
-				return gofakeit.URL()
+				return seedPlaceholderValue("URL", persona, locale, localRand)
 			}
 		case FieldTypeSelect:
-			if len(fieldInfo.Values) > 0 {
-				if fieldInfo.MaxSelect <= 1 {
-					return fieldInfo.Values[rand.Intn(len(fieldInfo.Values))]
-				}
+			if canonical, ok := canonicalSeedSelectValue(fieldInfo.Values, result); ok {
+				return canonical
+			}
+			if len(fieldInfo.Values) > 0 && fieldInfo.MaxSelect <= 1 {
+				return fieldInfo.Values[localRand.Intn(len(fieldInfo.Values))]
 			}
 		}
 	}
 
-	// Check field name patterns for additional mutation
 	lowerName := strings.ToLower(fieldName)
-	if result == value { // Not mutated yet
+	if result == value {
 		if strings.Contains(lowerName, "email") {
-			return gofakeit.Email()
+			return seedPlaceholderValue("EMAIL", persona, locale, localRand)
 		}
 		if strings.Contains(lowerName, "phone") {
-			return gofakeit.Phone()
+			return seedPlaceholderValue("PHONE", persona, locale, localRand)
 		}
 		if strings.Contains(lowerName, "username") || lowerName == "user" {
-			return gofakeit.Username()
+			return seedPlaceholderValue("USERNAME", persona, locale, localRand)
 		}
 	}
 
 	return result
 }
 
-// mutateNumberField generates a random number within field constraints
-func mutateNumberField(fieldInfo SeedFieldInfo) float64 {
+// mutateNumberFieldWithRand generates a random number with local rand
+//
+// The number is rounded to an integer within the field constraints if fieldInfo.OnlyInt is set.
+func mutateNumberFieldWithRand(fieldInfo SeedFieldInfo, localRand *rand.Rand) float64 {
+	if fieldInfo.Distribution != nil && fieldInfo.Distribution.Type != SeedNumberDistributionUniform {
+		value := sampleSeedNumberDistribution(*fieldInfo.Distribution, localRand)
+
+		// clamp to the field constraints (if any)
+		if fieldInfo.Min != 0 || fieldInfo.Max != 0 {
+			value = math.Max(value, fieldInfo.Min)
+		}
+		if fieldInfo.Max > fieldInfo.Min {
+			value = math.Min(value, fieldInfo.Max)
+		}
+
+		if fieldInfo.OnlyInt {
+			return roundSeedInt(value, fieldInfo.Min, fieldInfo.Max)
+		}
+
+		return value
+	}
+
 	min := fieldInfo.Min
 	max := fieldInfo.Max
-
-	// Set reasonable defaults if not specified
 	if min == 0 && max == 0 {
 		min = 0
 		max = 1000
@@ -1242,46 +3374,242 @@ func mutateNumberField(fieldInfo SeedFieldInfo) float64 {
 		max = min + 1000
 	}
 
-	return min + rand.Float64()*(max-min)
+	value := min + localRand.Float64()*(max-min)
+
+	if fieldInfo.OnlyInt {
+		return roundSeedInt(value, min, max)
+	}
+
+	return value
+}
+
+// roundSeedInt rounds value to the nearest integer within the [min, max] bounds.
+//
+// The min bound is ignored if both bounds are 0 and the max bound is ignored
+// if it is not greater than min (the same as the non-integer clamping).
+// If there is no integer within the bounds, value is returned as it is.
+func roundSeedInt(value, min, max float64) float64 {
+	rounded := math.Round(value)
+
+	hasMin := min != 0 || max != 0
+	hasMax := max > min
+
+	if hasMin && rounded < min {
+		rounded = math.Ceil(min)
+	}
+	if hasMax && rounded > max {
+		rounded = math.Floor(max)
+	}
+
+	// no integer within the bounds
+	if (hasMin && rounded < min) || (hasMax && rounded > max) {
+		return value
+	}
+
+	return rounded
+}
+
+// sampleSeedNumberDistribution returns a random sample of a non-uniform number distribution.
+func sampleSeedNumberDistribution(dist SeedNumberDistribution, localRand *rand.Rand) float64 {
+	switch dist.Type {
+	case SeedNumberDistributionNormal:
+		return dist.Mean + localRand.NormFloat64()*dist.StdDev
+	case SeedNumberDistributionExponential:
+		return localRand.ExpFloat64() * dist.Mean
+	case SeedNumberDistributionLogNormal:
+		return math.Exp(dist.Mean + localRand.NormFloat64()*dist.StdDev)
+	default:
+		return localRand.Float64()
+	}
+}
+
+// canonicalSeedSelectValue returns the allowed select value matching
+// case-insensitively the provided one (ex. "Published" -> "published").
+func canonicalSeedSelectValue(values []string, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// normalizeSeedSelectValuesWithRand replaces the archetype select values with
+// their canonical casing, dropping the unknown and duplicated ones.
+//
+// Falls back to random values if none of the archetype values is allowed.
+func normalizeSeedSelectValuesWithRand(fieldInfo SeedFieldInfo, values []any, localRand *rand.Rand) any {
+	normalized := make([]string, 0, len(values))
+
+	for _, raw := range values {
+		str, ok := raw.(string)
+		if !ok {
+			continue
+		}
+
+		canonical, ok := canonicalSeedSelectValue(fieldInfo.Values, str)
+		if !ok || slices.Contains(normalized, canonical) {
+			continue
+		}
+
+		normalized = append(normalized, canonical)
+	}
+
+	if len(normalized) == 0 {
+		return mutateSelectFieldWithRand(fieldInfo, localRand)
+	}
+
+	if fieldInfo.MaxSelect <= 1 {
+		return normalized[0]
+	}
+
+	if len(normalized) > fieldInfo.MaxSelect {
+		normalized = normalized[:fieldInfo.MaxSelect]
+	}
+
+	return normalized
 }
 
-// mutateSelectField picks random values from a select field
-func mutateSelectField(fieldInfo SeedFieldInfo) interface{} {
+// mutateSelectFieldWithRand picks random values with local rand
+// (following the field values proportions if any).
+func mutateSelectFieldWithRand(fieldInfo SeedFieldInfo, localRand *rand.Rand) interface{} {
 	if len(fieldInfo.Values) == 0 {
 		return ""
 	}
 
-	if fieldInfo.MaxSelect <= 1 {
-		// Single select - return a string
-		return fieldInfo.Values[rand.Intn(len(fieldInfo.Values))]
+	if len(fieldInfo.Proportions) > 0 {
+		return pickSeedSelectValuesWithProportions(fieldInfo, localRand)
 	}
 
-	// Multi-select - return an array
-	numSelections := rand.Intn(fieldInfo.MaxSelect) + 1
-	if numSelections > len(fieldInfo.Values) {
-		numSelections = len(fieldInfo.Values)
+	if fieldInfo.MaxSelect <= 1 {
+		return fieldInfo.Values[localRand.Intn(len(fieldInfo.Values))]
 	}
 
-	// Shuffle and pick
+	numSelections := sampleSeedSelectCount(fieldInfo, localRand)
+
 	shuffled := make([]string, len(fieldInfo.Values))
 	copy(shuffled, fieldInfo.Values)
-	rand.Shuffle(len(shuffled), func(i, j int) {
+	localRand.Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
 	return shuffled[:numSelections]
 }
 
-// mutateDateField generates a random date within a reasonable range
-func mutateDateField() string {
-	// Generate a date within the last 2 years
-	minDate := time.Now().AddDate(-2, 0, 0)
-	maxDate := time.Now()
+// pickSeedSelectValuesWithProportions picks random values weighted by the field values proportions.
+//
+// For multi-select fields the values are picked without replacement,
+// so the proportions apply to the first pick of each record and
+// the count is limited by the number of values with a positive proportion.
+func pickSeedSelectValuesWithProportions(fieldInfo SeedFieldInfo, localRand *rand.Rand) interface{} {
+	values := make([]string, 0, len(fieldInfo.Values))
+	weights := make([]float64, 0, len(fieldInfo.Values))
+	for _, v := range fieldInfo.Values {
+		if w := fieldInfo.Proportions[v]; w > 0 {
+			values = append(values, v)
+			weights = append(weights, w)
+		}
+	}
+
+	if len(values) == 0 {
+		if fieldInfo.MaxSelect <= 1 {
+			return ""
+		}
+		return []string{}
+	}
+
+	numSelections := 1
+	if fieldInfo.MaxSelect > 1 {
+		numSelections = min(sampleSeedSelectCount(fieldInfo, localRand), len(values))
+	}
+
+	picked := make([]string, 0, numSelections)
+	for len(picked) < numSelections {
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+
+		i := len(weights) - 1
+		r := localRand.Float64() * total
+		for j, w := range weights {
+			r -= w
+			if r < 0 {
+				i = j
+				break
+			}
+		}
+
+		picked = append(picked, values[i])
+		values = append(values[:i], values[i+1:]...)
+		weights = append(weights[:i], weights[i+1:]...)
+	}
+
+	if fieldInfo.MaxSelect <= 1 {
+		return picked[0]
+	}
+
+	return picked
+}
+
+// sampleSeedSelectCount returns a random number of selections (1..maxSelect)
+// following the field select count distribution (default to uniform).
+func sampleSeedSelectCount(fieldInfo SeedFieldInfo, localRand *rand.Rand) int {
+	maxCount := min(fieldInfo.MaxSelect, len(fieldInfo.Values))
+
+	if fieldInfo.SelectCountDistribution == nil || fieldInfo.SelectCountDistribution.Type == SeedSelectCountDistributionUniform {
+		return localRand.Intn(maxCount) + 1
+	}
+
+	weights := fieldInfo.SelectCountDistribution.weights(maxCount)
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return localRand.Intn(maxCount) + 1
+	}
+
+	r := localRand.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r < 0 {
+			return i + 1
+		}
+	}
+
+	return maxCount
+}
+
+// mutateDateFieldWithRand generates a random date within the field date range
+// (default to the last 2 years).
+func mutateDateFieldWithRand(fieldInfo SeedFieldInfo, localRand *rand.Rand) string {
+	// (truncated to the day so that the seeded generations are reproducible)
+	maxDate := time.Now().UTC().Truncate(24 * time.Hour)
+	minDate := maxDate.AddDate(-2, 0, 0)
+
+	if r := fieldInfo.DateRange; r != nil {
+		if !r.Max.IsZero() {
+			maxDate = r.Max.Time()
+			minDate = maxDate.AddDate(-2, 0, 0)
+		}
+		if !r.Min.IsZero() {
+			minDate = r.Min.Time()
+			if r.Max.IsZero() && !maxDate.After(minDate) {
+				maxDate = minDate.AddDate(2, 0, 0)
+			}
+		}
+	}
 
-	delta := maxDate.Sub(minDate)
-	randomDelta := time.Duration(rand.Int63n(int64(delta)))
+	randomDate := minDate
+	if delta := maxDate.Sub(minDate); delta > 0 {
+		randomDate = minDate.Add(time.Duration(localRand.Int63n(int64(delta) + 1)))
+	}
 
-	randomDate := minDate.Add(randomDelta)
-	return randomDate.Format("2006-01-02 15:04:05.000Z")
+	return randomDate.UTC().Format(types.DefaultDateLayout)
 }
 
@@ -0,0 +1,2793 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRegisterSeedGenerator(t *testing.T) {
+	// custom generators are global so register them with unique to the test field names and types
+	core.RegisterSeedGenerator("test_iban", func(fieldInfo core.SeedFieldInfo, r *rand.Rand) any {
+		return fmt.Sprintf("DE%020d", r.Int63())
+	})
+	core.RegisterSeedGenerator("*_test_code", func(fieldInfo core.SeedFieldInfo, r *rand.Rand) any {
+		return "code_" + fieldInfo.Name
+	})
+	core.RegisterSeedGenerator(core.SeedGeneratorTypePrefix+"test_custom_type", func(fieldInfo core.SeedFieldInfo, r *rand.Rand) any {
+		return "custom_type"
+	})
+	t.Cleanup(func() {
+		core.UnregisterSeedGenerator("test_iban")
+		core.UnregisterSeedGenerator("*_test_code")
+		core.UnregisterSeedGenerator(core.SeedGeneratorTypePrefix + "test_custom_type")
+	})
+
+	fields := []core.SeedFieldInfo{
+		{Name: "TEST_IBAN", Type: core.FieldTypeText},
+		{Name: "promo_test_code", Type: core.FieldTypeText},
+		{Name: "custom", Type: "test_custom_type"},
+		{Name: "title", Type: core.FieldTypeText},
+	}
+
+	archetypes := []map[string]any{
+		{
+			"TEST_IBAN":       "{{IBAN}}",
+			"promo_test_code": "abc",
+			"custom":          "abc",
+			"title":           "Lorem ipsum",
+			"missing":         "missing",
+		},
+	}
+
+	for _, count := range []int{10, 1001} {
+		t.Run(fmt.Sprintf("count_%d", count), func(t *testing.T) {
+			records := core.MultiplyArchetypes(archetypes, fields, count)
+			if len(records) != count {
+				t.Fatalf("Expected %d records, got %d", count, len(records))
+			}
+
+			for i, record := range records {
+				iban, _ := record["TEST_IBAN"].(string)
+				if !strings.HasPrefix(iban, "DE") || len(iban) != 22 {
+					t.Fatalf("[%d] Expected a generated IBAN, got %v", i, record["TEST_IBAN"])
+				}
+
+				if v := record["promo_test_code"]; v != "code_promo_test_code" {
+					t.Fatalf("[%d] Expected the wildcard generator value, got %v", i, v)
+				}
+
+				if v := record["custom"]; v != "custom_type" {
+					t.Fatalf("[%d] Expected the type generator value, got %v", i, v)
+				}
+
+				// built-in fallbacks
+				if v := record["title"]; v != "Lorem ipsum" {
+					t.Fatalf("[%d] Expected the title to remain unchanged, got %v", i, v)
+				}
+				if v := record["missing"]; v != "missing" {
+					t.Fatalf("[%d] Expected the unknown field to remain unchanged, got %v", i, v)
+				}
+			}
+		})
+	}
+
+	t.Run("unregister", func(t *testing.T) {
+		core.UnregisterSeedGenerator("TEST_IBAN") // should be case-insensitive
+
+		records := core.MultiplyArchetypes(archetypes, fields, 1)
+		if v := records[0]["TEST_IBAN"]; v != "{{IBAN}}" {
+			t.Fatalf("Expected the IBAN generator to be unregistered, got %v", v)
+		}
+	})
+}
+
+func TestGenerateSeedDataFixedFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_seed_fixed_fields")
+	collection.Fields.Add(&core.SelectField{Name: "status", Values: []string{"pending", "paid", "shipped"}, MaxSelect: 1})
+	collection.Fields.Add(&core.NumberField{Name: "amount"})
+	collection.Fields.Add(&core.TextField{Name: "customer"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"status": "paid", "amount": 10.0, "customer": "{{NAME}}"},
+		{"status": "shipped", "amount": 20.0, "customer": "{{NAME}}"},
+	})
+
+	t.Run("invalid fixed fields", func(t *testing.T) {
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 50,
+			FixedFields: map[string]any{
+				"status":  "invalid",
+				"missing": 123,
+			},
+		})
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		fixedErrs, ok := errs["fixedFields"].(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected fixedFields validation errors, got %v", errs)
+		}
+
+		for _, name := range []string{"status", "missing"} {
+			if _, ok := fixedErrs[name]; !ok {
+				t.Fatalf("Expected %q validation error, got %v", name, fixedErrs)
+			}
+		}
+	})
+
+	t.Run("valid fixed fields", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 50,
+			FixedFields: map[string]any{
+				"status": "pending",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 50 {
+			t.Fatalf("Expected 50 records, got %d", len(records))
+		}
+
+		amounts := map[any]struct{}{}
+		customers := map[any]struct{}{}
+		for i, record := range records {
+			if record["status"] != "pending" {
+				t.Fatalf("[%d] Expected status to be fixed to pending, got %v", i, record["status"])
+			}
+			amounts[record["amount"]] = struct{}{}
+			customers[record["customer"]] = struct{}{}
+		}
+
+		if len(amounts) < 2 {
+			t.Fatalf("Expected varied amounts, got %v", amounts)
+		}
+		if len(customers) < 2 {
+			t.Fatalf("Expected varied customers, got %v", customers)
+		}
+	})
+}
+
+func TestGenerateSeedDataNumberDistributions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_seed_number_distributions")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.NumberField{Name: "price", Min: types.Pointer(0.0), Max: types.Pointer(1000.0)})
+	collection.Fields.Add(&core.NumberField{Name: "age"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}}", "price": 10.0, "age": 20.0},
+	})
+
+	t.Run("invalid distributions", func(t *testing.T) {
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 50,
+			NumberDistributions: map[string]core.SeedNumberDistribution{
+				"missing": {Type: core.SeedNumberDistributionUniform},
+				"title":   {Type: core.SeedNumberDistributionUniform},
+				"price":   {Type: core.SeedNumberDistributionNormal, Mean: 100},
+				"age":     {Type: "invalid"},
+			},
+		})
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		distErrs, ok := errs["numberDistributions"].(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected numberDistributions validation errors, got %v", errs)
+		}
+
+		for _, name := range []string{"missing", "title", "price", "age"} {
+			if _, ok := distErrs[name]; !ok {
+				t.Fatalf("Expected %q validation error, got %v", name, distErrs)
+			}
+		}
+	})
+
+	t.Run("normal distribution", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 1000,
+			NumberDistributions: map[string]core.SeedNumberDistribution{
+				"price": {Type: core.SeedNumberDistributionNormal, Mean: 500, StdDev: 20},
+				"age":   {Type: core.SeedNumberDistributionNormal, Mean: 40, StdDev: 100},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sum float64
+		var withinStdDev, negativeAges int
+		for i, record := range records {
+			price, ok := record["price"].(float64)
+			if !ok {
+				t.Fatalf("[%d] Expected float64 price, got %T", i, record["price"])
+			}
+			if price < 0 || price > 1000 {
+				t.Fatalf("[%d] Expected price within the field constraints, got %v", i, price)
+			}
+			sum += price
+			if math.Abs(price-500) <= 20 {
+				withinStdDev++
+			}
+
+			if age, _ := record["age"].(float64); age < 0 {
+				negativeAges++
+			}
+		}
+
+		if mean := sum / float64(len(records)); math.Abs(mean-500) > 5 {
+			t.Fatalf("Expected the values to cluster around 500, got mean %v", mean)
+		}
+
+		// ~68% of the normally distributed values are within 1 standard deviation
+		if ratio := float64(withinStdDev) / float64(len(records)); ratio < 0.6 || ratio > 0.76 {
+			t.Fatalf("Expected ~68%% of the values within 1 stdDev, got %v", ratio)
+		}
+
+		// the unconstrained field values should not be clamped
+		if negativeAges == 0 {
+			t.Fatal("Expected some negative ages for the unconstrained field")
+		}
+	})
+
+	t.Run("exponential distribution", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 1000,
+			NumberDistributions: map[string]core.SeedNumberDistribution{
+				"price": {Type: core.SeedNumberDistributionExponential, Mean: 50},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sum float64
+		var belowMean int
+		for _, record := range records {
+			price := record["price"].(float64)
+			sum += price
+			if price < 50 {
+				belowMean++
+			}
+		}
+
+		if mean := sum / float64(len(records)); math.Abs(mean-50) > 7 {
+			t.Fatalf("Expected mean ~50, got %v", mean)
+		}
+
+		// ~63% of the exponentially distributed values are below the mean
+		if ratio := float64(belowMean) / float64(len(records)); ratio < 0.55 || ratio > 0.71 {
+			t.Fatalf("Expected ~63%% of the values below the mean, got %v", ratio)
+		}
+	})
+}
+
+func TestGenerateSeedDataOnlyIntNumbers(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_seed_only_int")
+	collection.Fields.Add(&core.NumberField{Name: "quantity", OnlyInt: true, Min: types.Pointer(1.0), Max: types.Pointer(10.0)})
+	collection.Fields.Add(&core.NumberField{Name: "rating", OnlyInt: true, Min: types.Pointer(1.0), Max: types.Pointer(5.0)})
+	collection.Fields.Add(&core.NumberField{Name: "price"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"quantity": 3.0, "rating": 4.0, "price": 9.99},
+	})
+
+	records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+		Count: 500,
+		NumberDistributions: map[string]core.SeedNumberDistribution{
+			"rating": {Type: core.SeedNumberDistributionNormal, Mean: 3, StdDev: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fractionalPrices int
+	for i, record := range records {
+		scenarios := []struct {
+			field string
+			min   float64
+			max   float64
+		}{
+			{"quantity", 1, 10},
+			{"rating", 1, 5},
+		}
+		for _, s := range scenarios {
+			value, _ := record[s.field].(float64)
+			if value != math.Trunc(value) || value < s.min || value > s.max {
+				t.Fatalf("[%d] Expected %s integer within [%v, %v], got %v", i, s.field, s.min, s.max, record[s.field])
+			}
+		}
+
+		if price, _ := record["price"].(float64); price != math.Trunc(price) {
+			fractionalPrices++
+		}
+	}
+
+	if fractionalPrices == 0 {
+		t.Fatal("Expected the non OnlyInt field to have fractional values")
+	}
+
+	// ensure that the records could be saved
+	record := core.NewRecord(collection)
+	record.Load(records[0])
+	if err := app.Save(record); err != nil {
+		t.Fatalf("Failed to save the seed record: %v", err)
+	}
+}
+
+func TestGenerateSeedDataDateFields(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	fieldMin, _ := types.ParseDateTime("2020-01-01 00:00:00.000Z")
+	fieldMax, _ := types.ParseDateTime("2020-12-31 00:00:00.000Z")
+
+	collection := core.NewBaseCollection("test_seed_dates")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.DateField{Name: "published_at"})
+	collection.Fields.Add(&core.DateField{Name: "event_at", Min: fieldMin, Max: fieldMax})
+	collection.Fields.Add(&core.DateField{Name: "plain"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	fixedDate := "2019-06-15 10:30:00.000Z"
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "a", "published_at": fixedDate, "event_at": fixedDate, "plain": fixedDate},
+	})
+
+	now := types.NowDateTime()
+	lastDays := now.AddDate(0, 0, -90)
+
+	dateRanges := map[string]core.SeedDateRange{
+		"published_at": {Min: lastDays},
+	}
+
+	assertDates := func(t *testing.T, records []map[string]any, minDistinct int) {
+		scenarios := []struct {
+			field string
+			min   types.DateTime
+			max   types.DateTime
+		}{
+			{"published_at", lastDays.Add(-time.Millisecond), now},
+			{"event_at", fieldMin, fieldMax},
+			{"plain", now.AddDate(-2, 0, -1), now},
+		}
+
+		for _, s := range scenarios {
+			distinct := map[string]struct{}{}
+
+			for i, record := range records {
+				date, err := types.ParseDateTime(record[s.field])
+				if err != nil || date.IsZero() || date.Before(s.min) || date.After(s.max) {
+					t.Fatalf("[%d] Expected %s within %s - %s, got %v", i, s.field, s.min, s.max, record[s.field])
+				}
+				distinct[date.String()] = struct{}{}
+			}
+
+			if len(distinct) < minDistinct {
+				t.Fatalf("Expected at least %d distinct %s dates, got %d", minDistinct, s.field, len(distinct))
+			}
+		}
+	}
+
+	t.Run("hybrid", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:      100,
+			DateRanges: dateRanges,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertDates(t, records, 50)
+
+		// ensure that the records could be saved
+		record := core.NewRecord(collection)
+		record.Load(records[0])
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save the seed record: %v", err)
+		}
+	})
+
+	t.Run("pure AI", func(t *testing.T) {
+		app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeChatTransport{content: `{"records":[
+			{"title":"a","published_at":"` + fixedDate + `","event_at":"2020-05-01 00:00:00.000Z","plain":"` + now.String() + `"},
+			{"title":"b","published_at":"invalid","event_at":"` + fixedDate + `","plain":"` + now.String() + `"}
+		]}`})
+
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:      2,
+			DateRanges: dateRanges,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertDates(t, records, 1)
+
+		// the values within the range are left untouched
+		if records[0]["event_at"] != "2020-05-01 00:00:00.000Z" {
+			t.Fatalf("Expected the in range event_at to be unchanged, got %v", records[0]["event_at"])
+		}
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		scenarios := []struct {
+			name   string
+			ranges map[string]core.SeedDateRange
+		}{
+			{"unknown field", map[string]core.SeedDateRange{"missing": {Min: lastDays}}},
+			{"non date field", map[string]core.SeedDateRange{"title": {Min: lastDays}}},
+			{"empty range", map[string]core.SeedDateRange{"published_at": {}}},
+			{"max before min", map[string]core.SeedDateRange{"published_at": {Min: now, Max: lastDays}}},
+			{"outside of the field bounds", map[string]core.SeedDateRange{"event_at": {Min: lastDays}}},
+		}
+
+		for _, s := range scenarios {
+			t.Run(s.name, func(t *testing.T) {
+				_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+					Count:      100,
+					DateRanges: s.ranges,
+				})
+
+				var errs validation.Errors
+				if !errors.As(err, &errs) || errs["dateRanges"] == nil {
+					t.Fatalf("Expected dateRanges validation error, got %v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestGenerateSeedDataInvalidFormats(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_seed_formats")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.EmailField{Name: "email"})
+	collection.Fields.Add(&core.EmailField{Name: "work_email", OnlyDomains: []string{"example.org"}})
+	collection.Fields.Add(&core.URLField{Name: "website", ExceptDomains: []string{"blocked.com"}})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeChatTransport{content: `{"records":[
+		{"title":"a","email":"valid@example.com","work_email":"valid@example.org","website":"https://example.com"},
+		{"title":"b","email":"not an email","work_email":"other@example.com","website":"https://blocked.com"},
+		{"title":"c","email":"missing.domain@","work_email":"","website":"invalid url"}
+	]}`})
+
+	records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	// the valid values are left untouched
+	expectedValid := map[string]any{
+		"email":      "valid@example.com",
+		"work_email": "valid@example.org",
+		"website":    "https://example.com",
+	}
+	for field, expected := range expectedValid {
+		if records[0][field] != expected {
+			t.Fatalf("Expected the valid %s value %q to be unchanged, got %v", field, expected, records[0][field])
+		}
+	}
+
+	invalid := []struct {
+		index int
+		field string
+		value string
+	}{
+		{1, "email", "not an email"},
+		{1, "work_email", "other@example.com"},
+		{1, "website", "https://blocked.com"},
+		{2, "email", "missing.domain@"},
+		{2, "website", "invalid url"},
+	}
+	for _, s := range invalid {
+		if records[s.index][s.field] == s.value {
+			t.Fatalf("[%d] Expected the invalid %s value %q to be regenerated", s.index, s.field, s.value)
+		}
+	}
+
+	if workEmail, _ := records[1]["work_email"].(string); !strings.HasSuffix(workEmail, "@example.org") {
+		t.Fatalf("Expected the regenerated work_email to be within the allowed domains, got %q", workEmail)
+	}
+
+	// the empty optional values are valid
+	if records[2]["work_email"] != "" {
+		t.Fatalf("Expected the empty work_email to be unchanged, got %v", records[2]["work_email"])
+	}
+
+	// ensure that the records could be saved
+	for i, data := range records {
+		record := core.NewRecord(collection)
+		record.Load(data)
+		if err := app.Save(record); err != nil {
+			t.Fatalf("[%d] Failed to save the seed record %v: %v", i, data, err)
+		}
+	}
+}
+
+func TestGenerateSeedDataSelectCountDistributions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tagsValues := []string{"a", "b", "c", "d", "e"}
+
+	collection := core.NewBaseCollection("test_seed_select_count_distributions")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.SelectField{Name: "tags", Values: tagsValues, MaxSelect: 5})
+	collection.Fields.Add(&core.SelectField{Name: "status", Values: []string{"draft", "published"}, MaxSelect: 1})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}}", "tags": []any{"a", "b", "c"}, "status": "draft"},
+	})
+
+	// countSelections returns the number of records per selections count
+	countSelections := func(t *testing.T, records []map[string]any) map[int]int {
+		counts := map[int]int{}
+		for i, record := range records {
+			tags, ok := record["tags"].([]string)
+			if !ok {
+				t.Fatalf("[%d] Expected []string tags, got %T", i, record["tags"])
+			}
+			for _, tag := range tags {
+				if !slices.Contains(tagsValues, tag) {
+					t.Fatalf("[%d] Unexpected tag %q", i, tag)
+				}
+			}
+			counts[len(tags)]++
+		}
+		return counts
+	}
+
+	t.Run("invalid distributions", func(t *testing.T) {
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 50,
+			SelectCountDistributions: map[string]core.SeedSelectCountDistribution{
+				"missing": {Type: core.SeedSelectCountDistributionUniform},
+				"title":   {Type: core.SeedSelectCountDistributionUniform},
+				"status":  {Type: core.SeedSelectCountDistributionUniform},
+				"tags":    {Type: core.SeedSelectCountDistributionGeometric, Probability: 1.5},
+			},
+		})
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		distErrs, ok := errs["selectCountDistributions"].(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected selectCountDistributions validation errors, got %v", errs)
+		}
+
+		for _, name := range []string{"missing", "title", "status", "tags"} {
+			if _, ok := distErrs[name]; !ok {
+				t.Fatalf("Expected %q validation error, got %v", name, distErrs)
+			}
+		}
+	})
+
+	t.Run("geometric distribution", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 2000,
+			SelectCountDistributions: map[string]core.SeedSelectCountDistribution{
+				"tags": {Type: core.SeedSelectCountDistributionGeometric, Probability: 0.6},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts := countSelections(t, records)
+
+		// ~61% of the records should have a single selection (0.6 normalized over the 1-5 counts)
+		if ratio := float64(counts[1]) / float64(len(records)); ratio < 0.55 || ratio > 0.67 {
+			t.Fatalf("Expected ~61%% of the records with 1 selection, got %v (%v)", ratio, counts)
+		}
+
+		// each extra selection should be less likely
+		for i := 2; i <= 5; i++ {
+			if counts[i] >= counts[i-1] {
+				t.Fatalf("Expected less records with %d than with %d selections, got %v", i, i-1, counts)
+			}
+		}
+	})
+
+	t.Run("weighted distribution", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 1000,
+			SelectCountDistributions: map[string]core.SeedSelectCountDistribution{
+				"tags": {Type: core.SeedSelectCountDistributionWeighted, Weights: []float64{3, 0, 0, 0, 1}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts := countSelections(t, records)
+
+		if counts[1]+counts[5] != len(records) {
+			t.Fatalf("Expected only 1 or 5 selections, got %v", counts)
+		}
+
+		if ratio := float64(counts[1]) / float64(len(records)); ratio < 0.7 || ratio > 0.8 {
+			t.Fatalf("Expected ~75%% of the records with 1 selection, got %v (%v)", ratio, counts)
+		}
+	})
+}
+
+func TestGenerateSeedDataSelectDistributions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_seed_select_distributions")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.SelectField{Name: "status", Values: []string{"active", "pending", "closed"}, MaxSelect: 1})
+	collection.Fields.Add(&core.SelectField{Name: "tags", Values: []string{"a", "b", "c", "d"}, MaxSelect: 4})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}}", "status": "closed", "tags": []any{"a", "b"}},
+	})
+
+	t.Run("invalid distributions", func(t *testing.T) {
+		scenarios := []struct {
+			name          string
+			distributions map[string]map[string]float64
+		}{
+			{"missing field", map[string]map[string]float64{"missing": {"a": 1}}},
+			{"non-select field", map[string]map[string]float64{"title": {"a": 1}}},
+			{"unknown value", map[string]map[string]float64{"status": {"active": 0.5, "unknown": 0.5}}},
+			{"negative proportion", map[string]map[string]float64{"status": {"active": 1.5, "pending": -0.5}}},
+			{"sum less than 1", map[string]map[string]float64{"status": {"active": 0.5, "pending": 0.2}}},
+			{"sum greater than 1", map[string]map[string]float64{"status": {"active": 0.7, "pending": 0.5}}},
+		}
+
+		for _, s := range scenarios {
+			t.Run(s.name, func(t *testing.T) {
+				_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+					Count:         10,
+					Distributions: s.distributions,
+				})
+
+				errs, ok := err.(validation.Errors)
+				if !ok {
+					t.Fatalf("Expected validation.Errors, got %v", err)
+				}
+
+				if _, ok := errs["distributions"]; !ok {
+					t.Fatalf("Expected distributions validation error, got %v", errs)
+				}
+			})
+		}
+	})
+
+	t.Run("single select proportions", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 2000,
+			Distributions: map[string]map[string]float64{
+				"status": {"active": 0.7, "pending": 0.2, "closed": 0.1},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts := map[string]int{}
+		for i, record := range records {
+			status, ok := record["status"].(string)
+			if !ok {
+				t.Fatalf("[%d] Expected string status, got %T", i, record["status"])
+			}
+			counts[status]++
+		}
+
+		expected := map[string]float64{"active": 0.7, "pending": 0.2, "closed": 0.1}
+		for value, target := range expected {
+			if ratio := float64(counts[value]) / float64(len(records)); ratio < target-0.05 || ratio > target+0.05 {
+				t.Fatalf("Expected ~%v of the records with status %q, got %v (%v)", target, value, ratio, counts)
+			}
+		}
+	})
+
+	t.Run("multi-select proportions", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 1000,
+			Distributions: map[string]map[string]float64{
+				"tags": {"a": 0.8, "b": 0.2},
+			},
+			SelectCountDistributions: map[string]core.SeedSelectCountDistribution{
+				"tags": {Type: core.SeedSelectCountDistributionWeighted, Weights: []float64{1}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts := map[string]int{}
+		for i, record := range records {
+			tags, ok := record["tags"].([]string)
+			if !ok || len(tags) != 1 {
+				t.Fatalf("[%d] Expected a single tag, got %v", i, record["tags"])
+			}
+			counts[tags[0]]++
+		}
+
+		if counts["c"] > 0 || counts["d"] > 0 {
+			t.Fatalf("Expected only the tags with a positive proportion, got %v", counts)
+		}
+
+		if ratio := float64(counts["a"]) / float64(len(records)); ratio < 0.75 || ratio > 0.85 {
+			t.Fatalf("Expected ~80%% of the records with tag a, got %v (%v)", ratio, counts)
+		}
+	})
+}
+
+func TestGenerateSeedDataSelectWeights(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_seed_select_weights")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.SelectField{Name: "status", Values: []string{"published", "draft", "archived"}, MaxSelect: 1})
+	collection.Fields.Add(&core.SelectField{Name: "category", Values: []string{"a", "b"}, MaxSelect: 1})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// the archetype status shouldn't skew the weights
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}}", "status": "archived", "category": "a"},
+	})
+
+	t.Run("invalid weights", func(t *testing.T) {
+		scenarios := []struct {
+			name          string
+			weights       map[string]map[string]float64
+			distributions map[string]map[string]float64
+		}{
+			{"missing field", map[string]map[string]float64{"missing": {"a": 1}}, nil},
+			{"non-select field", map[string]map[string]float64{"title": {"a": 1}}, nil},
+			{"unknown value", map[string]map[string]float64{"status": {"unknown": 2}}, nil},
+			{"negative weight", map[string]map[string]float64{"status": {"published": -1}}, nil},
+			{"all zero weights", map[string]map[string]float64{"category": {"a": 0, "b": 0}}, nil},
+			{
+				"combined with a distribution",
+				map[string]map[string]float64{"status": {"published": 2}},
+				map[string]map[string]float64{"status": {"published": 1}},
+			},
+		}
+
+		for _, s := range scenarios {
+			t.Run(s.name, func(t *testing.T) {
+				_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+					Count:         10,
+					SelectWeights: s.weights,
+					Distributions: s.distributions,
+				})
+
+				errs, ok := err.(validation.Errors)
+				if !ok {
+					t.Fatalf("Expected validation.Errors, got %v", err)
+				}
+
+				if _, ok := errs["selectWeights"]; !ok {
+					t.Fatalf("Expected selectWeights validation error, got %v", errs)
+				}
+			})
+		}
+	})
+
+	t.Run("hybrid", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 2000,
+			SelectWeights: map[string]map[string]float64{
+				"status": {"published": 8, "archived": 0.5}, // draft defaults to 1
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts := map[string]int{}
+		for _, record := range records {
+			counts[record["status"].(string)]++
+		}
+
+		expected := map[string]float64{"published": 8 / 9.5, "draft": 1 / 9.5, "archived": 0.5 / 9.5}
+		for value, target := range expected {
+			if ratio := float64(counts[value]) / float64(len(records)); ratio < target-0.04 || ratio > target+0.04 {
+				t.Fatalf("Expected ~%v of the records with status %q, got %v (%v)", target, value, ratio, counts)
+			}
+		}
+	})
+
+	t.Run("pure AI", func(t *testing.T) {
+		app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeChatTransport{content: `{"records":[
+			{"title":"a","status":"archived","category":"a"},
+			{"title":"b","status":"archived","category":"b"},
+			{"title":"c","status":"draft","category":"a"}
+		]}`})
+
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 3,
+			SelectWeights: map[string]map[string]float64{
+				"status": {"archived": 0},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, record := range records {
+			if status := record["status"]; status != "published" && status != "draft" {
+				t.Fatalf("[%d] Expected published or draft status, got %v", i, status)
+			}
+		}
+
+		// the fields without weights are left untouched
+		if records[1]["category"] != "b" {
+			t.Fatalf("Expected the category to be unchanged, got %v", records[1]["category"])
+		}
+	})
+}
+
+// note: not parallel because of the heap usage measurement
+func TestStreamSeedData(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_stream_seed_data")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.EmailField{Name: "email"})
+	collection.Fields.Add(&core.DateField{Name: "published"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	archetypes := []map[string]any{
+		{"title": "a", "email": "same@example.com", "published": "2020-01-01 00:00:00.000Z"},
+		{"title": "b", "email": "same@example.com", "published": "2020-01-01 00:00:00.000Z"},
+		{"title": "c", "email": "same@example.com", "published": "2020-01-01 00:00:00.000Z"},
+	}
+	core.CacheArchetypes(collection, archetypes)
+
+	start, _ := types.ParseDateTime("2024-01-01 00:00:00.000Z")
+	end, _ := types.ParseDateTime("2024-12-31 00:00:00.000Z")
+
+	t.Run("batches", func(t *testing.T) {
+		var batchSizes []int
+		var records []map[string]any
+
+		err := core.StreamSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:              3000,
+			ArchetypeSelection: core.ArchetypeSelectionRoundRobin,
+			TimeSeries:         &core.SeedTimeSeries{Field: "published", Start: start, End: end},
+		}, 250, func(batch []map[string]any) error {
+			batchSizes = append(batchSizes, len(batch))
+			records = append(records, batch...)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(batchSizes) != 12 {
+			t.Fatalf("Expected 12 batches, got %v", batchSizes)
+		}
+		for _, size := range batchSizes {
+			if size != 250 {
+				t.Fatalf("Expected batches with 250 records, got %v", batchSizes)
+			}
+		}
+
+		emails := map[string]struct{}{}
+		var prevPublished string
+		for i, record := range records {
+			// the round-robin order should continue between the batches
+			if expected := archetypes[i%len(archetypes)]["title"]; record["title"] != expected {
+				t.Fatalf("[%d] Expected title %q, got %q", i, expected, record["title"])
+			}
+
+			// the emails should be unique across all batches
+			email := record["email"].(string)
+			if _, ok := emails[email]; ok {
+				t.Fatalf("[%d] Duplicated email %q", i, email)
+			}
+			emails[email] = struct{}{}
+
+			// the time series dates should be ascending across all batches
+			published := record["published"].(string)
+			if published < prevPublished {
+				t.Fatalf("[%d] Expected published %q to be after %q", i, published, prevPublished)
+			}
+			prevPublished = published
+		}
+	})
+
+	t.Run("stop on callback error", func(t *testing.T) {
+		stopErr := errors.New("stop")
+
+		var calls int
+		err := core.StreamSeedData(app, collection, core.GenerateSeedDataRequest{Count: 1000}, 100, func(batch []map[string]any) error {
+			calls++
+			if calls == 3 {
+				return stopErr
+			}
+			return nil
+		})
+		if !errors.Is(err, stopErr) {
+			t.Fatalf("Expected the callback error, got %v", err)
+		}
+
+		if calls != 3 {
+			t.Fatalf("Expected 3 callback calls, got %d", calls)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		err := core.StreamSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:      100,
+			TimeSeries: &core.SeedTimeSeries{Field: "title", Start: start, End: end},
+		}, 10, func(batch []map[string]any) error {
+			t.Fatal("Expected no generated batches")
+			return nil
+		})
+		if _, ok := err.(validation.Errors); !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+	})
+
+	t.Run("bounded memory", func(t *testing.T) {
+		memCollection := core.NewBaseCollection("test_stream_seed_data_memory")
+		memCollection.Fields.Add(&core.TextField{Name: "title"})
+		memCollection.Fields.Add(&core.TextField{Name: "bio"})
+		if err := app.Save(memCollection); err != nil {
+			t.Fatal(err)
+		}
+
+		core.CacheArchetypes(memCollection, []map[string]any{
+			{"title": "{{NAME}}", "bio": "Lorem ipsum dolor sit amet, consectetur adipiscing elit."},
+		})
+
+		var stats runtime.MemStats
+		heapAlloc := func() int64 {
+			runtime.GC()
+			runtime.ReadMemStats(&stats)
+			return int64(stats.HeapAlloc)
+		}
+
+		// the whole set of 100k records takes ~40MB
+		const count = 100000
+		const maxGrowth = 8 << 20
+
+		base := heapAlloc()
+
+		var total, batches int
+		var peak int64
+		err := core.StreamSeedData(app, memCollection, core.GenerateSeedDataRequest{Count: count}, 500, func(batch []map[string]any) error {
+			total += len(batch)
+			batches++
+			if batches%20 == 0 {
+				peak = max(peak, heapAlloc()-base)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if total != count {
+			t.Fatalf("Expected %d records, got %d", count, total)
+		}
+
+		if peak > maxGrowth {
+			t.Fatalf("Expected the heap to grow with less than %d bytes, got %d", maxGrowth, peak)
+		}
+	})
+}
+
+func TestGenerateSeedDataTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_seed_time_series")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.DateField{Name: "published"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}}", "published": "2020-01-01 00:00:00.000Z"},
+	})
+
+	// 2024-01-01 is a Monday so that the range spans exactly 8 weeks
+	start, _ := types.ParseDateTime("2024-01-01 00:00:00.000Z")
+	end, _ := types.ParseDateTime("2024-02-26 00:00:00.000Z")
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 10,
+			TimeSeries: &core.SeedTimeSeries{
+				Field:        "title",
+				Start:        end,
+				End:          start,
+				Distribution: "invalid",
+			},
+		})
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		tsErrs, ok := errs["timeSeries"].(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected timeSeries validation errors, got %v", errs)
+		}
+
+		for _, name := range []string{"field", "end", "distribution"} {
+			if _, ok := tsErrs[name]; !ok {
+				t.Fatalf("Expected %q validation error, got %v", name, tsErrs)
+			}
+		}
+	})
+
+	t.Run("valid options", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:      100,
+			TimeSeries: &core.SeedTimeSeries{Field: "published", Start: start, End: end},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dates := seedTimeSeriesDates(t, records, "published")
+		if len(dates) != 100 {
+			t.Fatalf("Expected 100 dates, got %d", len(dates))
+		}
+
+		for i, date := range dates {
+			if date.Before(start.Time()) || date.After(end.Time()) {
+				t.Fatalf("[%d] Expected %v to be within the range", i, date)
+			}
+			if i > 0 && date.Before(dates[i-1]) {
+				t.Fatalf("[%d] Expected ascending dates, got %v before %v", i, dates[i-1], date)
+			}
+		}
+	})
+
+	distributionScenarios := []struct {
+		distribution string
+		check        func(t *testing.T, dates []time.Time)
+	}{
+		{
+			core.SeedDistributionUniform,
+			func(t *testing.T, dates []time.Time) {
+				first, second := seedTimeSeriesHalves(dates, start.Time(), end.Time())
+				ratio := float64(second) / float64(first)
+				if ratio < 0.8 || ratio > 1.25 {
+					t.Fatalf("Expected evenly spread halves, got %d and %d", first, second)
+				}
+			},
+		},
+		{
+			core.SeedDistributionIncreasing,
+			func(t *testing.T, dates []time.Time) {
+				// with linearly increasing density the second half should have ~3x more dates
+				first, second := seedTimeSeriesHalves(dates, start.Time(), end.Time())
+				ratio := float64(second) / float64(first)
+				if ratio < 2 {
+					t.Fatalf("Expected the second half to have significantly more dates, got %d and %d", first, second)
+				}
+			},
+		},
+		{
+			core.SeedDistributionSeasonal,
+			func(t *testing.T, dates []time.Time) {
+				var weekdays, weekends int
+				for _, date := range dates {
+					if day := date.Weekday(); day == time.Saturday || day == time.Sunday {
+						weekends++
+					} else {
+						weekdays++
+					}
+				}
+
+				weekdayAvg := float64(weekdays) / 5
+				weekendAvg := float64(weekends) / 2
+				if weekendAvg > weekdayAvg*0.6 {
+					t.Fatalf("Expected lower weekend volume, got %.2f weekend and %.2f weekday per day average", weekendAvg, weekdayAvg)
+				}
+			},
+		},
+	}
+
+	for _, s := range distributionScenarios {
+		t.Run("distribution "+s.distribution, func(t *testing.T) {
+			records := make([]map[string]any, 4000)
+			for i := range records {
+				records[i] = map[string]any{}
+			}
+
+			core.ApplySeedTimeSeries(records, core.SeedTimeSeries{
+				Field:        "published",
+				Start:        start,
+				End:          end,
+				Distribution: s.distribution,
+			}, rand.New(rand.NewSource(1)))
+
+			dates := seedTimeSeriesDates(t, records, "published")
+
+			for i, date := range dates {
+				if date.Before(start.Time()) || date.After(end.Time()) {
+					t.Fatalf("[%d] Expected %v to be within the range", i, date)
+				}
+				if i > 0 && date.Before(dates[i-1]) {
+					t.Fatalf("[%d] Expected ascending dates, got %v before %v", i, dates[i-1], date)
+				}
+			}
+
+			s.check(t, dates)
+		})
+	}
+}
+
+func seedTimeSeriesDates(t *testing.T, records []map[string]any, field string) []time.Time {
+	dates := make([]time.Time, 0, len(records))
+
+	for i, record := range records {
+		dt, err := types.ParseDateTime(record[field])
+		if err != nil || dt.IsZero() {
+			t.Fatalf("[%d] Expected valid %q date, got %v", i, field, record[field])
+		}
+		dates = append(dates, dt.Time())
+	}
+
+	return dates
+}
+
+func seedTimeSeriesHalves(dates []time.Time, start, end time.Time) (int, int) {
+	middle := start.Add(end.Sub(start) / 2)
+
+	var first, second int
+	for _, date := range dates {
+		if date.Before(middle) {
+			first++
+		} else {
+			second++
+		}
+	}
+
+	return first, second
+}
+
+func TestGenerateSeedDataForPersonas(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	authors := core.NewBaseCollection("test_personas_authors")
+	authors.Fields.Add(&core.TextField{Name: "name"})
+	authors.Fields.Add(&core.EmailField{Name: "email"})
+	if err := app.Save(authors); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles := core.NewBaseCollection("test_personas_profiles")
+	profiles.Fields.Add(&core.TextField{Name: "bio"})
+	profiles.Fields.Add(&core.TextField{Name: "contact"})
+	if err := app.Save(profiles); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(authors, []map[string]any{
+		{"name": "{{NAME}}", "email": "{{EMAIL}}"},
+	})
+	core.CacheArchetypes(profiles, []map[string]any{
+		{"bio": "Hi, I'm {{NAME}} from {{CITY}}", "contact": "{{EMAIL}}"},
+		{"bio": "{{NAME}} works at {{COMPANY}}", "contact": "{{PHONE}}"},
+	})
+
+	personas := core.GenerateSeedPersonas(5, nil)
+	if len(personas) != 5 {
+		t.Fatalf("Expected 5 personas, got %d", len(personas))
+	}
+
+	result, err := core.GenerateSeedDataForPersonas(app, []*core.Collection{authors, profiles}, personas, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorRecords := result[authors.Id]
+	profileRecords := result[profiles.Id]
+	if len(authorRecords) != len(personas) || len(profileRecords) != len(personas) {
+		t.Fatalf("Expected %d records per collection, got %d and %d", len(personas), len(authorRecords), len(profileRecords))
+	}
+
+	for i, persona := range personas {
+		if authorRecords[i]["name"] != persona["NAME"] {
+			t.Fatalf("[%d] Expected author name %q, got %v", i, persona["NAME"], authorRecords[i]["name"])
+		}
+		if authorRecords[i]["email"] != persona["EMAIL"] {
+			t.Fatalf("[%d] Expected author email %q, got %v", i, persona["EMAIL"], authorRecords[i]["email"])
+		}
+
+		bio, _ := profileRecords[i]["bio"].(string)
+		if !strings.Contains(bio, persona["NAME"]) {
+			t.Fatalf("[%d] Expected profile bio to contain %q, got %q", i, persona["NAME"], bio)
+		}
+	}
+
+	t.Run("seeded", func(t *testing.T) {
+		core.CacheArchetypes(profiles, []map[string]any{
+			{"bio": "{{TITLE}} in {{CITY}}", "contact": "{{EMAIL}}"},
+		})
+
+		seed := int64(42)
+
+		generate := func() ([]core.SeedPersona, map[string][]map[string]any) {
+			personas := core.GenerateSeedPersonas(3, &seed)
+
+			result, err := core.GenerateSeedDataForPersonas(app, []*core.Collection{profiles}, personas, "", &seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return personas, result
+		}
+
+		personas1, result1 := generate()
+		personas2, result2 := generate()
+
+		if !reflect.DeepEqual(personas1, personas2) {
+			t.Fatalf("Expected identical seeded personas, got\n%v\n%v", personas1, personas2)
+		}
+
+		if !reflect.DeepEqual(result1, result2) {
+			t.Fatalf("Expected identical seeded records, got\n%v\n%v", result1, result2)
+		}
+	})
+}
+
+func TestMultiplyArchetypesUniqueEmailsAndURLs(t *testing.T) {
+	t.Parallel()
+
+	fields := []core.SeedFieldInfo{
+		{Name: "email", Type: core.FieldTypeEmail},
+		{Name: "contact", Type: core.FieldTypeEmail},
+		{Name: "website", Type: core.FieldTypeURL},
+		{Name: "homepage", Type: core.FieldTypeURL},
+	}
+
+	archetypes := []map[string]any{
+		{
+			"email":    "{{EMAIL}}",
+			"contact":  "same@example.com",
+			"website":  "{{URL}}",
+			"homepage": "https://example.com/",
+		},
+	}
+
+	count := 100000
+
+	records := core.MultiplyArchetypes(archetypes, fields, count)
+	if len(records) != count {
+		t.Fatalf("Expected %d records, got %d", count, len(records))
+	}
+
+	for _, field := range fields {
+		seen := make(map[string]struct{}, count)
+		for i, record := range records {
+			value, _ := record[field.Name].(string)
+			if value == "" {
+				t.Fatalf("[%s:%d] Expected non-empty value", field.Name, i)
+			}
+
+			key := strings.ToLower(value)
+			if _, ok := seen[key]; ok {
+				t.Fatalf("[%s:%d] Duplicated value %q", field.Name, i, value)
+			}
+			seen[key] = struct{}{}
+		}
+	}
+
+	// the suffix is appended to the email local part and url path
+	for i, record := range records {
+		contact := record["contact"].(string)
+		if !strings.HasPrefix(contact, "same") || !strings.HasSuffix(contact, "@example.com") {
+			t.Fatalf("[%d] Expected the contact email to be suffixed in its local part, got %q", i, contact)
+		}
+
+		homepage := record["homepage"].(string)
+		if !strings.HasPrefix(homepage, "https://example.com/") {
+			t.Fatalf("[%d] Expected the homepage url to be suffixed in its path, got %q", i, homepage)
+		}
+	}
+}
+
+func TestGenerateSeedDataUniqueIndexes(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_seed_unique")
+	collection.Fields.Add(&core.TextField{Name: "slug"})
+	collection.Fields.Add(&core.TextField{Name: "country"})
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.AddIndex("idx_test_seed_unique_slug", true, "slug", "")
+	collection.AddIndex("idx_test_seed_unique_country", true, "country", "")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := core.NewRecord(collection)
+	existing.Set("slug", "fixed-slug")
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	suffixRegex := regexp.MustCompile(`-\d+$`)
+
+	assertUnique := func(t *testing.T, records []map[string]any, field string) {
+		seen := map[string]struct{}{"fixed-slug": {}}
+		for i, record := range records {
+			value, _ := record[field].(string)
+			if value == "" {
+				continue
+			}
+			if _, ok := seen[strings.ToLower(value)]; ok {
+				t.Fatalf("[%s:%d] Duplicated value %q", field, i, value)
+			}
+			seen[strings.ToLower(value)] = struct{}{}
+		}
+	}
+
+	saveAll := func(t *testing.T, records []map[string]any) {
+		err := app.RunInTransaction(func(txApp core.App) error {
+			for _, data := range records {
+				record := core.NewRecord(collection)
+				record.Load(data)
+				if err := txApp.Save(record); err != nil {
+					return err
+				}
+			}
+			return errors.New("rollback")
+		})
+		if err != nil && err.Error() != "rollback" {
+			t.Fatalf("Failed to insert the seed records: %v", err)
+		}
+	}
+
+	t.Run("hybrid", func(t *testing.T) {
+		core.CacheArchetypes(collection, []map[string]any{
+			{"slug": "fixed-slug", "country": "{{COUNTRY}}", "title": "same"},
+			{"slug": "{{USERNAME}}", "country": "{{COUNTRY}}", "title": "same"},
+		})
+
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:              200,
+			ArchetypeSelection: core.ArchetypeSelectionRoundRobin,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertUnique(t, records, "slug")
+		assertUnique(t, records, "country")
+
+		// the non-unique fields are left as they are
+		for i, record := range records {
+			if record["title"] != "same" {
+				t.Fatalf("[%d] Expected the non-unique title to not be changed, got %v", i, record["title"])
+			}
+		}
+
+		// the constant values are suffixed
+		if records[0]["slug"] != "fixed-slug-2" {
+			t.Fatalf("Expected the first fixed slug to be suffixed, got %v", records[0]["slug"])
+		}
+
+		// the placeholder values are regenerated instead of suffixed
+		// (the countries are only ~250 so collisions are expected)
+		var suffixed int
+		for _, record := range records {
+			if suffixRegex.MatchString(record["country"].(string)) {
+				suffixed++
+			}
+		}
+		if suffixed > len(records)/2 {
+			t.Fatalf("Expected most of the countries to be regenerated, got %d suffixed", suffixed)
+		}
+
+		saveAll(t, records)
+	})
+
+	t.Run("pure AI", func(t *testing.T) {
+		app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeChatTransport{content: `{"records":[
+			{"slug":"fixed-slug","country":"a"},
+			{"slug":"b","country":"a"},
+			{"slug":"B","country":"c"}
+		]}`})
+
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{Count: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"fixed-slug-2", "b", "B-2"}
+		for i, record := range records {
+			if record["slug"] != expected[i] {
+				t.Fatalf("[%d] Expected slug %q, got %v", i, expected[i], record["slug"])
+			}
+		}
+		assertUnique(t, records, "country")
+
+		saveAll(t, records)
+	})
+}
+
+func TestMultiplyArchetypesWithRand(t *testing.T) {
+	t.Parallel()
+
+	fields := []core.SeedFieldInfo{
+		{Name: "amount", Type: core.FieldTypeNumber, Min: 1, Max: 100},
+		{Name: "status", Type: core.FieldTypeSelect, Values: []string{"a", "b", "c", "d"}, MaxSelect: 1},
+		{Name: "tags", Type: core.FieldTypeSelect, Values: []string{"x", "y", "z"}, MaxSelect: 3},
+		{Name: "active", Type: core.FieldTypeBool},
+	}
+
+	archetypes := []map[string]any{
+		{"amount": 1.0, "status": "a", "tags": []any{"x"}, "active": true},
+		{"amount": 2.0, "status": "b", "tags": []any{"y"}, "active": false},
+	}
+
+	// both the sequential and the parallel paths
+	for _, count := range []int{100, 2000} {
+		t.Run(fmt.Sprintf("count_%d", count), func(t *testing.T) {
+			a := core.MultiplyArchetypesWithRand(archetypes, fields, count, rand.New(rand.NewSource(123)))
+			b := core.MultiplyArchetypesWithRand(archetypes, fields, count, rand.New(rand.NewSource(123)))
+			c := core.MultiplyArchetypesWithRand(archetypes, fields, count, rand.New(rand.NewSource(456)))
+
+			if len(a) != count || len(b) != count || len(c) != count {
+				t.Fatalf("Expected %d records, got %d, %d and %d", count, len(a), len(b), len(c))
+			}
+
+			if fmt.Sprint(a) != fmt.Sprint(b) {
+				t.Fatal("Expected the same records for the same seed")
+			}
+
+			if fmt.Sprint(a) == fmt.Sprint(c) {
+				t.Fatal("Expected different records for different seeds")
+			}
+
+			for i, r := range a {
+				amount, _ := r["amount"].(float64)
+				if amount < 1 || amount > 100 {
+					t.Fatalf("[%d] Expected amount within 1-100, got %v", i, r["amount"])
+				}
+			}
+		})
+	}
+}
+
+func TestMultiplyArchetypesRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	fields := []core.SeedFieldInfo{
+		{Name: "label", Type: core.FieldTypeText},
+		{Name: "amount", Type: core.FieldTypeNumber, Min: 1, Max: 100},
+	}
+
+	archetypes := []map[string]any{
+		{"label": "first", "amount": 1.0},
+		{"label": "second", "amount": 2.0},
+		{"label": "third", "amount": 3.0},
+	}
+
+	// both the sequential and the parallel paths
+	for _, count := range []int{10, 2000} {
+		t.Run(fmt.Sprintf("count_%d", count), func(t *testing.T) {
+			records := core.MultiplyArchetypesWithSelection(archetypes, fields, count, rand.New(rand.NewSource(123)), core.ArchetypeSelectionRoundRobin)
+
+			if len(records) != count {
+				t.Fatalf("Expected %d records, got %d", count, len(records))
+			}
+
+			for i, r := range records {
+				expected := archetypes[i%len(archetypes)]["label"]
+				if r["label"] != expected {
+					t.Fatalf("[%d] Expected archetype %q, got %q", i, expected, r["label"])
+				}
+			}
+
+			again := core.MultiplyArchetypesWithSelection(archetypes, fields, count, rand.New(rand.NewSource(123)), core.ArchetypeSelectionRoundRobin)
+			if fmt.Sprint(records) != fmt.Sprint(again) {
+				t.Fatal("Expected the same records for the same seed")
+			}
+		})
+	}
+}
+
+func TestMultiplyArchetypesSelectCasing(t *testing.T) {
+	t.Parallel()
+
+	fields := []core.SeedFieldInfo{
+		{Name: "status", Type: core.FieldTypeSelect, Values: []string{"draft", "published"}, MaxSelect: 1},
+		{Name: "tags", Type: core.FieldTypeSelect, Values: []string{"Go", "SQLite", "web"}, MaxSelect: 2},
+		{Name: "unknown", Type: core.FieldTypeSelect, Values: []string{"a", "b"}, MaxSelect: 1},
+	}
+
+	archetypes := []map[string]any{
+		{"status": "Published", "tags": []any{"GO", " sqlite ", "go", "WEB"}, "unknown": "c"},
+	}
+
+	records := core.MultiplyArchetypesWithRand(archetypes, fields, 50, rand.New(rand.NewSource(123)))
+
+	for i, r := range records {
+		if r["status"] != "published" {
+			t.Fatalf("[%d] Expected status %q, got %v", i, "published", r["status"])
+		}
+
+		if tags := fmt.Sprint(r["tags"]); tags != "[Go SQLite]" {
+			t.Fatalf("[%d] Expected tags [Go SQLite], got %s", i, tags)
+		}
+
+		if r["unknown"] != "a" && r["unknown"] != "b" {
+			t.Fatalf("[%d] Expected a random allowed value for the unmatched select, got %v", i, r["unknown"])
+		}
+	}
+
+	// multi-select without any matching value
+	records = core.MultiplyArchetypesWithRand(
+		[]map[string]any{{"tags": []any{"rust"}}},
+		fields,
+		20,
+		rand.New(rand.NewSource(123)),
+	)
+	for i, r := range records {
+		tags, ok := r["tags"].([]string)
+		if !ok || len(tags) == 0 {
+			t.Fatalf("[%d] Expected random allowed tags, got %v", i, r["tags"])
+		}
+		for _, tag := range tags {
+			if !slices.Contains(fields[1].Values, tag) {
+				t.Fatalf("[%d] Expected only allowed tags, got %v", i, tags)
+			}
+		}
+	}
+}
+
+func TestSanitizeSeedRecords(t *testing.T) {
+	t.Parallel()
+
+	fields := []core.SeedFieldInfo{
+		{Name: "title", Type: core.FieldTypeText},
+		{Name: "amount", Type: core.FieldTypeNumber},
+		{Name: "status", Type: core.FieldTypeSelect},
+	}
+
+	records := []map[string]any{
+		{"title": "a", "amount": 1.0, "bogus": "x", "titel": "typo"},
+		{"title": "b", "bogus": "y"},
+	}
+
+	report := core.SanitizeSeedRecords(records, fields)
+
+	if v := strings.Join(report.UnknownFields, ","); v != "bogus,titel" {
+		t.Fatalf("Expected unknown fields bogus,titel, got %v", report.UnknownFields)
+	}
+
+	if v := strings.Join(report.MissingFields, ","); v != "status" {
+		t.Fatalf("Expected missing fields status, got %v", report.MissingFields)
+	}
+
+	for i, record := range records {
+		for _, name := range []string{"bogus", "titel"} {
+			if _, ok := record[name]; ok {
+				t.Fatalf("[%d] Expected %q to be stripped, got %v", i, name, record)
+			}
+		}
+	}
+
+	if v := records[0]["title"]; v != "a" {
+		t.Fatalf("Expected the known fields to remain, got %v", records[0])
+	}
+
+	if report := core.SanitizeSeedRecords(records, fields[:2]); !report.IsEmpty() {
+		t.Fatalf("Expected empty report, got %+v", report)
+	}
+}
+
+func TestGenerateSeedDataStripsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, &fakeChatTransport{
+		content: `{"records":[{"title":"a","bogus":"x"}],"archetypes":[{"title":"a","bogus":"x"}]}`,
+	})
+
+	collection := core.NewBaseCollection("test_seed_unknown_fields")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// pure AI and archetypes
+	for _, count := range []int{1, core.HybridThreshold + 1} {
+		records, err := core.GenerateSeedDataHybrid(app, collection, count, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) == 0 {
+			t.Fatalf("[%d] Expected generated records", count)
+		}
+
+		for i, record := range records {
+			if _, ok := record["bogus"]; ok {
+				t.Fatalf("[%d:%d] Expected the bogus field to be stripped, got %v", count, i, record)
+			}
+			if _, ok := record["title"]; !ok {
+				t.Fatalf("[%d:%d] Expected the title field, got %v", count, i, record)
+			}
+		}
+	}
+}
+
+func TestAIOperationModels(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name                string
+		schemaModel         string
+		seedModel           string
+		expectedSchemaModel string
+		expectedSeedModel   string
+	}{
+		{"fallback to the default model", "", "", "default", "default"},
+		{"schema model only", "schema", "", "schema", "default"},
+		{"seed model only", "", "seed", "default", "seed"},
+		{"schema and seed models", "schema", "seed", "schema", "seed"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			transport := &fakeChatTransport{
+				content: `{"name":"test","fields":[{"name":"title","type":"text"}],"records":[{"title":"a"}],"archetypes":[{"title":"a"}]}`,
+			}
+			app := newTestAIApp(t, transport)
+			app.Settings().AI.Model = "default"
+			app.Settings().AI.SchemaModel = s.schemaModel
+			app.Settings().AI.SeedModel = s.seedModel
+
+			if _, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "test"}); err != nil {
+				t.Fatal(err)
+			}
+
+			collection := core.NewBaseCollection(fmt.Sprintf("test_operation_models_%d", i))
+			collection.Fields.Add(&core.TextField{Name: "title"})
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			// pure AI
+			if _, err := core.GenerateSeedDataHybrid(app, collection, 1, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			// archetypes
+			if _, err := core.GenerateSeedDataHybrid(app, collection, core.HybridThreshold+1, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []string{s.expectedSchemaModel, s.expectedSeedModel, s.expectedSeedModel}
+			if models := transport.Models(); strings.Join(models, ",") != strings.Join(expected, ",") {
+				t.Fatalf("Expected models %v, got %v", expected, models)
+			}
+		})
+	}
+}
+
+func TestAIFinishReason(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		finishReason string
+		expectedErr  error
+	}{
+		{"stop", nil},
+		{"", nil},
+		{"length", core.ErrAIResponseTruncated},
+		{"content_filter", core.ErrAIContentFiltered},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.finishReason), func(t *testing.T) {
+			app := newTestAIApp(t, &fakeChatTransport{
+				content:      `{"name":"test","fields":[{"name":"title","type":"text"}],"records":[{"title":"a"}],"archetypes":[{"title":"a"}]}`,
+				finishReason: s.finishReason,
+			})
+
+			collection := core.NewBaseCollection(fmt.Sprintf("test_finish_reason_%d", i))
+			collection.Fields.Add(&core.TextField{Name: "title"})
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			operations := map[string]func() error{
+				"schema": func() error {
+					_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "test"})
+					return err
+				},
+				"seed": func() error {
+					_, err := core.GenerateSeedDataHybrid(app, collection, 1, "")
+					return err
+				},
+				"archetypes": func() error {
+					_, err := core.GenerateSeedDataHybrid(app, collection, core.HybridThreshold+1, "")
+					return err
+				},
+			}
+
+			for name, operation := range operations {
+				if err := operation(); !errors.Is(err, s.expectedErr) {
+					t.Fatalf("[%s] Expected error %v, got %v", name, s.expectedErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSchemaFromPromptCanceled(t *testing.T) {
+	t.Parallel()
+
+	for _, provider := range []string{core.AIProviderOpenAI, core.AIProviderAnthropic} {
+		t.Run(provider, func(t *testing.T) {
+			transport := &fakeChatTransport{content: `{"name":"test","fields":[{"name":"title","type":"text"}]}`}
+			app := newTestAIApp(t, transport)
+			app.Settings().AI.Provider = provider
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := core.GenerateSchemaFromPromptWithContext(ctx, app, core.GenerateSchemaRequest{Prompt: "test"})
+			if !errors.Is(err, core.ErrAIRequestCanceled) {
+				t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+			}
+
+			if prompts := transport.Prompts(); len(prompts) != 0 {
+				t.Fatalf("Expected no AI requests, got %v", prompts)
+			}
+		})
+	}
+}
+
+func TestGenerateSeedDataConstraints(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_seed_constraints")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.DateField{Name: "start_date"})
+	collection.Fields.Add(&core.DateField{Name: "end_date"})
+	collection.Fields.Add(&core.NumberField{Name: "cost", Min: types.Pointer(1.0), Max: types.Pointer(100.0)})
+	collection.Fields.Add(&core.NumberField{Name: "price", Min: types.Pointer(1.0), Max: types.Pointer(100.0)})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "a", "start_date": "2024-06-01 00:00:00.000Z", "end_date": "2024-06-01 00:00:00.000Z", "cost": 10.0, "price": 5.0},
+	})
+
+	t.Run("invalid constraints", func(t *testing.T) {
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 10,
+			Constraints: []core.SeedConstraint{
+				{Field: "missing", Op: ">", Other: "start_date"},
+				{Field: "end_date", Op: "!=", Other: "start_date"},
+				{Field: "end_date", Op: ">", Other: "cost"},
+				{Field: "title", Op: ">", Other: "title"},
+				{Field: "price", Op: ">", Other: "cost"},
+			},
+			FixedFields: map[string]any{"price": 1},
+		})
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		constraintsErrs, ok := errs["constraints"].(validation.Errors)
+		if !ok || len(constraintsErrs) != 5 {
+			t.Fatalf("Expected 5 constraints validation errors, got %v", errs)
+		}
+	})
+
+	t.Run("valid constraints", func(t *testing.T) {
+		// randomize the end date around the fixed archetype start date
+		start, _ := types.ParseDateTime("2024-01-01 00:00:00.000Z")
+		end, _ := types.ParseDateTime("2024-12-31 00:00:00.000Z")
+
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:      200,
+			TimeSeries: &core.SeedTimeSeries{Field: "end_date", Start: start, End: end},
+			Constraints: []core.SeedConstraint{
+				{Field: "end_date", Op: ">", Other: "start_date"},
+				{Field: "price", Op: ">=", Other: "cost"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 200 {
+			t.Fatalf("Expected 200 records, got %d", len(records))
+		}
+
+		for i, r := range records {
+			startDate, err := types.ParseDateTime(r["start_date"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			endDate, err := types.ParseDateTime(r["end_date"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !endDate.After(startDate) {
+				t.Fatalf("[%d] Expected end_date %s to be after start_date %s", i, endDate, startDate)
+			}
+
+			cost, _ := r["cost"].(float64)
+			price, _ := r["price"].(float64)
+			if price < cost {
+				t.Fatalf("[%d] Expected price %v >= cost %v", i, price, cost)
+			}
+			if price > 100 {
+				t.Fatalf("[%d] Expected price %v within the field limits", i, price)
+			}
+		}
+	})
+}
+
+func TestGenerateSchemaExistingCollectionsContext(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	articles := core.NewBaseCollection("test_schema_context_articles")
+	articles.Fields.Add(&core.TextField{Name: "title"})
+	articles.Fields.Add(&core.TextField{Name: "slug"})
+	if err := app.Save(articles); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name            string
+		include         bool
+		expectedContext bool
+	}{
+		{"without context", false, false},
+		{"with context", true, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			transport := &fakeChatTransport{content: `{"name":"test_schema_context_new","fields":[{"name":"title","type":"text"}]}`}
+			app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+			_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{
+				Prompt:                     "blog categories",
+				CurrentCollection:          "demo1",
+				IncludeExistingCollections: s.include,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prompts := transport.Prompts()
+			if len(prompts) != 1 {
+				t.Fatalf("Expected 1 prompt, got %d", len(prompts))
+			}
+
+			hasContext := strings.Contains(prompts[0], "- test_schema_context_articles [base]: title (text), slug (text)")
+			if hasContext != s.expectedContext {
+				t.Fatalf("Expected existing collections context %v, got prompt:\n%s", s.expectedContext, prompts[0])
+			}
+
+			if s.expectedContext {
+				// the edited collection itself and the system collections shouldn't be included
+				for _, name := range []string{"- demo1 ", "- " + core.CollectionNameSuperusers + " "} {
+					if strings.Contains(prompts[0], name) {
+						t.Fatalf("Didn't expect %q in the prompt:\n%s", name, prompts[0])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSchemaExistingFieldsLimit(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	newFields := func(count int) []core.ExistingField {
+		fields := make([]core.ExistingField, count)
+		for i := range fields {
+			fields[i] = core.ExistingField{Name: fmt.Sprintf("field_%03d", i+1), Type: "text"}
+		}
+		return fields
+	}
+
+	scenarios := []struct {
+		name          string
+		fields        []core.ExistingField
+		expected      []string
+		notExpected   []string
+		maxPromptSize int
+	}{
+		{
+			"under the limit",
+			newFields(5),
+			[]string{"field_001 (text)", "field_005 (text)"},
+			[]string{"more fields not listed"},
+			0,
+		},
+		{
+			"over the limit",
+			newFields(500),
+			[]string{
+				"field_001 (text)",
+				fmt.Sprintf("field_%03d (text)", core.MaxSchemaExistingFields-1),
+				"field_321 (text)", // mentioned in the prompt
+				fmt.Sprintf("and %d more fields not listed here", 500-core.MaxSchemaExistingFields),
+			},
+			[]string{
+				fmt.Sprintf("field_%03d (text)", core.MaxSchemaExistingFields),
+				"field_500 (text)",
+			},
+			3000,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			transport := &fakeChatTransport{content: `{"name":"demo1","fields":[{"name":"summary","type":"text"}]}`}
+			app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+			_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{
+				Prompt:            "add a summary of field_321",
+				CurrentCollection: "demo1",
+				ExistingFields:    s.fields,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prompts := transport.Prompts()
+			if len(prompts) != 1 {
+				t.Fatalf("Expected 1 prompt, got %d", len(prompts))
+			}
+
+			for _, str := range s.expected {
+				if !strings.Contains(prompts[0], str) {
+					t.Fatalf("Expected %q in the prompt:\n%s", str, prompts[0])
+				}
+			}
+
+			for _, str := range s.notExpected {
+				if strings.Contains(prompts[0], str) {
+					t.Fatalf("Didn't expect %q in the prompt:\n%s", str, prompts[0])
+				}
+			}
+
+			if s.maxPromptSize > 0 && len(prompts[0]) > s.maxPromptSize {
+				t.Fatalf("Expected prompt size up to %d, got %d", s.maxPromptSize, len(prompts[0]))
+			}
+		})
+	}
+}
+
+func TestDiffSchemaFields(t *testing.T) {
+	t.Parallel()
+
+	collection := core.NewBaseCollection("test")
+	collection.Fields.Add(&core.TextField{Name: "summary"})
+	collection.Fields.Add(&core.TextField{Name: "Title"})
+	collection.Fields.Add(&core.NumberField{Name: "status"})
+	collection.Fields.Add(&core.BoolField{Name: "featured"})
+
+	existing := []core.ExistingField{
+		{Name: "id", Type: "text"},
+		{Name: "title", Type: "text"},
+		{Name: "status", Type: "select"},
+	}
+
+	diff := core.DiffSchemaFields(existing, collection)
+
+	raw, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"added":[{"name":"summary","type":"text"},{"name":"featured","type":"bool"}],"collisions":[` +
+		`{"name":"Title","type":"text","existingName":"title","existingType":"text","warning":"Field \"title\" already exists in the collection."},` +
+		`{"name":"status","type":"number","existingName":"status","existingType":"select","warning":"Field \"status\" already exists in the collection with a different type (select instead of number)."}]}`
+
+	if str := string(raw); str != expected {
+		t.Fatalf("Expected diff\n%s\ngot\n%s", expected, str)
+	}
+
+	t.Run("no collisions", func(t *testing.T) {
+		diff := core.DiffSchemaFields(nil, collection)
+
+		if len(diff.Added) != 4 || len(diff.Collisions) != 0 {
+			t.Fatalf("Expected 4 added fields and no collisions, got %+v", diff)
+		}
+	})
+}
+
+func TestGenerateSchemaAutoName(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	// no name in the response
+	app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeChatTransport{content: `{"fields":[{"name":"title","type":"text"}]}`})
+
+	scenarios := []struct {
+		name     string
+		req      core.GenerateSchemaRequest
+		expected string
+	}{
+		{
+			"ascii",
+			core.GenerateSchemaRequest{Prompt: "Blog posts - with tags!"},
+			"blog_posts_with_tags",
+		},
+		{
+			"accented",
+			core.GenerateSchemaRequest{Prompt: "Café crème recettes"},
+			"cafe_creme_recettes",
+		},
+		{
+			"cyrillic",
+			core.GenerateSchemaRequest{Prompt: "Список книг"},
+			"spisok_knig",
+		},
+		{
+			"without transliterable characters",
+			core.GenerateSchemaRequest{Prompt: "图书列表"},
+			"collection",
+		},
+		{
+			"with prefix and suffix",
+			core.GenerateSchemaRequest{Prompt: "Über Straße", NamePrefix: "app_", NameSuffix: "_v2"},
+			"app_uber_strasse_v2",
+		},
+		{
+			"long prompt truncated at word boundary",
+			core.GenerateSchemaRequest{Prompt: strings.Repeat("product ", 10)},
+			"product_product_product_product_product_product",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection, err := core.GenerateSchemaFromPrompt(app, s.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if collection.Name != s.expected {
+				t.Fatalf("Expected name %q, got %q", s.expected, collection.Name)
+			}
+		})
+	}
+}
+
+func TestGenerateParseRetries(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_parse_retries")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	generators := []struct {
+		name     string
+		valid    string
+		generate func() error
+	}{
+		{
+			"schema",
+			`{"name":"posts","fields":[{"name":"title","type":"text"}]}`,
+			func() error {
+				_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "posts"})
+				return err
+			},
+		},
+		{
+			"seed",
+			`{"records":[{"title":"a"}]}`,
+			func() error {
+				_, err := core.GenerateSeedDataFromSchema(app, collection, 1, "")
+				return err
+			},
+		},
+		{
+			"archetypes",
+			`{"archetypes":[{"title":"a"}]}`,
+			func() error {
+				// the temperature override bypasses the archetypes cache
+				temperature := 0.5
+				_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+					Count:                core.HybridThreshold + 1,
+					ArchetypeTemperature: &temperature,
+				})
+				return err
+			},
+		},
+	}
+
+	scenarios := []struct {
+		name             string
+		retries          int
+		invalid          []string
+		expectError      bool
+		expectedRequests int
+	}{
+		{"valid first response", core.DefaultAIGenerationParseRetries, nil, false, 1},
+		{"default retries recover", core.DefaultAIGenerationParseRetries, []string{`{"truncated`}, false, 2},
+		{"default retries exhausted", core.DefaultAIGenerationParseRetries, []string{`{"truncated`, "not json"}, true, 2},
+		{"no regeneration", 0, []string{`{"truncated`}, true, 1},
+		{"custom retries recover", 3, []string{`{"truncated`, "not json", "```"}, false, 4},
+		{"custom retries exhausted", 2, []string{`{"truncated`, "not json", "```"}, true, 3},
+	}
+
+	for _, g := range generators {
+		for _, s := range scenarios {
+			t.Run(g.name+"_"+s.name, func(t *testing.T) {
+				app.Settings().AI.GenerationParseRetries = s.retries
+
+				transport := &fakeChatTransport{content: g.valid, responses: slices.Clone(s.invalid)}
+				app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+				err := g.generate()
+
+				hasErr := err != nil
+				if hasErr != s.expectError {
+					t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+				}
+
+				if requests := len(transport.Models()); requests != s.expectedRequests {
+					t.Fatalf("Expected %d completion requests, got %d", s.expectedRequests, requests)
+				}
+			})
+		}
+	}
+}
+
+func TestArchetypeCacheStats(t *testing.T) {
+	t.Parallel()
+
+	cache := core.NewArchetypeCache()
+
+	cache.Set("c1", &core.CachedArchetypes{SchemaHash: "h1"})
+	cache.Set("c2", &core.CachedArchetypes{SchemaHash: "h2"})
+
+	// concurrent lookups to ensure that the counters are safe for concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cache.Get("c1", "h1")      // hit
+			cache.Get("c2", "h2")      // hit
+			cache.Get("c1", "changed") // miss (schema hash mismatch)
+			cache.Get("missing", "h1") // miss
+		}()
+	}
+	wg.Wait()
+
+	cache.Invalidate("c2")
+	cache.Get("c2", "h2") // miss (invalidated)
+
+	stats := cache.Stats()
+
+	expected := map[string]any{
+		"entriesCount": 1,
+		"hits":         int64(20),
+		"misses":       int64(21),
+		"hitRate":      20.0 / 41.0,
+	}
+
+	if !maps.Equal(stats, expected) {
+		t.Fatalf("Expected stats\n%v\ngot\n%v", expected, stats)
+	}
+}
+
+func TestGenerateSeedDataArchetypeTemperature(t *testing.T) {
+	t.Parallel()
+
+	transport := &fakeChatTransport{content: `{"archetypes":[{"title":"a"}]}`}
+	app := newTestAIApp(t, transport)
+
+	collection := core.NewBaseCollection("test_archetype_temperature")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{{"title": "cached"}})
+
+	temperature := func(v float64) *float64 {
+		return &v
+	}
+
+	t.Run("invalid temperature", func(t *testing.T) {
+		for _, v := range []float64{-1, 2.1} {
+			_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+				Count:                core.HybridThreshold + 1,
+				ArchetypeTemperature: temperature(v),
+			})
+
+			errs, ok := err.(validation.Errors)
+			if !ok || errs["archetypeTemperature"] == nil {
+				t.Fatalf("Expected archetypeTemperature validation error for %v, got %v", v, err)
+			}
+		}
+	})
+
+	t.Run("without override", func(t *testing.T) {
+		if _, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{Count: core.HybridThreshold + 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		if temps := transport.Temperatures(); len(temps) != 0 {
+			t.Fatalf("Expected the cached archetypes to be used, got requests with temperatures %v", temps)
+		}
+	})
+
+	t.Run("with override", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:                core.HybridThreshold + 1,
+			ArchetypeTemperature: temperature(0.2),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if temps := transport.Temperatures(); len(temps) != 1 || temps[0] != 0.2 {
+			t.Fatalf("Expected a single archetypes request with 0.2 temperature, got %v", temps)
+		}
+
+		for i, record := range records {
+			if record["title"] == "cached" {
+				t.Fatalf("[%d] Expected the regenerated archetypes to be used", i)
+			}
+		}
+	})
+}
+
+func TestGenerateSeedDataArchetypeSelection(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_archetype_selection")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	archetypes := []map[string]any{{"title": "a"}, {"title": "b"}, {"title": "c"}}
+	core.CacheArchetypes(collection, archetypes)
+
+	t.Run("invalid selection", func(t *testing.T) {
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:              core.HybridThreshold + 1,
+			ArchetypeSelection: "unknown",
+		})
+
+		errs, ok := err.(validation.Errors)
+		if !ok || errs["archetypeSelection"] == nil {
+			t.Fatalf("Expected archetypeSelection validation error, got %v", err)
+		}
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:              core.HybridThreshold + 1,
+			ArchetypeSelection: core.ArchetypeSelectionRoundRobin,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != core.HybridThreshold+1 {
+			t.Fatalf("Expected %d records, got %d", core.HybridThreshold+1, len(records))
+		}
+
+		for i, record := range records {
+			expected := archetypes[i%len(archetypes)]["title"]
+			if record["title"] != expected {
+				t.Fatalf("[%d] Expected title %q, got %q", i, expected, record["title"])
+			}
+		}
+	})
+
+	countTitles := func(records []map[string]any) map[string]int {
+		counts := map[string]int{}
+		for _, record := range records {
+			title, _ := record["title"].(string)
+			counts[title]++
+		}
+		return counts
+	}
+
+	t.Run("round robin remainder", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:              core.HybridThreshold + 3,
+			ArchetypeSelection: core.ArchetypeSelectionRoundRobin,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 23 records -> 7 per archetype and the 2 remaining to the first archetypes
+		expected := map[string]int{"a": 8, "b": 8, "c": 7}
+		if counts := countTitles(records); !maps.Equal(counts, expected) {
+			t.Fatalf("Expected archetype counts %v, got %v", expected, counts)
+		}
+	})
+
+	t.Run("invalid records per archetype", func(t *testing.T) {
+		invalid := []core.GenerateSeedDataRequest{
+			{Count: 10, RecordsPerArchetype: -1},
+			{RecordsPerArchetype: 2, ArchetypeSelection: core.ArchetypeSelectionRandom},
+		}
+
+		for i, req := range invalid {
+			_, err := core.GenerateSeedData(app, collection, req)
+
+			errs, ok := err.(validation.Errors)
+			if !ok || errs["recordsPerArchetype"] == nil {
+				t.Fatalf("[%d] Expected recordsPerArchetype validation error, got %v", i, err)
+			}
+		}
+	})
+
+	recordsPerArchetypeScenarios := []struct {
+		name                string
+		count               int
+		recordsPerArchetype int
+	}{
+		{"records per archetype below the hybrid threshold", 0, 2},
+		{"records per archetype ignoring the count", 5, 10},
+		{"records per archetype with multiple batches", 0, 400},
+	}
+
+	for _, s := range recordsPerArchetypeScenarios {
+		t.Run(s.name, func(t *testing.T) {
+			records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+				Count:               s.count,
+				RecordsPerArchetype: s.recordsPerArchetype,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != len(archetypes)*s.recordsPerArchetype {
+				t.Fatalf("Expected %d records, got %d", len(archetypes)*s.recordsPerArchetype, len(records))
+			}
+
+			expected := map[string]int{
+				"a": s.recordsPerArchetype,
+				"b": s.recordsPerArchetype,
+				"c": s.recordsPerArchetype,
+			}
+			if counts := countTitles(records); !maps.Equal(counts, expected) {
+				t.Fatalf("Expected archetype counts %v, got %v", expected, counts)
+			}
+		})
+	}
+}
+
+func TestGenerateSeedDataSeed(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_seed_data_seed")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.TextField{Name: "slug"})
+	collection.Fields.Add(&core.EmailField{Name: "email"})
+	collection.Fields.Add(&core.NumberField{Name: "price", Min: types.Pointer(0.0), Max: types.Pointer(100.0)})
+	collection.Fields.Add(&core.SelectField{Name: "status", Values: []string{"a", "b", "c"}, MaxSelect: 1})
+	collection.Fields.Add(&core.DateField{Name: "published"})
+	collection.AddIndex("idx_test_seed_data_seed_slug", true, "slug", "")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}} - {{TITLE}}", "slug": "{{USERNAME}}", "email": "{{EMAIL}}", "price": 10.0, "status": "a", "published": "2024-01-01 00:00:00.000Z"},
+		{"title": "{{COMPANY}} in {{CITY}}", "slug": "{{USERNAME}}", "email": "{{EMAIL}}", "price": 50.0, "status": "b", "published": "2024-06-01 00:00:00.000Z"},
+	})
+
+	generate := func(seed *int64, count int) string {
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: count,
+			Seed:  seed,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != count {
+			t.Fatalf("Expected %d records, got %d", count, len(records))
+		}
+
+		raw, err := json.Marshal(records)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(raw)
+	}
+
+	// the sequential and the parallel (> 1000 records) generation
+	for _, count := range []int{core.HybridThreshold + 1, 1500} {
+		t.Run(fmt.Sprintf("count %d", count), func(t *testing.T) {
+			first := generate(types.Pointer(int64(123)), count)
+
+			if second := generate(types.Pointer(int64(123)), count); second != first {
+				t.Fatal("Expected identical records with the same seed")
+			}
+
+			if other := generate(types.Pointer(int64(456)), count); other == first {
+				t.Fatal("Expected different records with a different seed")
+			}
+
+			if unseeded := generate(nil, count); unseeded == first {
+				t.Fatal("Expected different records without seed")
+			}
+		})
+	}
+}
+
+func TestAIMaxResponseSize(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+	app.Settings().AI.MaxResponseSize = 1024
+
+	// valid json padded with whitespaces up to 10MB
+	app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeOversizedTransport{
+		body: `{"choices":[{"message":{"content":"{\"name\":\"test\",\"records\":[]}"}}],"data":[]}`,
+		size: 10 << 20,
+	})
+
+	collection := core.NewBaseCollection("test_max_response_size")
+	collection.Fields.Add(&core.TextField{Name: "title", Embeddable: true})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "test")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedErr := "exceeds the max allowed size of 1024 bytes"
+
+	t.Run("chat", func(t *testing.T) {
+		_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "test"})
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Fatalf("Expected %q error, got %v", expectedErr, err)
+		}
+
+		_, err = core.GenerateSeedDataHybrid(app, collection, 1, "")
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Fatalf("Expected %q error, got %v", expectedErr, err)
+		}
+	})
+
+	t.Run("embeddings", func(t *testing.T) {
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generated != 0 || len(result.Errors) != 1 || !strings.Contains(result.Errors[0], expectedErr) {
+			t.Fatalf("Expected %q error, got %+v", expectedErr, result)
+		}
+	})
+
+	t.Run("within the limit", func(t *testing.T) {
+		app.Settings().AI.MaxResponseSize = 0 // default
+
+		_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: "test"})
+		if err != nil {
+			t.Fatalf("Expected the response to be within the default limit, got %v", err)
+		}
+	})
+}
+
+// fakeOversizedTransport is a fake AI provider that responds
+// with the specified body padded with whitespaces up to size bytes.
+type fakeOversizedTransport struct {
+	body string
+	size int
+}
+
+func (f *fakeOversizedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	padding := max(f.size-len(f.body), 0)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(io.MultiReader(strings.NewReader(f.body), strings.NewReader(strings.Repeat(" ", padding)))),
+		Request:    req,
+	}, nil
+}
+
+func TestGenerateTextLengthLimits(t *testing.T) {
+	t.Parallel()
+
+	transport := &fakeChatTransport{content: `{"name":"posts","fields":[{"name":"title","type":"text"}],"records":[{"title":"a"}]}`}
+	app := newTestAIApp(t, transport)
+
+	collection := core.NewBaseCollection("test_text_length_limits")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// multi-byte characters to ensure that the length is in characters and not in bytes
+	longPrompt := strings.Repeat("ж", core.MaxSchemaPromptLength+1)
+	longDescription := strings.Repeat("ж", core.MaxSeedDescriptionLength+1)
+
+	scenarios := []struct {
+		name     string
+		generate func() error
+	}{
+		{
+			"schema prompt",
+			func() error {
+				_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: longPrompt})
+				return err
+			},
+		},
+		{
+			"seed data description",
+			func() error {
+				_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{Count: 1, Description: longDescription})
+				return err
+			},
+		},
+		{
+			"seed data from schema description",
+			func() error {
+				_, err := core.GenerateSeedDataFromSchema(app, collection, 1, longDescription)
+				return err
+			},
+		},
+		{
+			"hybrid seed data description",
+			func() error {
+				_, err := core.GenerateSeedDataHybrid(app, collection, core.HybridThreshold+1, longDescription)
+				return err
+			},
+		},
+		{
+			"personas seed data description",
+			func() error {
+				_, err := core.GenerateSeedDataForPersonas(app, []*core.Collection{collection}, core.GenerateSeedPersonas(1, nil), longDescription, nil)
+				return err
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.generate()
+			if !errors.Is(err, core.ErrAITextTooLong) {
+				t.Fatalf("Expected ErrAITextTooLong, got %v", err)
+			}
+		})
+	}
+
+	if prompts := transport.Prompts(); len(prompts) != 0 {
+		t.Fatalf("Expected no AI requests, got %d", len(prompts))
+	}
+
+	t.Run("max length", func(t *testing.T) {
+		_, err := core.GenerateSchemaFromPrompt(app, core.GenerateSchemaRequest{Prompt: longPrompt[len("ж"):]})
+		if err != nil {
+			t.Fatalf("Expected the max length prompt to be allowed, got %v", err)
+		}
+
+		_, err = core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{Count: 1, Description: longDescription[len("ж"):]})
+		if err != nil {
+			t.Fatalf("Expected the max length description to be allowed, got %v", err)
+		}
+	})
+}
+
+// fakeChatTransport is a fake chat completions provider that
+// always responds with the same message content.
+type fakeChatTransport struct {
+	mu           sync.Mutex
+	models       []string
+	temperatures []float64
+	prompts      []string
+	content      string
+	finishReason string
+
+	// responses are optional contents returned in order
+	// by the first requests before fallbacking to content
+	responses []string
+}
+
+// Prompts returns the user prompts of all submitted chat completion requests.
+func (f *fakeChatTransport) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.prompts...)
+}
+
+// Temperatures returns the temperatures of all submitted chat completion requests.
+func (f *fakeChatTransport) Temperatures() []float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]float64(nil), f.temperatures...)
+}
+
+// Models returns the models of all submitted chat completion requests.
+func (f *fakeChatTransport) Models() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.models...)
+}
+
+func (f *fakeChatTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body struct {
+		Model       string  `json:"model"`
+		Temperature float64 `json:"temperature"`
+		Messages    []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.models = append(f.models, body.Model)
+	f.temperatures = append(f.temperatures, body.Temperature)
+	for _, message := range body.Messages {
+		if message.Role == "user" {
+			f.prompts = append(f.prompts, message.Content)
+		}
+	}
+	content := f.content
+	if len(f.responses) > 0 {
+		content = f.responses[0]
+		f.responses = f.responses[1:]
+	}
+	f.mu.Unlock()
+
+	raw, err := json.Marshal(map[string]any{
+		"choices": []map[string]any{
+			{"message": map[string]any{"role": "assistant", "content": content}, "finish_reason": f.finishReason},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(raw)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/routine"
+)
+
+// BindEmbeddingsAutoGenerate registers the record create and update hooks that
+// (re)generate in the background the embeddings of the saved records.
+//
+// Only the records of the collections with enabled EmbeddingConfig.AutoGenerate
+// and at least one embeddable field are processed (see [SaveEmbeddingConfig]).
+//
+// The generation doesn't block the record write and its errors are only logged.
+func BindEmbeddingsAutoGenerate(app App) {
+	runner := &embeddingsAutoRunner{app: app, states: map[string]embeddingsAutoState{}}
+
+	handler := &hook.Handler[*RecordEvent]{
+		Id: "__pbEmbeddingsAutoGenerate__",
+		Func: func(e *RecordEvent) error {
+			if shouldAutoGenerateEmbeddings(app, e.Record.Collection()) {
+				runner.schedule(e.Record.Collection().Id, e.Record.Id)
+			}
+
+			return e.Next()
+		},
+	}
+
+	app.OnRecordAfterCreateSuccess().Bind(handler)
+	app.OnRecordAfterUpdateSuccess().Bind(handler)
+}
+
+// shouldAutoGenerateEmbeddings performs the cheap (non db) checks whether
+// the embeddings of the collection records could be auto generated.
+func shouldAutoGenerateEmbeddings(app App, collection *Collection) bool {
+	// skip the system collections (including the embeddings ones)
+	if collection.System || strings.HasPrefix(collection.Name, "_") {
+		return false
+	}
+
+	if !app.Settings().AI.Enabled {
+		return false
+	}
+
+	for _, field := range collection.Fields {
+		if IsAppFieldEmbeddable(app, field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// embeddingsAutoMaxWorkers is the max number of the concurrently
+// processed records of the auto embeddings generation.
+const embeddingsAutoMaxWorkers = 4
+
+// embeddingsAutoState is the auto embeddings generation state of a single record.
+type embeddingsAutoState int
+
+const (
+	embeddingsAutoQueued embeddingsAutoState = iota
+	embeddingsAutoRunning
+	embeddingsAutoRerun
+)
+
+// embeddingsAutoJob identifies a single record of the auto embeddings generation queue.
+type embeddingsAutoJob struct {
+	collectionId string
+	recordId     string
+}
+
+// key returns the unique record key of the job.
+func (j embeddingsAutoJob) key() string {
+	return j.collectionId + "/" + j.recordId
+}
+
+// embeddingsAutoRunner runs the auto embeddings generation of the saved records
+// with a bounded number of workers and at most one queued or active run per record.
+//
+// A record saved again during its active run is queued once
+// more after the run completes (so that the latest changes are embedded).
+type embeddingsAutoRunner struct {
+	app App
+
+	mu      sync.Mutex
+	queue   []embeddingsAutoJob
+	states  map[string]embeddingsAutoState // recordKey -> state
+	workers int
+}
+
+// schedule queues the embeddings generation of the specified record
+// (or marks it for rerun if the record generation is already in progress)
+// and starts a new worker if the workers limit is not reached.
+func (r *embeddingsAutoRunner) schedule(collectionId string, recordId string) {
+	job := embeddingsAutoJob{collectionId: collectionId, recordId: recordId}
+	key := job.key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if state, ok := r.states[key]; ok {
+		if state == embeddingsAutoRunning {
+			r.states[key] = embeddingsAutoRerun
+		}
+		return // already queued or marked for rerun
+	}
+
+	r.states[key] = embeddingsAutoQueued
+	r.queue = append(r.queue, job)
+
+	if r.workers < embeddingsAutoMaxWorkers {
+		r.workers++
+		routine.FireAndForget(r.work)
+	}
+}
+
+// work processes the queued records until the queue is empty.
+func (r *embeddingsAutoRunner) work() {
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.workers--
+			r.mu.Unlock()
+			return
+		}
+		job := r.queue[0]
+		r.queue[0] = embeddingsAutoJob{}
+		r.queue = r.queue[1:]
+		r.states[job.key()] = embeddingsAutoRunning
+		r.mu.Unlock()
+
+		r.generate(job.collectionId, job.recordId)
+
+		// requeue or release the record in the same lock as the schedule
+		// checks so that a save during the run is never lost
+		r.mu.Lock()
+		if r.states[job.key()] == embeddingsAutoRerun {
+			r.states[job.key()] = embeddingsAutoQueued
+			r.queue = append(r.queue, job)
+		} else {
+			delete(r.states, job.key())
+		}
+		r.mu.Unlock()
+	}
+}
+
+// generate generates the embeddings of a single record using its collection embedding config.
+func (r *embeddingsAutoRunner) generate(collectionId string, recordId string) {
+	config, err := FindEmbeddingConfig(r.app, collectionId)
+	if err != nil || !config.AutoGenerate {
+		return // no config or auto generation is not enabled
+	}
+
+	result, err := GenerateEmbeddings(r.app, config.request(recordId))
+	if err != nil {
+		r.app.Logger().Warn(
+			"Failed to auto generate the record embeddings",
+			"collectionId", collectionId,
+			"recordId", recordId,
+			"error", err,
+		)
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		r.app.Logger().Warn(
+			"Failed to auto generate some of the record embeddings",
+			"collectionId", collectionId,
+			"recordId", recordId,
+			"errors", result.Errors,
+		)
+	}
+}
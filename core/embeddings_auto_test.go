@@ -0,0 +1,162 @@
+package core_test
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// note: not parallel because of the shared embeddings cache
+func TestBindEmbeddingsAutoGenerate(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+
+	release := make(chan struct{})
+	transport := &fakeEmbeddingsTransport{OnRequest: func() { <-release }}
+	app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+	enabled := createTestEmbeddingsSourceCollection(t, app, "test_auto_enabled")
+	disabled := createTestEmbeddingsSourceCollection(t, app, "test_auto_disabled")
+	unconfigured := createTestEmbeddingsSourceCollection(t, app, "test_auto_unconfigured")
+
+	configs := []core.EmbeddingConfig{
+		{CollectionId: enabled.Id, FieldName: "title", AutoGenerate: true},
+		{CollectionId: disabled.Id, FieldName: "title"},
+	}
+	for _, config := range configs {
+		if _, err := core.SaveEmbeddingConfig(app, config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	core.BindEmbeddingsAutoGenerate(app)
+
+	saveTitle := func(record *core.Record, title string) {
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the saves shouldn't wait for the (blocked) embeddings requests
+	done := make(chan struct{})
+	var enabledRecord *core.Record
+	go func() {
+		defer close(done)
+
+		saveTitle(core.NewRecord(disabled), "disabled")
+		saveTitle(core.NewRecord(unconfigured), "unconfigured")
+
+		enabledRecord = core.NewRecord(enabled)
+		saveTitle(enabledRecord, "created")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the record saves to not be blocked by the embeddings generation")
+	}
+
+	close(release)
+
+	waitForTestEmbeddingsInputs(t, transport, "created")
+
+	saveTitle(enabledRecord, "updated")
+
+	waitForTestEmbeddingsInputs(t, transport, "updated")
+
+	if inputs := transport.Inputs(); slices.Contains(inputs, "disabled") || slices.Contains(inputs, "unconfigured") {
+		t.Fatalf("Expected only the auto generate enabled collection records to be embedded, got %v", inputs)
+	}
+
+	// wait for the updated embedding to be stored
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err := core.GetEmbeddingStatsForField(app, enabled.Id, "title")
+		if err == nil && stats.EmbeddedRecords == 1 && len(transport.Inputs()) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 1 embedded record after 2 requests, got %+v (%v) and inputs %v", stats, err, transport.Inputs())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestBindEmbeddingsAutoGenerateBoundedWorkers(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+
+	var active, maxActive atomic.Int32
+	release := make(chan struct{})
+	transport := &fakeEmbeddingsTransport{OnRequest: func() {
+		current := active.Add(1)
+		defer active.Add(-1)
+
+		for {
+			prev := maxActive.Load()
+			if current <= prev || maxActive.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+
+		<-release
+	}}
+	app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_auto_bounded")
+
+	if _, err := core.SaveEmbeddingConfig(app, core.EmbeddingConfig{CollectionId: collection.Id, FieldName: "title", AutoGenerate: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	core.BindEmbeddingsAutoGenerate(app)
+
+	total := 10
+	for i := 0; i < total; i++ {
+		record := core.NewRecord(collection)
+		record.Set("title", fmt.Sprintf("title%d", i))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// give the workers time to pick up the queued records
+	time.Sleep(100 * time.Millisecond)
+
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(transport.Inputs()) < total {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d embedded records, got %v", total, transport.Inputs())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if max := maxActive.Load(); max > 4 {
+		t.Fatalf("Expected at most 4 concurrent generations, got %d", max)
+	}
+}
+
+// waitForTestEmbeddingsInputs waits until the fake transport receives the specified input text.
+func waitForTestEmbeddingsInputs(t *testing.T, transport *fakeEmbeddingsTransport, input string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if slices.Contains(transport.Inputs(), input) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Expected input %q to be embedded, got %v", input, transport.Inputs())
+}
@@ -2,27 +2,178 @@ package core
 
 import (
 	"fmt"
+	"math"
+	"regexp"
 	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 const (
 	// EmbeddingsCollectionName is the name of the system collection for storing embeddings
 	EmbeddingsCollectionName = "_embeddings"
+
+	// EmbeddingsFieldDeleted is the name of the embeddings collection tombstone date field
+	// (non-empty for soft-deleted embeddings)
+	EmbeddingsFieldDeleted = "deleted"
+
+	// EmbeddingsFieldText is the name of the embeddings collection field
+	// with the (truncated) embedded source text (see EmbeddingRequest.StoreText)
+	EmbeddingsFieldText = "text"
+
+	// MaxStoredEmbeddingTextLength is the max number of characters
+	// of the stored embedded source text
+	MaxStoredEmbeddingTextLength = 2000
 )
 
+func (app *BaseApp) registerEmbeddingsHooks() {
+	// remove the embeddings and the nearest neighbors of the deleted records
+	// (excluding the system collections records, aka. the embeddings and nearest
+	// neighbors records themselves, the otps, the superusers, etc.)
+	app.OnRecordAfterDeleteSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbEmbeddingsRecordDelete__",
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if collection := e.Record.Collection(); collection.System || strings.HasPrefix(collection.Name, "_") {
+				return nil
+			}
+
+			if err := DeleteEmbeddingsForRecord(e.App, e.Record.Collection().Id, e.Record.Id); err != nil {
+				e.App.Logger().Warn(
+					"Failed to delete the embeddings of the deleted record",
+					"collectionId", e.Record.Collection().Id,
+					"recordId", e.Record.Id,
+					"error", err,
+				)
+			}
+
+			if err := DeleteKNNForRecord(e.App, e.Record.Collection().Id, e.Record.Id); err != nil {
+				e.App.Logger().Warn(
+					"Failed to delete the nearest neighbors of the deleted record",
+					"collectionId", e.Record.Collection().Id,
+					"recordId", e.Record.Id,
+					"error", err,
+				)
+			}
+
+			return nil
+		},
+	})
+
+	// run every 6 hours to hard delete the tombstoned embeddings with expired grace period
+	app.Cron().Add("__pbEmbeddingTombstonesSweep__", "30 */6 * * *", func() {
+		if _, err := SweepEmbeddingTombstones(app); err != nil {
+			app.Logger().Warn("Failed to sweep the expired embedding tombstones", "error", err)
+		}
+	})
+}
+
+// embeddingsCollectionNameRegex is the format of the custom embeddings collection names
+// (ex. "_embeddings_tenant_a").
+var embeddingsCollectionNameRegex = regexp.MustCompile(`^` + EmbeddingsCollectionName + `_\w+$`)
+
+// ValidateEmbeddingsCollectionName checks whether name is a valid custom embeddings collection name.
+//
+// Custom embeddings collection names must start with "_embeddings_" (to avoid
+// clashing with the user collections) and contain only alphanumeric and underscore characters.
+func ValidateEmbeddingsCollectionName(name string) error {
+	if name == EmbeddingsCollectionName {
+		return nil
+	}
+
+	if len(name) > 55 || !embeddingsCollectionNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid embeddings collection name %q (must match %s and be at most 55 characters)", name, embeddingsCollectionNameRegex.String())
+	}
+
+	return nil
+}
+
+// resolveEmbeddingsCollectionName returns the validated embeddings collection name
+// (fallbacks to [EmbeddingsCollectionName] if empty).
+func resolveEmbeddingsCollectionName(name string) (string, error) {
+	if name == "" {
+		return EmbeddingsCollectionName, nil
+	}
+
+	if err := ValidateEmbeddingsCollectionName(name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// findEmbeddingsCollections returns the default and all custom embeddings collections
+// (ex. "_embeddings_tenant_a").
+//
+// The collections are looked up in the app collections cache
+// (see [BaseApp.FindCachedCollectionByNameOrId]) and must be used only for read-only operations.
+func findEmbeddingsCollections(app App) ([]*Collection, error) {
+	collections, _ := app.Store().Get(StoreKeyCachedCollections).([]*Collection)
+	if collections == nil {
+		// cache is not initialized yet (eg. run in a system migration)
+		var err error
+		collections, err = app.FindAllCollections()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*Collection, 0, 1)
+	for _, collection := range collections {
+		if collection.Name == EmbeddingsCollectionName || embeddingsCollectionNameRegex.MatchString(collection.Name) {
+			result = append(result, collection)
+		}
+	}
+
+	return result, nil
+}
+
+// embeddingsCacheCollectionKey returns the embeddings cache collection key
+// of collectionId scoped to the specified embeddings collection.
+//
+// The default embeddings collection uses the plain collectionId
+// so that its cache keys remain the same as before the custom stores.
+func embeddingsCacheCollectionKey(embeddingsName, collectionId string) string {
+	if embeddingsName == "" || embeddingsName == EmbeddingsCollectionName {
+		return collectionId
+	}
+
+	return embeddingsName + "/" + collectionId
+}
+
 // EnsureEmbeddingsCollection creates the _embeddings system collection if it doesn't exist.
 // Returns the embeddings collection.
 // This function is safe to call concurrently - if multiple goroutines try to create
 // the collection simultaneously, all but one will find it already exists.
 func EnsureEmbeddingsCollection(app App) (*Collection, error) {
+	return EnsureEmbeddingsCollectionByName(app, EmbeddingsCollectionName)
+}
+
+// EnsureEmbeddingsCollectionByName is similar to [EnsureEmbeddingsCollection]
+// but for a custom (ex. per tenant) embeddings collection.
+//
+// The name must be valid according to [ValidateEmbeddingsCollectionName].
+func EnsureEmbeddingsCollectionByName(app App, name string) (*Collection, error) {
+	if err := ValidateEmbeddingsCollectionName(name); err != nil {
+		return nil, err
+	}
+
 	// Try to find existing collection
-	collection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
+	collection, err := app.FindCollectionByNameOrId(name)
 	if err == nil {
+		if err := upgradeEmbeddingsCollection(app, collection); err != nil {
+			return nil, fmt.Errorf("failed to upgrade embeddings collection: %w", err)
+		}
 		return collection, nil
 	}
 
 	// Create the embeddings collection
-	collection = NewCollection(CollectionTypeBase, EmbeddingsCollectionName)
+	collection = NewCollection(CollectionTypeBase, name)
 	collection.System = true
 
 	// Add fields for the embeddings collection
@@ -63,10 +214,24 @@ func EnsureEmbeddingsCollection(app App) (*Collection, error) {
 		System:   true,
 	})
 
+	// Tombstone date of the soft-deleted embeddings
+	collection.Fields.Add(&DateField{
+		Name:   EmbeddingsFieldDeleted,
+		System: true,
+	})
+
+	// Optional truncated copy of the embedded source text
+	collection.Fields.Add(&TextField{
+		Name:   EmbeddingsFieldText,
+		Max:    MaxStoredEmbeddingTextLength,
+		System: true,
+	})
+
 	// Add indexes for efficient lookup
+	// (the record ids are unique only per collection so the collection_id is part of the unique index)
 	collection.Indexes = []string{
-		"CREATE UNIQUE INDEX idx_embeddings_record_field ON _embeddings (record_id, field_name)",
-		"CREATE INDEX idx_embeddings_collection_field ON _embeddings (collection_id, field_name)",
+		embeddingsRecordFieldIndex(name),
+		fmt.Sprintf("CREATE INDEX %s_collection_field ON %s (collection_id, field_name)", embeddingsIndexPrefix(name), name),
 	}
 
 	// Save the collection
@@ -78,7 +243,7 @@ func EnsureEmbeddingsCollection(app App) (*Collection, error) {
 		if strings.Contains(errStr, "UNIQUE constraint failed") ||
 			strings.Contains(errStr, "already exists") ||
 			strings.Contains(errStr, "must be unique") {
-			collection, findErr := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
+			collection, findErr := app.FindCollectionByNameOrId(name)
 			if findErr == nil {
 				return collection, nil
 			}
@@ -89,40 +254,247 @@ func EnsureEmbeddingsCollection(app App) (*Collection, error) {
 	return collection, nil
 }
 
-// DeleteEmbeddingsForRecord deletes all embeddings associated with a record
-func DeleteEmbeddingsForRecord(app App, recordId string) error {
-	collection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
+// embeddingsIndexPrefix returns the index names prefix of an embeddings collection
+// (the index names are global so they are prefixed with the collection name, aka. "idx_embeddings_*" for the default one).
+func embeddingsIndexPrefix(name string) string {
+	return "idx_" + strings.TrimPrefix(name, "_")
+}
+
+// embeddingsRecordFieldIndex returns the unique collection record field index of an embeddings collection.
+func embeddingsRecordFieldIndex(name string) string {
+	return fmt.Sprintf("CREATE UNIQUE INDEX %s_collection_record_field ON %s (collection_id, record_id, field_name)", embeddingsIndexPrefix(name), name)
+}
+
+// upgradeEmbeddingsCollection adds the embeddings collection fields
+// introduced after its initial creation (if missing).
+func upgradeEmbeddingsCollection(app App, collection *Collection) error {
+	var changed bool
+
+	// replace the old (record_id, field_name) unique index that
+	// doesn't allow the same record id in different collections
+	//
+	// note: saved without validation because the system fields
+	// unique indexes can't be otherwise changed
+	for i, index := range collection.Indexes {
+		if strings.Contains(index, "(record_id, field_name)") {
+			collection.Indexes[i] = embeddingsRecordFieldIndex(collection.Name)
+			if err := app.SaveNoValidate(collection); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	if collection.Fields.GetByName(EmbeddingsFieldDeleted) == nil {
+		collection.Fields.Add(&DateField{
+			Name:   EmbeddingsFieldDeleted,
+			System: true,
+		})
+		changed = true
+	}
+
+	if collection.Fields.GetByName(EmbeddingsFieldText) == nil {
+		collection.Fields.Add(&TextField{
+			Name:   EmbeddingsFieldText,
+			Max:    MaxStoredEmbeddingTextLength,
+			System: true,
+		})
+		changed = true
+	}
+
+	if !changed {
+		return nil // already up-to-date
+	}
+
+	return app.Save(collection)
+}
+
+// activeEmbeddingsFilter returns a filter expression part that
+// excludes the tombstoned embeddings (if tombstones are supported by the collection).
+func activeEmbeddingsFilter(embeddingsCollection *Collection) string {
+	if embeddingsCollection.Fields.GetByName(EmbeddingsFieldDeleted) == nil {
+		return "" // created before the tombstones support
+	}
+
+	return " && " + EmbeddingsFieldDeleted + " = ''"
+}
+
+// DeleteEmbeddingsForRecord deletes the embeddings of a single collection record
+// from the default and all custom embeddings collections.
+//
+// If the AIConfig.EmbeddingTombstoneDays setting is set, the embeddings are only
+// tombstoned (aka. excluded from search) and they are hard deleted by
+// [SweepEmbeddingTombstones] after the configured grace period.
+func DeleteEmbeddingsForRecord(app App, collectionId, recordId string) error {
+	embeddingsCollections, err := findEmbeddingsCollections(app)
 	if err != nil {
-		// Collection doesn't exist, nothing to delete
-		return nil
+		return err
 	}
 
+	tombstone := app.Settings().AI.EmbeddingTombstoneDays > 0
+
+	for _, embeddingsCollection := range embeddingsCollections {
+		if tombstone && activeEmbeddingsFilter(embeddingsCollection) != "" {
+			if err := tombstoneEmbeddingsForRecord(app, embeddingsCollection, collectionId, recordId); err != nil {
+				return err
+			}
+			continue
+		}
+
+		records, err := findRecordEmbeddings(app, embeddingsCollection, collectionId, recordId, "")
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if err := app.Delete(record); err != nil {
+				return fmt.Errorf("failed to delete embedding: %w", err)
+			}
+
+			// Invalidate the cache so that the deleted embedding is excluded from search
+			embeddingCache.Invalidate(embeddingsCacheCollectionKey(embeddingsCollection.Name, collectionId), record.GetString("field_name"))
+		}
+	}
+
+	return nil
+}
+
+// findRecordEmbeddings returns the embeddings of a single collection record
+// from embeddingsCollection matching the optional extra filter.
+func findRecordEmbeddings(app App, embeddingsCollection *Collection, collectionId, recordId, extraFilter string) ([]*Record, error) {
 	records, err := app.FindRecordsByFilter(
-		collection.Id,
-		"record_id = {:recordId}",
+		embeddingsCollection.Id,
+		"collection_id = {:collectionId} && record_id = {:recordId}"+extraFilter,
 		"",
 		0, // Get all
 		0,
 		map[string]any{
-			"recordId": recordId,
+			"collectionId": collectionId,
+			"recordId":     recordId,
 		},
 	)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("failed to fetch the record embeddings: %w", err)
+	}
+
+	return records, nil
+}
+
+// tombstoneEmbeddingsForRecord soft deletes all active embeddings of a single collection record.
+func tombstoneEmbeddingsForRecord(app App, embeddingsCollection *Collection, collectionId, recordId string) error {
+	records, err := findRecordEmbeddings(app, embeddingsCollection, collectionId, recordId, activeEmbeddingsFilter(embeddingsCollection))
+	if err != nil {
+		return err
 	}
 
+	now := types.NowDateTime()
+
 	for _, record := range records {
-		if err := app.Delete(record); err != nil {
-			return fmt.Errorf("failed to delete embedding: %w", err)
+		record.Set(EmbeddingsFieldDeleted, now)
+		if err := app.Save(record); err != nil {
+			return fmt.Errorf("failed to tombstone embedding: %w", err)
 		}
+
+		// Invalidate the cache so that the tombstoned embedding is excluded from search
+		embeddingCache.Invalidate(embeddingsCacheCollectionKey(embeddingsCollection.Name, collectionId), record.GetString("field_name"))
 	}
 
 	return nil
 }
 
+// RestoreEmbeddingsForRecord restores the tombstoned embeddings of a single collection
+// record in the default and all custom embeddings collections.
+func RestoreEmbeddingsForRecord(app App, collectionId, recordId string) error {
+	embeddingsCollections, err := findEmbeddingsCollections(app)
+	if err != nil {
+		return err
+	}
+
+	for _, embeddingsCollection := range embeddingsCollections {
+		if activeEmbeddingsFilter(embeddingsCollection) == "" {
+			continue // doesn't support tombstones, nothing to restore
+		}
+
+		records, err := findRecordEmbeddings(app, embeddingsCollection, collectionId, recordId, " && "+EmbeddingsFieldDeleted+" != ''")
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			record.Set(EmbeddingsFieldDeleted, "")
+			if err := app.Save(record); err != nil {
+				return fmt.Errorf("failed to restore embedding: %w", err)
+			}
+
+			embeddingCache.Invalidate(embeddingsCacheCollectionKey(embeddingsCollection.Name, collectionId), record.GetString("field_name"))
+		}
+	}
+
+	return nil
+}
+
+// SweepEmbeddingTombstones hard deletes all tombstoned embeddings (from the default and
+// all custom embeddings collections) whose AIConfig.EmbeddingTombstoneDays grace period has expired.
+//
+// If the grace period setting is not set, all tombstoned embeddings are deleted.
+//
+// Returns the number of the deleted embeddings.
+func SweepEmbeddingTombstones(app App) (int, error) {
+	embeddingsCollections, err := findEmbeddingsCollections(app)
+	if err != nil {
+		return 0, err
+	}
+
+	days := app.Settings().AI.EmbeddingTombstoneDays
+	if days < 0 {
+		days = 0
+	}
+
+	threshold, err := types.ParseDateTime(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int
+	for _, embeddingsCollection := range embeddingsCollections {
+		if activeEmbeddingsFilter(embeddingsCollection) == "" {
+			continue // doesn't support tombstones, nothing to sweep
+		}
+
+		records, err := app.FindRecordsByFilter(
+			embeddingsCollection.Id,
+			EmbeddingsFieldDeleted+" != '' && "+EmbeddingsFieldDeleted+" <= {:threshold}",
+			"",
+			0, // Get all
+			0,
+			map[string]any{
+				"threshold": threshold.String(),
+			},
+		)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to fetch embedding tombstones: %w", err)
+		}
+
+		for _, record := range records {
+			if err := app.Delete(record); err != nil {
+				return deleted, fmt.Errorf("failed to delete embedding tombstone: %w", err)
+			}
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
 // DeleteEmbeddingsForCollection deletes all embeddings for records in a collection
-func DeleteEmbeddingsForCollection(app App, collectionId string) error {
-	collection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
+// from the specified embeddings collection (empty for [EmbeddingsCollectionName]).
+func DeleteEmbeddingsForCollection(app App, embeddingsName, collectionId string) error {
+	embeddingsName, err := resolveEmbeddingsCollectionName(embeddingsName)
+	if err != nil {
+		return err
+	}
+
+	collection, err := app.FindCollectionByNameOrId(embeddingsName)
 	if err != nil {
 		// Collection doesn't exist, nothing to delete
 		return nil
@@ -149,14 +521,20 @@ func DeleteEmbeddingsForCollection(app App, collectionId string) error {
 	}
 
 	// Invalidate cache for this collection
-	embeddingCache.InvalidateCollection(collectionId)
+	embeddingCache.InvalidateCollection(embeddingsName, collectionId)
 
 	return nil
 }
 
 // DeleteEmbeddingsForField deletes all embeddings for a specific field in a collection
-func DeleteEmbeddingsForField(app App, collectionId, fieldName string) error {
-	collection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
+// from the specified embeddings collection (empty for [EmbeddingsCollectionName]).
+func DeleteEmbeddingsForField(app App, embeddingsName, collectionId, fieldName string) error {
+	embeddingsName, err := resolveEmbeddingsCollectionName(embeddingsName)
+	if err != nil {
+		return err
+	}
+
+	collection, err := app.FindCollectionByNameOrId(embeddingsName)
 	if err != nil {
 		// Collection doesn't exist, nothing to delete
 		return nil
@@ -184,16 +562,96 @@ func DeleteEmbeddingsForField(app App, collectionId, fieldName string) error {
 	}
 
 	// Invalidate cache for this collection/field
-	embeddingCache.Invalidate(collectionId, fieldName)
+	embeddingCache.Invalidate(embeddingsCacheCollectionKey(embeddingsName, collectionId), fieldName)
 
 	return nil
 }
 
+// EmbeddingText represents the stored embedded source text of a record.
+type EmbeddingText struct {
+	RecordId  string `json:"recordId"`
+	FieldName string `json:"fieldName"`
+	Model     string `json:"model"`
+	Text      string `json:"text"`
+}
+
+// GetEmbeddingText returns the stored embedded source text of a single
+// record field (or [RecordLevelFieldName] for the record-level embeddings).
+//
+// The text is available only for the embeddings generated with the
+// EmbeddingRequest.StoreText option.
+func GetEmbeddingText(app App, recordId, fieldName string) (*EmbeddingText, error) {
+	embeddingsCollection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings collection not found: %w", err)
+	}
+
+	if embeddingsCollection.Fields.GetByName(EmbeddingsFieldText) == nil {
+		return nil, fmt.Errorf("no stored text found for record %s", recordId)
+	}
+
+	record, err := app.FindFirstRecordByFilter(
+		embeddingsCollection.Id,
+		"record_id = {:recordId} && field_name = {:fieldName}"+activeEmbeddingsFilter(embeddingsCollection),
+		map[string]any{
+			"recordId":  recordId,
+			"fieldName": fieldName,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("no embedding found for record %s", recordId)
+	}
+
+	text := record.GetString(EmbeddingsFieldText)
+	if text == "" {
+		return nil, fmt.Errorf("no stored text found for record %s", recordId)
+	}
+
+	return &EmbeddingText{
+		RecordId:  recordId,
+		FieldName: fieldName,
+		Model:     record.GetString("model"),
+		Text:      text,
+	}, nil
+}
+
 // GetEmbeddingStats returns statistics about embeddings for a collection/field
 type EmbeddingStats struct {
 	TotalRecords       int `json:"totalRecords"`
 	EmbeddedRecords    int `json:"embeddedRecords"`
 	NotEmbeddedRecords int `json:"notEmbeddedRecords"`
+
+	// CoveragePercent is the percentage (0-100) of the embedded records
+	// (0 if the collection has no records).
+	CoveragePercent float64 `json:"coveragePercent"`
+}
+
+// FieldEmbeddingStats represents the embedding statistics of a single collection field.
+type FieldEmbeddingStats struct {
+	FieldName string `json:"fieldName"`
+	EmbeddingStats
+}
+
+// CollectionEmbeddingStats represents the aggregated embedding statistics
+// of all embeddable fields of a collection.
+type CollectionEmbeddingStats struct {
+	TotalRecords int                    `json:"totalRecords"`
+	Fields       []*FieldEmbeddingStats `json:"fields"`
+
+	// CoveragePercent is the percentage (0-100) of the embedded
+	// records/fields pairs of all embeddable fields
+	// (0 if the collection has no records or embeddable fields).
+	CoveragePercent float64 `json:"coveragePercent"`
+}
+
+// embeddingCoveragePercent returns the embedded percentage of total
+// (capped to 100 in case of orphaned embeddings).
+func embeddingCoveragePercent(embedded, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	return math.Min(float64(embedded)/float64(total)*100, 100)
 }
 
 // GetEmbeddingStatsForField returns embedding statistics for a specific field
@@ -218,12 +676,13 @@ func GetEmbeddingStatsForField(app App, collectionId, fieldName string) (*Embedd
 			TotalRecords:       totalRecords,
 			EmbeddedRecords:    0,
 			NotEmbeddedRecords: totalRecords,
+			CoveragePercent:    0,
 		}, nil
 	}
 
 	embeddedRecords, err := app.FindRecordsByFilter(
 		embeddingsCollection.Id,
-		"collection_id = {:collectionId} && field_name = {:fieldName}",
+		"collection_id = {:collectionId} && field_name = {:fieldName}"+activeEmbeddingsFilter(embeddingsCollection),
 		"",
 		0,
 		0,
@@ -240,9 +699,47 @@ func GetEmbeddingStatsForField(app App, collectionId, fieldName string) (*Embedd
 		TotalRecords:       totalRecords,
 		EmbeddedRecords:    len(embeddedRecords),
 		NotEmbeddedRecords: totalRecords - len(embeddedRecords),
+		CoveragePercent:    embeddingCoveragePercent(len(embeddedRecords), totalRecords),
 	}, nil
 }
 
+// GetEmbeddingStatsForCollection returns the embedding statistics of each
+// embeddable field of a collection and their aggregated coverage.
+func GetEmbeddingStatsForCollection(app App, collectionId string) (*CollectionEmbeddingStats, error) {
+	collection, err := app.FindCollectionByNameOrId(collectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	result := &CollectionEmbeddingStats{Fields: []*FieldEmbeddingStats{}}
+
+	var embedded, total int
+	for _, field := range collection.Fields {
+		// the id is a text field but it is never embedded
+		if field.GetName() == FieldNameId || !IsAppFieldEmbeddable(app, field) {
+			continue
+		}
+
+		stats, err := GetEmbeddingStatsForField(app, collection.Id, field.GetName())
+		if err != nil {
+			return nil, err
+		}
+
+		result.TotalRecords = stats.TotalRecords
+		result.Fields = append(result.Fields, &FieldEmbeddingStats{
+			FieldName:      field.GetName(),
+			EmbeddingStats: *stats,
+		})
+
+		embedded += min(stats.EmbeddedRecords, stats.TotalRecords)
+		total += stats.TotalRecords
+	}
+
+	result.CoveragePercent = embeddingCoveragePercent(embedded, total)
+
+	return result, nil
+}
+
 // GetPendingEmbeddingRecordIds returns IDs of records that don't have embeddings yet
 func GetPendingEmbeddingRecordIds(app App, collectionId, fieldName string) ([]string, error) {
 	// Get the source collection
@@ -275,7 +772,7 @@ func GetPendingEmbeddingRecordIds(app App, collectionId, fieldName string) ([]st
 
 	embeddedRecords, err := app.FindRecordsByFilter(
 		embeddingsCollection.Id,
-		"collection_id = {:collectionId} && field_name = {:fieldName}",
+		"collection_id = {:collectionId} && field_name = {:fieldName}"+activeEmbeddingsFilter(embeddingsCollection),
 		"",
 		0,
 		0,
@@ -302,3 +799,109 @@ func GetPendingEmbeddingRecordIds(app App, collectionId, fieldName string) ([]st
 	return result, nil
 }
 
+
+const (
+	// EmbeddingNearZeroMagnitude is the magnitude below which an embedding
+	// is considered degenerate (most likely a failed/empty generation)
+	EmbeddingNearZeroMagnitude = 1e-6
+
+	// EmbeddingQualitySampleSize is the max number of embeddings used
+	// for computing the average pairwise similarity
+	EmbeddingQualitySampleSize = 200
+)
+
+// EmbeddingQualityStats contains aggregate metrics for an embeddings set
+type EmbeddingQualityStats struct {
+	Count             int     `json:"count"`
+	Dimensions        int     `json:"dimensions"`
+	MeanMagnitude     float64 `json:"meanMagnitude"`
+	MagnitudeVariance float64 `json:"magnitudeVariance"`
+	NearZeroCount     int     `json:"nearZeroCount"`
+
+	// Average cosine similarity between all pairs of the sampled non-zero embeddings
+	// (values close to 1 indicate collapsed/degenerate embeddings)
+	AvgPairwiseSimilarity float64 `json:"avgPairwiseSimilarity"`
+	SampleSize            int     `json:"sampleSize"`
+}
+
+// GetEmbeddingQualityForField returns aggregate quality metrics for the embeddings of a specific field
+func GetEmbeddingQualityForField(app App, collectionId, fieldName string) (*EmbeddingQualityStats, error) {
+	collection, err := app.FindCollectionByNameOrId(collectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if _, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName); err != nil {
+		// No embeddings yet
+		return ComputeEmbeddingQuality(nil), nil
+	}
+
+	embeddings, err := loadCachedEmbeddings(app, EmbeddingsCollectionName, collection.Id, fieldName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeEmbeddingQuality(embeddings), nil
+}
+
+// ComputeEmbeddingQuality computes aggregate quality metrics for the provided embeddings.
+//
+// The pairwise similarity is computed on an evenly spaced sample of
+// up to [EmbeddingQualitySampleSize] non-zero embeddings.
+func ComputeEmbeddingQuality(embeddings []CachedEmbedding) *EmbeddingQualityStats {
+	stats := &EmbeddingQualityStats{
+		Count: len(embeddings),
+	}
+
+	if len(embeddings) == 0 {
+		return stats
+	}
+
+	stats.Dimensions = embeddings[0].Dimensions()
+
+	// magnitude mean and variance
+	nonZero := make([]CachedEmbedding, 0, len(embeddings))
+	var sum float64
+	for _, e := range embeddings {
+		sum += float64(e.Magnitude)
+		if e.Magnitude < EmbeddingNearZeroMagnitude {
+			stats.NearZeroCount++
+		} else {
+			nonZero = append(nonZero, e)
+		}
+	}
+	stats.MeanMagnitude = sum / float64(len(embeddings))
+
+	var variance float64
+	for _, e := range embeddings {
+		diff := float64(e.Magnitude) - stats.MeanMagnitude
+		variance += diff * diff
+	}
+	stats.MagnitudeVariance = variance / float64(len(embeddings))
+
+	// pick an evenly spaced (and therefore reproducible) sample
+	sample := nonZero
+	if len(sample) > EmbeddingQualitySampleSize {
+		sample = make([]CachedEmbedding, EmbeddingQualitySampleSize)
+		step := float64(len(nonZero)) / float64(EmbeddingQualitySampleSize)
+		for i := range sample {
+			sample[i] = nonZero[int(float64(i)*step)]
+		}
+	}
+	stats.SampleSize = len(sample)
+
+	// average pairwise similarity
+	var pairs int
+	var similaritySum float64
+	for i := 0; i < len(sample); i++ {
+		for j := i + 1; j < len(sample); j++ {
+			similaritySum += float64(sample[i].pairSimilarity(sample[j], SimilarityMetricCosine))
+			pairs++
+		}
+	}
+	if pairs > 0 {
+		stats.AvgPairwiseSimilarity = similaritySum / float64(pairs)
+	}
+
+	return stats
+}
@@ -0,0 +1,568 @@
+package core_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestEmbeddingTombstones(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+	app.Settings().AI.EmbeddingTombstoneDays = 7
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_tombstones")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": {1, 0},
+		"r2": {1, 0.1},
+		"r3": {1, 0.2},
+	})
+
+	search := func() []string {
+		result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RecordId:     "r3",
+			Limit:        10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(result.Results))
+		for i, r := range result.Results {
+			ids[i] = r.RecordId
+		}
+		return ids
+	}
+
+	// warm up the cache
+	if ids := search(); len(ids) != 2 {
+		t.Fatalf("Expected 2 search results before the delete, got %v", ids)
+	}
+
+	if err := core.DeleteEmbeddingsForRecord(app, collection.Id, "r1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := search(); len(ids) != 1 || ids[0] != "r2" {
+		t.Fatalf("Expected the tombstoned embedding to be excluded from search, got %v", ids)
+	}
+
+	tombstone, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = 'r1'")
+	if err != nil {
+		t.Fatalf("Expected the tombstoned embedding to remain in the table, got %v", err)
+	}
+	if tombstone.GetDateTime(core.EmbeddingsFieldDeleted).IsZero() {
+		t.Fatal("Expected the tombstone date to be set")
+	}
+
+	// within the grace period
+	swept, err := core.SweepEmbeddingTombstones(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swept != 0 {
+		t.Fatalf("Expected no swept embeddings within the grace period, got %d", swept)
+	}
+
+	// restore
+	if err := core.RestoreEmbeddingsForRecord(app, collection.Id, "r1"); err != nil {
+		t.Fatal(err)
+	}
+	if ids := search(); len(ids) != 2 {
+		t.Fatalf("Expected the restored embedding to be included in the search results, got %v", ids)
+	}
+
+	// expire the grace period
+	if err := core.DeleteEmbeddingsForRecord(app, collection.Id, "r1"); err != nil {
+		t.Fatal(err)
+	}
+	tombstone, err = app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = 'r1'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := types.ParseDateTime(time.Now().AddDate(0, 0, -8))
+	tombstone.Set(core.EmbeddingsFieldDeleted, expired)
+	if err := app.Save(tombstone); err != nil {
+		t.Fatal(err)
+	}
+
+	swept, err = core.SweepEmbeddingTombstones(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swept != 1 {
+		t.Fatalf("Expected 1 swept embedding, got %d", swept)
+	}
+
+	if _, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = 'r1'"); err == nil {
+		t.Fatal("Expected the swept embedding to be hard deleted")
+	}
+
+	// the other embeddings should remain untouched
+	total, err := app.CountRecords(core.EmbeddingsCollectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected 2 remaining embeddings, got %d", total)
+	}
+}
+
+func TestDeleteEmbeddingsForRecordWithoutTombstones(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_hard_delete")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": {1, 0},
+		"r2": {0, 1},
+	})
+
+	if err := core.DeleteEmbeddingsForRecord(app, collection.Id, "r1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = 'r1'"); err == nil {
+		t.Fatal("Expected the embedding to be hard deleted")
+	}
+
+	if _, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = 'r2'"); err != nil {
+		t.Fatalf("Expected the other embedding to remain, got %v", err)
+	}
+}
+
+func TestSystemRecordDeleteKeepsEmbeddings(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// embeddings that happen to be stored with the system collection record key
+	storeTestEmbeddings(t, app, superuser.Collection().Id, "title", map[string][]float32{
+		superuser.Id: {1, 0},
+	})
+
+	if err := app.Delete(superuser); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = {:id}", dbx.Params{"id": superuser.Id}); err != nil {
+		t.Fatalf("Expected the system collection record delete to be ignored, got %v", err)
+	}
+}
+
+func TestRecordDeleteRemovesEmbeddings(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_record_delete")
+
+	records := make([]*core.Record, 3)
+	for i := range records {
+		records[i] = core.NewRecord(collection)
+		records[i].Set("title", fmt.Sprintf("title%d", i))
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		records[0].Id: {1, 0},
+		records[1].Id: {0, 1},
+		records[2].Id: {1, 1},
+	})
+
+	// warm up the cache
+	_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+		RecordId:     records[2].Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := core.DumpEmbeddingCache().Entries; len(entries) != 1 {
+		t.Fatalf("Expected 1 cache entry before the delete, got %v", entries)
+	}
+
+	if err := app.Delete(records[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = {:id}", dbx.Params{"id": records[0].Id}); err == nil {
+		t.Fatal("Expected the deleted record embedding to be removed")
+	}
+
+	total, err := app.CountRecords(core.EmbeddingsCollectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected 2 remaining embeddings, got %d", total)
+	}
+
+	if entries := core.DumpEmbeddingCache().Entries; len(entries) != 0 {
+		t.Fatalf("Expected the cache entry to be invalidated, got %v", entries)
+	}
+
+	result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+		RecordId:     records[2].Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 1 || result.Results[0].RecordId != records[1].Id {
+		t.Fatalf("Expected only the %q search result, got %v", records[1].Id, result.Results)
+	}
+}
+
+func TestRecordDeleteRemovesEmbeddingsWithSharedId(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	const sharedId = "sharedrecord001"
+	const tenantEmbeddings = "_embeddings_tenant_a"
+
+	collectionA := createTestEmbeddingsSourceCollection(t, app, "test_shared_id_a")
+	collectionB := createTestEmbeddingsSourceCollection(t, app, "test_shared_id_b")
+
+	records := make([]*core.Record, 2)
+	for i, collection := range []*core.Collection{collectionA, collectionB} {
+		records[i] = core.NewRecord(collection)
+		records[i].Id = sharedId
+		records[i].Set("title", "test")
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+
+		storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{sharedId: {1, 0}})
+		storeTestEmbeddingsIn(t, app, tenantEmbeddings, collection.Id, "title", map[string][]float32{sharedId: {1, 0}})
+	}
+
+	if err := app.Delete(records[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, embeddingsName := range []string{core.EmbeddingsCollectionName, tenantEmbeddings} {
+		for _, s := range []struct {
+			collectionId string
+			expected     int
+		}{
+			{collectionA.Id, 0},
+			{collectionB.Id, 1},
+		} {
+			total, err := app.CountRecords(embeddingsName, dbx.HashExp{"collection_id": s.collectionId, "record_id": sharedId})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != int64(s.expected) {
+				t.Fatalf("[%s] Expected %d embeddings of collection %s, got %d", embeddingsName, s.expected, s.collectionId, total)
+			}
+		}
+	}
+}
+
+func TestEnsureEmbeddingsCollectionUpgradeRecordIndex(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := core.EnsureEmbeddingsCollection(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a collection created with the old record field unique index
+	collection.Indexes = []string{
+		"CREATE UNIQUE INDEX idx_embeddings_record_field ON _embeddings (record_id, field_name)",
+		"CREATE INDEX idx_embeddings_collection_field ON _embeddings (collection_id, field_name)",
+	}
+	if err := app.SaveNoValidate(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err = core.EnsureEmbeddingsCollection(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "CREATE UNIQUE INDEX idx_embeddings_collection_record_field ON _embeddings (collection_id, record_id, field_name)"
+	if len(collection.Indexes) != 2 || collection.Indexes[0] != expected {
+		t.Fatalf("Expected the upgraded %q index, got %v", expected, collection.Indexes)
+	}
+
+	// the same record id should be allowed in different collections
+	storeTestEmbeddings(t, app, "collection_a", "title", map[string][]float32{"r1": {1, 0}})
+	storeTestEmbeddings(t, app, "collection_b", "title", map[string][]float32{"r1": {1, 0}})
+}
+
+func TestDeleteEmbeddingsForCustomCollection(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	const customName = "_embeddings_tenant_a"
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_delete_custom_embeddings")
+
+	vectors := map[string][]float32{"r1": {1, 0}, "r2": {0, 1}}
+	storeTestEmbeddings(t, app, collection.Id, "title", vectors)
+	storeTestEmbeddingsIn(t, app, customName, collection.Id, "title", vectors)
+
+	customKey := customName + "/" + collection.Id
+	core.SetEmbeddingCacheEntry(collection.Id, "title", []core.CachedEmbedding{{RecordId: "r1"}})
+	core.SetEmbeddingCacheEntry(customKey, "title", []core.CachedEmbedding{{RecordId: "r1"}})
+
+	count := func(embeddingsName string) int64 {
+		total, err := app.CountRecords(embeddingsName, dbx.HashExp{"collection_id": collection.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	if err := core.DeleteEmbeddingsForCollection(app, "invalid", collection.Id); err == nil {
+		t.Fatal("Expected invalid embeddings collection name error")
+	}
+
+	if err := core.DeleteEmbeddingsForCollection(app, customName, collection.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := count(customName); total != 0 {
+		t.Fatalf("Expected the custom store embeddings to be deleted, got %d", total)
+	}
+	if _, ok := core.GetEmbeddingCacheEntry(customKey, "title"); ok {
+		t.Fatal("Expected the custom store cache entry to be invalidated")
+	}
+
+	if total := count(core.EmbeddingsCollectionName); total != 2 {
+		t.Fatalf("Expected the default store embeddings to remain, got %d", total)
+	}
+	if _, ok := core.GetEmbeddingCacheEntry(collection.Id, "title"); !ok {
+		t.Fatal("Expected the default store cache entry to remain")
+	}
+
+	if err := core.DeleteEmbeddingsForField(app, "", collection.Id, "title"); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := count(core.EmbeddingsCollectionName); total != 0 {
+		t.Fatalf("Expected the default store embeddings to be deleted, got %d", total)
+	}
+	if _, ok := core.GetEmbeddingCacheEntry(collection.Id, "title"); ok {
+		t.Fatal("Expected the default store cache entry to be invalidated")
+	}
+}
+
+// -------------------------------------------------------------------
+
+// newTestAIApp creates a new test app with enabled AI settings
+// (with "test-model" as embedding model) and sends the AI provider
+// requests to the specified transport (if not nil).
+//
+// The app is cleaned up automatically at the end of the test.
+func newTestAIApp(t testing.TB, transport http.RoundTripper) *tests.TestApp {
+	t.Helper()
+
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(app.Cleanup)
+
+	app.Settings().AI.Enabled = true
+	app.Settings().AI.APIKey = "test"
+	app.Settings().AI.EmbeddingModel = "test-model"
+
+	if transport != nil {
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+	}
+
+	return app
+}
+
+// createTestEmbeddingsSourceCollection creates a new base collection
+// with "title" and "content" embeddable fields.
+func createTestEmbeddingsSourceCollection(t testing.TB, app core.App, name string) *core.Collection {
+	collection := core.NewBaseCollection(name)
+	collection.Fields.Add(&core.TextField{Name: "title", Embeddable: true})
+	collection.Fields.Add(&core.EditorField{Name: "content", Embeddable: true})
+	collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+	collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+// storeTestEmbeddings creates embedding records for the specified collection field.
+func storeTestEmbeddings(t testing.TB, app core.App, collectionId string, fieldName string, vectors map[string][]float32) {
+	storeTestEmbeddingsIn(t, app, core.EmbeddingsCollectionName, collectionId, fieldName, vectors)
+}
+
+// storeTestEmbeddingsIn is the same as storeTestEmbeddings but stores
+// the embeddings in the specified (custom) embeddings collection.
+func storeTestEmbeddingsIn(t testing.TB, app core.App, embeddingsName string, collectionId string, fieldName string, vectors map[string][]float32) {
+	embeddingsCollection, err := core.EnsureEmbeddingsCollectionByName(app, embeddingsName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for recordId, vector := range vectors {
+		record := core.NewRecord(embeddingsCollection)
+		record.Set("record_id", recordId)
+		record.Set("collection_id", collectionId)
+		record.Set("field_name", fieldName)
+		record.Set("embedding", vector)
+		record.Set("model", "test")
+		record.Set("dimensions", len(vector))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetEmbeddingStatsCoverage(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embedding_coverage")
+
+	ids := make([]string, 4)
+	for i := range ids {
+		record := core.NewRecord(collection)
+		record.Set("title", "test")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = record.Id
+	}
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		ids[0]: {1, 0},
+		ids[1]: {1, 0},
+		ids[2]: {1, 0},
+	})
+	storeTestEmbeddings(t, app, collection.Id, "content", map[string][]float32{
+		ids[0]: {1, 0},
+	})
+
+	t.Run("field", func(t *testing.T) {
+		scenarios := []struct {
+			fieldName string
+			expected  float64
+		}{
+			{"title", 75},
+			{"content", 25},
+			{"missing", 0},
+		}
+
+		for _, s := range scenarios {
+			stats, err := core.GetEmbeddingStatsForField(app, collection.Id, s.fieldName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if stats.CoveragePercent != s.expected {
+				t.Fatalf("[%s] Expected %v%% coverage, got %v%%", s.fieldName, s.expected, stats.CoveragePercent)
+			}
+		}
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		stats, err := core.GetEmbeddingStatsForCollection(app, collection.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stats.TotalRecords != 4 {
+			t.Fatalf("Expected 4 total records, got %d", stats.TotalRecords)
+		}
+
+		if len(stats.Fields) != 2 ||
+			stats.Fields[0].FieldName != "title" || stats.Fields[0].CoveragePercent != 75 ||
+			stats.Fields[1].FieldName != "content" || stats.Fields[1].CoveragePercent != 25 {
+			t.Fatalf("Unexpected fields stats %+v", stats.Fields)
+		}
+
+		// (3 + 1) embedded of 2 fields * 4 records
+		if stats.CoveragePercent != 50 {
+			t.Fatalf("Expected 50%% coverage, got %v%%", stats.CoveragePercent)
+		}
+	})
+
+	t.Run("orphaned embeddings", func(t *testing.T) {
+		storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+			ids[3]:   {1, 0},
+			"orphan": {1, 0},
+		})
+
+		stats, err := core.GetEmbeddingStatsForField(app, collection.Id, "title")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stats.CoveragePercent != 100 {
+			t.Fatalf("Expected the coverage to be capped to 100%%, got %v%%", stats.CoveragePercent)
+		}
+	})
+
+	t.Run("empty collection", func(t *testing.T) {
+		empty := createTestEmbeddingsSourceCollection(t, app, "test_embedding_coverage_empty")
+
+		stats, err := core.GetEmbeddingStatsForCollection(app, empty.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(stats.Fields) != 2 || stats.TotalRecords != 0 || stats.CoveragePercent != 0 {
+			t.Fatalf("Unexpected empty collection stats %+v", stats)
+		}
+	})
+}
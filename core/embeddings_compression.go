@@ -0,0 +1,93 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	// EmbeddingCompressionNone stores the embeddings as plain JSON arrays (default)
+	EmbeddingCompressionNone = ""
+
+	// EmbeddingCompressionGzip stores the embeddings as gzip compressed
+	// little-endian float32 blobs (see [EmbeddingGzipPrefix])
+	EmbeddingCompressionGzip = "gzip"
+
+	// EmbeddingGzipPrefix is the format marker of the gzip compressed embeddings.
+	//
+	// The compressed embeddings are stored as JSON string in the format
+	// "gzip:" + base64(gzip(float32 little-endian bytes)) so that they could be
+	// distinguished from the plain JSON array embeddings.
+	EmbeddingGzipPrefix = "gzip:"
+)
+
+// encodeEmbedding returns the embeddings collection "embedding" field value
+// of the provided vector according to the compression setting.
+func encodeEmbedding(embedding []float32, compression string) (any, error) {
+	if compression != EmbeddingCompressionGzip {
+		// store as JSON array (convert float32 to float64 for JSON compatibility)
+		result := make([]float64, len(embedding))
+		for i, v := range embedding {
+			result[i] = float64(v)
+		}
+		return result, nil
+	}
+
+	raw := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress the embedding: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress the embedding: %w", err)
+	}
+
+	return EmbeddingGzipPrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeCompressedEmbedding decodes a compressed embedding string created with [encodeEmbedding].
+func decodeCompressedEmbedding(value string) ([]float32, error) {
+	encoded, ok := strings.CutPrefix(value, EmbeddingGzipPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unknown embedding format")
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the compressed embedding: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the embedding: %w", err)
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the embedding: %w", err)
+	}
+
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("invalid compressed embedding size %d", len(raw))
+	}
+
+	result := make([]float32, len(raw)/4)
+	for i := range result {
+		result[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+
+	return result, nil
+}
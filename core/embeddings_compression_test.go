@@ -0,0 +1,148 @@
+package core_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestEmbeddingCompressionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	embeddingsCollection, err := core.EnsureEmbeddingsCollection(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vector := []float32{0.1, -0.25, 3.5, 0, 1e-7, -1234.5678}
+
+	scenarios := []struct {
+		compression string
+		compressed  bool
+	}{
+		{core.EmbeddingCompressionNone, false},
+		{core.EmbeddingCompressionGzip, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run("compression_"+s.compression, func(t *testing.T) {
+			encoded, err := core.EncodeEmbedding(vector, s.compression)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			str, isString := encoded.(string)
+			if isString != s.compressed || (s.compressed && !strings.HasPrefix(str, core.EmbeddingGzipPrefix)) {
+				t.Fatalf("Expected compressed %v, got %v", s.compressed, encoded)
+			}
+
+			record := core.NewRecord(embeddingsCollection)
+			record.Set("record_id", "test_"+s.compression)
+			record.Set("collection_id", "test")
+			record.Set("field_name", "title")
+			record.Set("embedding", encoded)
+			record.Set("model", "test-model")
+			record.Set("dimensions", len(vector))
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+
+			// reload to read the value as stored in the db
+			stored, err := app.FindRecordById(embeddingsCollection, record.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := core.GetEmbeddingFromRecord(stored)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(decoded, vector) {
+				t.Fatalf("Expected %v, got %v", vector, decoded)
+			}
+		})
+	}
+}
+
+func TestGenerateEmbeddingsCompression(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{})
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_compression")
+
+	// embed the first record uncompressed and the second compressed
+	texts := []string{"plain embedding", "compressed embedding"}
+	records := make([]*core.Record, len(texts))
+	for i, text := range texts {
+		if i == 1 {
+			app.Settings().AI.EmbeddingCompression = core.EmbeddingCompressionGzip
+		}
+
+		records[i] = core.NewRecord(collection)
+		records[i].Set("title", text)
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RecordIds:    []string{records[i].Id},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	embeddingsCollection, err := app.FindCollectionByNameOrId(core.EmbeddingsCollectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, record := range records {
+		stored, err := app.FindFirstRecordByData(embeddingsCollection, "record_id", record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		compressed := strings.HasPrefix(stored.GetString("embedding"), `"`+core.EmbeddingGzipPrefix)
+		if compressed != (i == 1) {
+			t.Fatalf("[%d] Expected compressed %v, got %s", i, i == 1, stored.GetString("embedding"))
+		}
+
+		embedding, err := core.GetEmbeddingFromRecord(stored)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := fakeEmbedding(texts[i]); !slices.Equal(embedding, expected) {
+			t.Fatalf("[%d] Expected %v, got %v", i, expected, embedding)
+		}
+	}
+
+	// both formats should be searchable
+	for i, text := range texts {
+		response, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Text:         text,
+			Limit:        1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(response.Results) != 1 || response.Results[0].RecordId != records[i].Id {
+			t.Fatalf("[%d] Expected the best match to be %s, got %v", i, records[i].Id, response.Results)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EmbeddingConfigsCollectionName is the name of the system collection
+// for storing the per collection embedding configurations
+const EmbeddingConfigsCollectionName = "_embedding_configs"
+
+// EmbeddingConfig represents the stored embedding configuration of a collection
+// (used by [EmbedCollection] to generate the collection embeddings without
+// the caller having to specify the embedding parameters).
+type EmbeddingConfig struct {
+	CollectionId string        `json:"collectionId"`
+	Mode         EmbeddingMode `json:"mode,omitempty"`      // "field", "record" or "fields"
+	FieldName    string        `json:"fieldName,omitempty"` // For field-level mode
+	Fields       []string      `json:"fields,omitempty"`    // For multi-field mode
+	Model        string        `json:"model,omitempty"`     // If empty, the AIConfig.EmbeddingModel setting is used
+	Template     string        `json:"template,omitempty"`  // Optional template for record-level mode
+
+	// AutoGenerate indicates whether to (re)generate automatically the embeddings
+	// of the created and updated collection records (see [BindEmbeddingsAutoGenerate]).
+	AutoGenerate bool `json:"autoGenerate,omitempty"`
+}
+
+// request returns the embedding generation request of the config
+// (optionally limited to the specified record ids).
+func (config *EmbeddingConfig) request(recordIds ...string) EmbeddingRequest {
+	return EmbeddingRequest{
+		CollectionId: config.CollectionId,
+		Mode:         config.Mode,
+		FieldName:    config.FieldName,
+		Fields:       config.Fields,
+		Model:        config.Model,
+		Template:     config.Template,
+		RecordIds:    recordIds,
+	}
+}
+
+// EnsureEmbeddingConfigsCollection creates the _embedding_configs system collection if it doesn't exist.
+// Returns the embedding configs collection.
+func EnsureEmbeddingConfigsCollection(app App) (*Collection, error) {
+	collection, err := app.FindCollectionByNameOrId(EmbeddingConfigsCollectionName)
+	if err == nil {
+		if err := upgradeEmbeddingConfigsCollection(app, collection); err != nil {
+			return nil, fmt.Errorf("failed to upgrade embedding configs collection: %w", err)
+		}
+		return collection, nil
+	}
+
+	collection = NewCollection(CollectionTypeBase, EmbeddingConfigsCollectionName)
+	collection.System = true
+
+	collection.Fields.Add(&TextField{
+		Name:     "collection_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:   "mode",
+		System: true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:   "field_name",
+		System: true,
+	})
+
+	collection.Fields.Add(&JSONField{
+		Name:   "fields",
+		System: true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:   "model",
+		System: true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:   "template",
+		Max:    5000,
+		System: true,
+	})
+
+	collection.Fields.Add(&BoolField{
+		Name:   "auto_generate",
+		System: true,
+	})
+
+	collection.Indexes = []string{
+		"CREATE UNIQUE INDEX idx_embedding_configs_collection ON _embedding_configs (collection_id)",
+	}
+
+	if err := app.Save(collection); err != nil {
+		// another goroutine could have created the collection in the meantime
+		errStr := err.Error()
+		if strings.Contains(errStr, "UNIQUE constraint failed") ||
+			strings.Contains(errStr, "already exists") ||
+			strings.Contains(errStr, "must be unique") {
+			collection, findErr := app.FindCollectionByNameOrId(EmbeddingConfigsCollectionName)
+			if findErr == nil {
+				return collection, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create embedding configs collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// upgradeEmbeddingConfigsCollection adds the fields missing
+// from the embedding configs collections created by older versions.
+func upgradeEmbeddingConfigsCollection(app App, collection *Collection) error {
+	if collection.Fields.GetByName("auto_generate") != nil {
+		return nil // already up-to-date
+	}
+
+	collection.Fields.Add(&BoolField{
+		Name:   "auto_generate",
+		System: true,
+	})
+
+	return app.Save(collection)
+}
+
+// SaveEmbeddingConfig validates and stores (creates or replaces) the embedding configuration of a collection.
+func SaveEmbeddingConfig(app App, config EmbeddingConfig) (*EmbeddingConfig, error) {
+	collection, err := app.FindCollectionByNameOrId(config.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+	config.CollectionId = collection.Id
+
+	if config.Mode == "" {
+		config.Mode = EmbeddingModeField
+	}
+
+	if _, err := resolveEmbeddingFieldName(config.Mode, config.FieldName, config.Fields); err != nil {
+		return nil, err
+	}
+
+	var sourceFields []string
+	switch config.Mode {
+	case EmbeddingModeField:
+		sourceFields = []string{config.FieldName}
+	case EmbeddingModeFields:
+		sourceFields = config.Fields
+	}
+	for _, name := range sourceFields {
+		field := collection.Fields.GetByName(name)
+		if field == nil {
+			return nil, fmt.Errorf("field '%s' not found in collection", name)
+		}
+		if !IsAppFieldEmbeddable(app, field) {
+			return nil, fmt.Errorf("field '%s' is not a text/editor field or is not marked as embeddable", name)
+		}
+	}
+
+	configsCollection, err := EnsureEmbeddingConfigsCollection(app)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := app.FindFirstRecordByData(configsCollection, "collection_id", collection.Id)
+	if err != nil {
+		record = NewRecord(configsCollection)
+		record.Set("collection_id", collection.Id)
+	}
+
+	record.Set("mode", config.Mode)
+	record.Set("field_name", config.FieldName)
+	record.Set("fields", config.Fields)
+	record.Set("model", config.Model)
+	record.Set("template", config.Template)
+	record.Set("auto_generate", config.AutoGenerate)
+
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("failed to save the embedding config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// FindEmbeddingConfig returns the stored embedding configuration of a collection.
+func FindEmbeddingConfig(app App, collectionNameOrId string) (*EmbeddingConfig, error) {
+	collection, err := app.FindCachedCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	configsCollection, err := app.FindCachedCollectionByNameOrId(EmbeddingConfigsCollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("no embedding config found for collection %s", collection.Name)
+	}
+
+	record, err := app.FindFirstRecordByData(configsCollection, "collection_id", collection.Id)
+	if err != nil {
+		return nil, fmt.Errorf("no embedding config found for collection %s", collection.Name)
+	}
+
+	config := &EmbeddingConfig{
+		CollectionId: collection.Id,
+		Mode:         EmbeddingMode(record.GetString("mode")),
+		FieldName:    record.GetString("field_name"),
+		Model:        record.GetString("model"),
+		Template:     record.GetString("template"),
+		AutoGenerate: record.GetBool("auto_generate"),
+	}
+
+	if err := record.UnmarshalJSONField("fields", &config.Fields); err != nil {
+		return nil, fmt.Errorf("failed to parse the embedding config fields: %w", err)
+	}
+
+	return config, nil
+}
+
+// EmbedCollection generates the embeddings of all records of a collection
+// using its stored embedding configuration (see [SaveEmbeddingConfig]).
+func EmbedCollection(app App, collectionNameOrId string) (*EmbeddingResponse, error) {
+	return EmbedCollectionWithContext(context.Background(), app, collectionNameOrId)
+}
+
+// EmbedCollectionWithContext is the same as [EmbedCollection] but
+// stops the generation when ctx is done (see [GenerateEmbeddingsWithContext]).
+func EmbedCollectionWithContext(ctx context.Context, app App, collectionNameOrId string) (*EmbeddingResponse, error) {
+	config, err := FindEmbeddingConfig(app, collectionNameOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	return GenerateEmbeddingsWithContext(ctx, app, config.request())
+}
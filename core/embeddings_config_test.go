@@ -0,0 +1,125 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// note: not parallel because of the shared embeddings cache
+func TestEmbedCollection(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{})
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embed_collection")
+
+	for _, title := range []string{"Hello", "World"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("missing config", func(t *testing.T) {
+		if _, err := core.EmbedCollection(app, collection.Name); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		invalidConfigs := []core.EmbeddingConfig{
+			{CollectionId: "missing", FieldName: "title"},
+			{CollectionId: collection.Name},
+			{CollectionId: collection.Name, FieldName: "created"},
+			{CollectionId: collection.Name, Mode: core.EmbeddingModeFields},
+		}
+
+		for i, config := range invalidConfigs {
+			if _, err := core.SaveEmbeddingConfig(app, config); err == nil {
+				t.Fatalf("[%d] Expected error, got nil", i)
+			}
+		}
+	})
+
+	t.Run("stored config", func(t *testing.T) {
+		_, err := core.SaveEmbeddingConfig(app, core.EmbeddingConfig{
+			CollectionId: collection.Name,
+			FieldName:    "content",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// replace the previous config
+		_, err = core.SaveEmbeddingConfig(app, core.EmbeddingConfig{
+			CollectionId: collection.Name,
+			Mode:         core.EmbeddingModeFields,
+			Fields:       []string{"title", "content"},
+			Model:        "custom-model",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := core.FindEmbeddingConfig(app, collection.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if config.CollectionId != collection.Id ||
+			config.Mode != core.EmbeddingModeFields ||
+			len(config.Fields) != 2 ||
+			config.Model != "custom-model" {
+			t.Fatalf("Unexpected stored config %+v", config)
+		}
+
+		result, err := core.EmbedCollection(app, collection.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generated != 2 {
+			t.Fatalf("Expected 2 generated embeddings, got %+v", result)
+		}
+
+		stats, err := core.GetEmbeddingStatsForField(app, collection.Id, core.CombinedFieldName(config.Fields))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.EmbeddedRecords != 2 {
+			t.Fatalf("Expected 2 embedded records, got %+v", stats)
+		}
+
+		embeddings, err := app.FindAllRecords(core.EmbeddingsCollectionName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, embedding := range embeddings {
+			if model := embedding.GetString("model"); model != "custom-model" {
+				t.Fatalf("Expected the config model to be used, got %q", model)
+			}
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		records, err := app.FindAllRecords(collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[0].Set("title", "changed")
+		if err := app.Save(records[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := core.EmbedCollectionWithContext(ctx, app, collection.Name); !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+	})
+}
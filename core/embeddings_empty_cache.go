@@ -0,0 +1,78 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// emptyEmbeddingTextsMaxEntries is the max number of the remembered
+// records without embeddable text (the new ones are ignored when full).
+const emptyEmbeddingTextsMaxEntries = 100000
+
+// emptyEmbeddingTextCache remembers the records that produced no embeddable text
+// so that the repeated (incremental) embedding runs skip them without evaluating
+// their text again until their source content or the text options change.
+type emptyEmbeddingTextCache struct {
+	mu      sync.RWMutex
+	entries map[string]uint64 // key: "embeddingsName:collectionId:fieldName:recordId", value: content fingerprint
+}
+
+// emptyEmbeddingTexts is the global negative cache of the records without embeddable text.
+var emptyEmbeddingTexts = &emptyEmbeddingTextCache{entries: map[string]uint64{}}
+
+// emptyEmbeddingTextKey generates the negative cache key of a single record field.
+func emptyEmbeddingTextKey(embeddingsName, collectionId, fieldName, recordId string) string {
+	return embeddingsName + ":" + cacheKey(collectionId, fieldName) + ":" + recordId
+}
+
+// emptyEmbeddingTextFingerprint returns a hash of the text options and
+// of the raw values of the record fields the embedded text is generated from.
+func emptyEmbeddingTextFingerprint(record *Record, fieldNames []string, options string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(options))
+
+	for _, name := range fieldNames {
+		fmt.Fprintf(h, "\x00%s\x00%v", name, record.GetRaw(name))
+	}
+
+	return h.Sum64()
+}
+
+// Has reports whether the key is known to have no embeddable text with the specified fingerprint.
+func (c *emptyEmbeddingTextCache) Has(key string, fingerprint uint64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	existing, ok := c.entries[key]
+
+	return ok && existing == fingerprint
+}
+
+// Set marks the key as having no embeddable text for the specified fingerprint.
+func (c *emptyEmbeddingTextCache) Set(key string, fingerprint uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= emptyEmbeddingTextsMaxEntries {
+		return
+	}
+
+	c.entries[key] = fingerprint
+}
+
+// Delete removes the key from the cache (ex. after its text is no longer empty).
+func (c *emptyEmbeddingTextCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// Clear removes all cache entries.
+func (c *emptyEmbeddingTextCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]uint64{}
+}
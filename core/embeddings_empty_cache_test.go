@@ -0,0 +1,142 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestGenerateEmbeddingsKnownEmpty(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache and the global preprocessors
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	// blanks the placeholder texts and counts their evaluations
+	var evaluations int
+	core.RegisterEmbeddingPreprocessor("test_known_empty", func(text string) string {
+		if text == "n/a" {
+			evaluations++
+			return ""
+		}
+		return text
+	})
+	defer core.UnregisterEmbeddingPreprocessor("test_known_empty")
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embedding_known_empty")
+
+	titles := []string{"hello world", "n/a", ""}
+	records := make([]*core.Record, len(titles))
+	for i, title := range titles {
+		records[i] = core.NewRecord(collection)
+		records[i].Set("title", title)
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "title"}
+
+	generate := func() *core.EmbeddingResponse {
+		response, err := core.GenerateEmbeddings(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	first := generate()
+	if first.Generated != 1 || first.Skipped != 2 || first.KnownEmpty != 0 {
+		t.Fatalf("Expected 1 generated, 2 skipped and 0 known empty records, got %+v", first)
+	}
+	if evaluations != 1 {
+		t.Fatalf("Expected the empty text to be evaluated once, got %d", evaluations)
+	}
+
+	second := generate()
+	if second.Generated != 1 || second.Skipped != 2 || second.KnownEmpty != 2 {
+		t.Fatalf("Expected 1 generated, 2 skipped and 2 known empty records, got %+v", second)
+	}
+	if evaluations != 1 {
+		t.Fatalf("Expected the known empty text to be skipped without evaluation, got %d evaluations", evaluations)
+	}
+
+	t.Run("different text options", func(t *testing.T) {
+		response, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			Mode:         core.EmbeddingModeRecord,
+			Template:     "{title}",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.KnownEmpty != 0 {
+			t.Fatalf("Expected the record mode to be evaluated separately, got %+v", response)
+		}
+	})
+
+	t.Run("content change", func(t *testing.T) {
+		records[2].Set("title", "new title")
+		if err := app.Save(records[2]); err != nil {
+			t.Fatal(err)
+		}
+
+		response := generate()
+		if response.KnownEmpty != 1 || response.Skipped != 1 || response.Generated != 2 {
+			t.Fatalf("Expected the changed record to be embedded again, got %+v", response)
+		}
+	})
+
+	t.Run("cleared on preprocessors change", func(t *testing.T) {
+		core.RegisterEmbeddingPreprocessor("test_known_empty_noop", func(text string) string { return text })
+		defer core.UnregisterEmbeddingPreprocessor("test_known_empty_noop")
+
+		before := evaluations
+
+		if response := generate(); response.KnownEmpty != 0 {
+			t.Fatalf("Expected no known empty records, got %+v", response)
+		}
+
+		if evaluations != before+1 {
+			t.Fatalf("Expected the empty text to be evaluated again, got %d evaluations", evaluations-before)
+		}
+	})
+}
+
+func TestGenerateEmbeddingsAllKnownEmptySkipped(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embedding_all_known_empty")
+
+	for i := 0; i < 3; i++ {
+		record := core.NewRecord(collection)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "title"}
+
+	for i, expectedKnownEmpty := range []int{0, 3} {
+		response, err := core.GenerateEmbeddings(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Generated != 0 || response.Skipped != 3 || response.KnownEmpty != expectedKnownEmpty {
+			t.Fatalf("[run %d] Expected 0 generated, 3 skipped and %d known empty records, got %+v", i, expectedKnownEmpty, response)
+		}
+	}
+
+	if len(transport.Inputs()) != 0 {
+		t.Fatalf("Expected no embeddings requests, got %v", transport.Inputs())
+	}
+}
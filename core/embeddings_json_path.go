@@ -0,0 +1,205 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONPathFieldName returns the derived field name under which the embeddings
+// of the specified json field path are stored (ex. "data.content.body").
+func JSONPathFieldName(fieldName string, path string) string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimPrefix(path, ".")
+
+	if strings.HasPrefix(path, "[") {
+		return fieldName + path
+	}
+
+	return fieldName + "." + path
+}
+
+// jsonPathSegment is a single parsed json path segment.
+type jsonPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseEmbeddingJSONPath parses a simplified JSONPath-like selector
+// (ex. "content.body", "$.items[0].text", "items[*].text", "meta.*").
+//
+// The supported syntax is a "." separated list of object keys with optional
+// "[n]" array indexes and "*" or "[*]" wildcards (matching all items or object values).
+func parseEmbeddingJSONPath(path string) ([]jsonPathSegment, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+
+	if path == "" {
+		return nil, errors.New("the json path must not be empty")
+	}
+
+	var segments []jsonPathSegment
+
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		var brackets string
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			key = part[:i]
+			brackets = part[i:]
+		}
+
+		// a leading index is allowed only for the first segment (ex. "[0].text")
+		if key == "" && (brackets == "" || len(segments) > 0) {
+			return nil, fmt.Errorf("invalid json path %q (empty key)", path)
+		}
+
+		switch key {
+		case "":
+		case "*":
+			segments = append(segments, jsonPathSegment{wildcard: true})
+		default:
+			segments = append(segments, jsonPathSegment{key: key})
+		}
+
+		for brackets != "" {
+			end := strings.IndexByte(brackets, ']')
+			if brackets[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid json path %q (unclosed bracket)", path)
+			}
+
+			inner := brackets[1:end]
+			brackets = brackets[end+1:]
+
+			if inner == "*" {
+				segments = append(segments, jsonPathSegment{wildcard: true})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid json path %q (invalid array index %q)", path, inner)
+			}
+
+			segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+		}
+	}
+
+	return segments, nil
+}
+
+// extractJSONPathText returns the text of the values matching the path segments
+// within the raw json value (joined with a new line).
+//
+// The matching objects and arrays are flattened to their scalar values
+// (in the order of the array items and the sorted object keys).
+func extractJSONPathText(raw []byte, segments []jsonPathSegment) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	var texts []string
+	for _, match := range matchJSONPath(value, segments) {
+		texts = appendJSONScalarTexts(texts, match)
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+// matchJSONPath returns the values matching the path segments.
+func matchJSONPath(value any, segments []jsonPathSegment) []any {
+	if len(segments) == 0 {
+		return []any{value}
+	}
+
+	segment := segments[0]
+	rest := segments[1:]
+
+	switch v := value.(type) {
+	case map[string]any:
+		if segment.isIndex {
+			return nil
+		}
+
+		if !segment.wildcard {
+			item, ok := v[segment.key]
+			if !ok {
+				return nil
+			}
+			return matchJSONPath(item, rest)
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		var result []any
+		for _, k := range keys {
+			result = append(result, matchJSONPath(v[k], rest)...)
+		}
+		return result
+	case []any:
+		if segment.isIndex {
+			if segment.index >= len(v) {
+				return nil
+			}
+			return matchJSONPath(v[segment.index], rest)
+		}
+
+		if !segment.wildcard {
+			return nil
+		}
+
+		var result []any
+		for _, item := range v {
+			result = append(result, matchJSONPath(item, rest)...)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// appendJSONScalarTexts appends the non-empty text representation
+// of the value scalars (recursively for objects and arrays).
+func appendJSONScalarTexts(texts []string, value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return texts
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return texts
+		}
+		return append(texts, v)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			texts = appendJSONScalarTexts(texts, v[k])
+		}
+		return texts
+	case []any:
+		for _, item := range v {
+			texts = appendJSONScalarTexts(texts, item)
+		}
+		return texts
+	default:
+		return append(texts, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestJSONPathFieldName(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		path     string
+		expected string
+	}{
+		{"content.body", "data.content.body"},
+		{"$.content.body", "data.content.body"},
+		{"items[*].text", "data.items[*].text"},
+		{"$[0].text", "data[0].text"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.path, func(t *testing.T) {
+			if v := core.JSONPathFieldName("data", s.path); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestExtractEmbeddingJSONPathText(t *testing.T) {
+	t.Parallel()
+
+	raw := `{
+		"content": {"body": "Hello world", "meta": {"lang": "en", "words": 2}},
+		"items": [{"text": "first"}, {"text": "second"}, {"other": "x"}],
+		"tags": ["a", "", "b"],
+		"empty": null
+	}`
+
+	scenarios := []struct {
+		name        string
+		raw         string
+		path        string
+		expected    string
+		expectError bool
+	}{
+		{"empty path", raw, "", "", true},
+		{"root only", raw, "$", "", true},
+		{"empty key", raw, "content..body", "", true},
+		{"unclosed bracket", raw, "items[0", "", true},
+		{"invalid index", raw, "items[a]", "", true},
+		{"negative index", raw, "items[-1]", "", true},
+		{"nested key", raw, "content.body", "Hello world", false},
+		{"root prefix", raw, "$.content.body", "Hello world", false},
+		{"missing key", raw, "content.missing", "", false},
+		{"null value", raw, "empty", "", false},
+		{"object flattening", raw, "content.meta", "en\n2", false},
+		{"array index", raw, "items[1].text", "second", false},
+		{"array index out of range", raw, "items[10].text", "", false},
+		{"array wildcard", raw, "items[*].text", "first\nsecond", false},
+		{"object wildcard", raw, "content.*", "Hello world\nen\n2", false},
+		{"array of scalars", raw, "tags", "a\nb", false},
+		{"index of an object", raw, "content[0]", "", false},
+		{"root array", `[{"text": "root"}]`, "[0].text", "root", false},
+		{"invalid json", `{invalid`, "content", "", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			text, err := core.ExtractEmbeddingJSONPathText(s.raw, s.path)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if text != s.expected {
+				t.Fatalf("Expected text %q, got %q", s.expected, text)
+			}
+		})
+	}
+}
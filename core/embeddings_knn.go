@@ -0,0 +1,478 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/dbx"
+)
+
+const (
+	// KNNCollectionName is the name of the system collection for storing
+	// the precomputed nearest neighbors of the embedded records
+	KNNCollectionName = "_knn"
+
+	// DefaultKNNNeighbors is the default number of nearest neighbors computed per record
+	DefaultKNNNeighbors = 10
+
+	// MaxKNNNeighbors is the max number of nearest neighbors computed per record
+	MaxKNNNeighbors = 100
+
+	// MaxKNNEmbeddings is the max number of embeddings that could be
+	// processed in a single (brute-force) nearest neighbors build
+	MaxKNNEmbeddings = 20000
+)
+
+// BuildKNNRequest represents a request to build the nearest neighbors graph of a collection.
+type BuildKNNRequest struct {
+	CollectionId string        `json:"collectionId"`
+	FieldName    string        `json:"fieldName,omitempty"` // For field-level mode
+	Mode         EmbeddingMode `json:"mode,omitempty"`      // "field", "record" or "fields"
+	Fields       []string      `json:"fields,omitempty"`    // For multi-field mode
+	K            int           `json:"k"`
+
+	// Metric is the vectors comparison metric ("cosine", "dot" or "euclidean").
+	//
+	// Default to the [DefaultSimilarityMetric] of the collection embedding model.
+	Metric SimilarityMetric `json:"metric,omitempty"`
+
+	// Store indicates whether to persist the neighbors in the _knn collection
+	// (replacing any previously stored ones) instead of returning them.
+	Store bool `json:"store,omitempty"`
+
+	// EmbeddingsCollection is an optional custom embeddings collection name
+	// to build the neighbors from (default to [EmbeddingsCollectionName]).
+	//
+	// The stored neighbors are kept separately for each embeddings collection.
+	EmbeddingsCollection string `json:"embeddingsCollection,omitempty"`
+}
+
+// BuildKNNResponse represents the response from a nearest neighbors graph build.
+type BuildKNNResponse struct {
+	Processed int `json:"processed"`
+	Stored    int `json:"stored"`
+
+	// Metric is the resolved vectors comparison metric of the build.
+	Metric SimilarityMetric `json:"metric"`
+
+	// Neighbors contains the nearest neighbors of each record
+	// (populated only when the neighbors are not stored)
+	Neighbors map[string][]SimilarRecord `json:"neighbors,omitempty"`
+}
+
+// EnsureKNNCollection creates the _knn system collection if it doesn't exist.
+// Returns the nearest neighbors collection.
+func EnsureKNNCollection(app App) (*Collection, error) {
+	collection, err := app.FindCollectionByNameOrId(KNNCollectionName)
+	if err == nil {
+		var changed bool
+
+		if collection.Fields.GetByName("embeddings_collection") == nil {
+			collection.Fields.Add(&TextField{
+				Name:   "embeddings_collection",
+				System: true,
+			})
+			changed = true
+		}
+
+		// replace the old record field unique indexes that don't allow the
+		// same record id in different collections or embeddings collections
+		//
+		// note: saved without validation because the system fields
+		// unique indexes can't be otherwise changed
+		for i, index := range collection.Indexes {
+			if strings.Contains(index, "UNIQUE INDEX") && index != knnRecordFieldIndex {
+				collection.Indexes[i] = knnRecordFieldIndex
+				changed = true
+				break
+			}
+		}
+
+		if changed {
+			if err := app.SaveNoValidate(collection); err != nil {
+				return nil, fmt.Errorf("failed to upgrade the knn collection: %w", err)
+			}
+		}
+
+		return collection, nil
+	}
+
+	collection = NewCollection(CollectionTypeBase, KNNCollectionName)
+	collection.System = true
+
+	collection.Fields.Add(&TextField{
+		Name:     "record_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "collection_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "field_name",
+		Required: true,
+		System:   true,
+	})
+
+	// The custom embeddings collection name of the neighbors (empty for [EmbeddingsCollectionName])
+	collection.Fields.Add(&TextField{
+		Name:   "embeddings_collection",
+		System: true,
+	})
+
+	// Ordered list of {recordId, similarity} neighbors
+	collection.Fields.Add(&JSONField{
+		Name:   "neighbors",
+		System: true,
+	})
+
+	collection.Indexes = []string{
+		knnRecordFieldIndex,
+		"CREATE INDEX idx_knn_collection_field ON _knn (collection_id, field_name)",
+	}
+
+	if err := app.Save(collection); err != nil {
+		// another goroutine could have created the collection in the meantime
+		errStr := err.Error()
+		if strings.Contains(errStr, "UNIQUE constraint failed") ||
+			strings.Contains(errStr, "already exists") ||
+			strings.Contains(errStr, "must be unique") {
+			collection, findErr := app.FindCollectionByNameOrId(KNNCollectionName)
+			if findErr == nil {
+				return collection, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create knn collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// knnRecordFieldIndex is the unique index of the stored neighbors of a single record field.
+const knnRecordFieldIndex = "CREATE UNIQUE INDEX idx_knn_collection_record_field ON _knn (embeddings_collection, collection_id, record_id, field_name)"
+
+// knnEmbeddingsCollectionKey returns the "embeddings_collection" value of the
+// stored neighbors built from the specified (already resolved) embeddings collection.
+//
+// The default embeddings collection neighbors are stored with an empty value
+// to remain compatible with the neighbors stored before the custom embeddings collections.
+func knnEmbeddingsCollectionKey(embeddingsName string) string {
+	if embeddingsName == EmbeddingsCollectionName {
+		return ""
+	}
+	return embeddingsName
+}
+
+// BuildKNN computes the top K nearest neighbors of every embedded
+// record of a collection field (or the record-level embeddings).
+//
+// This is a brute-force computation over the cached embeddings and
+// it is bounded by [MaxKNNEmbeddings].
+func BuildKNN(app App, req BuildKNNRequest) (*BuildKNNResponse, error) {
+	return BuildKNNWithContext(context.Background(), app, req)
+}
+
+// BuildKNNWithContext is the same as [BuildKNN] but stops the
+// computation (without storing anything) when ctx is done,
+// returning [ErrAIRequestCanceled].
+func BuildKNNWithContext(ctx context.Context, app App, req BuildKNNRequest) (*BuildKNNResponse, error) {
+	settings := app.Settings()
+
+	if !settings.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	collection, err := app.FindCollectionByNameOrId(req.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	embeddingsName, err := resolveEmbeddingsCollectionName(req.EmbeddingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = EmbeddingModeField
+	}
+
+	fieldName, err := resolveEmbeddingFieldName(mode, req.FieldName, req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	k := req.K
+	if k <= 0 {
+		k = DefaultKNNNeighbors
+	}
+	if k > MaxKNNNeighbors {
+		return nil, fmt.Errorf("k must be at most %d", MaxKNNNeighbors)
+	}
+
+	// Use the same model as the one of the stored collection embedding config (if any)
+	model := settings.AI.EmbeddingModel
+	if config, err := FindEmbeddingConfig(app, collection.Id); err == nil && config.Model != "" {
+		model = config.Model
+	}
+
+	metric := req.Metric
+	if metric == "" {
+		metric = DefaultSimilarityMetric(model)
+	}
+	if metric != SimilarityMetricCosine && metric != SimilarityMetricDot && metric != SimilarityMetricEuclidean {
+		return nil, fmt.Errorf("invalid metric: %s (must be 'cosine', 'dot' or 'euclidean')", metric)
+	}
+
+	// check the limit before loading the (possibly huge) embeddings set
+	total, err := countEmbeddings(app, embeddingsName, collection.Id, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	if total > MaxKNNEmbeddings {
+		return nil, fmt.Errorf("too many embeddings (%d) for a nearest neighbors build (max %d)", total, MaxKNNEmbeddings)
+	}
+
+	embeddings, err := loadCachedEmbeddings(app, embeddingsName, collection.Id, fieldName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbors, err := computeKNN(ctx, embeddings, k, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &BuildKNNResponse{Processed: len(embeddings), Metric: metric}
+
+	if !req.Store {
+		response.Neighbors = neighbors
+		return response, nil
+	}
+
+	response.Stored, err = storeKNN(app, embeddingsName, collection.Id, fieldName, neighbors)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ComputeKNN returns the top k most similar embeddings (excluding itself)
+// for each of the provided embeddings compared with the specified metric.
+func ComputeKNN(embeddings []CachedEmbedding, k int, metric SimilarityMetric) map[string][]SimilarRecord {
+	result, _ := computeKNN(context.Background(), embeddings, k, metric)
+	return result
+}
+
+// computeKNN is the same as [ComputeKNN] but stops scheduling
+// the remaining embeddings when ctx is done (returning [ErrAIRequestCanceled]).
+func computeKNN(ctx context.Context, embeddings []CachedEmbedding, k int, metric SimilarityMetric) (map[string][]SimilarRecord, error) {
+	result := make(map[string][]SimilarRecord, len(embeddings))
+	if len(embeddings) == 0 || k <= 0 {
+		return result, nil
+	}
+
+	numWorkers := min(runtime.NumCPU(), len(embeddings))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobs := make(chan int)
+
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				current := embeddings[i]
+
+				similar := make([]SimilarRecord, 0, len(embeddings)-1)
+				for _, other := range embeddings {
+					if other.RecordId == current.RecordId {
+						continue
+					}
+					similar = append(similar, SimilarRecord{
+						RecordId:   other.RecordId,
+						Similarity: current.pairSimilarity(other, metric),
+					})
+				}
+
+				sortSimilarRecords(similar)
+
+				if len(similar) > k {
+					similar = similar[:k]
+				}
+
+				mu.Lock()
+				result[current.RecordId] = similar
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var canceled error
+	for i := range embeddings {
+		if canceled = checkAIContext(ctx); canceled != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	if canceled != nil {
+		return nil, canceled
+	}
+
+	return result, nil
+}
+
+// storeKNN replaces the stored nearest neighbors of a collection field
+// built from the specified embeddings collection.
+func storeKNN(app App, embeddingsName, collectionId, fieldName string, neighbors map[string][]SimilarRecord) (int, error) {
+	knnCollection, err := EnsureKNNCollection(app)
+	if err != nil {
+		return 0, err
+	}
+
+	embeddingsKey := knnEmbeddingsCollectionKey(embeddingsName)
+
+	var stored int
+
+	err = app.RunInTransaction(func(txApp App) error {
+		existing, err := txApp.FindAllRecords(knnCollection, dbx.HashExp{
+			"embeddings_collection": embeddingsKey,
+			"collection_id":         collectionId,
+			"field_name":            fieldName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch the existing neighbors: %w", err)
+		}
+
+		for _, record := range existing {
+			if err := txApp.Delete(record); err != nil {
+				return fmt.Errorf("failed to delete the existing neighbors: %w", err)
+			}
+		}
+
+		for recordId, similar := range neighbors {
+			record := NewRecord(knnCollection)
+			record.Set("record_id", recordId)
+			record.Set("collection_id", collectionId)
+			record.Set("field_name", fieldName)
+			record.Set("embeddings_collection", embeddingsKey)
+			record.Set("neighbors", similar)
+
+			if err := txApp.Save(record); err != nil {
+				return fmt.Errorf("failed to store the neighbors of record %s: %w", recordId, err)
+			}
+			stored++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return stored, nil
+}
+
+// countEmbeddings returns the number of the active embeddings of the specified collection/field
+// (from the embeddings cache if available).
+func countEmbeddings(app App, embeddingsName, collectionId, fieldName string) (int, error) {
+	if cached, ok := embeddingCache.Get(embeddingsCacheCollectionKey(embeddingsName, collectionId), fieldName); ok {
+		return len(cached), nil
+	}
+
+	embeddingsCollection, err := app.FindCollectionByNameOrId(embeddingsName)
+	if err != nil {
+		return 0, fmt.Errorf("embeddings collection not found: %w", err)
+	}
+
+	exprs := []dbx.Expression{dbx.HashExp{"collection_id": collectionId, "field_name": fieldName}}
+	if embeddingsCollection.Fields.GetByName(EmbeddingsFieldDeleted) != nil {
+		exprs = append(exprs, dbx.HashExp{EmbeddingsFieldDeleted: ""})
+	}
+
+	total, err := app.CountRecords(embeddingsCollection, exprs...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count the embeddings: %w", err)
+	}
+
+	return int(total), nil
+}
+
+// DeleteKNNForRecord deletes the stored nearest neighbors of a single collection record
+// from all embeddings collections (the record could still be listed in the stored neighbors of the other records until the next build).
+func DeleteKNNForRecord(app App, collectionId, recordId string) error {
+	knnCollection, err := app.FindCachedCollectionByNameOrId(KNNCollectionName)
+	if err != nil {
+		return nil // no stored neighbors
+	}
+
+	records, err := app.FindAllRecords(knnCollection, dbx.HashExp{
+		"collection_id": collectionId,
+		"record_id":     recordId,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch the record neighbors: %w", err)
+	}
+
+	for _, record := range records {
+		if err := app.Delete(record); err != nil {
+			return fmt.Errorf("failed to delete the record neighbors: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FindStoredKNN returns the stored nearest neighbors of a single collection record
+// built from the specified embeddings collection (empty for [EmbeddingsCollectionName])
+// (see [BuildKNN]).
+//
+// For record-level or multi-field neighbors use [RecordLevelFieldName]
+// or [CombinedFieldName] as fieldName.
+func FindStoredKNN(app App, embeddingsName, collectionId, recordId, fieldName string) ([]SimilarRecord, error) {
+	embeddingsName, err := resolveEmbeddingsCollectionName(embeddingsName)
+	if err != nil {
+		return nil, err
+	}
+
+	knnCollection, err := app.FindCollectionByNameOrId(KNNCollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("knn collection not found: %w", err)
+	}
+
+	record := &Record{}
+	err = app.RecordQuery(knnCollection).
+		AndWhere(dbx.HashExp{
+			"embeddings_collection": knnEmbeddingsCollectionKey(embeddingsName),
+			"collection_id":         collectionId,
+			"record_id":             recordId,
+			"field_name":            fieldName,
+		}).
+		Limit(1).
+		One(record)
+	if err != nil {
+		return nil, fmt.Errorf("no stored neighbors found for record %s", recordId)
+	}
+
+	var neighbors []SimilarRecord
+	if err := record.UnmarshalJSONField("neighbors", &neighbors); err != nil {
+		return nil, fmt.Errorf("failed to parse the stored neighbors: %w", err)
+	}
+
+	return neighbors, nil
+}
@@ -0,0 +1,375 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestComputeKNN(t *testing.T) {
+	t.Parallel()
+
+	embeddings := []core.CachedEmbedding{
+		{RecordId: "r1", Embedding: []float32{1, 0}, Magnitude: 1},
+		{RecordId: "r2", Embedding: []float32{0.8, 0.6}, Magnitude: 1},
+		{RecordId: "r3", Embedding: []float32{0, 1}, Magnitude: 1},
+	}
+
+	result := core.ComputeKNN(embeddings, 1, core.SimilarityMetricCosine)
+
+	expected := map[string]string{
+		"r1": "r2",
+		"r2": "r1",
+		"r3": "r2",
+	}
+
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), result)
+	}
+
+	for recordId, neighborId := range expected {
+		neighbors := result[recordId]
+		if len(neighbors) != 1 || neighbors[0].RecordId != neighborId {
+			t.Fatalf("Expected %s neighbors to be [%s], got %v", recordId, neighborId, neighbors)
+		}
+	}
+}
+
+func TestComputeKNNMetric(t *testing.T) {
+	t.Parallel()
+
+	embeddings := []core.CachedEmbedding{
+		{RecordId: "r1", Embedding: []float32{1, 0}, Magnitude: 1},
+		{RecordId: "r2", Embedding: []float32{0.8, 0.6}, Magnitude: 1},
+		{RecordId: "r3", Embedding: []float32{0, 3}, Magnitude: 3},
+	}
+
+	scenarios := []struct {
+		metric   core.SimilarityMetric
+		expected string
+	}{
+		{core.SimilarityMetricCosine, "r1"},
+		{core.SimilarityMetricDot, "r3"},
+		{core.SimilarityMetricEuclidean, "r1"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(string(s.metric), func(t *testing.T) {
+			neighbors := core.ComputeKNN(embeddings, 1, s.metric)["r2"]
+			if len(neighbors) != 1 || neighbors[0].RecordId != s.expected {
+				t.Fatalf("Expected r2 neighbors to be [%s], got %v", s.expected, neighbors)
+			}
+		})
+	}
+}
+
+func TestComputeKNNQuantized(t *testing.T) {
+	t.Parallel()
+
+	// mixed quantized (see AIConfig.EmbeddingCacheQuantization) and float embeddings
+	embeddings := []core.CachedEmbedding{
+		{RecordId: "r1", Quantized: []int8{127, 0}, Scale: 1.0 / 127, Magnitude: 1},
+		{RecordId: "r2", Quantized: []int8{127, 95}, Scale: 0.8 / 127, Magnitude: 1},
+		{RecordId: "r3", Embedding: []float32{0, 1}, Magnitude: 1},
+	}
+
+	result := core.ComputeKNN(embeddings, 2, core.SimilarityMetricCosine)
+
+	expected := map[string][]string{
+		"r1": {"r2", "r3"},
+		"r2": {"r1", "r3"},
+		"r3": {"r2", "r1"},
+	}
+
+	for recordId, neighborIds := range expected {
+		neighbors := result[recordId]
+		if len(neighbors) != len(neighborIds) {
+			t.Fatalf("Expected %s neighbors %v, got %v", recordId, neighborIds, neighbors)
+		}
+
+		for i, id := range neighborIds {
+			if neighbors[i].RecordId != id {
+				t.Fatalf("Expected %s neighbors %v, got %v", recordId, neighborIds, neighbors)
+			}
+		}
+	}
+
+	// r1·r2 ≈ 0.8
+	if v := result["r1"][0].Similarity; v < 0.79 || v > 0.81 {
+		t.Fatalf("Expected r1-r2 similarity ~0.8, got %v", v)
+	}
+}
+
+func TestBuildKNN(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_build_knn")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": {1, 0, 0},
+		"r2": {0.9, 0.1, 0},
+		"r3": {0.5, 0.5, 0.1},
+		"r4": {0, 1, 0.3},
+		"r5": {0, 0.2, 1},
+	})
+
+	t.Run("without store", func(t *testing.T) {
+		result, err := core.BuildKNN(app, core.BuildKNNRequest{
+			CollectionId: collection.Name,
+			FieldName:    "title",
+			K:            2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Processed != 5 || result.Stored != 0 || len(result.Neighbors) != 5 {
+			t.Fatalf("Expected 5 processed and returned (not stored) entries, got %+v", result)
+		}
+
+		if _, err := core.FindStoredKNN(app, "", collection.Id, "r1", "title"); err == nil {
+			t.Fatal("Expected no stored neighbors")
+		}
+	})
+
+	t.Run("with store", func(t *testing.T) {
+		// build twice to ensure that the old entries are replaced
+		for i := 0; i < 2; i++ {
+			result, err := core.BuildKNN(app, core.BuildKNNRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+				K:            2,
+				Store:        true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Processed != 5 || result.Stored != 5 || len(result.Neighbors) != 0 {
+				t.Fatalf("Expected 5 processed and stored entries, got %+v", result)
+			}
+		}
+
+		total, err := app.CountRecords(core.KNNCollectionName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 5 {
+			t.Fatalf("Expected 5 stored knn records, got %d", total)
+		}
+
+		for _, recordId := range []string{"r1", "r2", "r3", "r4", "r5"} {
+			stored, err := core.FindStoredKNN(app, "", collection.Id, recordId, "title")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			search, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+				RecordId:     recordId,
+				Limit:        2,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(stored) != len(search.Results) {
+				t.Fatalf("[%s] Expected %d stored neighbors, got %v", recordId, len(search.Results), stored)
+			}
+
+			for i, r := range search.Results {
+				if stored[i].RecordId != r.RecordId || stored[i].Similarity != r.Similarity {
+					t.Fatalf("[%s] Expected stored neighbor %d to be %v, got %v", recordId, i, r, stored[i])
+				}
+			}
+		}
+	})
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := core.BuildKNNWithContext(ctx, app, core.BuildKNNRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			K:            2,
+		})
+		if !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+	})
+}
+
+func TestBuildKNNSharedRecordIdAndDelete(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collectionA := createTestEmbeddingsSourceCollection(t, app, "test_knn_shared_a")
+	collectionB := createTestEmbeddingsSourceCollection(t, app, "test_knn_shared_b")
+
+	const sharedId = "sharedknn000001"
+
+	for _, collection := range []*core.Collection{collectionA, collectionB} {
+		record := core.NewRecord(collection)
+		record.Id = sharedId
+		record.Set("title", "shared")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+			sharedId: {1, 0},
+			"other":  {0.8, 0.6},
+		})
+
+		_, err := core.BuildKNN(app, core.BuildKNNRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			K:            1,
+			Store:        true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, collection := range []*core.Collection{collectionA, collectionB} {
+		if _, err := core.FindStoredKNN(app, "", collection.Id, sharedId, "title"); err != nil {
+			t.Fatalf("[%s] Expected stored neighbors, got %v", collection.Name, err)
+		}
+	}
+
+	record, err := app.FindRecordById(collectionA, sharedId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := core.FindStoredKNN(app, "", collectionA.Id, sharedId, "title"); err == nil {
+		t.Fatal("Expected the deleted record neighbors to be removed")
+	}
+
+	if _, err := core.FindStoredKNN(app, "", collectionB.Id, sharedId, "title"); err != nil {
+		t.Fatalf("Expected the other collection record neighbors to remain, got %v", err)
+	}
+}
+
+func TestEnsureKNNCollectionUpgradeRecordIndex(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := core.EnsureKNNCollection(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a collection created with the old record field unique index
+	// (and without the embeddings_collection field)
+	collection.Fields.RemoveByName("embeddings_collection")
+	collection.Indexes = []string{
+		"CREATE UNIQUE INDEX idx_knn_record_field ON _knn (record_id, field_name)",
+		"CREATE INDEX idx_knn_collection_field ON _knn (collection_id, field_name)",
+	}
+	if err := app.SaveNoValidate(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err = core.EnsureKNNCollection(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "CREATE UNIQUE INDEX idx_knn_collection_record_field ON _knn (embeddings_collection, collection_id, record_id, field_name)"
+	if len(collection.Indexes) != 2 || collection.Indexes[0] != expected {
+		t.Fatalf("Expected the upgraded %q index, got %v", expected, collection.Indexes)
+	}
+
+	if collection.Fields.GetByName("embeddings_collection") == nil {
+		t.Fatal("Expected the embeddings_collection field to be added")
+	}
+}
+
+func TestBuildKNNCustomEmbeddingsCollection(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_knn_custom_store")
+
+	const tenantEmbeddings = "_embeddings_tenant_knn"
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": {1, 0},
+		"r2": {0, 1},
+	})
+	storeTestEmbeddingsIn(t, app, tenantEmbeddings, collection.Id, "title", map[string][]float32{
+		"r1": {1, 0},
+		"r2": {0.8, 0.6},
+		"r3": {0.6, 0.8},
+	})
+
+	for _, embeddingsName := range []string{"", tenantEmbeddings} {
+		_, err := core.BuildKNN(app, core.BuildKNNRequest{
+			CollectionId:         collection.Id,
+			FieldName:            "title",
+			K:                    1,
+			Store:                true,
+			EmbeddingsCollection: embeddingsName,
+		})
+		if err != nil {
+			t.Fatalf("[%q] Failed to build the neighbors: %v", embeddingsName, err)
+		}
+	}
+
+	// rebuilding the default collection neighbors shouldn't replace the custom ones
+	scenarios := []struct {
+		embeddingsName   string
+		recordId         string
+		expectedNeighbor string
+	}{
+		{"", "r1", "r2"},
+		{tenantEmbeddings, "r1", "r2"},
+		{tenantEmbeddings, "r3", "r2"},
+	}
+
+	for _, s := range scenarios {
+		neighbors, err := core.FindStoredKNN(app, s.embeddingsName, collection.Id, s.recordId, "title")
+		if err != nil {
+			t.Fatalf("[%q:%s] Expected stored neighbors, got %v", s.embeddingsName, s.recordId, err)
+		}
+
+		if len(neighbors) != 1 || neighbors[0].RecordId != s.expectedNeighbor {
+			t.Fatalf("[%q:%s] Expected neighbor %s, got %v", s.embeddingsName, s.recordId, s.expectedNeighbor, neighbors)
+		}
+	}
+
+	if _, err := core.FindStoredKNN(app, "", collection.Id, "r3", "title"); err == nil {
+		t.Fatal("Expected no default collection neighbors for the custom collection only record")
+	}
+
+	if _, err := core.BuildKNN(app, core.BuildKNNRequest{CollectionId: collection.Id, FieldName: "title", EmbeddingsCollection: "invalid"}); err == nil {
+		t.Fatal("Expected invalid embeddings collection error, got nil")
+	}
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"strings"
+	"sync"
+)
+
+// EmbeddingTextPreprocessor defines a text transformation step applied
+// to the text before its embedding (ex. lowercasing or PII redaction).
+type EmbeddingTextPreprocessor func(text string) string
+
+type embeddingPreprocessorEntry struct {
+	name         string
+	preprocessor EmbeddingTextPreprocessor
+}
+
+// embeddingPreprocessors holds the registered text preprocessors in registration order
+var embeddingPreprocessors = struct {
+	mu      sync.RWMutex
+	entries []embeddingPreprocessorEntry
+}{}
+
+// RegisterEmbeddingPreprocessor registers a named text preprocessing step that is applied
+// (in registration order) to the embedded texts of all modes and to the search query texts
+// right before the embeddings API call.
+//
+// Registering a preprocessor with an already registered name replaces it.
+//
+// The built-in [CollapseEmbeddingWhitespace] step could be enabled with:
+//
+//	core.RegisterEmbeddingPreprocessor("whitespace", core.CollapseEmbeddingWhitespace)
+func RegisterEmbeddingPreprocessor(name string, preprocessor EmbeddingTextPreprocessor) {
+	if name == "" || preprocessor == nil {
+		return
+	}
+
+	embeddingPreprocessors.mu.Lock()
+	defer embeddingPreprocessors.mu.Unlock()
+
+	// the preprocessors could change which texts are empty
+	emptyEmbeddingTexts.Clear()
+
+	for i, entry := range embeddingPreprocessors.entries {
+		if entry.name == name {
+			embeddingPreprocessors.entries[i].preprocessor = preprocessor
+			return
+		}
+	}
+
+	embeddingPreprocessors.entries = append(embeddingPreprocessors.entries, embeddingPreprocessorEntry{
+		name:         name,
+		preprocessor: preprocessor,
+	})
+}
+
+// UnregisterEmbeddingPreprocessor removes the text preprocessor registered with the specified name.
+func UnregisterEmbeddingPreprocessor(name string) {
+	embeddingPreprocessors.mu.Lock()
+	defer embeddingPreprocessors.mu.Unlock()
+
+	emptyEmbeddingTexts.Clear()
+
+	for i, entry := range embeddingPreprocessors.entries {
+		if entry.name == name {
+			embeddingPreprocessors.entries = append(embeddingPreprocessors.entries[:i], embeddingPreprocessors.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// preprocessEmbeddingText applies the registered text preprocessors to text.
+func preprocessEmbeddingText(text string) string {
+	embeddingPreprocessors.mu.RLock()
+	defer embeddingPreprocessors.mu.RUnlock()
+
+	for _, entry := range embeddingPreprocessors.entries {
+		text = entry.preprocessor(text)
+	}
+
+	return text
+}
+
+// CollapseEmbeddingWhitespace is a built-in text preprocessor that trims the text
+// and collapses the consecutive whitespace characters into a single space.
+func CollapseEmbeddingWhitespace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,106 @@
+package core_test
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestCollapseEmbeddingWhitespace(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		text     string
+		expected string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"hello", "hello"},
+		{"  hello \n\n  world\t! ", "hello world !"},
+	}
+
+	for _, s := range scenarios {
+		if result := core.CollapseEmbeddingWhitespace(s.text); result != s.expected {
+			t.Fatalf("Expected %q for %q, got %q", s.expected, s.text, result)
+		}
+	}
+}
+
+func TestEmbeddingPreprocessors(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache and the global preprocessors
+	core.ClearEmbeddingCache()
+
+	emailRegex := regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+
+	var calls []string
+	core.RegisterEmbeddingPreprocessor("redact", func(text string) string {
+		calls = append(calls, "redact")
+		return emailRegex.ReplaceAllString(text, "[email]")
+	})
+	core.RegisterEmbeddingPreprocessor("whitespace", func(text string) string {
+		calls = append(calls, "whitespace")
+		return core.CollapseEmbeddingWhitespace(text)
+	})
+	defer core.UnregisterEmbeddingPreprocessor("redact")
+	defer core.UnregisterEmbeddingPreprocessor("whitespace")
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embedding_preprocessors")
+
+	record := core.NewRecord(collection)
+	record.Set("title", "contact   john@example.com  today")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []core.EmbeddingRequest{
+		{CollectionId: collection.Id, FieldName: "title"},
+		{CollectionId: collection.Id, Mode: core.EmbeddingModeRecord, Template: "Title: {title}"},
+	}
+	for _, req := range requests {
+		if _, err := core.GenerateEmbeddings(app, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+		Text:         "write to  jane@example.com",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedInputs := []string{
+		"contact [email] today",
+		"Title: contact [email] today",
+		"write to [email]",
+	}
+	if inputs := transport.Inputs(); !slices.Equal(inputs, expectedInputs) {
+		t.Fatalf("Expected inputs %q, got %q", expectedInputs, inputs)
+	}
+
+	// the preprocessors should run in registration order
+	if order := strings.Join(calls[:2], ","); order != "redact,whitespace" {
+		t.Fatalf("Expected the preprocessors to run in registration order, got %s", order)
+	}
+
+	t.Run("unregister", func(t *testing.T) {
+		core.UnregisterEmbeddingPreprocessor("redact")
+		core.UnregisterEmbeddingPreprocessor("whitespace")
+
+		if _, err := core.GenerateEmbeddings(app, requests[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		inputs := transport.Inputs()
+		if last := inputs[len(inputs)-1]; last != "contact   john@example.com  today" {
+			t.Fatalf("Expected the original text after unregistering, got %q", last)
+		}
+	})
+}
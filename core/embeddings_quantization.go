@@ -0,0 +1,159 @@
+package core
+
+import (
+	"math"
+)
+
+// quantizeEmbedding converts the vector to int8 values using a symmetric
+// per-vector scale factor (v[i] ≈ q[i] * scale).
+//
+// Returns a zero scale for all-zero vectors.
+func quantizeEmbedding(v []float32) ([]int8, float32) {
+	var maxAbs float32
+	for _, x := range v {
+		if x < 0 {
+			x = -x
+		}
+		if x > maxAbs {
+			maxAbs = x
+		}
+	}
+
+	quantized := make([]int8, len(v))
+	if maxAbs == 0 {
+		return quantized, 0
+	}
+
+	scale := maxAbs / math.MaxInt8
+	for i, x := range v {
+		quantized[i] = int8(math.Round(float64(x / scale)))
+	}
+
+	return quantized, scale
+}
+
+// quantizeCachedEmbeddings replaces the float32 vectors of the embeddings
+// with their int8 quantized version (the magnitudes are kept as they are
+// since they are computed from the original vectors).
+func quantizeCachedEmbeddings(embeddings []CachedEmbedding) {
+	for i := range embeddings {
+		if embeddings[i].Quantized != nil {
+			continue
+		}
+
+		embeddings[i].Quantized, embeddings[i].Scale = quantizeEmbedding(embeddings[i].Embedding)
+		embeddings[i].Embedding = nil
+	}
+}
+
+// quantizedDotProduct calculates the dot product of a float32 vector
+// and an int8 quantized vector with the specified scale factor.
+func quantizedDotProduct(a []float32, b []int8, scaleB float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot float32
+	for i := range a {
+		dot += a[i] * float32(b[i])
+	}
+
+	return dot * scaleB
+}
+
+// quantizedPairDotProduct calculates the dot product of two int8 quantized vectors
+// (the integer products are accumulated in int32 to avoid overflows).
+func quantizedPairDotProduct(a []int8, scaleA float32, b []int8, scaleB float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot int32
+	for i := range a {
+		dot += int32(a[i]) * int32(b[i])
+	}
+
+	return float32(dot) * scaleA * scaleB
+}
+
+// Dimensions returns the number of the embedding vector dimensions
+// (regardless of whether it is quantized or not).
+func (e CachedEmbedding) Dimensions() int {
+	if e.Quantized != nil {
+		return len(e.Quantized)
+	}
+
+	return len(e.Embedding)
+}
+
+// dotProduct calculates the dot product of the embedding with the query vector.
+func (e CachedEmbedding) dotProduct(query []float32) float32 {
+	if e.Quantized != nil {
+		return quantizedDotProduct(query, e.Quantized, e.Scale)
+	}
+
+	return dotProduct(query, e.Embedding)
+}
+
+// similarity returns the metric similarity score of the embedding and
+// the query vector with its pre-computed magnitude.
+func (e CachedEmbedding) similarity(query []float32, queryMagnitude float32, metric SimilarityMetric) float32 {
+	if e.Quantized == nil {
+		switch metric {
+		case SimilarityMetricDot:
+			return dotProduct(query, e.Embedding)
+		case SimilarityMetricEuclidean:
+			return euclideanDistanceToSimilarity(euclideanDistanceOptimized(query, queryMagnitude, e.Embedding, e.Magnitude))
+		default:
+			return cosineSimilarityOptimized(query, queryMagnitude, e.Embedding, e.Magnitude)
+		}
+	}
+
+	if len(query) != len(e.Quantized) {
+		return 0
+	}
+
+	dot := e.dotProduct(query)
+
+	switch metric {
+	case SimilarityMetricDot:
+		return dot
+	case SimilarityMetricEuclidean:
+		return euclideanDistanceToSimilarity(euclideanDistanceFromDot(dot, queryMagnitude, e.Magnitude))
+	default:
+		if queryMagnitude == 0 || e.Magnitude == 0 {
+			return 0
+		}
+		return dot / (queryMagnitude * e.Magnitude)
+	}
+}
+
+// pairSimilarity returns the metric similarity score of two cached embeddings
+// (any of them could be quantized).
+func (e CachedEmbedding) pairSimilarity(other CachedEmbedding, metric SimilarityMetric) float32 {
+	if e.Quantized == nil {
+		return other.similarity(e.Embedding, e.Magnitude, metric)
+	}
+
+	if other.Quantized == nil {
+		return e.similarity(other.Embedding, other.Magnitude, metric)
+	}
+
+	if len(e.Quantized) != len(other.Quantized) {
+		return 0
+	}
+
+	dot := quantizedPairDotProduct(e.Quantized, e.Scale, other.Quantized, other.Scale)
+
+	switch metric {
+	case SimilarityMetricDot:
+		return dot
+	case SimilarityMetricEuclidean:
+		return euclideanDistanceToSimilarity(euclideanDistanceFromDot(dot, e.Magnitude, other.Magnitude))
+	default:
+		if e.Magnitude == 0 || other.Magnitude == 0 {
+			return 0
+		}
+		return dot / (e.Magnitude * other.Magnitude)
+	}
+}
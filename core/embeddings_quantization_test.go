@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestFindSimilarRecordsCacheQuantization(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_quantization")
+
+	const (
+		total      = 200
+		dimensions = 256
+		limit      = 20
+	)
+
+	r := rand.New(rand.NewSource(1))
+
+	vectors := make(map[string][]float32, total)
+	for i := 0; i < total; i++ {
+		vector := make([]float32, dimensions)
+		for j := range vector {
+			vector[j] = float32(r.NormFloat64())
+		}
+		vectors[fmt.Sprintf("r%03d", i)] = vector
+	}
+	storeTestEmbeddings(t, app, collection.Id, "title", vectors)
+
+	search := func(quantize bool, metric core.SimilarityMetric) ([]core.SimilarRecord, float64) {
+		core.ClearEmbeddingCache()
+
+		app.Settings().AI.EmbeddingCacheQuantization = quantize
+
+		result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RecordId:     "r000",
+			Metric:       metric,
+			Limit:        limit,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		memory, _ := core.GetEmbeddingCacheStats()["memoryUsedMB"].(float64)
+
+		return result.Results, memory
+	}
+
+	metrics := []core.SimilarityMetric{
+		core.SimilarityMetricCosine,
+		core.SimilarityMetricDot,
+		core.SimilarityMetricEuclidean,
+	}
+
+	for _, metric := range metrics {
+		t.Run(string(metric), func(t *testing.T) {
+			expected, expectedMemory := search(false, metric)
+			quantized, quantizedMemory := search(true, metric)
+
+			if len(quantized) != limit {
+				t.Fatalf("Expected %d results, got %d", limit, len(quantized))
+			}
+
+			// the quantized cache entry should be ~4x smaller
+			// (excluding the record ids and the per embedding overhead)
+			if ratio := expectedMemory / quantizedMemory; ratio < 3 {
+				t.Fatalf("Expected at least 3x less memory, got %v (%vMB vs %vMB)", ratio, quantizedMemory, expectedMemory)
+			}
+
+			expectedScores := make(map[string]float32, len(expected))
+			for _, result := range expected {
+				expectedScores[result.RecordId] = result.Similarity
+			}
+
+			// recall@limit
+			var matches int
+			for _, result := range quantized {
+				if _, ok := expectedScores[result.RecordId]; ok {
+					matches++
+				}
+			}
+			if recall := float64(matches) / float64(limit); recall < 0.9 {
+				t.Fatalf("Expected recall >= 0.9, got %v", recall)
+			}
+
+			// relative scores error
+			for _, result := range quantized {
+				score, ok := expectedScores[result.RecordId]
+				if !ok {
+					continue
+				}
+
+				diff := math.Abs(float64(result.Similarity - score))
+				if diff > 0.01*math.Max(1, math.Abs(float64(score))) {
+					t.Fatalf("Expected %q score %v, got %v", result.RecordId, score, result.Similarity)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/dbx"
+)
+
+// DefaultReembedBatchSize is the default number of records embedded
+// with a single [GenerateEmbeddings] call of a regeneration job.
+const DefaultReembedBatchSize = 500
+
+// MaxReembedConcurrency is the max number of the fields
+// regenerated at the same time by a single regeneration job.
+const MaxReembedConcurrency = 10
+
+// ReembedRequest represents a multi-field embeddings regeneration job request.
+type ReembedRequest struct {
+	CollectionId string `json:"collectionId"`
+
+	// Fields is the ordered list of the embeddable fields whose
+	// field-level embeddings to regenerate.
+	Fields []string `json:"fields"`
+
+	// Model is the target embedding model (default to the AIConfig.EmbeddingModel setting).
+	Model string `json:"model,omitempty"`
+
+	// BatchSize is the max number of records embedded with a single
+	// [GenerateEmbeddings] call (default to [DefaultReembedBatchSize]).
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// StoreText indicates whether to store a truncated copy of the embedded text.
+	StoreText bool `json:"storeText,omitempty"`
+
+	// Concurrency is the max number of the fields regenerated at the same time
+	// (default to 1, aka. one field after another, up to [MaxReembedConcurrency]).
+	//
+	// Note that the embeddings API requests of all fields (and of all other generation runs)
+	// are still limited to [MaxConcurrentEmbeddingRequests] at a time.
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// RunId is an optional embedding run identifier used to write the job progress
+	// to a status record in the _embedding_runs collection (see [FindEmbeddingRunStatus]).
+	RunId string `json:"runId,omitempty"`
+
+	// EmbeddingsCollection is an optional custom embeddings collection name
+	// to regenerate the embeddings into (default to [EmbeddingsCollectionName]).
+	EmbeddingsCollection string `json:"embeddingsCollection,omitempty"`
+}
+
+// ReembedFieldResult represents the regeneration result of a single field.
+type ReembedFieldResult struct {
+	FieldName string   `json:"fieldName"`
+	Pending   int      `json:"pending"`
+	Generated int      `json:"generated"`
+	Skipped   int      `json:"skipped"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ReembedResponse represents the response of an embeddings regeneration job.
+type ReembedResponse struct {
+	Model  string                `json:"model"`
+	Fields []*ReembedFieldResult `json:"fields"`
+}
+
+// RegenerateEmbeddings regenerates the field-level embeddings of multiple
+// collection fields with the target model (ex. after an embedding model change).
+//
+// The job is resumable at the field and record granularity - the records
+// of each field are queried with [GetPendingModelEmbeddingRecordIds] so
+// rerunning an interrupted job embeds only the records that don't have
+// a stored embedding with the target model yet.
+func RegenerateEmbeddings(app App, req ReembedRequest) (*ReembedResponse, error) {
+	return RegenerateEmbeddingsWithContext(context.Background(), app, req)
+}
+
+// RegenerateEmbeddingsWithContext is the same as [RegenerateEmbeddings]
+// but stops the job when ctx is done, returning [ErrAIRequestCanceled]
+// (the already stored embeddings are kept and skipped on the next run).
+func RegenerateEmbeddingsWithContext(ctx context.Context, app App, req ReembedRequest) (*ReembedResponse, error) {
+	settings := app.Settings()
+
+	if !settings.AI.Enabled {
+		return nil, errors.New("AI features are not enabled")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = settings.AI.EmbeddingModel
+	}
+	if model == "" {
+		return nil, errors.New("embedding model is not configured")
+	}
+
+	collection, err := app.FindCollectionByNameOrId(req.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	embeddingsName, err := resolveEmbeddingsCollectionName(req.EmbeddingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+
+	for _, name := range req.Fields {
+		field := collection.Fields.GetByName(name)
+		if field == nil {
+			return nil, fmt.Errorf("field '%s' not found in collection", name)
+		}
+		if !IsAppFieldEmbeddable(app, field) {
+			return nil, fmt.Errorf("field '%s' is not a text/editor field or is not marked as embeddable", name)
+		}
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultReembedBatchSize
+	}
+
+	if req.Concurrency < 0 || req.Concurrency > MaxReembedConcurrency {
+		return nil, fmt.Errorf("concurrency must be between 1 and %d", MaxReembedConcurrency)
+	}
+	concurrency := max(req.Concurrency, 1)
+
+	// load upfront the pending ids of all fields to report the job total
+	pendingIds := make([][]string, len(req.Fields))
+	var total int
+	for i, name := range req.Fields {
+		pendingIds[i], err = GetPendingModelEmbeddingRecordIds(app, embeddingsName, collection.Id, name, model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the pending records of field '%s': %w", name, err)
+		}
+		total += len(pendingIds[i])
+	}
+
+	var tracker *embeddingRunTracker
+	if req.RunId != "" {
+		tracker, err = newEmbeddingRunTracker(app, req.RunId, collection.Id, strings.Join(req.Fields, ","), total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the embedding run status: %w", err)
+		}
+	}
+
+	response := &ReembedResponse{Model: model, Fields: make([]*ReembedFieldResult, len(req.Fields))}
+	progress := &EmbeddingResponse{} // the totals of all fields
+
+	// the other fields are stopped on the first field error
+	fieldsCtx, cancelFields := context.WithCancel(ctx)
+	defer cancelFields()
+
+	var mu sync.Mutex // guards progress, the tracker updates and firstErr
+	var firstErr error
+
+	workers := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, name := range req.Fields {
+		result := &ReembedFieldResult{FieldName: name, Pending: len(pendingIds[i])}
+		response.Fields[i] = result
+
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			err := reembedField(fieldsCtx, app, embeddingsName, collection.Id, name, model, req.StoreText, pendingIds[i], batchSize, result, func(batchResponse *EmbeddingResponse) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				progress.Generated += batchResponse.Generated
+				progress.Skipped += batchResponse.Skipped
+				tracker.Update(EmbeddingRunStatusRunning, progress)
+			})
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+
+				cancelFields()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		if errors.Is(firstErr, ErrAIRequestCanceled) {
+			tracker.Update(EmbeddingRunStatusCanceled, progress)
+		}
+		return nil, firstErr
+	}
+
+	tracker.Update(EmbeddingRunStatusCompleted, progress)
+
+	return response, nil
+}
+
+// reembedField regenerates in batches the pendingIds records embeddings of a single field,
+// collecting the batches totals into result and invoking onBatch after each batch.
+func reembedField(
+	ctx context.Context,
+	app App,
+	embeddingsName string,
+	collectionId string,
+	fieldName string,
+	model string,
+	storeText bool,
+	pendingIds []string,
+	batchSize int,
+	result *ReembedFieldResult,
+	onBatch func(batchResponse *EmbeddingResponse),
+) error {
+	for _, ids := range batchTexts(pendingIds, batchSize) {
+		if err := checkAIContext(ctx); err != nil {
+			return err
+		}
+
+		batchResponse, err := GenerateEmbeddingsWithContext(ctx, app, EmbeddingRequest{
+			CollectionId:         collectionId,
+			FieldName:            fieldName,
+			RecordIds:            ids,
+			Model:                model,
+			StoreText:            storeText,
+			EmbeddingsCollection: embeddingsName,
+		})
+		if err != nil {
+			return fmt.Errorf("field '%s': %w", fieldName, err)
+		}
+
+		result.Generated += batchResponse.Generated
+		result.Skipped += batchResponse.Skipped
+		if len(result.Errors) < 10 {
+			result.Errors = append(result.Errors, batchResponse.Errors...)
+		}
+
+		onBatch(batchResponse)
+	}
+
+	if len(result.Errors) > 10 {
+		result.Errors = result.Errors[:10]
+	}
+
+	return nil
+}
+
+// GetPendingModelEmbeddingRecordIds returns the sorted ids of the collection
+// records that don't have an active field embedding generated with the specified model
+// in the specified embeddings collection (empty for [EmbeddingsCollectionName]).
+func GetPendingModelEmbeddingRecordIds(app App, embeddingsName, collectionId, fieldName, model string) ([]string, error) {
+	embeddingsName, err := resolveEmbeddingsCollectionName(embeddingsName)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := app.FindCollectionByNameOrId(collectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	query := app.DB().
+		Select("id").
+		From(collection.Name).
+		OrderBy("id ASC")
+
+	embeddingsCollection, err := app.FindCollectionByNameOrId(embeddingsName)
+	if err == nil {
+		deletedFilter := ""
+		if embeddingsCollection.Fields.GetByName(EmbeddingsFieldDeleted) != nil {
+			deletedFilter = " AND e.[[" + EmbeddingsFieldDeleted + "]] = ''"
+		}
+
+		query.AndWhere(dbx.NewExp(
+			"NOT EXISTS (SELECT 1 FROM {{"+embeddingsCollection.Name+"}} e "+
+				"WHERE e.[[record_id]] = {{"+collection.Name+"}}.[[id]] "+
+				"AND e.[[collection_id]] = {:collectionId} "+
+				"AND e.[[field_name]] = {:fieldName} "+
+				"AND e.[[model]] = {:model}"+deletedFilter+")",
+			dbx.Params{
+				"collectionId": collection.Id,
+				"fieldName":    fieldName,
+				"model":        model,
+			},
+		))
+	}
+
+	ids := []string{}
+	if err := query.Column(&ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
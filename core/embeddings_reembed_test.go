@@ -0,0 +1,313 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestRegenerateEmbeddingsResume(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+	app.Settings().AI.EmbeddingModel = "test-new"
+	app.Settings().AI.EmbeddingBatchSize = 2
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_reembed")
+
+	oldVectors := map[string][]float32{}
+	for i := 0; i < 5; i++ {
+		record := core.NewRecord(collection)
+		record.Set("title", fmt.Sprintf("title record%d", i))
+		record.Set("content", fmt.Sprintf("content record%d", i))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		oldVectors[record.Id] = []float32{1, 0}
+	}
+
+	// embeddings generated with the previous ("test") model
+	storeTestEmbeddings(t, app, collection.Id, "title", oldVectors)
+	storeTestEmbeddings(t, app, collection.Id, "content", oldVectors)
+
+	pending := func(fieldName string) []string {
+		ids, err := core.GetPendingModelEmbeddingRecordIds(app, "", collection.Id, fieldName, "test-new")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ids
+	}
+
+	countInputs := func(inputs []string, prefix string) int {
+		var total int
+		for _, input := range inputs {
+			if strings.HasPrefix(input, prefix) {
+				total++
+			}
+		}
+		return total
+	}
+
+	req := core.ReembedRequest{
+		CollectionId: collection.Name,
+		Fields:       []string{"title", "content"},
+		BatchSize:    2,
+		RunId:        "test_reembed_run",
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		invalid := []core.ReembedRequest{
+			{CollectionId: "missing", Fields: []string{"title"}},
+			{CollectionId: collection.Id},
+			{CollectionId: collection.Id, Fields: []string{"missing"}},
+			{CollectionId: collection.Id, Fields: []string{"created"}},
+			{CollectionId: collection.Id, Fields: []string{"title"}, Concurrency: -1},
+			{CollectionId: collection.Id, Fields: []string{"title"}, Concurrency: core.MaxReembedConcurrency + 1},
+		}
+
+		for i, r := range invalid {
+			if _, err := core.RegenerateEmbeddings(app, r); err == nil {
+				t.Fatalf("[%d] Expected error, got nil", i)
+			}
+		}
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// simulate a crash right after the first content embeddings request
+		// (title: 3 requests with 2, 2 and 1 texts; content: the 4th request)
+		var requests atomic.Int32
+		transport := &fakeEmbeddingsTransport{OnRequest: func() {
+			if requests.Add(1) == 4 {
+				cancel()
+			}
+		}}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		_, err := core.RegenerateEmbeddingsWithContext(ctx, app, req)
+		if !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+
+		if ids := pending("title"); len(ids) != 0 {
+			t.Fatalf("Expected all title embeddings to be regenerated, got pending %v", ids)
+		}
+
+		if ids := pending("content"); len(ids) != 3 {
+			t.Fatalf("Expected 3 pending content embeddings, got %v", ids)
+		}
+
+		status, err := core.FindEmbeddingRunStatus(app, req.RunId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Status != core.EmbeddingRunStatusCanceled || status.Total != 10 || status.Generated != 7 {
+			t.Fatalf("Expected canceled status with 10 total and 7 generated, got %+v", status)
+		}
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+		pendingContent := pending("content")
+
+		transport := &fakeEmbeddingsTransport{}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		response, err := core.RegenerateEmbeddings(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		inputs := transport.Inputs()
+		if total := countInputs(inputs, "title"); total != 0 {
+			t.Fatalf("Expected no title texts to be embedded again, got %v", inputs)
+		}
+		if total := countInputs(inputs, "content"); total != len(pendingContent) {
+			t.Fatalf("Expected only the %d pending content texts to be embedded, got %v", len(pendingContent), inputs)
+		}
+
+		if len(response.Fields) != 2 ||
+			response.Fields[0].Pending != 0 || response.Fields[0].Generated != 0 ||
+			response.Fields[1].Pending != 3 || response.Fields[1].Generated != 3 {
+			t.Fatalf("Unexpected fields results %+v, %+v", response.Fields[0], response.Fields[1])
+		}
+
+		for _, field := range req.Fields {
+			if ids := pending(field); len(ids) != 0 {
+				t.Fatalf("Expected no pending %s embeddings, got %v", field, ids)
+			}
+		}
+
+		models, err := app.FindRecordsByFilter(core.EmbeddingsCollectionName, "model != 'test-new'", "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(models) != 0 {
+			t.Fatalf("Expected all embeddings to be with the new model, got %d stale", len(models))
+		}
+
+		status, err := core.FindEmbeddingRunStatus(app, req.RunId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Status != core.EmbeddingRunStatusCompleted || status.Total != 3 || status.Generated != 3 {
+			t.Fatalf("Expected completed status with 3 total and 3 generated, got %+v", status)
+		}
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		transport := &fakeEmbeddingsTransport{}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		if _, err := core.RegenerateEmbeddings(app, req); err != nil {
+			t.Fatal(err)
+		}
+
+		if inputs := transport.Inputs(); len(inputs) != 0 {
+			t.Fatalf("Expected no embedding requests, got %v", inputs)
+		}
+	})
+
+	t.Run("custom embeddings collection", func(t *testing.T) {
+		const tenantEmbeddings = "_embeddings_tenant_reembed"
+
+		storeTestEmbeddingsIn(t, app, tenantEmbeddings, collection.Id, "title", oldVectors)
+
+		tenantPending, err := core.GetPendingModelEmbeddingRecordIds(app, tenantEmbeddings, collection.Id, "title", "test-new")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tenantPending) != len(oldVectors) {
+			t.Fatalf("Expected %d pending custom collection embeddings, got %v", len(oldVectors), tenantPending)
+		}
+
+		defaultTotal, err := app.CountRecords(core.EmbeddingsCollectionName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		transport := &fakeEmbeddingsTransport{}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		_, err = core.RegenerateEmbeddings(app, core.ReembedRequest{
+			CollectionId:         collection.Id,
+			Fields:               []string{"title"},
+			EmbeddingsCollection: tenantEmbeddings,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if inputs := transport.Inputs(); len(inputs) != len(oldVectors) {
+			t.Fatalf("Expected %d embedded texts, got %v", len(oldVectors), inputs)
+		}
+
+		stale, err := app.FindRecordsByFilter(tenantEmbeddings, "model != 'test-new'", "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stale) != 0 {
+			t.Fatalf("Expected all custom collection embeddings to be with the new model, got %d stale", len(stale))
+		}
+
+		if total, _ := app.CountRecords(core.EmbeddingsCollectionName); total != defaultTotal {
+			t.Fatalf("Expected the default embeddings collection to remain with %d embeddings, got %d", defaultTotal, total)
+		}
+	})
+}
+
+func TestRegenerateEmbeddingsConcurrency(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache and requests limit
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+	app.Settings().AI.EmbeddingModel = "test"
+	app.Settings().AI.EmbeddingBatchSize = 1
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_reembed_concurrency")
+	fields := []string{"title", "content"}
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("extra%d", i)
+		collection.Fields.Add(&core.TextField{Name: name, Embeddable: true})
+		fields = append(fields, name)
+	}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		record := core.NewRecord(collection)
+		for _, name := range fields {
+			record.Set(name, fmt.Sprintf("%s record%d", name, i))
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		concurrency int
+		model       string
+		minParallel int32
+		maxParallel int32
+	}{
+		{"sequential", 0, "test-sequential", 1, 1},
+		{"concurrent", len(fields), "test-concurrent", 2, core.MaxConcurrentEmbeddingRequests},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var inProgress, maxInProgress atomic.Int32
+			transport := &fakeEmbeddingsTransport{OnRequest: func() {
+				current := inProgress.Add(1)
+				defer inProgress.Add(-1)
+
+				for {
+					prev := maxInProgress.Load()
+					if current <= prev || maxInProgress.CompareAndSwap(prev, current) {
+						break
+					}
+				}
+
+				time.Sleep(20 * time.Millisecond)
+			}}
+			app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+			response, err := core.RegenerateEmbeddings(app, core.ReembedRequest{
+				CollectionId: collection.Id,
+				Fields:       fields,
+				Model:        s.model,
+				Concurrency:  s.concurrency,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(response.Fields) != len(fields) {
+				t.Fatalf("Expected %d fields results, got %d", len(fields), len(response.Fields))
+			}
+			for i, result := range response.Fields {
+				if result.FieldName != fields[i] || result.Pending != 2 || result.Generated != 2 || len(result.Errors) != 0 {
+					t.Fatalf("[%d] Unexpected field result %+v", i, result)
+				}
+			}
+
+			if total := len(transport.BatchSizes()); total != 2*len(fields) {
+				t.Fatalf("Expected %d embeddings requests, got %d", 2*len(fields), total)
+			}
+
+			if max := maxInProgress.Load(); max < s.minParallel || max > s.maxParallel {
+				t.Fatalf("Expected between %d and %d concurrent requests, got %d", s.minParallel, s.maxParallel, max)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// EmbeddingRunsCollectionName is the name of the system collection for
+	// tracking the progress of the embedding generation runs (see EmbeddingRequest.RunId)
+	EmbeddingRunsCollectionName = "_embedding_runs"
+
+	// EmbeddingRunStatusRunning is the status of an in progress embedding run
+	EmbeddingRunStatusRunning = "running"
+
+	// EmbeddingRunStatusCompleted is the status of a finished embedding run
+	EmbeddingRunStatusCompleted = "completed"
+
+	// EmbeddingRunStatusCanceled is the status of an embedding run
+	// stopped before its completion because its context was canceled
+	EmbeddingRunStatusCanceled = "canceled"
+)
+
+// EmbeddingRunStatus represents the progress of a single embedding generation run.
+type EmbeddingRunStatus struct {
+	RunId        string `json:"runId"`
+	CollectionId string `json:"collectionId"`
+	FieldName    string `json:"fieldName"`
+	Status       string `json:"status"`
+	Total        int    `json:"total"`
+	Generated    int    `json:"generated"`
+	Skipped      int    `json:"skipped"`
+}
+
+// EnsureEmbeddingRunsCollection creates the _embedding_runs system collection if it doesn't exist.
+// Returns the embedding runs collection.
+func EnsureEmbeddingRunsCollection(app App) (*Collection, error) {
+	collection, err := app.FindCollectionByNameOrId(EmbeddingRunsCollectionName)
+	if err == nil {
+		return collection, nil
+	}
+
+	collection = NewCollection(CollectionTypeBase, EmbeddingRunsCollectionName)
+	collection.System = true
+
+	collection.Fields.Add(&TextField{
+		Name:     "run_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "collection_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "field_name",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "status",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&NumberField{
+		Name:    "total",
+		OnlyInt: true,
+		System:  true,
+	})
+
+	collection.Fields.Add(&NumberField{
+		Name:    "generated",
+		OnlyInt: true,
+		System:  true,
+	})
+
+	collection.Fields.Add(&NumberField{
+		Name:    "skipped",
+		OnlyInt: true,
+		System:  true,
+	})
+
+	collection.Fields.Add(&AutodateField{
+		Name:     "updated",
+		OnCreate: true,
+		OnUpdate: true,
+		System:   true,
+	})
+
+	collection.Indexes = []string{
+		"CREATE UNIQUE INDEX idx_embedding_runs_run ON _embedding_runs (run_id)",
+	}
+
+	if err := app.Save(collection); err != nil {
+		// another goroutine could have created the collection in the meantime
+		errStr := err.Error()
+		if strings.Contains(errStr, "UNIQUE constraint failed") ||
+			strings.Contains(errStr, "already exists") ||
+			strings.Contains(errStr, "must be unique") {
+			collection, findErr := app.FindCollectionByNameOrId(EmbeddingRunsCollectionName)
+			if findErr == nil {
+				return collection, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create embedding runs collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// FindEmbeddingRunStatus returns the progress status of the runId embedding run.
+func FindEmbeddingRunStatus(app App, runId string) (*EmbeddingRunStatus, error) {
+	collection, err := app.FindCachedCollectionByNameOrId(EmbeddingRunsCollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("embedding run %s not found", runId)
+	}
+
+	record, err := app.FindFirstRecordByData(collection, "run_id", runId)
+	if err != nil {
+		return nil, fmt.Errorf("embedding run %s not found", runId)
+	}
+
+	return &EmbeddingRunStatus{
+		RunId:        runId,
+		CollectionId: record.GetString("collection_id"),
+		FieldName:    record.GetString("field_name"),
+		Status:       record.GetString("status"),
+		Total:        record.GetInt("total"),
+		Generated:    record.GetInt("generated"),
+		Skipped:      record.GetInt("skipped"),
+	}, nil
+}
+
+// embeddingRunTracker writes the progress of an embedding run to its status record.
+//
+// A nil tracker is a no-op so that it could be used unconditionally.
+type embeddingRunTracker struct {
+	app    App
+	record *Record
+}
+
+// newEmbeddingRunTracker creates (or resets) the status record of the runId embedding run.
+func newEmbeddingRunTracker(app App, runId, collectionId, fieldName string, total int) (*embeddingRunTracker, error) {
+	collection, err := EnsureEmbeddingRunsCollection(app)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := app.FindFirstRecordByData(collection, "run_id", runId)
+	if err != nil {
+		record = NewRecord(collection)
+		record.Set("run_id", runId)
+	}
+
+	record.Set("collection_id", collectionId)
+	record.Set("field_name", fieldName)
+	record.Set("total", total)
+
+	tracker := &embeddingRunTracker{app: app, record: record}
+	tracker.Update(EmbeddingRunStatusRunning, &EmbeddingResponse{})
+
+	return tracker, nil
+}
+
+// Update persists the current run progress.
+//
+// Failures are only logged because the progress reporting is not essential for the run itself.
+func (t *embeddingRunTracker) Update(status string, response *EmbeddingResponse) {
+	if t == nil {
+		return
+	}
+
+	t.record.Set("status", status)
+	t.record.Set("generated", response.Generated)
+	t.record.Set("skipped", response.Skipped)
+
+	if err := t.app.Save(t.record); err != nil {
+		t.app.Logger().Warn(
+			"Failed to update the embedding run status",
+			"runId", t.record.GetString("run_id"),
+			"error", err,
+		)
+	}
+}
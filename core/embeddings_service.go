@@ -2,30 +2,85 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"math"
+	"math/rand"
 	"net/http"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/sync/semaphore"
 )
 
 const (
-	openAIEmbeddingsURL = "https://api.openai.com/v1/embeddings"
-
 	// MaxTextsPerBatch is the maximum number of texts to send in a single embedding request
+	// (could be lowered with the AIConfig.EmbeddingBatchSize setting)
 	MaxTextsPerBatch = 2048
 
 	// MaxTokensPerBatch is an approximate limit on tokens per batch
+	// (the texts with more estimated tokens are truncated, see [EstimateEmbeddingTokens])
 	MaxTokensPerBatch = 8000
+
+	// DefaultHTMLStripMaxSize is the default max size (in bytes) of an HTML input
+	// before stripping its tags (could be changed with the AIConfig.HTMLStripMaxSize setting)
+	DefaultHTMLStripMaxSize = 1 << 20
+
+	// DefaultHTMLStripMaxTags is the default max number of tags to strip from a single HTML input
+	// (could be changed with the AIConfig.HTMLStripMaxTags setting)
+	DefaultHTMLStripMaxTags = 100000
+
+	// DefaultEmbeddingTimeout is the default bulk embeddings request timeout in seconds
+	// (could be changed with the AIConfig.EmbeddingTimeout setting)
+	DefaultEmbeddingTimeout = 120
+
+	// DefaultEmbeddingQueryTimeout is the default search query embedding request timeout in seconds
+	// (could be changed with the AIConfig.EmbeddingQueryTimeout setting)
+	DefaultEmbeddingQueryTimeout = 10
+
+	// DefaultSimilarityMaxLimit is the default max number of results of a single similarity search
+	// (could be changed with the AIConfig.SimilarityMaxLimit setting)
+	DefaultSimilarityMaxLimit = 100
+
+	// DefaultEmbeddingMaxRetries is the default max number of retries of a failed
+	// (429 or 5xx) embeddings batch request before skipping the batch
+	// (could be changed with the AIConfig.EmbeddingMaxRetries setting)
+	DefaultEmbeddingMaxRetries = 5
+
+	// MaxEmbeddingRetries is the max allowed value of the AIConfig.EmbeddingMaxRetries setting
+	MaxEmbeddingRetries = 20
+
+	// DefaultEmbeddingRetryBaseDelay is the default initial wait time in milliseconds
+	// before retrying a failed embeddings batch request (doubled on each retry)
+	// (could be changed with the AIConfig.EmbeddingRetryBaseDelay setting)
+	DefaultEmbeddingRetryBaseDelay = 500
+
+	// maxEmbeddingsRetryBackoff is the max wait time between the retried
+	// embedding requests when the response doesn't specify a Retry-After
+	maxEmbeddingsRetryBackoff = 30 * time.Second
+
+	// MaxConcurrentEmbeddingRequests is the max number of the in progress embeddings
+	// generation API requests of the application (shared by all generation runs)
+	MaxConcurrentEmbeddingRequests = 4
 )
 
+// embeddingRequestsSem limits the concurrent embeddings generation API requests
+// (see [MaxConcurrentEmbeddingRequests]).
+var embeddingRequestsSem = semaphore.NewWeighted(MaxConcurrentEmbeddingRequests)
+
 const (
 	// EmbeddingCacheMaxMemoryMB is the total memory budget for the cache in megabytes
 	// When exceeded, oldest entries are evicted until under budget
@@ -38,9 +93,44 @@ const (
 	// EmbeddingCacheTTL is how long cached embeddings remain valid (sliding window)
 	EmbeddingCacheTTL = 10 * time.Minute
 
-	// embeddingMemoryPerRecord is the estimated memory per cached embedding in bytes
-	// 1536 floats × 4 bytes + record ID (~20 bytes) + magnitude (4 bytes) + overhead
-	embeddingMemoryPerRecord = 6200 // ~6.2KB
+	// embeddingMemoryOverhead is the estimated memory per cached embedding in bytes
+	// excluding the vector floats and the record ID bytes
+	// (magnitude + the vector slice and record ID string headers + padding)
+	embeddingMemoryOverhead = 48
+
+	// EmbeddingsLoadPageSize is the number of embeddings loaded from the database at once
+	EmbeddingsLoadPageSize = 1000
+
+	// EmbeddingCacheMaxEvictions is the number of the most recent
+	// eviction events kept for the cache stats
+	EmbeddingCacheMaxEvictions = 50
+)
+
+// CacheEvictionReason represents the reason of an embedding cache entry eviction
+type CacheEvictionReason string
+
+const (
+	CacheEvictionReasonTTL    CacheEvictionReason = "ttl"    // The entry was not accessed within the TTL
+	CacheEvictionReasonMemory CacheEvictionReason = "memory" // The entry was evicted to stay within the memory budget
+	CacheEvictionReasonCount  CacheEvictionReason = "count"  // The reloaded entry exceeded the per entry embeddings cap
+)
+
+// CacheEviction describes a single embedding cache entry eviction.
+type CacheEviction struct {
+	Key          string              `json:"key"`
+	CollectionId string              `json:"collectionId"`
+	FieldName    string              `json:"fieldName"`
+	Reason       CacheEvictionReason `json:"reason"`
+	Count        int                 `json:"count"`
+	MemoryMB     float64             `json:"memoryMB"`
+	EvictedAt    time.Time           `json:"evictedAt"`
+}
+
+var (
+	// embeddingCacheMaxPerEntry and embeddingsLoadPageSize are the
+	// effective cache entry and load page limits (they are vars so that they could be lowered in tests)
+	embeddingCacheMaxPerEntry = EmbeddingCacheMaxPerEntry
+	embeddingsLoadPageSize    = EmbeddingsLoadPageSize
 )
 
 // embeddingCache stores embeddings in memory for fast similarity search
@@ -63,6 +153,13 @@ type EmbeddingCache struct {
 	cache         map[string]*cacheEntry // key: "collectionId:fieldName"
 	accessLog     []string               // Track access order for LRU eviction
 	totalMemoryMB float64                // Track total memory usage
+
+	evictions      []CacheEviction             // Ring buffer of the most recent evictions
+	evictionsNext  int                         // The ring buffer position of the next eviction
+	evictionsTotal map[CacheEvictionReason]int // Total evictions count per reason
+
+	hits   atomic.Uint64 // Total Get calls returning cached embeddings
+	misses atomic.Uint64 // Total Get calls without (or with expired) cached embeddings
 }
 
 // CachedEmbedding stores a pre-loaded embedding with its record ID
@@ -70,6 +167,22 @@ type CachedEmbedding struct {
 	RecordId  string
 	Embedding []float32
 	Magnitude float32 // Pre-computed for faster cosine similarity
+
+	// Quantized is the int8 version of the vector (Embedding is nil when set)
+	// with Scale as its dequantization factor (see AIConfig.EmbeddingCacheQuantization).
+	Quantized []int8
+	Scale     float32
+}
+
+// estimateEmbeddingsMemoryMB returns the estimated memory in MB of the cached embeddings
+// based on their actual vector dimensions (4 bytes per float or 1 byte per quantized value) and record IDs.
+func estimateEmbeddingsMemoryMB(embeddings []CachedEmbedding) float64 {
+	var bytes int
+	for _, e := range embeddings {
+		bytes += len(e.Embedding)*4 + len(e.Quantized) + len(e.RecordId) + embeddingMemoryOverhead
+	}
+
+	return float64(bytes) / (1024 * 1024)
 }
 
 // cacheKey generates a cache key from collection ID and field name
@@ -85,19 +198,24 @@ func (c *EmbeddingCache) Get(collectionId, fieldName string) ([]CachedEmbedding,
 	key := cacheKey(collectionId, fieldName)
 	entry, ok := c.cache[key]
 	if !ok {
+		c.misses.Add(1)
 		return nil, false
 	}
 
 	// Check TTL expiration (sliding window - resets on each access)
 	if time.Since(entry.accessedAt) > EmbeddingCacheTTL {
+		c.totalMemoryMB -= entry.memoryMB
 		delete(c.cache, key)
 		c.removeFromAccessLog(key)
+		c.recordEviction(key, entry, CacheEvictionReasonTTL)
+		c.misses.Add(1)
 		return nil, false
 	}
 
 	// Update access time for LRU and sliding TTL
 	entry.accessedAt = time.Now()
 	c.moveToEndOfAccessLog(key)
+	c.hits.Add(1)
 
 	return entry.embeddings, true
 }
@@ -105,27 +223,31 @@ func (c *EmbeddingCache) Get(collectionId, fieldName string) ([]CachedEmbedding,
 // Set stores embeddings in the cache with memory-based eviction
 // Returns true if cached, false if skipped (too large for single entry)
 func (c *EmbeddingCache) Set(collectionId, fieldName string, embeddings []CachedEmbedding) bool {
-	// Skip caching if single entry exceeds per-entry limit
-	if len(embeddings) > EmbeddingCacheMaxPerEntry {
-		return false
-	}
-
-	// Calculate memory for this entry
-	entryMemoryMB := float64(len(embeddings)*embeddingMemoryPerRecord) / (1024 * 1024)
-
-	// Skip if single entry would exceed entire budget
-	if entryMemoryMB > EmbeddingCacheMaxMemoryMB {
-		return false
-	}
+	// Calculate memory for this entry from the actual vectors dimensions
+	entryMemoryMB := estimateEmbeddingsMemoryMB(embeddings)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	key := cacheKey(collectionId, fieldName)
 
-	// If updating existing entry, subtract its old memory first
+	// Skip caching if single entry exceeds per-entry limit or the entire budget
+	// (the existing entry is evicted since it is no longer up to date)
+	if len(embeddings) > embeddingCacheMaxPerEntry {
+		c.evict(key, CacheEvictionReasonCount)
+		return false
+	}
+	if entryMemoryMB > EmbeddingCacheMaxMemoryMB {
+		c.evict(key, CacheEvictionReasonMemory)
+		return false
+	}
+
+	// If updating existing entry, remove it first
+	// (so that it is not accounted again by the eviction below)
 	if existing, ok := c.cache[key]; ok {
 		c.totalMemoryMB -= existing.memoryMB
+		delete(c.cache, key)
+		c.removeFromAccessLog(key)
 	}
 
 	// Evict oldest entries until we have room for the new entry
@@ -134,6 +256,7 @@ func (c *EmbeddingCache) Set(collectionId, fieldName string, embeddings []Cached
 		if oldEntry, ok := c.cache[oldestKey]; ok {
 			c.totalMemoryMB -= oldEntry.memoryMB
 			delete(c.cache, oldestKey)
+			c.recordEviction(oldestKey, oldEntry, CacheEvictionReasonMemory)
 		}
 		c.accessLog = c.accessLog[1:]
 	}
@@ -166,10 +289,11 @@ func (c *EmbeddingCache) Invalidate(collectionId, fieldName string) {
 }
 
 // InvalidateCollection removes all cached embeddings for a collection
-func (c *EmbeddingCache) InvalidateCollection(collectionId string) {
+// of the specified embeddings collection (empty for [EmbeddingsCollectionName]).
+func (c *EmbeddingCache) InvalidateCollection(embeddingsName, collectionId string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	prefix := collectionId + ":"
+	prefix := embeddingsCacheCollectionKey(embeddingsName, collectionId) + ":"
 	for key, entry := range c.cache {
 		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
 			c.totalMemoryMB -= entry.memoryMB
@@ -189,6 +313,83 @@ func (c *EmbeddingCache) removeFromAccessLog(key string) {
 	}
 }
 
+// evict removes the cache entry with the specified key (if any)
+// and records its eviction (helper, must hold lock).
+func (c *EmbeddingCache) evict(key string, reason CacheEvictionReason) {
+	entry, ok := c.cache[key]
+	if !ok {
+		return
+	}
+
+	c.totalMemoryMB -= entry.memoryMB
+	delete(c.cache, key)
+	c.removeFromAccessLog(key)
+	c.recordEviction(key, entry, reason)
+}
+
+// recordEviction adds an eviction event to the recent evictions
+// ring buffer (helper, must hold lock).
+func (c *EmbeddingCache) recordEviction(key string, entry *cacheEntry, reason CacheEvictionReason) {
+	collectionId, fieldName, _ := strings.Cut(key, ":")
+
+	eviction := CacheEviction{
+		Key:          key,
+		CollectionId: collectionId,
+		FieldName:    fieldName,
+		Reason:       reason,
+		Count:        len(entry.embeddings),
+		MemoryMB:     entry.memoryMB,
+		EvictedAt:    time.Now(),
+	}
+
+	if len(c.evictions) < EmbeddingCacheMaxEvictions {
+		c.evictions = append(c.evictions, eviction)
+	} else {
+		c.evictions[c.evictionsNext] = eviction
+	}
+	c.evictionsNext = (c.evictionsNext + 1) % EmbeddingCacheMaxEvictions
+
+	if c.evictionsTotal == nil {
+		c.evictionsTotal = map[CacheEvictionReason]int{}
+	}
+	c.evictionsTotal[reason]++
+}
+
+// totalEvictions returns the total number of evicted entries (regardless of the reason).
+//
+// note: the caller must hold the cache lock.
+func (c *EmbeddingCache) totalEvictions() uint64 {
+	var total uint64
+	for _, count := range c.evictionsTotal {
+		total += uint64(count)
+	}
+	return total
+}
+
+// hitRate returns the percentage of the Get calls returning cached embeddings.
+func (c *EmbeddingCache) hitRate() float64 {
+	hits := c.hits.Load()
+	total := hits + c.misses.Load()
+	if total == 0 {
+		return 0
+	}
+
+	return float64(hits) / float64(total) * 100
+}
+
+// recentEvictions returns a copy of the recorded evictions
+// from the oldest to the most recent one (helper, must hold lock).
+func (c *EmbeddingCache) recentEvictions() []CacheEviction {
+	result := make([]CacheEviction, 0, len(c.evictions))
+
+	if len(c.evictions) < EmbeddingCacheMaxEvictions {
+		return append(result, c.evictions...)
+	}
+
+	result = append(result, c.evictions[c.evictionsNext:]...)
+	return append(result, c.evictions[:c.evictionsNext]...)
+}
+
 // moveToEndOfAccessLog moves a key to the end of access log (most recently used)
 func (c *EmbeddingCache) moveToEndOfAccessLog(key string) {
 	c.removeFromAccessLog(key)
@@ -207,25 +408,118 @@ func (c *EmbeddingCache) Stats() map[string]any {
 		count := len(entry.embeddings)
 		totalEmbeddings += count
 
+		var dimensions int
+		if count > 0 {
+			dimensions = entry.embeddings[0].Dimensions()
+		}
+
 		entries = append(entries, map[string]any{
 			"key":        key,
 			"count":      count,
+			"dimensions": dimensions,
 			"memoryMB":   entry.memoryMB,
 			"age":        time.Since(entry.createdAt).String(),
 			"lastAccess": time.Since(entry.accessedAt).String(),
 		})
 	}
 
+	evictionsCount := make(map[CacheEvictionReason]int, len(c.evictionsTotal))
+	for reason, total := range c.evictionsTotal {
+		evictionsCount[reason] = total
+	}
+
 	return map[string]any{
-		"entriesCount":     len(c.cache),
-		"totalEmbeddings":  totalEmbeddings,
-		"memoryUsedMB":     c.totalMemoryMB,
-		"memoryBudgetMB":   EmbeddingCacheMaxMemoryMB,
+		"entriesCount":       len(c.cache),
+		"totalEmbeddings":    totalEmbeddings,
+		"memoryUsedMB":       c.totalMemoryMB,
+		"memoryBudgetMB":     EmbeddingCacheMaxMemoryMB,
 		"memoryUsagePercent": (c.totalMemoryMB / EmbeddingCacheMaxMemoryMB) * 100,
-		"maxPerEntry":      EmbeddingCacheMaxPerEntry,
-		"ttl":              EmbeddingCacheTTL.String(),
-		"entries":          entries,
+		"maxPerEntry":        embeddingCacheMaxPerEntry,
+		"ttl":                EmbeddingCacheTTL.String(),
+		"entries":            entries,
+		"evictionsCount":     evictionsCount,
+		"recentEvictions":    c.recentEvictions(),
+		"hits":               c.hits.Load(),
+		"misses":             c.misses.Load(),
+		"hitRate":            c.hitRate(),
+	}
+}
+
+// CacheDump contains the embedding cache internal state (without the raw vectors).
+type CacheDump struct {
+	// Entries are the cache entries ordered by their access order
+	// (from the least to the most recently used, aka. the eviction order).
+	Entries []CacheDumpEntry `json:"entries"`
+
+	TotalMemoryMB  float64 `json:"totalMemoryMB"`
+	MemoryBudgetMB float64 `json:"memoryBudgetMB"`
+	MaxPerEntry    int     `json:"maxPerEntry"`
+	TTL            string  `json:"ttl"`
+}
+
+// CacheDumpEntry contains the metadata of a single embedding cache entry.
+type CacheDumpEntry struct {
+	Key          string    `json:"key"`
+	CollectionId string    `json:"collectionId"`
+	FieldName    string    `json:"fieldName"`
+	AccessOrder  int       `json:"accessOrder"`
+	Count        int       `json:"count"`
+	Dimensions   int       `json:"dimensions"`
+	MemoryMB     float64   `json:"memoryMB"`
+	CreatedAt    time.Time `json:"createdAt"`
+	AccessedAt   time.Time `json:"accessedAt"`
+	Age          string    `json:"age"`
+	Idle         string    `json:"idle"`
+	Expired      bool      `json:"expired"` // TTL expired but not yet removed
+}
+
+// Dump returns the cache entries metadata in their access order.
+//
+// Note that the dump doesn't include the raw embedding vectors.
+func (c *EmbeddingCache) Dump() *CacheDump {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	dump := &CacheDump{
+		Entries:        make([]CacheDumpEntry, 0, len(c.cache)),
+		TotalMemoryMB:  c.totalMemoryMB,
+		MemoryBudgetMB: EmbeddingCacheMaxMemoryMB,
+		MaxPerEntry:    embeddingCacheMaxPerEntry,
+		TTL:            EmbeddingCacheTTL.String(),
+	}
+
+	now := time.Now()
+
+	for i, key := range c.accessLog {
+		entry, ok := c.cache[key]
+		if !ok {
+			continue
+		}
+
+		collectionId, fieldName, _ := strings.Cut(key, ":")
+
+		var dimensions int
+		if len(entry.embeddings) > 0 {
+			dimensions = entry.embeddings[0].Dimensions()
+		}
+
+		dump.Entries = append(dump.Entries, CacheDumpEntry{
+			Key:          key,
+			CollectionId: collectionId,
+			FieldName:    fieldName,
+			AccessOrder:  i,
+			Count:        len(entry.embeddings),
+			Dimensions:   dimensions,
+			MemoryMB:     entry.memoryMB,
+			CreatedAt:    entry.createdAt,
+			AccessedAt:   entry.accessedAt,
+			Age:          now.Sub(entry.createdAt).String(),
+			Idle:         now.Sub(entry.accessedAt).String(),
+			Expired:      now.Sub(entry.accessedAt) > EmbeddingCacheTTL,
+		})
 	}
+
+	return dump
 }
 
 // Clear removes all cached embeddings
@@ -235,6 +529,11 @@ func (c *EmbeddingCache) Clear() {
 	c.cache = make(map[string]*cacheEntry)
 	c.accessLog = make([]string, 0, 10)
 	c.totalMemoryMB = 0
+	c.evictions = nil
+	c.evictionsNext = 0
+	c.evictionsTotal = nil
+	c.hits.Store(0)
+	c.misses.Store(0)
 }
 
 // Info returns a summary of cache state
@@ -246,6 +545,10 @@ func (c *EmbeddingCache) Info() *CacheInfo {
 		MemoryUsedMB:       c.totalMemoryMB,
 		MemoryBudgetMB:     EmbeddingCacheMaxMemoryMB,
 		MemoryUsagePercent: (c.totalMemoryMB / EmbeddingCacheMaxMemoryMB) * 100,
+		Hits:               c.hits.Load(),
+		Misses:             c.misses.Load(),
+		HitRate:            c.hitRate(),
+		Evictions:          c.totalEvictions(),
 	}
 }
 
@@ -263,14 +566,37 @@ func GetEmbeddingCacheStats() map[string]any {
 	return embeddingCache.Stats()
 }
 
+// DumpEmbeddingCache returns the embedding cache internal state for debugging.
+func DumpEmbeddingCache() *CacheDump {
+	return embeddingCache.Dump()
+}
+
 // ClearEmbeddingCache clears all cached embeddings
+// (including the remembered records without embeddable text).
 func ClearEmbeddingCache() {
 	embeddingCache.Clear()
+	emptyEmbeddingTexts.Clear()
 }
 
 const (
 	// RecordLevelFieldName is the special field name used for record-level embeddings
 	RecordLevelFieldName = "_record"
+
+	// CombinedFieldNamePrefix is the prefix of the derived field names used for the combined fields embeddings
+	CombinedFieldNamePrefix = "_fields:"
+
+	// DefaultCombinedFieldsSeparator is the default separator between the combined fields values
+	DefaultCombinedFieldsSeparator = "\n\n"
+
+	// MaxEmbeddingQueryTextLength is the max number of characters of a similarity search query text
+	// (~8k tokens which is the input limit of most embedding models).
+	MaxEmbeddingQueryTextLength = 32000
+
+	// DefaultRecordFieldSeparator is the default separator between the fields of the record-level text
+	DefaultRecordFieldSeparator = "\n"
+
+	// DefaultRecordLabelSeparator is the default separator between the field name and value of the record-level text
+	DefaultRecordLabelSeparator = ": "
 )
 
 // EmbeddingMode represents the mode for embedding generation
@@ -279,15 +605,152 @@ type EmbeddingMode string
 const (
 	EmbeddingModeField  EmbeddingMode = "field"  // Embed individual fields
 	EmbeddingModeRecord EmbeddingMode = "record" // Embed entire record as one text
+	EmbeddingModeFields EmbeddingMode = "fields" // Embed a concatenation of specific fields as one text
+
+	// EmbeddingModeCombined is a search only mode that queries both
+	// the field-level and the record-level embeddings and merges the results
+	EmbeddingModeCombined EmbeddingMode = "combined"
+)
+
+// CombinedFieldName returns the derived field name under which
+// the combined embeddings of the specified ordered fields are stored
+// (ex. "_fields:title+body").
+func CombinedFieldName(fields []string) string {
+	return CombinedFieldNamePrefix + strings.Join(fields, "+")
+}
+
+// resolveEmbeddingFieldName returns the name under which the embeddings
+// of the specified mode are stored (aka. the field name, [RecordLevelFieldName]
+// or the [CombinedFieldName] of the fields).
+func resolveEmbeddingFieldName(mode EmbeddingMode, fieldName string, fields []string) (string, error) {
+	switch mode {
+	case "", EmbeddingModeField:
+		if fieldName == "" {
+			return "", fmt.Errorf("fieldName is required for field-level mode")
+		}
+		return fieldName, nil
+	case EmbeddingModeRecord:
+		return RecordLevelFieldName, nil
+	case EmbeddingModeFields:
+		if len(fields) == 0 {
+			return "", fmt.Errorf("fields are required for multi-field mode")
+		}
+		return CombinedFieldName(fields), nil
+	default:
+		return "", fmt.Errorf("invalid embedding mode: %s (must be 'field', 'record' or 'fields')", mode)
+	}
+}
+
+// SimilarityScoreScale represents the scale of the returned similarity scores
+type SimilarityScoreScale string
+
+const (
+	SimilarityScoreScaleRaw     SimilarityScoreScale = "raw"     // Cosine similarity as is (-1..1)
+	SimilarityScoreScalePercent SimilarityScoreScale = "percent" // Cosine similarity mapped to 0..100
+)
+
+// SimilarityMetric represents the vectors comparison metric of a similarity search
+type SimilarityMetric string
+
+const (
+	SimilarityMetricCosine SimilarityMetric = "cosine" // Cosine similarity (-1..1)
+	SimilarityMetricDot    SimilarityMetric = "dot"    // Dot product (the same as cosine for normalized vectors)
+
+	// SimilarityMetricEuclidean is the L2 (Euclidean) distance of the vectors
+	// reported as a 1/(1+distance) score (0..1) so that, as with the other metrics,
+	// the higher scores are closer (aka. the results are sorted by ascending distance).
+	SimilarityMetricEuclidean SimilarityMetric = "euclidean"
 )
 
+// embeddingModelMetrics maps the known embedding models to the metric they were trained for.
+//
+// The models are matched case-insensitively by their name without the
+// organization prefix (ex. "sentence-transformers/multi-qa-mpnet-base-dot-v1").
+var embeddingModelMetrics = map[string]SimilarityMetric{
+	"text-embedding-3-small":        SimilarityMetricCosine,
+	"text-embedding-3-large":        SimilarityMetricCosine,
+	"text-embedding-ada-002":        SimilarityMetricCosine,
+	"all-minilm-l6-v2":              SimilarityMetricCosine,
+	"all-mpnet-base-v2":             SimilarityMetricCosine,
+	"multi-qa-mpnet-base-dot-v1":    SimilarityMetricDot,
+	"multi-qa-minilm-l6-dot-v1":     SimilarityMetricDot,
+	"multi-qa-distilbert-dot-v1":    SimilarityMetricDot,
+	"msmarco-bert-base-dot-v5":      SimilarityMetricDot,
+	"msmarco-distilbert-base-tas-b": SimilarityMetricDot,
+}
+
+// DefaultSimilarityMetric returns the recommended similarity metric of the
+// specified embedding model (fallbacks to [SimilarityMetricCosine] for unknown models).
+func DefaultSimilarityMetric(model string) SimilarityMetric {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+
+	if metric, ok := embeddingModelMetrics[model]; ok {
+		return metric
+	}
+
+	return SimilarityMetricCosine
+}
+
 // EmbeddingRequest represents a request to generate embeddings for records.
 type EmbeddingRequest struct {
 	CollectionId string        `json:"collectionId"`
-	FieldName    string        `json:"fieldName,omitempty"`              // For field-level mode
-	Mode         EmbeddingMode `json:"mode,omitempty"`                   // "field" or "record"
-	RecordIds    []string      `json:"recordIds,omitempty"`              // If empty, process all records
-	Template     string        `json:"template,omitempty"`               // Optional template for record-level mode
+	FieldName    string        `json:"fieldName,omitempty"` // For field-level mode
+	Mode         EmbeddingMode `json:"mode,omitempty"`      // "field", "record" or "fields"
+	RecordIds    []string      `json:"recordIds,omitempty"` // If empty, process all records
+	Template     string        `json:"template,omitempty"`  // Optional template for record-level mode (see [GenerateRecordText])
+
+	// Fields is the ordered list of fields to embed together for the multi-field mode
+	Fields []string `json:"fields,omitempty"`
+
+	// FieldTemplate is an optional template for field-level mode
+	// wrapping the field value with context (supports {value} placeholder)
+	FieldTemplate string `json:"fieldTemplate,omitempty"`
+
+	// JSONPath is an optional path selector of the json field value to embed for
+	// the field-level mode (ex. "content.body", "items[*].text", "$.tags[0]").
+	//
+	// The embeddings are stored under the [JSONPathFieldName] of the field and path.
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Separator is the separator between the fields values for the multi-field mode
+	// (default to [DefaultCombinedFieldsSeparator])
+	Separator string `json:"separator,omitempty"`
+
+	// RecordFieldSeparator and RecordLabelSeparator are the separators between the
+	// fields and between the field name and value of the default record-level text
+	// (without Template; default to [DefaultRecordFieldSeparator] and [DefaultRecordLabelSeparator]).
+	RecordFieldSeparator string `json:"recordFieldSeparator,omitempty"`
+	RecordLabelSeparator string `json:"recordLabelSeparator,omitempty"`
+
+	// RetryMissing indicates whether to retry individually the records
+	// that are missing from an incomplete batch response.
+	RetryMissing bool `json:"retryMissing,omitempty"`
+
+	// StoreText indicates whether to store a truncated copy of the embedded
+	// text alongside the vector (see [GetEmbeddingText]).
+	StoreText bool `json:"storeText,omitempty"`
+
+	// EmbeddingsCollection is an optional custom embeddings collection name
+	// to store the generated embeddings in (ex. "_embeddings_tenant_a" for per tenant isolation;
+	// default to [EmbeddingsCollectionName]).
+	//
+	// Note that the embeddings of the deleted records are automatically removed only from the default collection.
+	EmbeddingsCollection string `json:"embeddingsCollection,omitempty"`
+
+	// RunId is an optional embedding run identifier used to write the run progress
+	// to a status record in the _embedding_runs collection (see [FindEmbeddingRunStatus]).
+	RunId string `json:"runId,omitempty"`
+
+	// Model is an optional embedding model overwriting the
+	// AIConfig.EmbeddingModel setting (eg. from a stored [EmbeddingConfig]).
+	Model string `json:"model,omitempty"`
+
+	// Debug indicates whether to include the per batch diagnostics
+	// in the response (see [EmbeddingDebug]).
+	Debug bool `json:"debug,omitempty"`
 }
 
 // EmbeddingResponse represents the response from embedding generation.
@@ -295,6 +758,42 @@ type EmbeddingResponse struct {
 	Generated int      `json:"generated"`
 	Skipped   int      `json:"skipped"`
 	Errors    []string `json:"errors,omitempty"`
+
+	// Deduplicated is the number of records that reused the embedding
+	// of another record with identical text (instead of being embedded again).
+	Deduplicated int `json:"deduplicated,omitempty"`
+
+	// KnownEmpty is the number of the skipped records (already included in Skipped)
+	// that weren't evaluated because a previous run found that they have
+	// no embeddable text and their source content hasn't changed since then.
+	KnownEmpty int `json:"knownEmpty,omitempty"`
+
+	// Debug contains the generation diagnostics (populated only with EmbeddingRequest.Debug).
+	Debug *EmbeddingDebug `json:"debug,omitempty"`
+}
+
+// EmbeddingDebug contains debug information for embedding generation
+type EmbeddingDebug struct {
+	Batches []EmbeddingBatchDebug `json:"batches"`
+}
+
+// EmbeddingBatchDebug contains the size and timing details of a single embeddings batch request.
+type EmbeddingBatchDebug struct {
+	Texts           int `json:"texts"`
+	EstimatedTokens int `json:"estimatedTokens"` // see [EstimateEmbeddingTokens]
+
+	// PromptTokens is the provider reported token usage of the batch
+	// (0 if the provider doesn't report it).
+	PromptTokens int `json:"promptTokens"`
+
+	// Attempts is the number of the batch API calls (including the retries).
+	Attempts int `json:"attempts"`
+
+	// Duration is the total duration of the batch API calls
+	// (excluding the retries backoff).
+	Duration float64 `json:"duration"` // in milliseconds
+
+	Error string `json:"error,omitempty"`
 }
 
 // SimilarRecord represents a record with its similarity score.
@@ -307,16 +806,100 @@ type SimilarRecord struct {
 type FindSimilarRequest struct {
 	CollectionId string        `json:"collectionId"`
 	FieldName    string        `json:"fieldName,omitempty"` // For field-level search
-	Mode         EmbeddingMode `json:"mode,omitempty"`      // "field" or "record"
+	Mode         EmbeddingMode `json:"mode,omitempty"`      // "field", "record", "fields" or "combined"
+	Fields       []string      `json:"fields,omitempty"`    // For multi-field search
 	Text         string        `json:"text,omitempty"`      // Text to find similar records for
 	RecordId     string        `json:"recordId,omitempty"`  // Or use existing record's embedding
 	Limit        int           `json:"limit"`
+
+	// ScoreScale is the scale of the returned similarity scores ("raw" by default or "percent")
+	ScoreScale SimilarityScoreScale `json:"scoreScale,omitempty"`
+
+	// MinSimilarity is an optional min raw similarity score (ex. 0.3 for cosine)
+	// of the returned results (the results below it are dropped before applying the limit).
+	//
+	// Note that it is compared with the raw (after the recency boost) score regardless of ScoreScale.
+	MinSimilarity float32 `json:"minSimilarity,omitempty"`
+
+	// Metric is the vectors comparison metric ("cosine", "dot" or "euclidean").
+	//
+	// Default to the [DefaultSimilarityMetric] of the collection embedding model.
+	Metric SimilarityMetric `json:"metric,omitempty"`
+
+	// RecencyHalfLifeDays enables the recency boost when positive, blending the similarity
+	// with a time-decay factor that halves every RecencyHalfLifeDays since the source record last update.
+	RecencyHalfLifeDays float64 `json:"recencyHalfLifeDays,omitempty"`
+
+	// RecencyWeight is the weight (0-1) of the recency time-decay factor in the final score
+	// (default to [DefaultRecencyWeight]).
+	RecencyWeight float64 `json:"recencyWeight,omitempty"`
+
+	// EmbeddingsCollection is an optional custom embeddings collection name
+	// to search in (default to [EmbeddingsCollectionName]).
+	EmbeddingsCollection string `json:"embeddingsCollection,omitempty"`
+
+	// JSONPath is an optional json field path selector for the field-level and combined modes
+	// (the same as the one of the [EmbeddingRequest] used to generate the embeddings).
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Prefilter is an optional filter expression of the source collection records
+	// limiting the scored candidates before the vector comparison (ex. `status = "published" && category = "news"`).
+	//
+	// It uses the standard PocketBase filter syntax and supports {:name} placeholders
+	// resolved from PrefilterParams. When searching by RecordId, the query record
+	// field values are also available as placeholders (ex. `category = {:category}`).
+	Prefilter string `json:"prefilter,omitempty"`
+
+	// PrefilterParams are the optional Prefilter placeholder values
+	// (they take precedence over the query record field values with the same name).
+	PrefilterParams map[string]any `json:"prefilterParams,omitempty"`
+
+	// StrictModel fails the search with [ErrEmbeddingModelMismatch] instead of
+	// returning a warning when the stored embeddings were generated with
+	// a different model than the query one.
+	StrictModel bool `json:"strictModel,omitempty"`
+
+	// GroupBy is an optional single value source collection field name
+	// to group the results by (ex. "category").
+	//
+	// When set, Limit is the max number of returned groups (ordered by their best match)
+	// and PerGroupLimit is the max number of results within each group.
+	GroupBy string `json:"groupBy,omitempty"`
+
+	// PerGroupLimit is the max number of results of a single group
+	// (default to [DefaultSimilarityPerGroupLimit]).
+	PerGroupLimit int `json:"perGroupLimit,omitempty"`
+
+	// Expand indicates whether to load the matched source records
+	// into the response Expand map (keyed by record id).
+	//
+	// Note that the records are loaded without access checks
+	// and their hidden fields are excluded from the JSON serialization.
+	Expand bool `json:"expand,omitempty"`
+}
+
+// SimilarRecordsGroup represents the similar records sharing the same GroupBy field value.
+type SimilarRecordsGroup struct {
+	Value   string          `json:"value"`
+	Results []SimilarRecord `json:"results"`
 }
 
 // FindSimilarResponse represents the response from finding similar records.
 type FindSimilarResponse struct {
-	Results []SimilarRecord `json:"results"`
+	Results []SimilarRecord  `json:"results"`
 	Debug   *SimilarityDebug `json:"debug,omitempty"`
+
+	// Groups lists the grouped results when [FindSimilarRequest.GroupBy] is set
+	// (Results contains the same records flattened in the groups order).
+	Groups []SimilarRecordsGroup `json:"groups,omitempty"`
+
+	// Expand contains the matched source records keyed by their id
+	// when [FindSimilarRequest.Expand] is set (the results without
+	// an existing source record, ex. orphaned embeddings, are missing).
+	Expand map[string]*Record `json:"expand,omitempty"`
+
+	// Warnings lists the non-fatal search issues (ex. an embedding model mismatch).
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // SimilarityDebug contains debug information for similarity search
@@ -331,6 +914,17 @@ type SimilarityDebug struct {
 	CacheSkipped      bool       `json:"cacheSkipped,omitempty"` // True if too large to cache
 	CacheStats        *CacheInfo `json:"cacheStats,omitempty"`
 	Errors            []string   `json:"errors,omitempty"`
+
+	// Metric is the resolved vectors comparison metric of the search
+	Metric SimilarityMetric `json:"metric,omitempty"`
+
+	// CandidatesCount is the number of the scored records before
+	// applying the MinSimilarity threshold and the results limit
+	CandidatesCount int `json:"candidatesCount"`
+
+	// RawSimilarities contains the raw metric scores of the results
+	// (populated only when the results are with non-raw score scale)
+	RawSimilarities map[string]float32 `json:"rawSimilarities,omitempty"`
 }
 
 // CacheInfo contains summary info about the embedding cache
@@ -339,6 +933,17 @@ type CacheInfo struct {
 	MemoryUsedMB       float64 `json:"memoryUsedMB"`
 	MemoryBudgetMB     float64 `json:"memoryBudgetMB"`
 	MemoryUsagePercent float64 `json:"memoryUsagePercent"`
+
+	// Hits and Misses are the total number of cache lookups
+	// with and without (or with expired) cached embeddings.
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+
+	// HitRate is the percentage of the cache lookups that were hits.
+	HitRate float64 `json:"hitRate"`
+
+	// Evictions is the total number of evicted cache entries.
+	Evictions uint64 `json:"evictions"`
 }
 
 // OpenAI Embeddings API structures
@@ -363,25 +968,66 @@ type openAIEmbeddingResponse struct {
 	} `json:"usage"`
 }
 
+// RecordTextOptions defines the record-level text representation format (see [GenerateRecordTextWithOptions]).
+type RecordTextOptions struct {
+	// Template is an optional custom text template (supports {fieldName} placeholders,
+	// {fieldName|"default"} fallbacks and {?fieldName}...{/fieldName} conditional blocks).
+	Template string
+
+	// FieldSeparator is the separator between the fields of the default format
+	// (default to [DefaultRecordFieldSeparator]).
+	FieldSeparator string
+
+	// LabelSeparator is the separator between the field name and value of the default format
+	// (default to [DefaultRecordLabelSeparator]).
+	LabelSeparator string
+}
+
 // GenerateRecordText creates a text representation of an entire record for embedding.
 // It concatenates all text and editor fields into a structured format.
-// If a template is provided, it uses that instead (supports {fieldName} placeholders).
+// If a template is provided, it uses that instead (supports {fieldName} placeholders,
+// {fieldName|"default"} fallbacks and {?fieldName}...{/fieldName} conditional blocks).
 func GenerateRecordText(record *Record, collection *Collection, template string) string {
-	if template != "" {
-		// Use custom template with {fieldName} placeholders
-		result := template
-		for _, field := range collection.Fields {
-			placeholder := "{" + field.GetName() + "}"
-			value := record.GetString(field.GetName())
+	return GenerateRecordTextWithOptions(record, collection, RecordTextOptions{Template: template})
+}
+
+// GenerateRecordTextWithOptions is the same as [GenerateRecordText] but
+// allows customizing the separators of the default structured format
+// (ex. ". " and " is " for a more sentence-like text).
+func GenerateRecordTextWithOptions(record *Record, collection *Collection, options RecordTextOptions) string {
+	return generateRecordText(record, collection, options, DefaultHTMLStripMaxSize, DefaultHTMLStripMaxTags)
+}
+
+// generateRecordText is the same as [GenerateRecordTextWithOptions] but with
+// custom HTML stripping limits for the editor fields.
+func generateRecordText(record *Record, collection *Collection, options RecordTextOptions, htmlMaxSize, htmlMaxTags int) string {
+	if options.Template != "" {
+		// Use custom template with {fieldName} placeholders and expressions
+		result := renderEmbeddingTemplate(options.Template, func(name string) (string, bool) {
+			field := collection.Fields.GetByName(name)
+			if field == nil {
+				return "", false
+			}
+			value := record.GetString(name)
 			// Strip HTML for editor fields
 			if field.Type() == "editor" {
-				value = stripHTML(value)
+				value = stripHTMLWithLimits(value, htmlMaxSize, htmlMaxTags)
 			}
-			result = strings.ReplaceAll(result, placeholder, value)
-		}
+			return value, true
+		})
 		return strings.TrimSpace(result)
 	}
 
+	fieldSeparator := options.FieldSeparator
+	if fieldSeparator == "" {
+		fieldSeparator = DefaultRecordFieldSeparator
+	}
+
+	labelSeparator := options.LabelSeparator
+	if labelSeparator == "" {
+		labelSeparator = DefaultRecordLabelSeparator
+	}
+
 	// Default format: structured key-value pairs
 	var parts []string
 	for _, field := range collection.Fields {
@@ -395,45 +1041,102 @@ func GenerateRecordText(record *Record, collection *Collection, template string)
 			}
 			// Strip HTML for editor fields
 			if fieldType == "editor" {
-				value = stripHTML(value)
+				value = stripHTMLWithLimits(value, htmlMaxSize, htmlMaxTags)
 			}
 			// Truncate very long values to avoid token limits
 			if len(value) > 2000 {
 				value = value[:2000] + "..."
 			}
-			parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+			parts = append(parts, name+labelSeparator+value)
 		}
 	}
-	return strings.Join(parts, "\n")
+	return strings.Join(parts, fieldSeparator)
 }
 
-// stripHTML removes HTML tags from a string
+// stripHTML removes HTML tags from a string using the default size and tags limits.
 func stripHTML(s string) string {
-	// Simple HTML stripping - removes tags
-	result := s
+	return stripHTMLWithLimits(s, DefaultHTMLStripMaxSize, DefaultHTMLStripMaxTags)
+}
+
+// stripHTMLWithLimits removes HTML tags from a string.
+//
+// The input is truncated to maxSize bytes (at a valid UTF-8 boundary) before the stripping
+// and the stripping stops after maxTags removed tags, discarding the rest of the input.
+// Non-positive limits fallback to their defaults.
+func stripHTMLWithLimits(s string, maxSize int, maxTags int) string {
+	if maxSize <= 0 {
+		maxSize = DefaultHTMLStripMaxSize
+	}
+	if maxTags <= 0 {
+		maxTags = DefaultHTMLStripMaxTags
+	}
+
+	if len(s) > maxSize {
+		// make sure that we don't cut in the middle of a multi-byte character
+		cut := maxSize
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut]
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	// write appends str to the result collapsing the consecutive spaces
+	var lastSpace bool
+	write := func(str string) {
+		for i := 0; i < len(str); i++ {
+			isSpace := str[i] == ' '
+			if isSpace && lastSpace {
+				continue
+			}
+			lastSpace = isSpace
+			sb.WriteByte(str[i])
+		}
+	}
+
+	rest := s
+	var tags int
 	for {
-		start := strings.Index(result, "<")
+		start := strings.IndexByte(rest, '<')
 		if start == -1 {
 			break
 		}
-		end := strings.Index(result[start:], ">")
+		end := strings.IndexByte(rest[start:], '>')
 		if end == -1 {
 			break
 		}
-		result = result[:start] + " " + result[start+end+1:]
-	}
-	// Clean up multiple spaces
-	for strings.Contains(result, "  ") {
-		result = strings.ReplaceAll(result, "  ", " ")
+
+		if tags >= maxTags {
+			rest = rest[:start] // limit reached
+			break
+		}
+
+		write(rest[:start])
+		write(" ")
+		rest = rest[start+end+1:]
+		tags++
 	}
-	return strings.TrimSpace(result)
+	write(rest)
+
+	return strings.TrimSpace(sb.String())
 }
 
 // GenerateEmbeddings generates vector embeddings for records.
-// Supports two modes:
+// Supports three modes:
 // - "field" (default): Embed a specific text/editor field
 // - "record": Embed the entire record as a single text representation
+// - "fields": Embed the concatenation of specific text/editor fields as a single text
+//   (stored under the [CombinedFieldName] of the fields)
 func GenerateEmbeddings(app App, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	return GenerateEmbeddingsWithContext(context.Background(), app, req)
+}
+
+// GenerateEmbeddingsWithContext is the same as [GenerateEmbeddings] but
+// stops issuing new embedding requests (and cancels the in progress one)
+// when ctx is done, returning [ErrAIRequestCanceled].
+func GenerateEmbeddingsWithContext(ctx context.Context, app App, req EmbeddingRequest) (*EmbeddingResponse, error) {
 	settings := app.Settings()
 
 	if !settings.AI.Enabled {
@@ -444,7 +1147,11 @@ func GenerateEmbeddings(app App, req EmbeddingRequest) (*EmbeddingResponse, erro
 		return nil, fmt.Errorf("AI API key is not configured")
 	}
 
-	if settings.AI.EmbeddingModel == "" {
+	model := req.Model
+	if model == "" {
+		model = settings.AI.EmbeddingModel
+	}
+	if model == "" {
 		return nil, fmt.Errorf("embedding model is not configured")
 	}
 
@@ -460,28 +1167,66 @@ func GenerateEmbeddings(app App, req EmbeddingRequest) (*EmbeddingResponse, erro
 		mode = EmbeddingModeField
 	}
 
-	// For field mode, verify the field exists and is embeddable
-	fieldName := req.FieldName
-	if mode == EmbeddingModeField {
-		if fieldName == "" {
-			return nil, fmt.Errorf("fieldName is required for field-level embedding mode")
+	fieldName, err := resolveEmbeddingFieldName(mode, req.FieldName, req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify that the source fields exist and are embeddable
+	var sourceFields []string
+	switch mode {
+	case EmbeddingModeField:
+		sourceFields = []string{fieldName}
+	case EmbeddingModeFields:
+		sourceFields = req.Fields
+	}
+
+	var jsonPath []jsonPathSegment
+	if req.JSONPath != "" {
+		if mode != EmbeddingModeField {
+			return nil, fmt.Errorf("jsonPath is supported only for field-level mode")
+		}
+
+		if _, ok := collection.Fields.GetByName(fieldName).(*JSONField); !ok {
+			return nil, fmt.Errorf("field '%s' is not a json field", fieldName)
 		}
-		field := collection.Fields.GetByName(fieldName)
+
+		jsonPath, err = parseEmbeddingJSONPath(req.JSONPath)
+		if err != nil {
+			return nil, err
+		}
+
+		// the json field itself is not embeddable so its checks are skipped
+		sourceFields = nil
+		fieldName = JSONPathFieldName(fieldName, req.JSONPath)
+	}
+
+	for _, name := range sourceFields {
+		field := collection.Fields.GetByName(name)
 		if field == nil {
-			return nil, fmt.Errorf("field '%s' not found in collection", fieldName)
+			return nil, fmt.Errorf("field '%s' not found in collection", name)
 		}
-		if !IsFieldEmbeddable(field) {
-			return nil, fmt.Errorf("field '%s' is not a text/editor field or is not marked as embeddable", fieldName)
+		if !IsAppFieldEmbeddable(app, field) {
+			return nil, fmt.Errorf("field '%s' is not a text/editor field or is not marked as embeddable", name)
 		}
-	} else if mode == EmbeddingModeRecord {
-		// For record mode, use special field name
-		fieldName = RecordLevelFieldName
-	} else {
-		return nil, fmt.Errorf("invalid embedding mode: %s (must be 'field' or 'record')", mode)
+	}
+
+	if req.FieldTemplate != "" && !strings.Contains(req.FieldTemplate, "{value}") {
+		return nil, fmt.Errorf("fieldTemplate must contain the {value} placeholder")
+	}
+
+	separator := req.Separator
+	if separator == "" {
+		separator = DefaultCombinedFieldsSeparator
+	}
+
+	embeddingsName, err := resolveEmbeddingsCollectionName(req.EmbeddingsCollection)
+	if err != nil {
+		return nil, err
 	}
 
 	// Ensure embeddings collection exists
-	embeddingsCollection, err := EnsureEmbeddingsCollection(app)
+	embeddingsCollection, err := EnsureEmbeddingsCollectionByName(app, embeddingsName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ensure embeddings collection: %w", err)
 	}
@@ -512,79 +1257,318 @@ func GenerateEmbeddings(app App, req EmbeddingRequest) (*EmbeddingResponse, erro
 	type textRecord struct {
 		RecordId string
 		Text     string
+		Tokens   int // the estimated tokens of Text
 	}
 	var textsToEmbed []textRecord
 
+	response := &EmbeddingResponse{}
+	if req.Debug {
+		response.Debug = &EmbeddingDebug{Batches: []EmbeddingBatchDebug{}}
+	}
+
+	var tracker *embeddingRunTracker
+	if req.RunId != "" {
+		tracker, err = newEmbeddingRunTracker(app, req.RunId, collection.Id, fieldName, len(records))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the embedding run status: %w", err)
+		}
+	}
+
+	recordTextOptions := RecordTextOptions{
+		Template:       req.Template,
+		FieldSeparator: req.RecordFieldSeparator,
+		LabelSeparator: req.RecordLabelSeparator,
+	}
+
+	// The records without embeddable text are remembered (until their source fields
+	// or the text options change) so that the next runs don't evaluate them again
+	fingerprintFields := sourceFields
+	if mode == EmbeddingModeRecord {
+		fingerprintFields = collection.Fields.FieldNames()
+	} else if jsonPath != nil {
+		fingerprintFields = []string{req.FieldName}
+	}
+	fingerprintOptions := strings.Join([]string{
+		string(mode),
+		req.Template,
+		req.FieldTemplate,
+		req.JSONPath,
+		separator,
+		req.RecordFieldSeparator,
+		req.RecordLabelSeparator,
+		strconv.Itoa(settings.AI.HTMLStripMaxSize),
+		strconv.Itoa(settings.AI.HTMLStripMaxTags),
+	}, "\x00")
+
 	for _, record := range records {
+		emptyKey := emptyEmbeddingTextKey(embeddingsName, collection.Id, fieldName, record.Id)
+		fingerprint := emptyEmbeddingTextFingerprint(record, fingerprintFields, fingerprintOptions)
+		if emptyEmbeddingTexts.Has(emptyKey, fingerprint) {
+			response.KnownEmpty++
+			response.Skipped++
+			continue
+		}
+
 		var text string
 		if mode == EmbeddingModeRecord {
 			// Generate full record text representation
-			text = GenerateRecordText(record, collection, req.Template)
+			text = generateRecordText(record, collection, recordTextOptions, settings.AI.HTMLStripMaxSize, settings.AI.HTMLStripMaxTags)
+		} else if jsonPath != nil {
+			// Extract the text of the selected json field path
+			text = extractJSONPathText([]byte(record.GetString(req.FieldName)), jsonPath)
+
+			if req.FieldTemplate != "" && text != "" {
+				text = strings.ReplaceAll(req.FieldTemplate, "{value}", text)
+			}
 		} else {
-			// Get specific field value
-			text = record.GetString(fieldName)
-			// Strip HTML for editor fields
-			field := collection.Fields.GetByName(fieldName)
-			if field != nil && field.Type() == "editor" {
-				text = stripHTML(text)
+			// Get the (ordered) source fields values
+			values := make([]string, 0, len(sourceFields))
+			for _, name := range sourceFields {
+				value := record.GetString(name)
+				// Strip HTML for editor fields
+				field := collection.Fields.GetByName(name)
+				if field != nil && field.Type() == "editor" {
+					value = stripHTMLWithLimits(value, settings.AI.HTMLStripMaxSize, settings.AI.HTMLStripMaxTags)
+				}
+				if value != "" {
+					values = append(values, value)
+				}
+			}
+			text = strings.Join(values, separator)
+
+			// Wrap the field value with the field template
+			if mode == EmbeddingModeField && req.FieldTemplate != "" && text != "" {
+				text = strings.ReplaceAll(req.FieldTemplate, "{value}", text)
 			}
 		}
 
 		if text != "" {
-			textsToEmbed = append(textsToEmbed, textRecord{
-				RecordId: record.Id,
-				Text:     text,
-			})
+			text = preprocessEmbeddingText(text)
 		}
-	}
 
-	if len(textsToEmbed) == 0 {
-		return &EmbeddingResponse{Generated: 0, Skipped: len(records)}, nil
-	}
+		if text == "" {
+			emptyEmbeddingTexts.Set(emptyKey, fingerprint)
+			response.Skipped++
+			continue
+		}
+		emptyEmbeddingTexts.Delete(emptyKey)
 
-	// Process in batches
-	response := &EmbeddingResponse{}
-	batches := batchTexts(textsToEmbed)
+		// Skip the too short texts since they usually produce low quality embeddings
+		if reason := checkEmbeddingTextLength(text, settings.AI.EmbeddingMinChars, settings.AI.EmbeddingMinWords); reason != "" {
+			response.Errors = append(response.Errors, fmt.Sprintf("record %s: %s", record.Id, reason))
+			response.Skipped++
+			continue
+		}
 
-	for _, batch := range batches {
-		// Extract just the texts for the API call
-		texts := make([]string, len(batch))
-		for i, tr := range batch {
-			texts[i] = tr.Text
+		// Truncate the texts that alone exceed the batch tokens budget
+		// (otherwise the whole batch request would fail)
+		tokens := EstimateEmbeddingTokens(text)
+		if tokens > MaxTokensPerBatch {
+			app.Logger().Warn(
+				"Truncated embedding text exceeding the batch tokens limit",
+				"recordId", record.Id,
+				"fieldName", fieldName,
+				"estimatedTokens", tokens,
+				"maxTokens", MaxTokensPerBatch,
+			)
+			text = truncateEmbeddingTextTokens(text, MaxTokensPerBatch)
+			tokens = EstimateEmbeddingTokens(text)
+		}
+
+		textsToEmbed = append(textsToEmbed, textRecord{
+			RecordId: record.Id,
+			Text:     text,
+			Tokens:   tokens,
+		})
+	}
+
+	// Embed the identical texts only once and fan out the resulting
+	// vector to all records sharing the same text
+	duplicates := map[string][]textRecord{}
+	uniqueTexts := make([]textRecord, 0, len(textsToEmbed))
+	for _, tr := range textsToEmbed {
+		if _, ok := duplicates[tr.Text]; ok {
+			duplicates[tr.Text] = append(duplicates[tr.Text], tr)
+			continue
+		}
+		duplicates[tr.Text] = []textRecord{}
+		uniqueTexts = append(uniqueTexts, tr)
+	}
+	textsToEmbed = uniqueTexts
+
+	// Process in batches
+
+	storeOne := func(tr textRecord, embedding []float32) {
+		var text string
+		if req.StoreText {
+			text = truncateEmbeddingText(tr.Text)
+		}
+
+		err := storeEmbedding(app, embeddingsCollection, StoreEmbeddingParams{
+			RecordId:     tr.RecordId,
+			CollectionId: collection.Id,
+			FieldName:    fieldName,
+			Embedding:    embedding,
+			Model:        model,
+			Dimensions:   len(embedding),
+			Text:         text,
+		})
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("record %s: %s", tr.RecordId, err.Error()))
+			response.Skipped++
+		} else {
+			response.Generated++
+		}
+	}
+
+	store := func(tr textRecord, embedding []float32) {
+		storeOne(tr, embedding)
+		for _, dup := range duplicates[tr.Text] {
+			storeOne(dup, embedding)
+			response.Deduplicated++
+		}
+	}
+
+	// skip marks as skipped the text record and its duplicates
+	skip := func(tr textRecord, reason string) {
+		response.Errors = append(response.Errors, fmt.Sprintf("record %s: %s", tr.RecordId, reason))
+		response.Skipped += 1 + len(duplicates[tr.Text])
+	}
+
+	batchSize := newAdaptiveBatchSize(settings.AI.EmbeddingBatchSize)
+
+	maxRetries := settings.AI.EmbeddingMaxRetriesOrDefault()
+	retryBaseDelay := settings.AI.EmbeddingRetryBaseDelayDuration()
+
+	var retries int
+
+	// the current batch attempts and total API calls duration (for the debug info)
+	var attempts int
+	var apiDuration time.Duration
+
+	// cancel stops the run, keeping the progress of the already stored embeddings
+	cancel := func(err error) (*EmbeddingResponse, error) {
+		tracker.Update(EmbeddingRunStatusCanceled, response)
+		return nil, err
+	}
+
+	for pos := 0; pos < len(textsToEmbed); {
+		if err := checkAIContext(ctx); err != nil {
+			return cancel(err)
+		}
+
+		// Close the batch when either the batch size or the tokens budget is reached
+		// (a batch has always at least one text)
+		end := pos
+		var batchTokens int
+		for end < len(textsToEmbed) && end-pos < batchSize.Current() {
+			if end > pos && batchTokens+textsToEmbed[end].Tokens > MaxTokensPerBatch {
+				break
+			}
+			batchTokens += textsToEmbed[end].Tokens
+			end++
+		}
+		batch := textsToEmbed[pos:end]
+
+		// Extract just the texts for the API call
+		texts := make([]string, len(batch))
+		for i, tr := range batch {
+			texts[i] = tr.Text
 		}
 
 		// Call OpenAI API
-		embeddings, err := callOpenAIEmbeddings(app, texts)
+		attempts++
+		started := time.Now()
+		embeddings, promptTokens, err := callOpenAIEmbeddingsLimited(ctx, app, model, texts, settings.AI.EmbeddingTimeoutDuration())
+		apiDuration += time.Since(started)
+		if errors.Is(err, ErrAIRequestCanceled) {
+			return cancel(err)
+		}
+
+		// Retry the transient errors with backoff
+		// (the batch is skipped only after all retries are exhausted)
+		var retryErr *aiRetryableError
+		if errors.As(err, &retryErr) && retries < maxRetries {
+			retries++
+			if retryErr.StatusCode == http.StatusTooManyRequests {
+				// shrink the batch size to reduce the tokens per minute pressure
+				batchSize.Shrink()
+			}
+			if err := sleepWithContext(ctx, retryErr.Backoff(retries, retryBaseDelay)); err != nil {
+				return cancel(err)
+			}
+			continue
+		}
+		retries = 0
+		pos += len(batch)
+
+		if response.Debug != nil {
+			batchDebug := EmbeddingBatchDebug{
+				Texts:           len(batch),
+				EstimatedTokens: batchTokens,
+				PromptTokens:    promptTokens,
+				Attempts:        attempts,
+				Duration:        float64(apiDuration.Microseconds()) / 1000,
+			}
+			if err != nil {
+				batchDebug.Error = err.Error()
+			}
+			response.Debug.Batches = append(response.Debug.Batches, batchDebug)
+		}
+		attempts = 0
+		apiDuration = 0
+
 		if err != nil {
 			response.Errors = append(response.Errors, fmt.Sprintf("batch error: %s", err.Error()))
-			response.Skipped += len(batch)
+			for _, tr := range batch {
+				response.Skipped += 1 + len(duplicates[tr.Text])
+			}
+			tracker.Update(EmbeddingRunStatusRunning, response)
 			continue
 		}
 
+		batchSize.Grow()
+
+		var received int
+		for _, embedding := range embeddings {
+			if embedding != nil {
+				received++
+			}
+		}
+
 		// Store embeddings
-		for i, embedding := range embeddings {
-			if i >= len(batch) {
-				break
+		// (some OpenAI compatible endpoints could omit the embeddings of some of the submitted texts)
+		for i, tr := range batch {
+			if embeddings[i] != nil {
+				store(tr, embeddings[i])
+				continue
+			}
+
+			if !req.RetryMissing {
+				skip(tr, fmt.Sprintf("missing embedding in the batch response (received %d of %d)", received, len(batch)))
+				continue
+			}
+
+			retried, _, err := callOpenAIEmbeddingsLimited(ctx, app, model, []string{tr.Text}, settings.AI.EmbeddingTimeoutDuration())
+			if errors.Is(err, ErrAIRequestCanceled) {
+				return cancel(err)
+			}
+			if err == nil && retried[0] == nil {
+				err = errors.New("missing embedding in the response")
 			}
-			tr := batch[i]
-
-			err := storeEmbedding(app, embeddingsCollection, StoreEmbeddingParams{
-				RecordId:     tr.RecordId,
-				CollectionId: collection.Id,
-				FieldName:    fieldName,
-				Embedding:    embedding,
-				Model:        settings.AI.EmbeddingModel,
-				Dimensions:   len(embedding),
-			})
 			if err != nil {
-				response.Errors = append(response.Errors, fmt.Sprintf("record %s: %s", tr.RecordId, err.Error()))
-				response.Skipped++
-			} else {
-				response.Generated++
+				skip(tr, "retry error: "+err.Error())
+				continue
 			}
+
+			store(tr, retried[0])
 		}
+
+		tracker.Update(EmbeddingRunStatusRunning, response)
 	}
 
+	tracker.Update(EmbeddingRunStatusCompleted, response)
+
 	// Limit errors to 10
 	if len(response.Errors) > 10 {
 		response.Errors = append(response.Errors[:10], fmt.Sprintf("... and %d more errors", len(response.Errors)-10))
@@ -593,15 +1577,64 @@ func GenerateEmbeddings(app App, req EmbeddingRequest) (*EmbeddingResponse, erro
 	return response, nil
 }
 
-// batchTexts groups texts into batches respecting token limits
-func batchTexts[T any](texts []T) [][]T {
+// checkEmbeddingTextLength checks whether the text satisfies the min characters
+// and words limits (non-positive limits are ignored).
+//
+// Returns the skip reason or empty string if the text is long enough.
+func checkEmbeddingTextLength(text string, minChars int, minWords int) string {
+	if minChars > 0 {
+		if chars := utf8.RuneCountInString(strings.TrimSpace(text)); chars < minChars {
+			return fmt.Sprintf("text is too short (%d characters, min %d)", chars, minChars)
+		}
+	}
+
+	if minWords > 0 {
+		if words := len(strings.Fields(text)); words < minWords {
+			return fmt.Sprintf("text is too short (%d words, min %d)", words, minWords)
+		}
+	}
+
+	return ""
+}
+
+// EstimateEmbeddingTokens returns the approximate number of tokens of
+// an embedding input text (~4 bytes per token, rounded up).
+//
+// It is only a heuristic and the real tokenizer count could differ,
+// especially for non-English texts.
+func EstimateEmbeddingTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// truncateEmbeddingTextTokens truncates the text so that its
+// estimated tokens are at most maxTokens (at a valid UTF-8 boundary).
+func truncateEmbeddingTextTokens(text string, maxTokens int) string {
+	maxSize := maxTokens * 4
+	if len(text) <= maxSize {
+		return text
+	}
+
+	// make sure that we don't cut in the middle of a multi-byte character
+	cut := maxSize
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+
+	return text[:cut]
+}
+
+// batchTexts groups texts into batches with up to batchSize items
+// (non-positive or larger than MaxTextsPerBatch sizes fallback to MaxTextsPerBatch)
+func batchTexts[T any](texts []T, batchSize int) [][]T {
 	if len(texts) == 0 {
 		return nil
 	}
 
 	// Simple batching by count (can be enhanced with token counting)
 	var batches [][]T
-	batchSize := MaxTextsPerBatch
+	if batchSize <= 0 || batchSize > MaxTextsPerBatch {
+		batchSize = MaxTextsPerBatch
+	}
 	if batchSize > len(texts) {
 		batchSize = len(texts)
 	}
@@ -617,12 +1650,170 @@ func batchTexts[T any](texts []T) [][]T {
 	return batches
 }
 
+// adaptiveBatchSize is an embeddings batch size that shrinks on rate
+// limit errors and gradually grows back (up to its max) on success.
+type adaptiveBatchSize struct {
+	max     int
+	current int
+}
+
+// newAdaptiveBatchSize creates a new adaptive batch size starting from the max size
+// (non-positive or larger than MaxTextsPerBatch sizes fallback to MaxTextsPerBatch).
+func newAdaptiveBatchSize(maxSize int) *adaptiveBatchSize {
+	if maxSize <= 0 || maxSize > MaxTextsPerBatch {
+		maxSize = MaxTextsPerBatch
+	}
+
+	return &adaptiveBatchSize{max: maxSize, current: maxSize}
+}
+
+// Current returns the current batch size.
+func (s *adaptiveBatchSize) Current() int {
+	return s.current
+}
+
+// Shrink halves the current batch size (min 1).
+func (s *adaptiveBatchSize) Shrink() {
+	s.current = max(1, s.current/2)
+}
+
+// Grow increases the current batch size with 50% (min 1, capped to the max batch size).
+func (s *adaptiveBatchSize) Grow() {
+	s.current = min(s.max, s.current+max(1, s.current/2))
+}
+
+// aiRetryableError is returned when the AI provider responds with
+// a transient error status code (see [isAIRetryableStatus]).
+type aiRetryableError struct {
+	// RetryAfter is the parsed Retry-After response header (if any).
+	RetryAfter *time.Duration
+
+	StatusCode int
+	Body       string
+}
+
+// Error implements the [error] interface.
+func (e *aiRetryableError) Error() string {
+	return fmt.Sprintf("OpenAI API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Backoff returns the wait time before the next request attempt.
+//
+// It is the Retry-After of the response if specified, otherwise an
+// exponential backoff based on the number of consecutive attempts
+// with random jitter (between half and the full backoff).
+func (e *aiRetryableError) Backoff(attempt int, baseDelay time.Duration) time.Duration {
+	if e.RetryAfter != nil {
+		return *e.RetryAfter
+	}
+
+	backoff := baseDelay
+	for i := 1; i < attempt && backoff < maxEmbeddingsRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxEmbeddingsRetryBackoff {
+		backoff = maxEmbeddingsRetryBackoff
+	}
+
+	if half := int64(backoff / 2); half > 0 {
+		return time.Duration(half + rand.Int63n(half+1))
+	}
+
+	return backoff
+}
+
+// isAIRetryableStatus reports whether the AI provider response status code
+// is a transient error that could be retried (429, 500, 502, 503 and 504).
+func isAIRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses the Retry-After header value
+// (either delay seconds or an HTTP date).
+//
+// Returns nil if the value is missing or invalid.
+func parseRetryAfter(value string) *time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return nil
+		}
+		retryAfter := time.Duration(seconds) * time.Second
+		return &retryAfter
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		retryAfter := max(0, time.Until(date))
+		return &retryAfter
+	}
+
+	return nil
+}
+
+// sleepWithContext pauses the current goroutine for the specified duration
+// or until ctx is done (returning [ErrAIRequestCanceled]).
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return checkAIContext(ctx)
+	}
+}
+
 // callOpenAIEmbeddings calls the OpenAI embeddings API with a batch of texts
-func callOpenAIEmbeddings(app App, texts []string) ([][]float32, error) {
+func callOpenAIEmbeddings(ctx context.Context, app App, model string, texts []string, timeout time.Duration) ([][]float32, error) {
+	embeddings, _, err := callOpenAIEmbeddingsWithUsage(ctx, app, model, texts, timeout)
+	return embeddings, err
+}
+
+// callOpenAIEmbeddingsLimited is the same as [callOpenAIEmbeddingsWithUsage]
+// but waits for a free slot of the shared generation requests limit
+// (the search query embeddings are not limited to keep the search responsive).
+func callOpenAIEmbeddingsLimited(ctx context.Context, app App, model string, texts []string, timeout time.Duration) ([][]float32, int, error) {
+	if err := embeddingRequestsSem.Acquire(ctx, 1); err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrAIRequestCanceled, err)
+	}
+	defer embeddingRequestsSem.Release(1)
+
+	return callOpenAIEmbeddingsWithUsage(ctx, app, model, texts, timeout)
+}
+
+// callOpenAIEmbeddingsWithUsage is the same as [callOpenAIEmbeddings]
+// but also returns the reported prompt tokens usage of the request.
+//
+// The returned embeddings are always with the same length as texts
+// and the ones missing in the response are nil.
+func callOpenAIEmbeddingsWithUsage(ctx context.Context, app App, model string, texts []string, timeout time.Duration) ([][]float32, int, error) {
 	settings := app.Settings()
 
+	if settings.AI.Provider == AIProviderAnthropic {
+		return nil, 0, ErrAIEmbeddingsNotSupported
+	}
+
+	if err := checkAIContext(ctx); err != nil {
+		return nil, 0, err
+	}
+
 	reqBody := openAIEmbeddingRequest{
-		Model:          settings.AI.EmbeddingModel,
+		Model:          model,
 		Input:          texts,
 		EncodingFormat: "float",
 	}
@@ -634,291 +1825,995 @@ func callOpenAIEmbeddings(app App, texts []string) ([][]float32, error) {
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, 0, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", openAIEmbeddingsURL, bytes.NewBuffer(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", openAIURL(settings.AI, settings.AI.EmbeddingsPathOrDefault()), bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", settings.AI.APIKey))
+	setOpenAIAuthHeader(httpReq, settings.AI)
 
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-	}
+	client := newAIHTTPClient(app, timeout)
 
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call OpenAI API: %w", err)
+		if ctxErr := checkAIContext(ctx); ctxErr != nil {
+			return nil, 0, ctxErr
+		}
+		return nil, 0, fmt.Errorf("failed to call OpenAI API: %w", err)
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := readAIResponseBody(resp.Body, settings.AI.MaxResponseSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if isAIRetryableStatus(resp.StatusCode) {
+		return nil, 0, &aiRetryableError{
+			StatusCode: resp.StatusCode,
+			Body:       string(respBody),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, 0, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
 	}
 
 	var openAIResp openAIEmbeddingResponse
 	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Place each vector at the position of its input text
+	// (some OpenAI compatible endpoints could omit items, leaving nil slots)
+	embeddings := make([][]float32, len(texts))
+	for _, data := range openAIResp.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, 0, fmt.Errorf("invalid embedding index %d in the response (%d inputs)", data.Index, len(texts))
+		}
+		if embeddings[data.Index] != nil {
+			return nil, 0, fmt.Errorf("duplicated embedding index %d in the response", data.Index)
+		}
+		if len(data.Embedding) == 0 {
+			continue // treat as missing
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+
+	return embeddings, openAIResp.Usage.PromptTokens, nil
+}
+
+// StoreEmbeddingParams contains parameters for storing an embedding
+type StoreEmbeddingParams struct {
+	RecordId     string
+	CollectionId string
+	FieldName    string
+	Embedding    []float32
+	Model        string
+	Dimensions   int
+
+	// Text is the optional embedded source text to store
+	// (an empty value clears the previously stored text)
+	Text string
+}
+
+// truncateEmbeddingText truncates the text to [MaxStoredEmbeddingTextLength] characters.
+func truncateEmbeddingText(text string) string {
+	if utf8.RuneCountInString(text) <= MaxStoredEmbeddingTextLength {
+		return text
+	}
+
+	return string([]rune(text)[:MaxStoredEmbeddingTextLength])
+}
+
+// ErrDegenerateEmbedding is returned when trying to store an all-zero
+// or near-zero embedding (see [EmbeddingNearZeroMagnitude]).
+var ErrDegenerateEmbedding = errors.New("degenerate embedding (near-zero magnitude), the input text may be problematic")
+
+// storeEmbedding stores or updates an embedding in the embeddings collection
+//
+// Degenerate (near-zero) embeddings are rejected with [ErrDegenerateEmbedding]
+// since they would silently score 0 in every search.
+func storeEmbedding(app App, embeddingsCollection *Collection, params StoreEmbeddingParams) error {
+	if computeMagnitude(params.Embedding) < EmbeddingNearZeroMagnitude {
+		return ErrDegenerateEmbedding
+	}
+
+	// Check if embedding already exists for this record+field
+	existingRecords, err := app.FindRecordsByFilter(
+		embeddingsCollection.Id,
+		"collection_id = {:collectionId} && record_id = {:recordId} && field_name = {:fieldName}",
+		"",
+		1,
+		0,
+		map[string]any{
+			"collectionId": params.CollectionId,
+			"recordId":     params.RecordId,
+			"fieldName":    params.FieldName,
+		},
+	)
+
+	var record *Record
+	if err == nil && len(existingRecords) > 0 {
+		// Update existing
+		record = existingRecords[0]
+	} else {
+		// Create new
+		record = NewRecord(embeddingsCollection)
+		record.Set("record_id", params.RecordId)
+		record.Set("collection_id", params.CollectionId)
+		record.Set("field_name", params.FieldName)
+	}
+
+	// Store embedding as JSON array or compressed blob (see AIConfig.EmbeddingCompression)
+	embeddingValue, err := encodeEmbedding(params.Embedding, app.Settings().AI.EmbeddingCompression)
+	if err != nil {
+		return err
+	}
+
+	record.Set("embedding", embeddingValue)
+	record.Set("model", params.Model)
+	record.Set("dimensions", params.Dimensions)
+	record.Set(EmbeddingsFieldDeleted, "") // revive if previously tombstoned
+	if embeddingsCollection.Fields.GetByName(EmbeddingsFieldText) != nil {
+		record.Set(EmbeddingsFieldText, params.Text)
+	}
+
+	err = app.Save(record)
+	if err == nil {
+		// Invalidate cache for this collection/field since embeddings changed
+		embeddingCache.Invalidate(embeddingsCacheCollectionKey(embeddingsCollection.Name, params.CollectionId), params.FieldName)
+	}
+	return err
+}
+
+// ErrEmbeddingModelMismatch is returned by the strict model similarity search
+// when the stored embeddings were generated with a different model than the query one.
+var ErrEmbeddingModelMismatch = errors.New("embedding model mismatch")
+
+// findStoredEmbeddingModels returns the sorted distinct models of the
+// active stored embeddings of the specified collection field names.
+//
+// Returns nil if the embeddings collection doesn't exist yet.
+func findStoredEmbeddingModels(app App, embeddingsName string, collectionId string, fieldNames []string) ([]string, error) {
+	embeddingsCollection, err := app.FindCachedCollectionByNameOrId(embeddingsName)
+	if err != nil {
+		return nil, nil
+	}
+
+	names := make([]any, len(fieldNames))
+	for i, name := range fieldNames {
+		names[i] = name
+	}
+
+	query := app.DB().
+		Select("model").
+		Distinct(true).
+		From(embeddingsCollection.Name).
+		Where(dbx.HashExp{"collection_id": collectionId}).
+		AndWhere(dbx.In("field_name", names...)).
+		AndWhere(dbx.NewExp("[[model]] != ''"))
+
+	if embeddingsCollection.Fields.GetByName(EmbeddingsFieldDeleted) != nil {
+		query.AndWhere(dbx.HashExp{EmbeddingsFieldDeleted: ""})
+	}
+
+	var models []string
+	if err := query.OrderBy("model ASC").Column(&models); err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// embeddingModelWarning returns a warning message if the stored embeddings models
+// are not comparable with the query embedding model (or an empty string otherwise).
+//
+// The text queries are embedded with queryModel so every other stored model is a mismatch,
+// while the record queries reuse a stored embedding so only mixed stored models are a mismatch.
+func embeddingModelWarning(queryModel string, storedModels []string, textQuery bool) string {
+	if textQuery {
+		var mismatched []string
+		for _, m := range storedModels {
+			if m != queryModel {
+				mismatched = append(mismatched, strconv.Quote(m))
+			}
+		}
+		if len(mismatched) == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf(
+			"the stored embeddings were generated with model %s but the query embedding uses model %q, regenerate the embeddings to get meaningful results",
+			strings.Join(mismatched, ", "),
+			queryModel,
+		)
+	}
+
+	if len(storedModels) <= 1 {
+		return ""
+	}
+
+	quoted := make([]string, len(storedModels))
+	for i, m := range storedModels {
+		quoted[i] = strconv.Quote(m)
+	}
+
+	return fmt.Sprintf(
+		"the stored embeddings were generated with different models (%s), regenerate the embeddings to get meaningful results",
+		strings.Join(quoted, ", "),
+	)
+}
+
+// FindSimilarRecords finds records similar to the given text or record
+//
+// A warning is added to the response if the stored embeddings were generated
+// with a different model than the query one (see also [FindSimilarRequest.StrictModel]).
+func FindSimilarRecords(app App, req FindSimilarRequest) (*FindSimilarResponse, error) {
+	return FindSimilarRecordsWithContext(context.Background(), app, req)
+}
+
+// FindSimilarRecordsWithContext is the same as [FindSimilarRecords]
+// but the query text embedding request is canceled when ctx is done.
+func FindSimilarRecordsWithContext(ctx context.Context, app App, req FindSimilarRequest) (*FindSimilarResponse, error) {
+	settings := app.Settings()
+
+	if !settings.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	if err := checkAITextLength("text", req.Text, MaxEmbeddingQueryTextLength); err != nil {
+		return nil, err
+	}
+
+	// Resolve collection ID (user might pass name or ID)
+	collection, err := app.FindCollectionByNameOrId(req.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+	collectionId := collection.Id // Use the actual ID for queries
+
+	embeddingsName, err := resolveEmbeddingsCollectionName(req.EmbeddingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine field name based on mode
+	fieldNames, err := resolveSimilarityFieldNames(req.Mode, req.FieldName, req.Fields, req.JSONPath)
+	if err != nil {
+		return nil, err
+	}
+
+	scoreScale := req.ScoreScale
+	if scoreScale == "" {
+		scoreScale = SimilarityScoreScaleRaw
+	}
+	if scoreScale != SimilarityScoreScaleRaw && scoreScale != SimilarityScoreScalePercent {
+		return nil, fmt.Errorf("invalid score scale: %s (must be 'raw' or 'percent')", scoreScale)
+	}
+
+	if maxLimit := settings.AI.SimilarityMaxLimitOrDefault(); req.Limit > maxLimit {
+		return nil, fmt.Errorf("limit must not exceed %d", maxLimit)
+	}
+
+	if req.RecencyHalfLifeDays < 0 {
+		return nil, fmt.Errorf("recencyHalfLifeDays must be a positive number")
+	}
+	recencyWeight := req.RecencyWeight
+	if recencyWeight == 0 {
+		recencyWeight = DefaultRecencyWeight
+	}
+	if recencyWeight < 0 || recencyWeight > 1 {
+		return nil, fmt.Errorf("recencyWeight must be between 0 and 1")
+	}
+
+	if req.PerGroupLimit < 0 || req.PerGroupLimit > settings.AI.SimilarityMaxLimitOrDefault() {
+		return nil, fmt.Errorf("perGroupLimit must be between 1 and %d", settings.AI.SimilarityMaxLimitOrDefault())
+	}
+	if req.GroupBy != "" {
+		field := collection.Fields.GetByName(req.GroupBy)
+		if field == nil {
+			return nil, fmt.Errorf("groupBy field %q not found in collection %s", req.GroupBy, collection.Name)
+		}
+		if mv, ok := field.(MultiValuer); ok && mv.IsMultiple() {
+			return nil, fmt.Errorf("groupBy field %q must be a single value field", req.GroupBy)
+		}
+	}
+
+	// Use the same model as the one of the stored collection embedding config (if any)
+	model := settings.AI.EmbeddingModel
+	if config, err := FindEmbeddingConfig(app, collectionId); err == nil && config.Model != "" {
+		model = config.Model
+	}
+
+	metric := req.Metric
+	if metric == "" {
+		metric = DefaultSimilarityMetric(model)
+	}
+	if metric != SimilarityMetricCosine && metric != SimilarityMetricDot && metric != SimilarityMetricEuclidean {
+		return nil, fmt.Errorf("invalid metric: %s (must be 'cosine', 'dot' or 'euclidean')", metric)
+	}
+
+	// Detect the vectors from different embedding spaces (ex. after an EmbeddingModel change)
+	storedModels, err := findStoredEmbeddingModels(app, embeddingsName, collectionId, fieldNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the stored embedding models: %w", err)
+	}
+	var warnings []string
+	if warning := embeddingModelWarning(model, storedModels, req.Text != ""); warning != "" {
+		if req.StrictModel {
+			return nil, fmt.Errorf("%w: %s", ErrEmbeddingModelMismatch, warning)
+		}
+		warnings = append(warnings, warning)
+	}
+
+	// Get the query embedding(s) for each of the searched field names
+	queryEmbeddings := make(map[string][]float32, len(fieldNames))
+
+	if req.Text != "" {
+		// Generate embedding for the query text (failing fast to keep the search responsive)
+		embeddings, err := callOpenAIEmbeddings(ctx, app, model, []string{preprocessEmbeddingText(req.Text)}, settings.AI.EmbeddingQueryTimeoutDuration())
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+		}
+		if embeddings[0] == nil {
+			return nil, fmt.Errorf("no embedding returned for query text")
+		}
+		for _, fieldName := range fieldNames {
+			queryEmbeddings[fieldName] = embeddings[0]
+		}
+	} else if req.RecordId != "" {
+		// Find existing embedding(s) for the record
+		embeddingsCollection, err := app.FindCollectionByNameOrId(embeddingsName)
+		if err != nil {
+			return nil, fmt.Errorf("embeddings collection not found: %w", err)
+		}
+
+		for _, fieldName := range fieldNames {
+			records, err := app.FindRecordsByFilter(
+				embeddingsCollection.Id,
+				"collection_id = {:collectionId} && record_id = {:recordId} && field_name = {:fieldName}"+activeEmbeddingsFilter(embeddingsCollection),
+				"",
+				1,
+				0,
+				map[string]any{
+					"collectionId": collectionId,
+					"recordId":     req.RecordId,
+					"fieldName":    fieldName,
+				},
+			)
+			if err != nil || len(records) == 0 {
+				continue
+			}
+
+			queryEmbeddings[fieldName], err = getEmbeddingFromRecord(records[0])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse existing embedding: %w", err)
+			}
+		}
+
+		if len(queryEmbeddings) == 0 {
+			return nil, fmt.Errorf("no embedding found for record %s", req.RecordId)
+		}
+	} else {
+		return nil, fmt.Errorf("either text or recordId must be provided")
 	}
 
-	// Sort by index to maintain order
-	sort.Slice(openAIResp.Data, func(i, j int) bool {
-		return openAIResp.Data[i].Index < openAIResp.Data[j].Index
+	// Debug info
+	debug := &SimilarityDebug{
+		CollectionId: collectionId,
+		FieldName:    strings.Join(fieldNames, ","),
+		Metric:       metric,
+	}
+
+	// Resolve the structured prefilter candidates (if any)
+	var candidates map[string]struct{}
+	if req.Prefilter != "" {
+		candidates, err = findSimilarityCandidates(app, collection, req.Prefilter, req.PrefilterParams, req.RecordId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Score the embeddings of each field name and keep the max score per record
+	bestScores := map[string]float32{}
+
+	for _, fieldName := range fieldNames {
+		queryEmbedding, ok := queryEmbeddings[fieldName]
+		if !ok {
+			continue
+		}
+		debug.QueryEmbeddingLen = len(queryEmbedding)
+
+		// Score the embeddings from the cache or incrementally as they are loaded from the database
+		// (large embeddings sets are streamed in chunks instead of being loaded all at once)
+		fieldDebug := &SimilarityDebug{}
+		err := scanEmbeddings(app, embeddingsName, collectionId, fieldName, fieldDebug, func(chunk []CachedEmbedding) error {
+			if candidates != nil {
+				filtered := make([]CachedEmbedding, 0, min(len(candidates), len(chunk)))
+				for _, e := range chunk {
+					if _, ok := candidates[e.RecordId]; ok {
+						filtered = append(filtered, e)
+					}
+				}
+				chunk = filtered
+			}
+
+			for _, result := range scoreEmbeddings(queryEmbedding, chunk, req.RecordId, metric) {
+				debug.ProcessedCount++
+				if best, ok := bestScores[result.RecordId]; !ok || result.Similarity > best {
+					bestScores[result.RecordId] = result.Similarity
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		debug.StoredEmbeddings += fieldDebug.StoredEmbeddings
+		debug.ErrorCount += fieldDebug.ErrorCount
+		debug.Errors = append(debug.Errors, fieldDebug.Errors...)
+		debug.CacheHit = fieldDebug.CacheHit
+		debug.CacheSkipped = debug.CacheSkipped || fieldDebug.CacheSkipped
+	}
+
+	// Blend the similarity scores with the source records recency
+	if req.RecencyHalfLifeDays > 0 {
+		if err := applyRecencyBoost(app, collection, bestScores, req.RecencyHalfLifeDays, recencyWeight, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	debug.CandidatesCount = len(bestScores)
+
+	results := make([]SimilarRecord, 0, len(bestScores))
+	for recordId, similarity := range bestScores {
+		// Drop the results below the min similarity threshold (if any)
+		if req.MinSimilarity != 0 && similarity < req.MinSimilarity {
+			continue
+		}
+
+		results = append(results, SimilarRecord{
+			RecordId:   recordId,
+			Similarity: similarity,
+		})
+	}
+
+	// Sort by similarity (descending)
+	sortSimilarRecords(results)
+
+	// Limit results
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var groups []SimilarRecordsGroup
+	if req.GroupBy != "" {
+		perGroupLimit := req.PerGroupLimit
+		if perGroupLimit == 0 {
+			perGroupLimit = DefaultSimilarityPerGroupLimit
+		}
+
+		groups, err = groupSimilarRecords(app, collection, results, req.GroupBy, limit, perGroupLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		results = results[:0]
+		for _, group := range groups {
+			results = append(results, group.Results...)
+		}
+	} else {
+		if limit > len(results) {
+			limit = len(results)
+		}
+		results = results[:limit]
+	}
+
+	// Rescale the scores for display (the raw ones remain available in the debug info)
+	if scoreScale == SimilarityScoreScalePercent {
+		toPercent := similarityPercentFunc(metric)
+
+		debug.RawSimilarities = make(map[string]float32, len(results))
+		for i := range results {
+			debug.RawSimilarities[results[i].RecordId] = results[i].Similarity
+			results[i].Similarity = toPercent(results[i].Similarity)
+		}
+		for _, group := range groups {
+			for i := range group.Results {
+				group.Results[i].Similarity = toPercent(group.Results[i].Similarity)
+			}
+		}
+	}
+
+	// Load the matched source records
+	var expand map[string]*Record
+	if req.Expand {
+		expand, err = findSimilarRecordsExpand(app, collection, results)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add cache stats to debug info
+	debug.CacheStats = embeddingCache.Info()
+
+	return &FindSimilarResponse{Results: results, Groups: groups, Expand: expand, Debug: debug, Warnings: warnings}, nil
+}
+
+// resolveSimilarityFieldNames returns the embeddings field names searched
+// by a similarity search with the specified mode (default to [EmbeddingModeField]).
+//
+// The combined mode searches both the field-level and the record-level embeddings.
+func resolveSimilarityFieldNames(mode EmbeddingMode, fieldName string, fields []string, jsonPath string) ([]string, error) {
+	if mode == "" {
+		mode = EmbeddingModeField
+	}
+
+	var fieldNames []string
+	if mode == EmbeddingModeCombined {
+		if fieldName == "" {
+			return nil, fmt.Errorf("fieldName is required for combined search mode")
+		}
+		fieldNames = []string{fieldName, RecordLevelFieldName}
+	} else {
+		resolved, err := resolveEmbeddingFieldName(mode, fieldName, fields)
+		if err != nil {
+			return nil, err
+		}
+		fieldNames = []string{resolved}
+	}
+
+	if jsonPath != "" {
+		if mode != EmbeddingModeField && mode != EmbeddingModeCombined {
+			return nil, fmt.Errorf("jsonPath is supported only for field-level and combined modes")
+		}
+		fieldNames[0] = JSONPathFieldName(fieldName, jsonPath)
+	}
+
+	return fieldNames, nil
+}
+
+// similarityPercentFunc returns the 0..100 percentage mapping function of the metric scores.
+func similarityPercentFunc(metric SimilarityMetric) func(float32) float32 {
+	if metric == SimilarityMetricEuclidean {
+		return euclideanSimilarityToPercent
+	}
+
+	return similarityToPercent
+}
+
+// findSimilarRecordsExpand returns the source records of the results keyed by their id.
+func findSimilarRecordsExpand(app App, collection *Collection, results []SimilarRecord) (map[string]*Record, error) {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.RecordId
+	}
+
+	expand := make(map[string]*Record, len(ids))
+
+	// fetch in chunks to avoid exceeding the max query parameters limit
+	for _, chunk := range batchTexts(ids, 500) {
+		records, err := app.FindRecordsByIds(collection.Id, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the matched source records: %w", err)
+		}
+
+		for _, record := range records {
+			expand[record.Id] = record
+		}
+	}
+
+	return expand, nil
+}
+
+// MaxGlobalSimilarityTargets is the max number of targets of a single global similarity search.
+const MaxGlobalSimilarityTargets = 20
+
+// SimilarityTarget represents a single collection field searched by [FindSimilarGlobal].
+type SimilarityTarget struct {
+	CollectionId string `json:"collectionId"`
+	FieldName    string `json:"fieldName"` // Field name or [RecordLevelFieldName]
+}
+
+// FindSimilarGlobalRequest represents a request to find similar records across multiple collections.
+type FindSimilarGlobalRequest struct {
+	Text    string             `json:"text"`
+	Targets []SimilarityTarget `json:"targets"`
+	Limit   int                `json:"limit"`
+
+	// ScoreScale is the scale of the returned similarity scores ("raw" by default or "percent")
+	ScoreScale SimilarityScoreScale `json:"scoreScale,omitempty"`
+
+	// Metric is the vectors comparison metric ("cosine", "dot" or "euclidean") of all targets.
+	//
+	// Default to the [DefaultSimilarityMetric] of each target embedding model.
+	Metric SimilarityMetric `json:"metric,omitempty"`
+
+	// EmbeddingsCollection is an optional custom embeddings collection name
+	// to search in (default to [EmbeddingsCollectionName]).
+	EmbeddingsCollection string `json:"embeddingsCollection,omitempty"`
+}
+
+// GlobalSimilarRecord represents a similar record tagged with its source collection.
+type GlobalSimilarRecord struct {
+	CollectionId   string           `json:"collectionId"`
+	CollectionName string           `json:"collectionName"`
+	FieldName      string           `json:"fieldName"`
+	RecordId       string           `json:"recordId"`
+	Similarity     float32          `json:"similarity"`
+	Metric         SimilarityMetric `json:"metric"`
+}
+
+// FindSimilarGlobalResponse represents the response from a global similarity search.
+type FindSimilarGlobalResponse struct {
+	Results []GlobalSimilarRecord `json:"results"`
+}
+
+// FindSimilarGlobal finds the records similar to the query text across
+// multiple collection fields and returns a single merged ranked result set.
+//
+// The query text is embedded only once (per distinct embedding model
+// in case the targets have stored embedding configs with different models).
+//
+// Each target is scored with its own resolved metric and only its
+// top limit records are kept while its embeddings are scanned.
+func FindSimilarGlobal(app App, req FindSimilarGlobalRequest) (*FindSimilarGlobalResponse, error) {
+	return FindSimilarGlobalWithContext(context.Background(), app, req)
+}
+
+// FindSimilarGlobalWithContext is the same as [FindSimilarGlobal]
+// but the query text embedding requests are canceled when ctx is done.
+func FindSimilarGlobalWithContext(ctx context.Context, app App, req FindSimilarGlobalRequest) (*FindSimilarGlobalResponse, error) {
+	settings := app.Settings()
+
+	if !settings.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	if req.Text == "" {
+		return nil, fmt.Errorf("text must be provided")
+	}
+
+	if err := checkAITextLength("text", req.Text, MaxEmbeddingQueryTextLength); err != nil {
+		return nil, err
+	}
+
+	if len(req.Targets) == 0 {
+		return nil, fmt.Errorf("at least one target must be provided")
+	}
+	if len(req.Targets) > MaxGlobalSimilarityTargets {
+		return nil, fmt.Errorf("too many targets (max %d)", MaxGlobalSimilarityTargets)
+	}
+
+	if maxLimit := settings.AI.SimilarityMaxLimitOrDefault(); req.Limit > maxLimit {
+		return nil, fmt.Errorf("limit must not exceed %d", maxLimit)
+	}
+
+	scoreScale := req.ScoreScale
+	if scoreScale == "" {
+		scoreScale = SimilarityScoreScaleRaw
+	}
+	if scoreScale != SimilarityScoreScaleRaw && scoreScale != SimilarityScoreScalePercent {
+		return nil, fmt.Errorf("invalid score scale: %s (must be 'raw' or 'percent')", scoreScale)
+	}
+
+	if req.Metric != "" && req.Metric != SimilarityMetricCosine && req.Metric != SimilarityMetricDot && req.Metric != SimilarityMetricEuclidean {
+		return nil, fmt.Errorf("invalid metric: %s (must be 'cosine', 'dot' or 'euclidean')", req.Metric)
+	}
+
+	embeddingsName, err := resolveEmbeddingsCollectionName(req.EmbeddingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	queryEmbeddings := map[string][]float32{} // per model
+	results := []GlobalSimilarRecord{}
+
+	for i, target := range req.Targets {
+		if target.FieldName == "" {
+			return nil, fmt.Errorf("targets[%d]: fieldName is required", i)
+		}
+
+		collection, err := app.FindCachedCollectionByNameOrId(target.CollectionId)
+		if err != nil {
+			return nil, fmt.Errorf("targets[%d]: collection not found: %w", i, err)
+		}
+
+		model := settings.AI.EmbeddingModel
+		if config, err := FindEmbeddingConfig(app, collection.Id); err == nil && config.Model != "" {
+			model = config.Model
+		}
+
+		queryEmbedding, ok := queryEmbeddings[model]
+		if !ok {
+			embeddings, err := callOpenAIEmbeddings(ctx, app, model, []string{preprocessEmbeddingText(req.Text)}, settings.AI.EmbeddingQueryTimeoutDuration())
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+			}
+			if embeddings[0] == nil {
+				return nil, fmt.Errorf("no embedding returned for query text")
+			}
+			queryEmbedding = embeddings[0]
+			queryEmbeddings[model] = queryEmbedding
+		}
+
+		metric := req.Metric
+		if metric == "" {
+			metric = DefaultSimilarityMetric(model)
+		}
+
+		// only the top limit records of each target could be part of the merged results
+		top := newSimilarTopK(limit)
+		err = scanEmbeddings(app, embeddingsName, collection.Id, target.FieldName, nil, func(chunk []CachedEmbedding) error {
+			if err := checkAIContext(ctx); err != nil {
+				return err
+			}
+
+			for _, result := range scoreEmbeddings(queryEmbedding, chunk, "", metric) {
+				top.Add(result)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range top.Results() {
+			results = append(results, GlobalSimilarRecord{
+				CollectionId:   collection.Id,
+				CollectionName: collection.Name,
+				FieldName:      target.FieldName,
+				RecordId:       result.RecordId,
+				Similarity:     result.Similarity,
+				Metric:         metric,
+			})
+		}
+	}
+
+	// Sort by similarity (descending) with deterministic ties order
+	slices.SortStableFunc(results, func(a, b GlobalSimilarRecord) int {
+		if a.Similarity != b.Similarity {
+			if a.Similarity > b.Similarity {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(a.CollectionName, b.CollectionName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RecordId, b.RecordId)
 	})
 
-	embeddings := make([][]float32, len(openAIResp.Data))
-	for i, data := range openAIResp.Data {
-		embeddings[i] = data.Embedding
+	if limit < len(results) {
+		results = results[:limit]
 	}
 
-	return embeddings, nil
-}
+	if scoreScale == SimilarityScoreScalePercent {
+		for i := range results {
+			results[i].Similarity = similarityPercentFunc(results[i].Metric)(results[i].Similarity)
+		}
+	}
 
-// StoreEmbeddingParams contains parameters for storing an embedding
-type StoreEmbeddingParams struct {
-	RecordId     string
-	CollectionId string
-	FieldName    string
-	Embedding    []float32
-	Model        string
-	Dimensions   int
+	return &FindSimilarGlobalResponse{Results: results}, nil
 }
 
-// storeEmbedding stores or updates an embedding in the embeddings collection
-func storeEmbedding(app App, embeddingsCollection *Collection, params StoreEmbeddingParams) error {
-	// Check if embedding already exists for this record+field
-	existingRecords, err := app.FindRecordsByFilter(
-		embeddingsCollection.Id,
-		"record_id = {:recordId} && field_name = {:fieldName}",
-		"",
-		1,
-		0,
-		map[string]any{
-			"recordId":  params.RecordId,
-			"fieldName": params.FieldName,
-		},
-	)
+// DefaultSimilarityPerGroupLimit is the default max number of results
+// of a single group of a grouped similarity search.
+const DefaultSimilarityPerGroupLimit = 3
 
-	var record *Record
-	if err == nil && len(existingRecords) > 0 {
-		// Update existing
-		record = existingRecords[0]
-	} else {
-		// Create new
-		record = NewRecord(embeddingsCollection)
-		record.Set("record_id", params.RecordId)
-		record.Set("collection_id", params.CollectionId)
-		record.Set("field_name", params.FieldName)
+// groupSimilarRecords buckets the sorted results by the groupBy field value of their source records
+// and returns the first limit groups (ordered by their best match) with up to perGroupLimit results each.
+//
+// The results without an existing source record (ex. orphaned embeddings) are skipped.
+func groupSimilarRecords(app App, collection *Collection, results []SimilarRecord, groupBy string, limit int, perGroupLimit int) ([]SimilarRecordsGroup, error) {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.RecordId
 	}
 
-	// Store embedding as JSON array (convert float32 to float64 for JSON compatibility)
-	embeddingJSON := make([]float64, len(params.Embedding))
-	for i, v := range params.Embedding {
-		embeddingJSON[i] = float64(v)
+	values := make(map[string]string, len(ids))
+
+	// fetch in chunks to avoid exceeding the max query parameters limit
+	for _, chunk := range batchTexts(ids, 500) {
+		records, err := app.FindRecordsByIds(collection.Id, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the source records group values: %w", err)
+		}
+
+		for _, record := range records {
+			values[record.Id] = record.GetString(groupBy)
+		}
 	}
 
-	record.Set("embedding", embeddingJSON)
-	record.Set("model", params.Model)
-	record.Set("dimensions", params.Dimensions)
+	groups := []SimilarRecordsGroup{}
+	positions := map[string]int{}
 
-	err = app.Save(record)
-	if err == nil {
-		// Invalidate cache for this collection/field since embeddings changed
-		embeddingCache.Invalidate(params.CollectionId, params.FieldName)
+	for _, r := range results {
+		value, ok := values[r.RecordId]
+		if !ok {
+			continue
+		}
+
+		pos, ok := positions[value]
+		if !ok {
+			if len(groups) >= limit {
+				continue
+			}
+			pos = len(groups)
+			positions[value] = pos
+			groups = append(groups, SimilarRecordsGroup{Value: value})
+		}
+
+		if len(groups[pos].Results) < perGroupLimit {
+			groups[pos].Results = append(groups[pos].Results, r)
+		}
 	}
-	return err
-}
 
-// FindSimilarRecords finds records similar to the given text or record
-func FindSimilarRecords(app App, req FindSimilarRequest) (*FindSimilarResponse, error) {
-	settings := app.Settings()
+	return groups, nil
+}
 
-	if !settings.AI.Enabled {
-		return nil, fmt.Errorf("AI features are not enabled")
+// DefaultRecencyWeight is the default weight of the recency time-decay factor
+// in the final similarity score when the recency boost is enabled.
+const DefaultRecencyWeight = 0.2
+
+// findSimilarityCandidates returns the ids of the source collection records matching the prefilter expression.
+//
+// If queryRecordId is set, the query record field values are available as filter placeholders
+// (the explicit prefilterParams with the same name take precedence).
+func findSimilarityCandidates(app App, collection *Collection, prefilter string, prefilterParams map[string]any, queryRecordId string) (map[string]struct{}, error) {
+	params := dbx.Params{}
+	if queryRecordId != "" {
+		queryRecord, err := app.FindRecordById(collection, queryRecordId)
+		if err != nil {
+			return nil, fmt.Errorf("query record not found: %w", err)
+		}
+		for _, field := range collection.Fields {
+			params[field.GetName()] = queryRecord.Get(field.GetName())
+		}
+	}
+	for name, value := range prefilterParams {
+		params[name] = value
 	}
 
-	// Resolve collection ID (user might pass name or ID)
-	collection, err := app.FindCollectionByNameOrId(req.CollectionId)
+	resolver := NewRecordFieldResolver(app, collection, nil, true)
+
+	expr, err := search.FilterData(prefilter).BuildExpr(resolver, params)
 	if err != nil {
-		return nil, fmt.Errorf("collection not found: %w", err)
+		return nil, fmt.Errorf("invalid prefilter expression: %w", err)
 	}
-	collectionId := collection.Id // Use the actual ID for queries
 
-	// Determine field name based on mode
-	mode := req.Mode
-	if mode == "" {
-		mode = EmbeddingModeField
-	}
+	// select only the ids to keep the prefilter cheap
+	q := app.RecordQuery(collection).
+		Distinct(true).
+		Select(inflector.Columnify(collection.Name) + ".id").
+		AndWhere(expr)
 
-	fieldName := req.FieldName
-	if mode == EmbeddingModeRecord {
-		fieldName = RecordLevelFieldName
-	} else if fieldName == "" {
-		return nil, fmt.Errorf("fieldName is required for field-level search mode")
+	if err := resolver.UpdateQuery(q); err != nil {
+		return nil, err
 	}
 
-	// Get the query embedding
-	var queryEmbedding []float32
+	var ids []string
+	if err := q.Column(&ids); err != nil {
+		return nil, fmt.Errorf("failed to fetch the prefilter candidates: %w", err)
+	}
 
-	if req.Text != "" {
-		// Generate embedding for the query text
-		embeddings, err := callOpenAIEmbeddings(app, []string{req.Text})
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate query embedding: %w", err)
-		}
-		if len(embeddings) == 0 {
-			return nil, fmt.Errorf("no embedding returned for query text")
-		}
-		queryEmbedding = embeddings[0]
-	} else if req.RecordId != "" {
-		// Find existing embedding for the record
-		embeddingsCollection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
-		if err != nil {
-			return nil, fmt.Errorf("embeddings collection not found: %w", err)
-		}
+	candidates := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		candidates[id] = struct{}{}
+	}
 
-		records, err := app.FindRecordsByFilter(
-			embeddingsCollection.Id,
-			"record_id = {:recordId} && field_name = {:fieldName}",
-			"",
-			1,
-			0,
-			map[string]any{
-				"recordId":  req.RecordId,
-				"fieldName": fieldName,
-			},
-		)
-		if err != nil || len(records) == 0 {
-			return nil, fmt.Errorf("no embedding found for record %s", req.RecordId)
-		}
+	return candidates, nil
+}
 
-		queryEmbedding, err = getEmbeddingFromRecord(records[0])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse existing embedding: %w", err)
+// applyRecencyBoost blends (in place) the records similarity scores with
+// an exponential time-decay factor of the source records age:
+//
+//	score * (1 - weight + weight * 0.5^(ageDays/halfLifeDays))
+//
+// The age is based on the "updated" autodate field of the source records
+// (or "created" if missing). Records without timestamp receive no boost.
+func applyRecencyBoost(app App, collection *Collection, scores map[string]float32, halfLifeDays float64, weight float64, now time.Time) error {
+	var dateField string
+	for _, name := range []string{"updated", "created"} {
+		if _, ok := collection.Fields.GetByName(name).(*AutodateField); ok {
+			dateField = name
+			break
 		}
-	} else {
-		return nil, fmt.Errorf("either text or recordId must be provided")
+	}
+	if dateField == "" {
+		return fmt.Errorf("the recency boost requires an updated or created autodate field in collection %s", collection.Name)
 	}
 
-	// Try to get embeddings from cache first
-	cachedEmbeddings, cacheHit := embeddingCache.Get(collectionId, fieldName)
-
-	// Debug info
-	debug := &SimilarityDebug{
-		CollectionId:      collectionId,
-		FieldName:         fieldName,
-		QueryEmbeddingLen: len(queryEmbedding),
-		ProcessedCount:    0,
-		ErrorCount:        0,
-		CacheHit:          cacheHit,
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
 	}
 
-	if !cacheHit {
-		// Load embeddings from database and cache them
-		embeddingsCollection, err := app.FindCollectionByNameOrId(EmbeddingsCollectionName)
-		if err != nil {
-			return nil, fmt.Errorf("embeddings collection not found: %w", err)
-		}
+	decays := make(map[string]float64, len(ids))
 
-		allEmbeddings, err := app.FindRecordsByFilter(
-			embeddingsCollection.Id,
-			"collection_id = {:collectionId} && field_name = {:fieldName}",
-			"",
-			0, // Get all
-			0,
-			map[string]any{
-				"collectionId": collectionId,
-				"fieldName":    fieldName,
-			},
-		)
+	// fetch in chunks to avoid exceeding the max query parameters limit
+	for _, chunk := range batchTexts(ids, 500) {
+		records, err := app.FindRecordsByIds(collection.Id, chunk)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch embeddings: %w", err)
+			return fmt.Errorf("failed to load the source records timestamps: %w", err)
 		}
 
-		debug.StoredEmbeddings = len(allEmbeddings)
-
-		// Parse and cache all embeddings with pre-computed magnitudes
-		cachedEmbeddings = make([]CachedEmbedding, 0, len(allEmbeddings))
-		for _, embRecord := range allEmbeddings {
-			recordId := embRecord.GetString("record_id")
-			embedding, err := getEmbeddingFromRecord(embRecord)
-			if err != nil {
-				debug.ErrorCount++
-				if len(debug.Errors) < 3 {
-					debug.Errors = append(debug.Errors, fmt.Sprintf("record %s: %v", recordId, err))
-				}
+		for _, record := range records {
+			date := record.GetDateTime(dateField)
+			if date.IsZero() {
 				continue
 			}
-			cachedEmbeddings = append(cachedEmbeddings, CachedEmbedding{
-				RecordId:  recordId,
-				Embedding: embedding,
-				Magnitude: computeMagnitude(embedding),
-			})
-		}
 
-		// Store in cache for future queries (skipped if too large)
-		cached := embeddingCache.Set(collectionId, fieldName, cachedEmbeddings)
-		if !cached {
-			debug.CacheSkipped = true
+			ageDays := max(0, now.Sub(date.Time()).Hours()/24)
+			decays[record.Id] = math.Pow(0.5, ageDays/halfLifeDays)
 		}
-	} else {
-		debug.StoredEmbeddings = len(cachedEmbeddings)
 	}
 
+	for id, score := range scores {
+		scores[id] = score * float32(1-weight+weight*decays[id])
+	}
+
+	return nil
+}
+
+// scoreEmbeddings computes in parallel the metric similarity between the
+// query embedding and each of the provided embeddings (excluding the excludeRecordId one).
+func scoreEmbeddings(queryEmbedding []float32, embeddings []CachedEmbedding, excludeRecordId string, metric SimilarityMetric) []SimilarRecord {
 	// Pre-compute query magnitude for optimized similarity calculation
 	queryMagnitude := computeMagnitude(queryEmbedding)
 
 	// Use parallel computation for similarity scores
 	numWorkers := runtime.NumCPU()
-	if numWorkers > len(cachedEmbeddings) {
-		numWorkers = len(cachedEmbeddings)
+	if numWorkers > len(embeddings) {
+		numWorkers = len(embeddings)
 	}
 	if numWorkers < 1 {
 		numWorkers = 1
 	}
 
-	// Channel for results
-	type similarityResult struct {
-		recordId   string
-		similarity float32
-	}
-	resultsChan := make(chan similarityResult, len(cachedEmbeddings))
+	resultsChan := make(chan SimilarRecord, len(embeddings))
 
 	// Split work across goroutines
 	var wg sync.WaitGroup
-	chunkSize := (len(cachedEmbeddings) + numWorkers - 1) / numWorkers
+	chunkSize := (len(embeddings) + numWorkers - 1) / numWorkers
 
 	for i := 0; i < numWorkers; i++ {
 		start := i * chunkSize
 		end := start + chunkSize
-		if end > len(cachedEmbeddings) {
-			end = len(cachedEmbeddings)
+		if end > len(embeddings) {
+			end = len(embeddings)
 		}
 		if start >= end {
 			continue
 		}
 
 		wg.Add(1)
-		go func(embeddings []CachedEmbedding) {
+		go func(chunk []CachedEmbedding) {
 			defer wg.Done()
-			for _, cached := range embeddings {
+			for _, cached := range chunk {
 				// Skip the query record itself
-				if cached.RecordId == req.RecordId {
+				if cached.RecordId == excludeRecordId {
 					continue
 				}
-				// Optimized cosine similarity using pre-computed magnitudes
-				similarity := cosineSimilarityOptimized(queryEmbedding, queryMagnitude, cached.Embedding, cached.Magnitude)
-				resultsChan <- similarityResult{cached.RecordId, similarity}
+				// Optimized similarity using the pre-computed magnitudes
+				similarity := cached.similarity(queryEmbedding, queryMagnitude, metric)
+				resultsChan <- SimilarRecord{RecordId: cached.RecordId, Similarity: similarity}
 			}
-		}(cachedEmbeddings[start:end])
+		}(embeddings[start:end])
 	}
 
 	// Close channel when all workers done
@@ -928,34 +2823,174 @@ func FindSimilarRecords(app App, req FindSimilarRequest) (*FindSimilarResponse,
 	}()
 
 	// Collect results
-	results := make([]SimilarRecord, 0, len(cachedEmbeddings))
+	results := make([]SimilarRecord, 0, len(embeddings))
 	for result := range resultsChan {
-		debug.ProcessedCount++
-		results = append(results, SimilarRecord{
-			RecordId:   result.recordId,
-			Similarity: result.similarity,
-		})
+		results = append(results, result)
 	}
 
-	// Sort by similarity (descending)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Similarity > results[j].Similarity
+	return results
+}
+
+// sortSimilarRecords sorts the records by their similarity in descending order.
+//
+// Records with equal similarity are sorted by their id (ascending)
+// so that the order of the ties is deterministic between calls.
+func sortSimilarRecords(records []SimilarRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Similarity != records[j].Similarity {
+			return records[i].Similarity > records[j].Similarity
+		}
+		return records[i].RecordId < records[j].RecordId
 	})
+}
 
-	// Limit results
-	limit := req.Limit
-	if limit <= 0 {
-		limit = 10
+// loadCachedEmbeddings returns the parsed embeddings of the specified collection/field.
+//
+// The embeddings are returned from the cache if available, otherwise they
+// are loaded from the database and cached for future queries.
+//
+// The embeddings are loaded from the embeddingsName embeddings collection
+// (see [EmbeddingsCollectionName]).
+//
+// If debug is not nil, it is populated with the cache and load details.
+func loadCachedEmbeddings(app App, embeddingsName, collectionId, fieldName string, debug *SimilarityDebug) ([]CachedEmbedding, error) {
+	var result []CachedEmbedding
+
+	err := scanEmbeddings(app, embeddingsName, collectionId, fieldName, debug, func(chunk []CachedEmbedding) error {
+		if result == nil {
+			result = chunk
+		} else {
+			result = append(result, chunk...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// scanEmbeddings calls fn with the parsed embeddings of the specified collection/field.
+//
+// The embeddings are passed in a single chunk if they are cached or small enough
+// to be cached (in which case they are also stored in the cache for future queries).
+//
+// Embeddings sets larger than the cache per entry limit are not cached and instead
+// they are paged from the database and passed to fn in consecutive chunks,
+// so that the memory usage remains bounded regardless of the set size.
+//
+// If debug is not nil, it is populated with the cache and load details.
+func scanEmbeddings(app App, embeddingsName, collectionId, fieldName string, debug *SimilarityDebug, fn func(chunk []CachedEmbedding) error) error {
+	if debug == nil {
+		debug = &SimilarityDebug{}
 	}
-	if limit > len(results) {
-		limit = len(results)
+
+	cacheCollectionKey := embeddingsCacheCollectionKey(embeddingsName, collectionId)
+
+	// Try to get embeddings from cache first
+	cachedEmbeddings, cacheHit := embeddingCache.Get(cacheCollectionKey, fieldName)
+	debug.CacheHit = cacheHit
+
+	if cacheHit {
+		debug.StoredEmbeddings = len(cachedEmbeddings)
+		return fn(cachedEmbeddings)
 	}
-	results = results[:limit]
 
-	// Add cache stats to debug info
-	debug.CacheStats = embeddingCache.Info()
+	// Load embeddings from database (paged by id to avoid a single huge result set)
+	embeddingsCollection, err := app.FindCollectionByNameOrId(embeddingsName)
+	if err != nil {
+		return fmt.Errorf("embeddings collection not found: %w", err)
+	}
+
+	filter := "collection_id = {:collectionId} && field_name = {:fieldName} && id > {:lastId}" + activeEmbeddingsFilter(embeddingsCollection)
+
+	pageSize := max(1, embeddingsLoadPageSize)
+
+	quantize := app.Settings().AI.EmbeddingCacheQuantization
+
+	// the embeddings are accumulated for caching until they exceed the per entry limit
+	streaming := false
+	cachedEmbeddings = []CachedEmbedding{}
+
+	var lastId string
+	for {
+		page, err := app.FindRecordsByFilter(
+			embeddingsCollection.Id,
+			filter,
+			"id",
+			pageSize,
+			0,
+			map[string]any{
+				"collectionId": collectionId,
+				"fieldName":    fieldName,
+				"lastId":       lastId,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to fetch embeddings: %w", err)
+		}
+
+		debug.StoredEmbeddings += len(page)
+
+		// Parse the embeddings with pre-computed magnitudes
+		chunk := make([]CachedEmbedding, 0, len(page))
+		for _, embRecord := range page {
+			recordId := embRecord.GetString("record_id")
+			embedding, err := getEmbeddingFromRecord(embRecord)
+			if err != nil {
+				debug.ErrorCount++
+				if len(debug.Errors) < 3 {
+					debug.Errors = append(debug.Errors, fmt.Sprintf("record %s: %v", recordId, err))
+				}
+				continue
+			}
+			chunk = append(chunk, CachedEmbedding{
+				RecordId:  recordId,
+				Embedding: embedding,
+				Magnitude: computeMagnitude(embedding),
+			})
+		}
+
+		if quantize {
+			quantizeCachedEmbeddings(chunk)
+		}
+
+		if streaming {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+		} else {
+			cachedEmbeddings = append(cachedEmbeddings, chunk...)
+
+			// too large to cache -> flush the accumulated embeddings and continue streaming
+			if len(cachedEmbeddings) > embeddingCacheMaxPerEntry {
+				streaming = true
+				debug.CacheSkipped = true
+				if err := fn(cachedEmbeddings); err != nil {
+					return err
+				}
+				cachedEmbeddings = nil
+			}
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+		lastId = page[len(page)-1].Id
+	}
+
+	if streaming {
+		return nil
+	}
 
-	return &FindSimilarResponse{Results: results, Debug: debug}, nil
+	// Store in cache for future queries (skipped if too large)
+	cached := embeddingCache.Set(cacheCollectionKey, fieldName, cachedEmbeddings)
+	if !cached {
+		debug.CacheSkipped = true
+	}
+
+	return fn(cachedEmbeddings)
 }
 
 // cosineSimilarity calculates the cosine similarity between two vectors
@@ -978,6 +3013,21 @@ func cosineSimilarity(a, b []float32) float32 {
 	return dot / (float32(math.Sqrt(float64(normA))) * float32(math.Sqrt(float64(normB))))
 }
 
+// similarityToPercent maps a cosine similarity score (-1..1) to a 0..100 percentage
+func similarityToPercent(similarity float32) float32 {
+	percent := (similarity + 1) / 2 * 100
+
+	// guard against floating point drifts outside of the cosine range
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+
+	return percent
+}
+
 // cosineSimilarityOptimized calculates cosine similarity using pre-computed magnitudes
 // This avoids recomputing magnitudes for cached embeddings on every query
 func cosineSimilarityOptimized(a []float32, magA float32, b []float32, magB float32) float32 {
@@ -994,6 +3044,65 @@ func cosineSimilarityOptimized(a []float32, magA float32, b []float32, magB floa
 	return dot / (magA * magB)
 }
 
+// euclideanDistanceOptimized calculates the L2 distance of two vectors
+// using their pre-computed magnitudes (|a-b|² = |a|² + |b|² - 2a·b).
+//
+// Returns +Inf for vectors with different dimensions.
+func euclideanDistanceOptimized(a []float32, magA float32, b []float32, magB float32) float32 {
+	if len(a) != len(b) {
+		return float32(math.Inf(1))
+	}
+
+	return euclideanDistanceFromDot(dotProduct(a, b), magA, magB)
+}
+
+// euclideanDistanceFromDot calculates the L2 distance of two vectors
+// from their dot product and magnitudes.
+func euclideanDistanceFromDot(dot float32, magA float32, magB float32) float32 {
+	squared := magA*magA + magB*magB - 2*dot
+
+	// guard against floating point drifts for (almost) identical vectors
+	if squared <= 0 {
+		return 0
+	}
+
+	return float32(math.Sqrt(float64(squared)))
+}
+
+// euclideanDistanceToSimilarity maps a Euclidean distance (0..+Inf)
+// to a 0..1 similarity score (1 for identical vectors).
+func euclideanDistanceToSimilarity(distance float32) float32 {
+	return 1 / (1 + distance)
+}
+
+// euclideanSimilarityToPercent maps a Euclidean similarity score (0..1) to a 0..100 percentage
+func euclideanSimilarityToPercent(similarity float32) float32 {
+	percent := similarity * 100
+
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+
+	return percent
+}
+
+// dotProduct calculates the dot product of two vectors
+func dotProduct(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+
+	return dot
+}
+
 // getEmbeddingFromRecord extracts the embedding vector from a record's JSON field
 func getEmbeddingFromRecord(record *Record) ([]float32, error) {
 	raw := record.Get("embedding")
@@ -1004,6 +3113,15 @@ func getEmbeddingFromRecord(record *Record) ([]float32, error) {
 	// Handle different possible types from JSON unmarshaling
 	switch v := raw.(type) {
 	case types.JSONRaw:
+		// compressed embeddings are stored as JSON string
+		if len(v) > 0 && v[0] == '"' {
+			var str string
+			if err := json.Unmarshal(v, &str); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal JSONRaw: %w", err)
+			}
+			return decodeCompressedEmbedding(str)
+		}
+
 		// PocketBase stores JSON fields as types.JSONRaw (raw JSON bytes)
 		var floats []float64
 		if err := json.Unmarshal(v, &floats); err != nil {
@@ -1038,6 +3156,10 @@ func getEmbeddingFromRecord(record *Record) ([]float32, error) {
 	case []float32:
 		return v, nil
 	case string:
+		if strings.HasPrefix(v, EmbeddingGzipPrefix) {
+			return decodeCompressedEmbedding(v)
+		}
+
 		// JSON field might be stored as string - try to unmarshal
 		var floats []float64
 		if err := json.Unmarshal([]byte(v), &floats); err != nil {
@@ -1078,6 +3200,21 @@ func IsFieldEmbeddable(field Field) bool {
 	}
 }
 
+// IsAppFieldEmbeddable checks if a field is embeddable in the context of the app settings.
+//
+// When the AutoEmbedTextFields setting is enabled all text and editor fields
+// are embeddable, otherwise it fallbacks to the explicit field flag (see [IsFieldEmbeddable]).
+func IsAppFieldEmbeddable(app App, field Field) bool {
+	if app.Settings().AI.AutoEmbedTextFields {
+		switch field.(type) {
+		case *TextField, *EditorField:
+			return true
+		}
+	}
+
+	return IsFieldEmbeddable(field)
+}
+
 // GetEmbeddableFields returns all embeddable fields from a collection (text and editor)
 func GetEmbeddableFields(collection *Collection) []EmbeddableField {
 	var fields []EmbeddableField
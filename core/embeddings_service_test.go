@@ -0,0 +1,3302 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestBatchTexts(t *testing.T) {
+	t.Parallel()
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text%d", i)
+	}
+
+	scenarios := []struct {
+		name          string
+		texts         []string
+		batchSize     int
+		expectedSizes []int
+	}{
+		{"no texts", nil, 3, nil},
+		{"zero batch size (default)", texts, 0, []int{10}},
+		{"negative batch size (default)", texts, -1, []int{10}},
+		{"larger than the max batch size (default)", texts, core.MaxTextsPerBatch + 1, []int{10}},
+		{"batch size larger than the texts", texts, 100, []int{10}},
+		{"batch size equal to the texts", texts, 10, []int{10}},
+		{"small batch size", texts, 3, []int{3, 3, 3, 1}},
+		{"single item batches", texts, 1, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			batches := core.BatchTexts(s.texts, s.batchSize)
+
+			if len(batches) != len(s.expectedSizes) {
+				t.Fatalf("Expected %d batches, got %d", len(s.expectedSizes), len(batches))
+			}
+
+			var merged []string
+			for i, b := range batches {
+				if len(b) != s.expectedSizes[i] {
+					t.Fatalf("Expected batch %d to have %d items, got %d", i, s.expectedSizes[i], len(b))
+				}
+				merged = append(merged, b...)
+			}
+
+			// ensure that the original order is preserved
+			for i, text := range merged {
+				if text != s.texts[i] {
+					t.Fatalf("Expected item %d to be %q, got %q", i, s.texts[i], text)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateEmbeddingTokens(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("a", 4000), 1000},
+		{"ąćę", 2}, // 6 bytes
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.text, func(t *testing.T) {
+			if v := core.EstimateEmbeddingTokens(s.text); v != s.expected {
+				t.Fatalf("Expected %d tokens, got %d", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestSimilarityToPercent(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		similarity float32
+		expected   float32
+	}{
+		{-1, 0},
+		{-0.5, 25},
+		{0, 50},
+		{0.5, 75},
+		{0.9, 95},
+		{1, 100},
+		// floating point drifts
+		{-1.0001, 0},
+		{1.0001, 100},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%v", s.similarity), func(t *testing.T) {
+			result := core.SimilarityToPercent(s.similarity)
+
+			if math.Abs(float64(result-s.expected)) > 0.0001 {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestStripHTMLWithLimits(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		html     string
+		maxSize  int
+		maxTags  int
+		expected string
+	}{
+		{"empty", "", 0, 0, ""},
+		{"plain text", "hello  world", 0, 0, "hello world"},
+		{"tags", "<p>hello <b>world</b></p>", 0, 0, "hello world"},
+		{"unclosed tag", "<p>hello <b", 0, 0, "hello <b"},
+		{"size limit", "<p>hello</p><p>world</p>", 12, 0, "hello"},
+		{"size limit with multi-byte char", "<p>абв</p>", 8, 0, "аб"},
+		{"tags limit", "<p>hello</p><p>world</p>", 0, 2, "hello"},
+		{"tags limit with trailing text", "<p>hello</p> world <p>!</p>", 0, 2, "hello world"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := core.StripHTMLWithLimits(s.html, s.maxSize, s.maxTags)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestStripHTMLWithLimitsLargeInput(t *testing.T) {
+	t.Parallel()
+
+	// ~10MB of deeply nested tags
+	html := strings.Repeat("<div>a", 1_000_000) + strings.Repeat("</div>", 1_000_000)
+
+	start := time.Now()
+	result := core.StripHTMLWithLimits(html, 600, 50)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Expected bounded execution, took %v", elapsed)
+	}
+
+	expected := strings.TrimSpace(strings.Repeat(" a", 50))
+	if result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+
+	// default limits
+	start = time.Now()
+	result = core.StripHTMLWithLimits(html, 0, 0)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected bounded execution with the default limits, took %v", elapsed)
+	}
+	if len(result) > core.DefaultHTMLStripMaxSize {
+		t.Fatalf("Expected result to be truncated to max %d bytes, got %d", core.DefaultHTMLStripMaxSize, len(result))
+	}
+}
+
+func TestFindSimilarRecordsRecencyBoost(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_recency_boost")
+
+	dates := map[string]string{
+		"aaaaaaaaaaaaold": "2020-01-01 00:00:00.000Z",
+		"bbbbbbbbbbbbnew": types.NowDateTime().String(),
+	}
+	for id, date := range dates {
+		record := core.NewRecord(collection)
+		record.Id = id
+		record.Set("title", id)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		// overwrite the autodate value
+		_, err := app.DB().Update(collection.Name, dbx.Params{"updated": date}, dbx.HashExp{"id": id}).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"query":           {1, 0},
+		"aaaaaaaaaaaaold": {1, 1},
+		"bbbbbbbbbbbbnew": {1, 1},
+	})
+
+	scenarios := []struct {
+		name     string
+		halfLife float64
+		expected string
+	}{
+		{"without recency boost", 0, "aaaaaaaaaaaaold,bbbbbbbbbbbbnew"},
+		{"with recency boost", 30, "bbbbbbbbbbbbnew,aaaaaaaaaaaaold"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId:        collection.Id,
+				FieldName:           "title",
+				RecordId:            "query",
+				Limit:               10,
+				RecencyHalfLifeDays: s.halfLife,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]string, len(result.Results))
+			for i, r := range result.Results {
+				ids[i] = r.RecordId
+			}
+
+			if joined := strings.Join(ids, ","); joined != s.expected {
+				t.Fatalf("Expected order %s, got %s", s.expected, joined)
+			}
+		})
+	}
+
+	t.Run("invalid recency options", func(t *testing.T) {
+		invalid := []core.FindSimilarRequest{
+			{CollectionId: collection.Id, FieldName: "title", RecordId: "query", RecencyHalfLifeDays: -1},
+			{CollectionId: collection.Id, FieldName: "title", RecordId: "query", RecencyHalfLifeDays: 1, RecencyWeight: 2},
+		}
+
+		for i, req := range invalid {
+			if _, err := core.FindSimilarRecords(app, req); err == nil {
+				t.Fatalf("[%d] Expected error, got nil", i)
+			}
+		}
+	})
+}
+
+func TestFindSimilarGlobal(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	articles := createTestEmbeddingsSourceCollection(t, app, "test_global_articles")
+	products := createTestEmbeddingsSourceCollection(t, app, "test_global_products")
+
+	storeTestEmbeddings(t, app, articles.Id, "title", map[string][]float32{
+		"a1": fakeEmbedding("apple"),
+		"a2": fakeEmbedding("zzz"),
+	})
+	storeTestEmbeddings(t, app, products.Id, "content", map[string][]float32{
+		"p1": fakeEmbedding("apples"),
+		"p2": fakeEmbedding("xyz"),
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		invalid := []core.FindSimilarGlobalRequest{
+			{Targets: []core.SimilarityTarget{{CollectionId: articles.Id, FieldName: "title"}}},
+			{Text: "apple"},
+			{Text: "apple", Targets: []core.SimilarityTarget{{CollectionId: "missing", FieldName: "title"}}},
+			{Text: "apple", Targets: []core.SimilarityTarget{{CollectionId: articles.Id}}},
+		}
+
+		for i, req := range invalid {
+			if _, err := core.FindSimilarGlobal(app, req); err == nil {
+				t.Fatalf("[%d] Expected error, got nil", i)
+			}
+		}
+	})
+
+	t.Run("merged results", func(t *testing.T) {
+		before := len(transport.Inputs())
+
+		result, err := core.FindSimilarGlobal(app, core.FindSimilarGlobalRequest{
+			Text: "apple",
+			Targets: []core.SimilarityTarget{
+				{CollectionId: articles.Name, FieldName: "title"},
+				{CollectionId: products.Id, FieldName: "content"},
+			},
+			Limit: 3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if inputs := transport.Inputs()[before:]; len(inputs) != 1 {
+			t.Fatalf("Expected the query to be embedded once, got %v", inputs)
+		}
+
+		expected := []string{
+			"test_global_articles:a1",
+			"test_global_products:p1",
+		}
+
+		if len(result.Results) != 3 {
+			t.Fatalf("Expected 3 results, got %v", result.Results)
+		}
+
+		for i, name := range expected {
+			r := result.Results[i]
+			if tagged := r.CollectionName + ":" + r.RecordId; tagged != name {
+				t.Fatalf("[%d] Expected %s, got %s (%v)", i, name, tagged, result.Results)
+			}
+
+			expectedId := articles.Id
+			if r.CollectionName == products.Name {
+				expectedId = products.Id
+			}
+			if r.CollectionId != expectedId {
+				t.Fatalf("[%d] Expected collection id %s, got %s", i, expectedId, r.CollectionId)
+			}
+		}
+
+		for i := 1; i < len(result.Results); i++ {
+			if result.Results[i].Similarity > result.Results[i-1].Similarity {
+				t.Fatalf("Expected results sorted by similarity, got %v", result.Results)
+			}
+		}
+	})
+
+	t.Run("per target metric", func(t *testing.T) {
+		_, err := core.SaveEmbeddingConfig(app, core.EmbeddingConfig{
+			CollectionId: products.Id,
+			FieldName:    "content",
+			Model:        "multi-qa-mpnet-base-dot-v1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := core.FindSimilarGlobalRequest{
+			Text: "apple",
+			Targets: []core.SimilarityTarget{
+				{CollectionId: articles.Id, FieldName: "title"},
+				{CollectionId: products.Id, FieldName: "content"},
+			},
+			Limit: 4,
+		}
+
+		result, err := core.FindSimilarGlobal(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range result.Results {
+			expected := core.SimilarityMetricCosine
+			if r.CollectionId == products.Id {
+				expected = core.SimilarityMetricDot
+			}
+			if r.Metric != expected {
+				t.Fatalf("Expected %s metric for %s, got %s", expected, r.CollectionName, r.Metric)
+			}
+		}
+
+		req.Metric = core.SimilarityMetricEuclidean
+		result, err = core.FindSimilarGlobal(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range result.Results {
+			if r.Metric != core.SimilarityMetricEuclidean {
+				t.Fatalf("Expected the request metric for %s, got %s", r.CollectionName, r.Metric)
+			}
+		}
+
+		req.Metric = "invalid"
+		if _, err := core.FindSimilarGlobal(app, req); err == nil {
+			t.Fatal("Expected invalid metric error, got nil")
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := core.FindSimilarGlobalWithContext(ctx, app, core.FindSimilarGlobalRequest{
+			Text:    "cherry",
+			Targets: []core.SimilarityTarget{{CollectionId: articles.Id, FieldName: "title"}},
+		})
+		if !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+	})
+
+	t.Run("custom embeddings collection", func(t *testing.T) {
+		storeTestEmbeddingsIn(t, app, "_embeddings_tenant_global", articles.Id, "title", map[string][]float32{
+			"t1": fakeEmbedding("apple"),
+		})
+
+		req := core.FindSimilarGlobalRequest{
+			Text:                 "apple",
+			Targets:              []core.SimilarityTarget{{CollectionId: articles.Id, FieldName: "title"}},
+			EmbeddingsCollection: "_embeddings_tenant_global",
+		}
+
+		result, err := core.FindSimilarGlobal(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(result.Results) != 1 || result.Results[0].RecordId != "t1" {
+			t.Fatalf("Expected only the custom embeddings collection t1 result, got %v", result.Results)
+		}
+
+		req.EmbeddingsCollection = "invalid"
+		if _, err := core.FindSimilarGlobal(app, req); err == nil {
+			t.Fatal("Expected invalid embeddings collection error, got nil")
+		}
+	})
+}
+
+func TestFindSimilarRecordsTiesOrder(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_ties")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"query": {1, 0},
+		"r5":    {1, 1},
+		"r3":    {1, 1},
+		"r1":    {1, 1},
+		"r4":    {1, 1},
+		"r2":    {1, 1},
+		"best":  {1, 0.1},
+		"worst": {0, 1},
+	})
+
+	expected := "best,r1,r2,r3,r4,r5,worst"
+
+	for i := 0; i < 10; i++ {
+		// alternate between the db loaded and the cached embeddings
+		if i%2 == 0 {
+			core.ClearEmbeddingCache()
+		}
+
+		result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RecordId:     "query",
+			Limit:        10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(result.Results))
+		for j, r := range result.Results {
+			ids[j] = r.RecordId
+		}
+
+		if v := strings.Join(ids, ","); v != expected {
+			t.Fatalf("[%d] Expected order %s, got %s", i, expected, v)
+		}
+	}
+}
+
+func TestFindSimilarRecordsMinSimilarity(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_min")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"query":     {1, 0},
+		"same":      {1, 0},
+		"close":     {1, 1},
+		"unrelated": {0, 1},
+		"opposite":  {-1, 0.1},
+	})
+
+	scenarios := []struct {
+		name          string
+		minSimilarity float32
+		limit         int
+		scoreScale    core.SimilarityScoreScale
+		expected      string
+	}{
+		{"no threshold", 0, 10, "", "same,close,unrelated,opposite"},
+		{"negative threshold", -0.5, 10, "", "same,close,unrelated"},
+		{"positive threshold", 0.5, 10, "", "same,close"},
+		{"threshold before limit", 0.5, 1, "", "same"},
+		{"threshold with percent scale", 0.5, 10, core.SimilarityScoreScalePercent, "same,close"},
+		{"all filtered", 1.5, 10, "", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId:  collection.Id,
+				FieldName:     "title",
+				RecordId:      "query",
+				Limit:         s.limit,
+				MinSimilarity: s.minSimilarity,
+				ScoreScale:    s.scoreScale,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Results == nil {
+				t.Fatal("Expected non-nil results")
+			}
+
+			ids := make([]string, len(result.Results))
+			for i, r := range result.Results {
+				ids[i] = r.RecordId
+			}
+			if v := strings.Join(ids, ","); v != s.expected {
+				t.Fatalf("Expected results %q, got %q", s.expected, v)
+			}
+
+			if result.Debug.CandidatesCount != 4 {
+				t.Fatalf("Expected 4 candidates, got %d", result.Debug.CandidatesCount)
+			}
+		})
+	}
+}
+
+func TestFindSimilarRecordsModelMismatch(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingModel = "test"
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_models")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": fakeEmbedding("apple"),
+		"r2": fakeEmbedding("zzz"),
+	})
+
+	search := func(req core.FindSimilarRequest) (*core.FindSimilarResponse, error) {
+		req.CollectionId = collection.Id
+		req.FieldName = "title"
+		return core.FindSimilarRecords(app, req)
+	}
+
+	t.Run("matching models", func(t *testing.T) {
+		result, err := search(core.FindSimilarRequest{Text: "apple", StrictModel: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Fatalf("Expected no warnings, got %v", result.Warnings)
+		}
+	})
+
+	// simulate an embedding model change
+	app.Settings().AI.EmbeddingModel = "test-new"
+
+	t.Run("text query warning", func(t *testing.T) {
+		result, err := search(core.FindSimilarRequest{Text: "apple"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `model "test" but the query embedding uses model "test-new"`) {
+			t.Fatalf("Expected a single model mismatch warning, got %v", result.Warnings)
+		}
+		if len(result.Results) != 2 {
+			t.Fatalf("Expected the results to be still returned, got %v", result.Results)
+		}
+	})
+
+	t.Run("text query strict", func(t *testing.T) {
+		before := len(transport.Inputs())
+
+		_, err := search(core.FindSimilarRequest{Text: "apple", StrictModel: true})
+		if !errors.Is(err, core.ErrEmbeddingModelMismatch) {
+			t.Fatalf("Expected ErrEmbeddingModelMismatch, got %v", err)
+		}
+
+		if inputs := transport.Inputs()[before:]; len(inputs) != 0 {
+			t.Fatalf("Expected no query embedding requests, got %v", inputs)
+		}
+	})
+
+	t.Run("record query with a single stored model", func(t *testing.T) {
+		result, err := search(core.FindSimilarRequest{RecordId: "r1", StrictModel: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Fatalf("Expected no warnings, got %v", result.Warnings)
+		}
+	})
+
+	// mix the stored models
+	embedding, err := app.FindFirstRecordByFilter(core.EmbeddingsCollectionName, "record_id = 'r2'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedding.Set("model", "test-new")
+	if err := app.Save(embedding); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("record query with mixed stored models", func(t *testing.T) {
+		result, err := search(core.FindSimilarRequest{RecordId: "r1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `different models ("test", "test-new")`) {
+			t.Fatalf("Expected a single mixed models warning, got %v", result.Warnings)
+		}
+
+		_, err = search(core.FindSimilarRequest{RecordId: "r1", StrictModel: true})
+		if !errors.Is(err, core.ErrEmbeddingModelMismatch) {
+			t.Fatalf("Expected ErrEmbeddingModelMismatch, got %v", err)
+		}
+	})
+
+	t.Run("tombstoned embeddings are ignored", func(t *testing.T) {
+		embedding.Set(core.EmbeddingsFieldDeleted, types.NowDateTime())
+		if err := app.Save(embedding); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := search(core.FindSimilarRequest{RecordId: "r1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Fatalf("Expected no warnings, got %v", result.Warnings)
+		}
+	})
+}
+
+func TestFindSimilarRecordsMaxLimit(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{})
+	app.Settings().AI.EmbeddingModel = "test"
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_max_limit")
+
+	vectors := map[string][]float32{}
+	for i := 0; i < core.DefaultSimilarityMaxLimit+10; i++ {
+		vectors[fmt.Sprintf("r%d", i)] = []float32{1, float32(i)}
+	}
+	storeTestEmbeddings(t, app, collection.Id, "title", vectors)
+
+	scenarios := []struct {
+		name          string
+		maxLimit      int
+		limit         int
+		expectError   bool
+		expectedCount int
+	}{
+		{"default cap (within)", 0, core.DefaultSimilarityMaxLimit, false, core.DefaultSimilarityMaxLimit},
+		{"default cap (exceeded)", 0, core.DefaultSimilarityMaxLimit + 1, true, 0},
+		{"lowered cap (exceeded)", 5, 6, true, 0},
+		{"raised cap (within)", 1000, core.DefaultSimilarityMaxLimit + 5, false, core.DefaultSimilarityMaxLimit + 5},
+		{"raised cap (exceeded)", 1000, 1001, true, 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app.Settings().AI.SimilarityMaxLimit = s.maxLimit
+
+			result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+				RecordId:     "r0",
+				Limit:        s.limit,
+			})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				if _, err := core.FindSimilarGlobal(app, core.FindSimilarGlobalRequest{
+					Text:    "test",
+					Targets: []core.SimilarityTarget{{CollectionId: collection.Id, FieldName: "title"}},
+					Limit:   s.limit,
+				}); err == nil {
+					t.Fatal("Expected the global search to be also capped")
+				}
+				return
+			}
+
+			if len(result.Results) != s.expectedCount {
+				t.Fatalf("Expected %d results, got %d", s.expectedCount, len(result.Results))
+			}
+		})
+	}
+}
+
+func TestDefaultSimilarityMetric(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		model    string
+		expected core.SimilarityMetric
+	}{
+		{"", core.SimilarityMetricCosine},
+		{"unknown", core.SimilarityMetricCosine},
+		{"text-embedding-3-small", core.SimilarityMetricCosine},
+		{"text-embedding-ada-002", core.SimilarityMetricCosine},
+		{"multi-qa-mpnet-base-dot-v1", core.SimilarityMetricDot},
+		{"sentence-transformers/multi-qa-MiniLM-L6-dot-v1", core.SimilarityMetricDot},
+		{" MSMARCO-distilbert-base-tas-b ", core.SimilarityMetricDot},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.model, func(t *testing.T) {
+			if v := core.DefaultSimilarityMetric(s.model); v != s.expected {
+				t.Fatalf("Expected metric %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestFindSimilarRecordsMetric(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_metric")
+
+	// "a" is the closest by direction (cosine) and "b" by magnitude (dot product),
+	// while "c" is with opposite direction but closer than "b" by distance (euclidean)
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"query": {2, 0},
+		"a":     {1, 0},
+		"b":     {3, 3},
+		"c":     {-1, 0},
+	})
+
+	scenarios := []struct {
+		name           string
+		model          string
+		metric         core.SimilarityMetric
+		expectError    bool
+		expectedMetric core.SimilarityMetric
+		expectedOrder  string
+	}{
+		{
+			name:           "unknown model default",
+			model:          "custom-model",
+			expectedMetric: core.SimilarityMetricCosine,
+			expectedOrder:  "a,b,c",
+		},
+		{
+			name:           "cosine model default",
+			model:          "text-embedding-3-small",
+			expectedMetric: core.SimilarityMetricCosine,
+			expectedOrder:  "a,b,c",
+		},
+		{
+			name:           "dot model default",
+			model:          "sentence-transformers/multi-qa-mpnet-base-dot-v1",
+			expectedMetric: core.SimilarityMetricDot,
+			expectedOrder:  "b,a,c",
+		},
+		{
+			name:           "dot model with explicit cosine metric",
+			model:          "multi-qa-mpnet-base-dot-v1",
+			metric:         core.SimilarityMetricCosine,
+			expectedMetric: core.SimilarityMetricCosine,
+			expectedOrder:  "a,b,c",
+		},
+		{
+			name:           "cosine model with explicit dot metric",
+			model:          "text-embedding-3-small",
+			metric:         core.SimilarityMetricDot,
+			expectedMetric: core.SimilarityMetricDot,
+			expectedOrder:  "b,a,c",
+		},
+		{
+			name:           "explicit euclidean metric",
+			model:          "text-embedding-3-small",
+			metric:         core.SimilarityMetricEuclidean,
+			expectedMetric: core.SimilarityMetricEuclidean,
+			expectedOrder:  "a,c,b",
+		},
+		{
+			name:        "invalid metric",
+			model:       "text-embedding-3-small",
+			metric:      "manhattan",
+			expectError: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app.Settings().AI.EmbeddingModel = s.model
+
+			result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+				RecordId:     "query",
+				Metric:       s.metric,
+				Limit:        10,
+			})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+			if hasErr {
+				return
+			}
+
+			if result.Debug.Metric != s.expectedMetric {
+				t.Fatalf("Expected metric %q, got %q", s.expectedMetric, result.Debug.Metric)
+			}
+
+			ids := make([]string, len(result.Results))
+			for i, r := range result.Results {
+				ids[i] = r.RecordId
+			}
+
+			if v := strings.Join(ids, ","); v != s.expectedOrder {
+				t.Fatalf("Expected order %s, got %s", s.expectedOrder, v)
+			}
+		})
+	}
+
+	t.Run("euclidean scores", func(t *testing.T) {
+		app.Settings().AI.EmbeddingModel = "text-embedding-3-small"
+
+		for _, scale := range []core.SimilarityScoreScale{core.SimilarityScoreScaleRaw, core.SimilarityScoreScalePercent} {
+			result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+				RecordId:     "query",
+				Metric:       core.SimilarityMetricEuclidean,
+				ScoreScale:   scale,
+				Limit:        10,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 1/(1+distance)
+			expected := map[string]float64{"a": 0.5, "c": 0.25, "b": 1 / (1 + math.Sqrt(10))}
+
+			for _, r := range result.Results {
+				score := expected[r.RecordId]
+				if scale == core.SimilarityScoreScalePercent {
+					score *= 100
+				}
+
+				if math.Abs(float64(r.Similarity)-score) > 1e-4 {
+					t.Fatalf("[%s] Expected %q score %v, got %v", scale, r.RecordId, score, r.Similarity)
+				}
+			}
+		}
+	})
+}
+
+func TestFindSimilarRecordsCombinedMode(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_combined")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"query":       {1, 0},
+		"title_match": {1, 0.1},
+		"both":        {0, 1},
+	})
+	storeTestEmbeddings(t, app, collection.Id, core.RecordLevelFieldName, map[string][]float32{
+		"query":        {0, 1},
+		"record_match": {0.2, 1},
+		"both":         {0.5, 1},
+	})
+
+	t.Run("missing field name", func(t *testing.T) {
+		_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			Mode:         core.EmbeddingModeCombined,
+			RecordId:     "query",
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+		Mode:         core.EmbeddingModeCombined,
+		RecordId:     "query",
+		Limit:        10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, len(result.Results))
+	for i, r := range result.Results {
+		ids[i] = r.RecordId
+	}
+
+	expected := "title_match,record_match,both"
+	if v := strings.Join(ids, ","); v != expected {
+		t.Fatalf("Expected merged and deduplicated results %s, got %s", expected, v)
+	}
+
+	// "both" should have its max (record-level) score instead of the title one (0)
+	both := result.Results[2].Similarity
+	expectedBoth := float32(1 / math.Sqrt(1.25))
+	if math.Abs(float64(both-expectedBoth)) > 0.0001 {
+		t.Fatalf("Expected the max score %v for the duplicated record, got %v", expectedBoth, both)
+	}
+
+	if result.Debug.StoredEmbeddings != 6 {
+		t.Fatalf("Expected 6 stored embeddings to be searched, got %d", result.Debug.StoredEmbeddings)
+	}
+}
+
+func TestDumpEmbeddingCache(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_cache_dump")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": {1, 0, 0},
+		"r2": {0, 1, 0},
+	})
+	storeTestEmbeddings(t, app, collection.Id, "content", map[string][]float32{
+		"r1": {1, 0},
+	})
+
+	if dump := core.DumpEmbeddingCache(); len(dump.Entries) != 0 {
+		t.Fatalf("Expected empty cache dump, got %v", dump.Entries)
+	}
+
+	search := func(fieldName string) {
+		_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    fieldName,
+			RecordId:     "r1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search("title")
+	search("content")
+	search("title") // move to the end of the access log
+
+	dump := core.DumpEmbeddingCache()
+
+	if len(dump.Entries) != 2 {
+		t.Fatalf("Expected 2 cache entries, got %v", dump.Entries)
+	}
+
+	expected := []struct {
+		fieldName  string
+		count      int
+		dimensions int
+	}{
+		{"content", 1, 2},
+		{"title", 2, 3},
+	}
+
+	for i, e := range expected {
+		entry := dump.Entries[i]
+		if entry.CollectionId != collection.Id ||
+			entry.FieldName != e.fieldName ||
+			entry.Key != collection.Id+":"+e.fieldName ||
+			entry.AccessOrder != i ||
+			entry.Count != e.count ||
+			entry.Dimensions != e.dimensions ||
+			entry.Expired {
+			t.Fatalf("[%d] Unexpected cache dump entry %+v", i, entry)
+		}
+	}
+
+	if !dump.Entries[1].AccessedAt.After(dump.Entries[1].CreatedAt) {
+		t.Fatalf("Expected the title entry access time to be after its creation, got %+v", dump.Entries[1])
+	}
+
+	raw, err := json.Marshal(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "embedding\"") {
+		t.Fatalf("Expected the dump to not include the raw vectors, got %s", raw)
+	}
+}
+
+func TestEmbeddingCacheEvictions(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	small := make([]core.CachedEmbedding, 2)
+
+	// ttl
+	core.SetEmbeddingCacheEntry("c1", "title", small)
+	core.ExpireEmbeddingCacheEntry("c1", "title")
+	if _, ok := core.GetEmbeddingCacheEntry("c1", "title"); ok {
+		t.Fatal("Expected the expired entry to be evicted")
+	}
+
+	// memory pressure (each entry is ~266MB)
+	// (the same vector is shared to keep the test memory usage low)
+	vector := make([]float32, 1536)
+	large := make([]core.CachedEmbedding, 45000)
+	for i := range large {
+		large[i].Embedding = vector
+	}
+	core.SetEmbeddingCacheEntry("c2", "title", large)
+	core.SetEmbeddingCacheEntry("c2", "content", large)
+	if _, ok := core.GetEmbeddingCacheEntry("c2", "title"); ok {
+		t.Fatal("Expected the least recently used entry to be evicted")
+	}
+
+	// count cap
+	core.SetEmbeddingCacheEntry("c3", "title", small)
+	restore := core.SetEmbeddingsLoadLimits(1, core.EmbeddingsLoadPageSize)
+	cached := core.SetEmbeddingCacheEntry("c3", "title", small)
+	restore()
+	if cached {
+		t.Fatal("Expected the entry exceeding the per entry cap to not be cached")
+	}
+	if _, ok := core.GetEmbeddingCacheEntry("c3", "title"); ok {
+		t.Fatal("Expected the stale entry to be evicted")
+	}
+
+	// explicit invalidations are not evictions
+	core.SetEmbeddingCacheEntry("c4", "title", small)
+	core.InvalidateEmbeddingCacheEntry("c4", "title")
+
+	stats := core.GetEmbeddingCacheStats()
+
+	evictions, _ := stats["recentEvictions"].([]core.CacheEviction)
+
+	expected := []struct {
+		key    string
+		reason core.CacheEvictionReason
+		count  int
+	}{
+		{"c1:title", core.CacheEvictionReasonTTL, 2},
+		{"c2:title", core.CacheEvictionReasonMemory, 45000},
+		{"c3:title", core.CacheEvictionReasonCount, 2},
+	}
+
+	if len(evictions) != len(expected) {
+		t.Fatalf("Expected %d evictions, got %+v", len(expected), evictions)
+	}
+
+	for i, e := range expected {
+		eviction := evictions[i]
+		collectionId, fieldName, _ := strings.Cut(e.key, ":")
+		if eviction.Key != e.key ||
+			eviction.CollectionId != collectionId ||
+			eviction.FieldName != fieldName ||
+			eviction.Reason != e.reason ||
+			eviction.Count != e.count ||
+			eviction.EvictedAt.IsZero() {
+			t.Fatalf("[%d] Unexpected eviction %+v", i, eviction)
+		}
+	}
+
+	counts, _ := stats["evictionsCount"].(map[core.CacheEvictionReason]int)
+	for _, e := range expected {
+		if counts[e.reason] != 1 {
+			t.Fatalf("Expected 1 %s eviction, got %v", e.reason, counts)
+		}
+	}
+
+	t.Run("ring buffer", func(t *testing.T) {
+		core.ClearEmbeddingCache()
+
+		total := core.EmbeddingCacheMaxEvictions + 5
+		for i := 0; i < total; i++ {
+			field := fmt.Sprintf("field%d", i)
+			core.SetEmbeddingCacheEntry("c5", field, small)
+			core.ExpireEmbeddingCacheEntry("c5", field)
+			core.GetEmbeddingCacheEntry("c5", field)
+		}
+
+		stats := core.GetEmbeddingCacheStats()
+
+		evictions, _ := stats["recentEvictions"].([]core.CacheEviction)
+		if len(evictions) != core.EmbeddingCacheMaxEvictions {
+			t.Fatalf("Expected %d evictions, got %d", core.EmbeddingCacheMaxEvictions, len(evictions))
+		}
+
+		// the oldest evictions are overwritten
+		if evictions[0].FieldName != "field5" || evictions[len(evictions)-1].FieldName != fmt.Sprintf("field%d", total-1) {
+			t.Fatalf("Expected evictions from field5 to field%d, got %s to %s", total-1, evictions[0].FieldName, evictions[len(evictions)-1].FieldName)
+		}
+
+		counts, _ := stats["evictionsCount"].(map[core.CacheEvictionReason]int)
+		if counts[core.CacheEvictionReasonTTL] != total {
+			t.Fatalf("Expected %d ttl evictions, got %v", total, counts)
+		}
+	})
+}
+
+func TestEmbeddingCacheHitsAndMisses(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	small := make([]core.CachedEmbedding, 2)
+
+	core.GetEmbeddingCacheEntry("c1", "title") // miss
+
+	core.SetEmbeddingCacheEntry("c1", "title", small)
+	core.GetEmbeddingCacheEntry("c1", "title") // hit
+	core.GetEmbeddingCacheEntry("c1", "title") // hit
+	core.GetEmbeddingCacheEntry("c1", "title") // hit
+
+	core.ExpireEmbeddingCacheEntry("c1", "title")
+	core.GetEmbeddingCacheEntry("c1", "title") // miss (+ttl eviction)
+
+	// count cap eviction
+	core.SetEmbeddingCacheEntry("c2", "title", small)
+	restore := core.SetEmbeddingsLoadLimits(1, core.EmbeddingsLoadPageSize)
+	core.SetEmbeddingCacheEntry("c2", "title", small)
+	restore()
+
+	stats := core.GetEmbeddingCacheStats()
+	if v, _ := stats["hits"].(uint64); v != 3 {
+		t.Fatalf("Expected 3 hits, got %v", stats["hits"])
+	}
+	if v, _ := stats["misses"].(uint64); v != 2 {
+		t.Fatalf("Expected 2 misses, got %v", stats["misses"])
+	}
+	if _, ok := stats["evictions"]; ok {
+		t.Fatal("Expected no duplicated evictions total in the stats")
+	}
+	counts, _ := stats["evictionsCount"].(map[core.CacheEvictionReason]int)
+	if counts[core.CacheEvictionReasonTTL] != 1 || counts[core.CacheEvictionReasonCount] != 1 {
+		t.Fatalf("Expected 1 ttl and 1 count cap evictions, got %v", stats["evictionsCount"])
+	}
+	if v, _ := stats["hitRate"].(float64); v != 60 {
+		t.Fatalf("Expected 60%% hit rate, got %v", stats["hitRate"])
+	}
+
+	info := core.GetEmbeddingCacheInfo()
+	if info.Hits != 3 || info.Misses != 2 || info.Evictions != 2 || info.HitRate != 60 {
+		t.Fatalf("Unexpected cache info %+v", info)
+	}
+
+	core.ClearEmbeddingCache()
+
+	info = core.GetEmbeddingCacheInfo()
+	if info.Hits != 0 || info.Misses != 0 || info.Evictions != 0 || info.HitRate != 0 {
+		t.Fatalf("Expected the counters to be reset, got %+v", info)
+	}
+}
+
+func TestEmbeddingCacheMemoryEstimate(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	newEmbeddings := func(count int, dimensions int) []core.CachedEmbedding {
+		// the same vector is shared to keep the test memory usage low
+		vector := make([]float32, dimensions)
+		embeddings := make([]core.CachedEmbedding, count)
+		for i := range embeddings {
+			embeddings[i] = core.CachedEmbedding{RecordId: fmt.Sprintf("%015d", i), Embedding: vector}
+		}
+		return embeddings
+	}
+
+	expectedMB := func(count int, dimensions int) float64 {
+		return float64(count*(dimensions*4+15+core.EmbeddingMemoryOverhead)) / (1024 * 1024)
+	}
+
+	core.SetEmbeddingCacheEntry("c1", "small", newEmbeddings(100, 256))
+	core.SetEmbeddingCacheEntry("c1", "large", newEmbeddings(100, 3072))
+
+	dump := core.DumpEmbeddingCache()
+	if len(dump.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", dump.Entries)
+	}
+
+	small, large := dump.Entries[0], dump.Entries[1]
+	if math.Abs(small.MemoryMB-expectedMB(100, 256)) > 1e-9 {
+		t.Fatalf("Expected the 256 dimensions entry memory %v, got %v", expectedMB(100, 256), small.MemoryMB)
+	}
+	if math.Abs(large.MemoryMB-expectedMB(100, 3072)) > 1e-9 {
+		t.Fatalf("Expected the 3072 dimensions entry memory %v, got %v", expectedMB(100, 3072), large.MemoryMB)
+	}
+
+	info := core.GetEmbeddingCacheInfo()
+	if math.Abs(info.MemoryUsedMB-(small.MemoryMB+large.MemoryMB)) > 1e-9 {
+		t.Fatalf("Expected the total memory to be the entries sum, got %v", info.MemoryUsedMB)
+	}
+
+	// updating an entry replaces its memory
+	core.SetEmbeddingCacheEntry("c1", "small", newEmbeddings(10, 256))
+	info = core.GetEmbeddingCacheInfo()
+	if math.Abs(info.MemoryUsedMB-(expectedMB(10, 256)+large.MemoryMB)) > 1e-9 {
+		t.Fatalf("Expected the updated entry memory to be replaced, got %v", info.MemoryUsedMB)
+	}
+
+	// 2 entries with 3072 dimensions exceed the budget
+	// (while the same entries with 1536 dimensions fit)
+	core.ClearEmbeddingCache()
+	core.SetEmbeddingCacheEntry("c2", "a", newEmbeddings(30000, 1536))
+	core.SetEmbeddingCacheEntry("c2", "b", newEmbeddings(30000, 1536))
+	if entries := len(core.DumpEmbeddingCache().Entries); entries != 2 {
+		t.Fatalf("Expected the 1536 dimensions entries to fit the budget, got %d entries", entries)
+	}
+
+	core.ClearEmbeddingCache()
+	core.SetEmbeddingCacheEntry("c3", "a", newEmbeddings(30000, 3072))
+	core.SetEmbeddingCacheEntry("c3", "b", newEmbeddings(30000, 3072))
+	dump = core.DumpEmbeddingCache()
+	if len(dump.Entries) != 1 || dump.Entries[0].FieldName != "b" {
+		t.Fatalf("Expected only the last 3072 dimensions entry to be cached, got %v", dump.Entries)
+	}
+	if dump.TotalMemoryMB > dump.MemoryBudgetMB {
+		t.Fatalf("Expected the total memory %v to be within the budget", dump.TotalMemoryMB)
+	}
+}
+
+func TestComputeEmbeddingQuality(t *testing.T) {
+	t.Parallel()
+
+	newEmbedding := func(id string, v ...float32) core.CachedEmbedding {
+		var sum float64
+		for _, x := range v {
+			sum += float64(x * x)
+		}
+		return core.CachedEmbedding{RecordId: id, Embedding: v, Magnitude: float32(math.Sqrt(sum))}
+	}
+
+	t.Run("empty set", func(t *testing.T) {
+		stats := core.ComputeEmbeddingQuality(nil)
+		if stats.Count != 0 || stats.SampleSize != 0 || stats.MeanMagnitude != 0 {
+			t.Fatalf("Expected zero stats, got %#v", stats)
+		}
+	})
+
+	t.Run("known-good set", func(t *testing.T) {
+		stats := core.ComputeEmbeddingQuality([]core.CachedEmbedding{
+			newEmbedding("a", 1, 0, 0),
+			newEmbedding("b", 0, 1, 0),
+			newEmbedding("c", 0, 0, 1),
+		})
+
+		if stats.Count != 3 {
+			t.Fatalf("Expected count 3, got %d", stats.Count)
+		}
+		if stats.Dimensions != 3 {
+			t.Fatalf("Expected 3 dimensions, got %d", stats.Dimensions)
+		}
+		if math.Abs(stats.MeanMagnitude-1) > 1e-6 {
+			t.Fatalf("Expected mean magnitude 1, got %v", stats.MeanMagnitude)
+		}
+		if stats.MagnitudeVariance > 1e-6 {
+			t.Fatalf("Expected zero magnitude variance, got %v", stats.MagnitudeVariance)
+		}
+		if stats.NearZeroCount != 0 {
+			t.Fatalf("Expected no near-zero vectors, got %d", stats.NearZeroCount)
+		}
+		if stats.SampleSize != 3 {
+			t.Fatalf("Expected sample size 3, got %d", stats.SampleSize)
+		}
+		// orthogonal vectors
+		if math.Abs(stats.AvgPairwiseSimilarity) > 1e-6 {
+			t.Fatalf("Expected avg pairwise similarity 0, got %v", stats.AvgPairwiseSimilarity)
+		}
+	})
+
+	t.Run("degenerate set", func(t *testing.T) {
+		stats := core.ComputeEmbeddingQuality([]core.CachedEmbedding{
+			newEmbedding("a", 1, 1, 0),
+			newEmbedding("b", 2, 2, 0),
+			newEmbedding("c", 3, 3, 0),
+			newEmbedding("d", 0, 0, 0),
+			newEmbedding("e", 0, 0, 0),
+		})
+
+		if stats.NearZeroCount != 2 {
+			t.Fatalf("Expected 2 near-zero vectors, got %d", stats.NearZeroCount)
+		}
+		if stats.SampleSize != 3 {
+			t.Fatalf("Expected the near-zero vectors to be excluded from the sample, got sample size %d", stats.SampleSize)
+		}
+		if stats.MagnitudeVariance < 1 {
+			t.Fatalf("Expected high magnitude variance, got %v", stats.MagnitudeVariance)
+		}
+		// collapsed (all pointing in the same direction)
+		if math.Abs(stats.AvgPairwiseSimilarity-1) > 1e-6 {
+			t.Fatalf("Expected avg pairwise similarity 1, got %v", stats.AvgPairwiseSimilarity)
+		}
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		embeddings := make([]core.CachedEmbedding, core.EmbeddingQualitySampleSize*3)
+		for i := range embeddings {
+			embeddings[i] = newEmbedding(fmt.Sprintf("r%d", i), 1, float32(i%2))
+		}
+
+		stats := core.ComputeEmbeddingQuality(embeddings)
+
+		if stats.Count != len(embeddings) {
+			t.Fatalf("Expected count %d, got %d", len(embeddings), stats.Count)
+		}
+		if stats.SampleSize != core.EmbeddingQualitySampleSize {
+			t.Fatalf("Expected sample size %d, got %d", core.EmbeddingQualitySampleSize, stats.SampleSize)
+		}
+	})
+}
+
+func TestGenerateEmbeddingsCombinedFields(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_combined_fields")
+
+	record := core.NewRecord(collection)
+	record.Set("title", "Hello")
+	record.Set("content", "<p>World</p>")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("missing fields", func(t *testing.T) {
+		_, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			Mode:         core.EmbeddingModeFields,
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			Mode:         core.EmbeddingModeFields,
+			Fields:       []string{"title", "missing"},
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	for _, req := range []core.EmbeddingRequest{
+		{CollectionId: collection.Id, FieldName: "title"},
+		{CollectionId: collection.Id, FieldName: "content"},
+		{CollectionId: collection.Id, Mode: core.EmbeddingModeFields, Fields: []string{"title", "content"}, Separator: " | "},
+	} {
+		result, err := core.GenerateEmbeddings(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Generated != 1 {
+			t.Fatalf("Expected 1 generated embedding, got %+v", result)
+		}
+	}
+
+	if v := transport.Inputs(); len(v) != 3 || v[2] != "Hello | World" {
+		t.Fatalf("Expected the combined text to be %q, got %v", "Hello | World", v)
+	}
+
+	combinedFieldName := core.CombinedFieldName([]string{"title", "content"})
+	if combinedFieldName != "_fields:title+content" {
+		t.Fatalf("Expected the combined field name to be %q, got %q", "_fields:title+content", combinedFieldName)
+	}
+
+	findEmbedding := func(fieldName string) string {
+		embedding, err := app.FindFirstRecordByFilter(
+			core.EmbeddingsCollectionName,
+			"record_id = {:recordId} && field_name = {:fieldName}",
+			map[string]any{"recordId": record.Id, "fieldName": fieldName},
+		)
+		if err != nil {
+			t.Fatalf("Missing %q embedding: %v", fieldName, err)
+		}
+		return embedding.GetString("embedding")
+	}
+
+	combined := findEmbedding(combinedFieldName)
+	for _, fieldName := range []string{"title", "content"} {
+		if single := findEmbedding(fieldName); single == combined {
+			t.Fatalf("Expected the combined embedding to differ from the %q one, got %s", fieldName, single)
+		}
+	}
+
+	// search with the combined embeddings
+	result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		Mode:         core.EmbeddingModeFields,
+		Fields:       []string{"title", "content"},
+		Text:         "Hello | World",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 1 || result.Results[0].RecordId != record.Id || result.Results[0].Similarity < 0.999 {
+		t.Fatalf("Expected the combined embedding to be the best match, got %v", result.Results)
+	}
+}
+
+func TestGenerateEmbeddingsFieldTemplate(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_field_template")
+
+	for _, title := range []string{"42", ""} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if title != "" {
+			record.Set("content", "<p>Hello</p>")
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("template without placeholder", func(t *testing.T) {
+		_, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId:  collection.Id,
+			FieldName:     "title",
+			FieldTemplate: "The price is",
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	for _, req := range []core.EmbeddingRequest{
+		{CollectionId: collection.Id, FieldName: "title", FieldTemplate: "The price is {value} dollars"},
+		{CollectionId: collection.Id, FieldName: "content", FieldTemplate: "Description: {value}"},
+	} {
+		result, err := core.GenerateEmbeddings(app, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Generated != 1 {
+			t.Fatalf("Expected 1 generated embedding, got %+v", result)
+		}
+	}
+
+	expected := []string{"The price is 42 dollars", "Description: Hello"}
+	if inputs := transport.Inputs(); strings.Join(inputs, "|") != strings.Join(expected, "|") {
+		t.Fatalf("Expected the embedded texts to be %v, got %v", expected, inputs)
+	}
+}
+
+func TestGenerateEmbeddingsJSONPath(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_json_path")
+	collection.Fields.Add(&core.JSONField{Name: "data"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]string{}
+	for _, body := range []string{"apple pie recipe", "rocket launch schedule", ""} {
+		record := core.NewRecord(collection)
+		record.Set("title", "same title")
+		record.Set("data", map[string]any{"content": map[string]any{"body": body}, "other": "ignored"})
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		ids[body] = record.Id
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, req := range []core.EmbeddingRequest{
+			{CollectionId: collection.Id, FieldName: "title", JSONPath: "content.body"},
+			{CollectionId: collection.Id, FieldName: "data", JSONPath: "content..body"},
+			{CollectionId: collection.Id, Mode: core.EmbeddingModeRecord, FieldName: "data", JSONPath: "content.body"},
+		} {
+			if _, err := core.GenerateEmbeddings(app, req); err == nil {
+				t.Fatalf("Expected error for %+v, got nil", req)
+			}
+		}
+	})
+
+	result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId: collection.Id,
+		FieldName:    "data",
+		JSONPath:     "$.content.body",
+		StoreText:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Generated != 2 {
+		t.Fatalf("Expected 2 generated embeddings, got %+v", result)
+	}
+
+	expectedInputs := []string{"apple pie recipe", "rocket launch schedule"}
+	if inputs := transport.Inputs(); strings.Join(inputs, "|") != strings.Join(expectedInputs, "|") {
+		t.Fatalf("Expected the embedded texts to be %v, got %v", expectedInputs, inputs)
+	}
+
+	for _, text := range expectedInputs {
+		stored, err := core.GetEmbeddingText(app, ids[text], core.JSONPathFieldName("data", "content.body"))
+		if err != nil {
+			t.Fatalf("Expected the embedding to be stored under the json path field name, got %v", err)
+		}
+		if stored.Text != text {
+			t.Fatalf("Expected stored text %q, got %q", text, stored.Text)
+		}
+	}
+
+	search, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		FieldName:    "data",
+		JSONPath:     "content.body",
+		Text:         "rocket launch schedule",
+		Limit:        10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(search.Results) != 2 || search.Results[0].RecordId != ids["rocket launch schedule"] {
+		t.Fatalf("Expected the rocket record as best match, got %+v", search.Results)
+	}
+}
+
+// fakeEmbeddingsTransport is a fake embeddings provider that returns
+// deterministic (text content based) embeddings.
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsStoreText(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{})
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_store_text")
+
+	record := core.NewRecord(collection)
+	record.Set("title", strings.Repeat("a", core.MaxStoredEmbeddingTextLength+100))
+	record.Set("content", "<p>Hello <b>world</b></p>")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		req       core.EmbeddingRequest
+		fieldName string
+		expected  string
+	}{
+		{
+			core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "content", StoreText: true},
+			"content",
+			"Hello world",
+		},
+		{
+			core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "title", StoreText: true},
+			"title",
+			strings.Repeat("a", core.MaxStoredEmbeddingTextLength),
+		},
+		{
+			core.EmbeddingRequest{CollectionId: collection.Id, Mode: core.EmbeddingModeRecord, Template: "{content}", StoreText: true},
+			core.RecordLevelFieldName,
+			core.GenerateRecordText(record, collection, "{content}"),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.fieldName, func(t *testing.T) {
+			if _, err := core.GenerateEmbeddings(app, s.req); err != nil {
+				t.Fatal(err)
+			}
+
+			stored, err := core.GetEmbeddingText(app, record.Id, s.fieldName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if stored.Text != s.expected {
+				t.Fatalf("Expected stored text %q, got %q", s.expected, stored.Text)
+			}
+
+			if stored.Model != "test-model" {
+				t.Fatalf("Expected model test-model, got %q", stored.Model)
+			}
+		})
+	}
+
+	t.Run("regenerate without storing the text", func(t *testing.T) {
+		if _, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "content"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := core.GetEmbeddingText(app, record.Id, "content"); err == nil {
+			t.Fatal("Expected the previously stored text to be cleared")
+		}
+	})
+}
+
+func TestGenerateRecordTextSeparators(t *testing.T) {
+	t.Parallel()
+
+	collection := core.NewBaseCollection("test_record_text_separators")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.EditorField{Name: "content"},
+		&core.NumberField{Name: "year"},
+		&core.TextField{Name: "empty"},
+	)
+
+	record := core.NewRecord(collection)
+	record.Set("title", "Acme")
+	record.Set("content", "<p>Very <b>fast</b></p>")
+	record.Set("year", 2024)
+
+	scenarios := []struct {
+		name     string
+		options  core.RecordTextOptions
+		expected string
+	}{
+		{"defaults", core.RecordTextOptions{}, "title: Acme\ncontent: Very fast"},
+		{"custom field separator", core.RecordTextOptions{FieldSeparator: " | "}, "title: Acme | content: Very fast"},
+		{"custom label separator", core.RecordTextOptions{LabelSeparator: " = "}, "title = Acme\ncontent = Very fast"},
+		{"sentence-like", core.RecordTextOptions{FieldSeparator: ". ", LabelSeparator: " is "}, "title is Acme. content is Very fast"},
+		{"ignored with template", core.RecordTextOptions{Template: "{title}", FieldSeparator: ". "}, "Acme"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := core.GenerateRecordTextWithOptions(record, collection, s.options)
+			if result != s.expected {
+				t.Fatalf("Expected\n%q\ngot\n%q", s.expected, result)
+			}
+		})
+	}
+
+	if result := core.GenerateRecordText(record, collection, ""); result != scenarios[0].expected {
+		t.Fatalf("Expected GenerateRecordText to use the default separators, got %q", result)
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsRecordSeparators(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_record_separators")
+
+	record := core.NewRecord(collection)
+	record.Id = "separators00001"
+	record.Set("title", "Hello")
+	record.Set("content", "<p>world</p>")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId:         collection.Id,
+		Mode:                 core.EmbeddingModeRecord,
+		RecordFieldSeparator: "; ",
+		RecordLabelSeparator: "=",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"id=separators00001; title=Hello; content=world"}
+	if inputs := transport.Inputs(); !slices.Equal(inputs, expected) {
+		t.Fatalf("Expected embedded texts %q, got %q", expected, inputs)
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestFindSimilarTextLengthLimits(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingModel = "test"
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_text_length")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": fakeEmbedding("apple"),
+	})
+
+	longText := strings.Repeat("ж", core.MaxEmbeddingQueryTextLength+1)
+
+	scenarios := []struct {
+		name   string
+		search func() error
+	}{
+		{
+			"find similar",
+			func() error {
+				_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+					CollectionId: collection.Id,
+					FieldName:    "title",
+					Text:         longText,
+				})
+				return err
+			},
+		},
+		{
+			"find similar global",
+			func() error {
+				_, err := core.FindSimilarGlobal(app, core.FindSimilarGlobalRequest{
+					Text:    longText,
+					Targets: []core.SimilarityTarget{{CollectionId: collection.Id, FieldName: "title"}},
+				})
+				return err
+			},
+		},
+		{
+			"find similar batch",
+			func() error {
+				_, err := core.FindSimilarRecordsBatch(context.Background(), app, core.FindSimilarBatchRequest{
+					CollectionId: collection.Id,
+					FieldName:    "title",
+					Queries:      []core.SimilarityBatchQuery{{Text: "apple"}, {Text: longText}},
+				})
+				return err
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.search()
+			if !errors.Is(err, core.ErrAITextTooLong) {
+				t.Fatalf("Expected ErrAITextTooLong, got %v", err)
+			}
+		})
+	}
+
+	if inputs := transport.Inputs(); len(inputs) != 0 {
+		t.Fatalf("Expected no embeddings requests, got %d", len(inputs))
+	}
+
+	t.Run("max length", func(t *testing.T) {
+		_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Text:         longText[len("ж"):],
+		})
+		if err != nil {
+			t.Fatalf("Expected the max length text to be allowed, got %v", err)
+		}
+	})
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsRunProgress(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+	app.Settings().AI.EmbeddingBatchSize = 2
+
+	// capture the run status as seen by the client at the time of each batch request
+	var observed []int
+	transport := &fakeEmbeddingsTransport{}
+	transport.OnRequest = func() {
+		status, err := core.FindEmbeddingRunStatus(app, "test_run")
+		if err != nil {
+			t.Errorf("Failed to find the run status: %v", err)
+			return
+		}
+		if status.Status != core.EmbeddingRunStatusRunning {
+			t.Errorf("Expected status %q, got %q", core.EmbeddingRunStatusRunning, status.Status)
+		}
+		observed = append(observed, status.Generated)
+	}
+	app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_run_progress")
+
+	for i := 0; i < 5; i++ {
+		record := core.NewRecord(collection)
+		record.Set("title", fmt.Sprintf("run progress record %d", i))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	response, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+		RunId:        "test_run",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Generated != 5 {
+		t.Fatalf("Expected 5 generated embeddings, got %d", response.Generated)
+	}
+
+	if !slices.Equal(observed, []int{0, 2, 4}) {
+		t.Fatalf("Expected the observed progress [0 2 4], got %v", observed)
+	}
+
+	status, err := core.FindEmbeddingRunStatus(app, "test_run")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.Status != core.EmbeddingRunStatusCompleted {
+		t.Fatalf("Expected status %q, got %q", core.EmbeddingRunStatusCompleted, status.Status)
+	}
+	if status.Total != 5 || status.Generated != 5 || status.Skipped != 0 {
+		t.Fatalf("Expected total 5, generated 5 and skipped 0, got %d, %d and %d", status.Total, status.Generated, status.Skipped)
+	}
+	if status.CollectionId != collection.Id || status.FieldName != "title" {
+		t.Fatalf("Expected %s/title run, got %s/%s", collection.Id, status.CollectionId, status.FieldName)
+	}
+
+	if _, err := core.FindEmbeddingRunStatus(app, "missing"); err == nil {
+		t.Fatal("Expected error for missing run")
+	}
+}
+
+func TestEmbeddingsCollectionOverride(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{})
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embeddings_override")
+
+	stores := map[string]string{
+		"_embeddings_a": "tenant a document",
+		"_embeddings_b": "tenant b document",
+	}
+
+	recordIds := map[string]string{}
+	for store, text := range stores {
+		record := core.NewRecord(collection)
+		record.Set("title", text)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		recordIds[store] = record.Id
+
+		response, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId:         collection.Id,
+			FieldName:            "title",
+			RecordIds:            []string{record.Id},
+			EmbeddingsCollection: store,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.Generated != 1 {
+			t.Fatalf("[%s] Expected 1 generated embedding, got %d", store, response.Generated)
+		}
+	}
+
+	t.Run("invalid name", func(t *testing.T) {
+		for _, name := range []string{"posts", "_embeddingsx", "_embeddings_", "_embeddings_a-b", "_embeddings_" + strings.Repeat("a", 50)} {
+			_, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+				CollectionId:         collection.Id,
+				FieldName:            "title",
+				EmbeddingsCollection: name,
+			})
+			if err == nil {
+				t.Fatalf("Expected error for embeddings collection %q", name)
+			}
+		}
+	})
+
+	t.Run("default store untouched", func(t *testing.T) {
+		if _, err := app.FindCollectionByNameOrId(core.EmbeddingsCollectionName); err == nil {
+			t.Fatal("Expected the default embeddings collection to not be created")
+		}
+	})
+
+	for store, text := range stores {
+		t.Run("search "+store, func(t *testing.T) {
+			// search twice to check both the db and the cache lookups
+			for i := 0; i < 2; i++ {
+				response, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+					CollectionId:         collection.Id,
+					FieldName:            "title",
+					Text:                 text,
+					Limit:                10,
+					EmbeddingsCollection: store,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(response.Results) != 1 || response.Results[0].RecordId != recordIds[store] {
+					t.Fatalf("[%d] Expected only record %s, got %v", i, recordIds[store], response.Results)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateEmbeddingsDeduplication(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingBatchSize = 2
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embeddings_dedup")
+
+	texts := []string{"unique first"}
+	for i := 0; i < 8; i++ {
+		texts = append(texts, "shared boilerplate")
+	}
+	texts = append(texts, "unique second")
+
+	for _, text := range texts {
+		record := core.NewRecord(collection)
+		record.Set("title", text)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	response, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Generated != len(texts) || response.Skipped != 0 {
+		t.Fatalf("Expected %d generated and 0 skipped, got %d and %d (%v)", len(texts), response.Generated, response.Skipped, response.Errors)
+	}
+
+	if response.Deduplicated != 7 {
+		t.Fatalf("Expected 7 deduplicated records, got %d", response.Deduplicated)
+	}
+
+	inputs := transport.Inputs()
+	slices.Sort(inputs)
+	expectedInputs := []string{"shared boilerplate", "unique first", "unique second"}
+	if !slices.Equal(inputs, expectedInputs) {
+		t.Fatalf("Expected embedded texts %v, got %v", expectedInputs, inputs)
+	}
+
+	// all records sharing the text should be searchable
+	result, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+		Text:         "shared boilerplate",
+		Limit:        len(texts),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exact int
+	for _, r := range result.Results {
+		if r.Similarity > 0.9999 {
+			exact++
+		}
+	}
+	if exact != 8 {
+		t.Fatalf("Expected 8 exact matches, got %d (%v)", exact, result.Results)
+	}
+}
+
+func TestFindSimilarRecordsPrefilter(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similar_prefilter")
+	collection.Fields.Add(&core.TextField{Name: "category"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	categories := []string{"news", "news", "news", "sports", "sports", "sports"}
+	vectors := map[string][]float32{}
+	ids := make([]string, len(categories))
+	for i, category := range categories {
+		record := core.NewRecord(collection)
+		record.Set("title", fmt.Sprintf("record %d", i))
+		record.Set("category", category)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = record.Id
+
+		// the sports records are the closest to the query record
+		if category == "sports" {
+			vectors[record.Id] = []float32{1, 0.1 * float32(i)}
+		} else {
+			vectors[record.Id] = []float32{0.1 * float32(i), 1}
+		}
+	}
+	vectors[ids[0]] = []float32{1, 0}
+
+	storeTestEmbeddings(t, app, collection.Id, "title", vectors)
+
+	scenarios := []struct {
+		name              string
+		prefilter         string
+		params            map[string]any
+		expectedProcessed int
+		expectedIds       []string
+	}{
+		{"no prefilter", "", nil, 5, []string{ids[3], ids[4], ids[5], ids[2], ids[1]}},
+		{"query record placeholder", "category = {:category}", nil, 2, []string{ids[2], ids[1]}},
+		{"literal value", `category = "sports"`, nil, 3, []string{ids[3], ids[4], ids[5]}},
+		{"no match", `category = "missing"`, nil, 0, []string{}},
+		{"custom param", "category = {:c} && id != {:skip}", map[string]any{"c": "sports", "skip": ids[4]}, 2, []string{ids[3], ids[5]}},
+		{"custom param overriding the query record placeholder", "category = {:category}", map[string]any{"category": "sports"}, 3, []string{ids[3], ids[4], ids[5]}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			response, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId:    collection.Id,
+				FieldName:       "title",
+				RecordId:        ids[0],
+				Limit:           10,
+				Prefilter:       s.prefilter,
+				PrefilterParams: s.params,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Debug.ProcessedCount != s.expectedProcessed {
+				t.Fatalf("Expected %d scored candidates, got %d", s.expectedProcessed, response.Debug.ProcessedCount)
+			}
+
+			resultIds := make([]string, len(response.Results))
+			for i, r := range response.Results {
+				resultIds[i] = r.RecordId
+			}
+			if !slices.Equal(resultIds, s.expectedIds) {
+				t.Fatalf("Expected results %v, got %v", s.expectedIds, resultIds)
+			}
+		})
+	}
+
+	t.Run("invalid prefilter", func(t *testing.T) {
+		_, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RecordId:     ids[0],
+			Prefilter:    "missing_field = 1",
+		})
+		if err == nil {
+			t.Fatal("Expected invalid prefilter error")
+		}
+	})
+}
+
+func TestFindSimilarRecordsGroupBy(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similar_group_by")
+	collection.Fields.Add(&core.TextField{Name: "category"})
+	collection.Fields.Add(&core.SelectField{Name: "tags", Values: []string{"a", "b"}, MaxSelect: 2})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// ordered by their similarity to the query record
+	records := []struct {
+		name     string
+		category string
+		vector   []float32
+	}{
+		{"a1", "a", []float32{1, 0.1}},
+		{"b1", "b", []float32{1, 0.15}},
+		{"a2", "a", []float32{1, 0.2}},
+		{"b2", "b", []float32{1, 0.25}},
+		{"a3", "a", []float32{1, 0.3}},
+		{"c1", "c", []float32{1, 0.5}},
+	}
+
+	query := core.NewRecord(collection)
+	query.Set("title", "query")
+	if err := app.Save(query); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]string{}
+	vectors := map[string][]float32{
+		query.Id: {1, 0},
+		"orphan": {1, 0.05}, // without source record
+	}
+	for _, r := range records {
+		record := core.NewRecord(collection)
+		record.Set("title", r.name)
+		record.Set("category", r.category)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		ids[record.Id] = r.name
+		vectors[record.Id] = r.vector
+	}
+
+	storeTestEmbeddings(t, app, collection.Id, "title", vectors)
+
+	scenarios := []struct {
+		name           string
+		limit          int
+		perGroupLimit  int
+		expectedGroups map[string][]string
+		expectedOrder  []string
+	}{
+		{
+			"per group limit",
+			10,
+			2,
+			map[string][]string{"a": {"a1", "a2"}, "b": {"b1", "b2"}, "c": {"c1"}},
+			[]string{"a", "b", "c"},
+		},
+		{
+			"groups limit with default per group limit",
+			2,
+			0,
+			map[string][]string{"a": {"a1", "a2", "a3"}, "b": {"b1", "b2"}},
+			[]string{"a", "b"},
+		},
+		{
+			"single result",
+			1,
+			1,
+			map[string][]string{"a": {"a1"}},
+			[]string{"a"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			response, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+				CollectionId:  collection.Id,
+				FieldName:     "title",
+				RecordId:      query.Id,
+				Limit:         s.limit,
+				GroupBy:       "category",
+				PerGroupLimit: s.perGroupLimit,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(response.Groups) != len(s.expectedOrder) {
+				t.Fatalf("Expected %d groups, got %+v", len(s.expectedOrder), response.Groups)
+			}
+
+			var expectedResults []string
+			for i, group := range response.Groups {
+				if group.Value != s.expectedOrder[i] {
+					t.Fatalf("[%d] Expected group %q, got %q", i, s.expectedOrder[i], group.Value)
+				}
+
+				names := make([]string, len(group.Results))
+				for j, r := range group.Results {
+					names[j] = ids[r.RecordId]
+				}
+				if !slices.Equal(names, s.expectedGroups[group.Value]) {
+					t.Fatalf("Expected group %q results %v, got %v", group.Value, s.expectedGroups[group.Value], names)
+				}
+
+				expectedResults = append(expectedResults, names...)
+			}
+
+			// the flat results list the grouped records in the groups order
+			names := make([]string, len(response.Results))
+			for i, r := range response.Results {
+				names[i] = ids[r.RecordId]
+			}
+			if !slices.Equal(names, expectedResults) {
+				t.Fatalf("Expected results %v, got %v", expectedResults, names)
+			}
+		})
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		invalid := []core.FindSimilarRequest{
+			{GroupBy: "missing"},
+			{GroupBy: "tags"},
+			{GroupBy: "category", PerGroupLimit: -1},
+			{GroupBy: "category", PerGroupLimit: core.DefaultSimilarityMaxLimit + 1},
+		}
+
+		for i, req := range invalid {
+			req.CollectionId = collection.Id
+			req.FieldName = "title"
+			req.RecordId = query.Id
+
+			if _, err := core.FindSimilarRecords(app, req); err == nil {
+				t.Fatalf("[%d] Expected error for %+v", i, req)
+			}
+		}
+	})
+}
+
+func TestFindSimilarRecordsExceedingCacheEntryLimit(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache and load limits
+	core.ClearEmbeddingCache()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AI.Enabled = true
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similar_large")
+
+	vectors := map[string][]float32{"query": {1, 0}}
+	for i := 0; i < 25; i++ {
+		vectors[fmt.Sprintf("record%d", i)] = []float32{1, float32(i) * 0.1}
+	}
+	storeTestEmbeddings(t, app, collection.Id, "title", vectors)
+
+	search := func() *core.FindSimilarResponse {
+		response, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RecordId:     "query",
+			Limit:        5,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	expectedIds := []string{"record0", "record1", "record2", "record3", "record4"}
+
+	scenarios := []struct {
+		name                 string
+		maxPerEntry          int
+		pageSize             int
+		expectedCacheSkipped bool
+	}{
+		{"exceeding the cache entry limit", 10, 4, true},
+		{"within the cache entry limit", 100, 4, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			core.ClearEmbeddingCache()
+
+			restore := core.SetEmbeddingsLoadLimits(s.maxPerEntry, s.pageSize)
+			defer restore()
+
+			response := search()
+
+			if response.Debug.CacheSkipped != s.expectedCacheSkipped {
+				t.Fatalf("Expected cacheSkipped %v, got %v", s.expectedCacheSkipped, response.Debug.CacheSkipped)
+			}
+
+			if response.Debug.StoredEmbeddings != len(vectors) {
+				t.Fatalf("Expected %d loaded embeddings, got %d", len(vectors), response.Debug.StoredEmbeddings)
+			}
+
+			if response.Debug.ProcessedCount != len(vectors)-1 {
+				t.Fatalf("Expected %d scored embeddings, got %d", len(vectors)-1, response.Debug.ProcessedCount)
+			}
+
+			resultIds := make([]string, len(response.Results))
+			for i, r := range response.Results {
+				resultIds[i] = r.RecordId
+			}
+			if !slices.Equal(resultIds, expectedIds) {
+				t.Fatalf("Expected results %v, got %v", expectedIds, resultIds)
+			}
+
+			// the too large embeddings sets shouldn't be cached
+			cachedEntries := len(core.DumpEmbeddingCache().Entries)
+			if s.expectedCacheSkipped && cachedEntries != 0 {
+				t.Fatalf("Expected no cache entries, got %d", cachedEntries)
+			}
+			if !s.expectedCacheSkipped && cachedEntries != 1 {
+				t.Fatalf("Expected 1 cache entry, got %d", cachedEntries)
+			}
+		})
+	}
+}
+
+func TestGenerateEmbeddingsMinTextLength(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_min_text_length")
+
+	for _, title := range []string{"N/A", "ok", "A longer meaningful title"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name     string
+		minChars int
+		minWords int
+		req      core.EmbeddingRequest
+	}{
+		{
+			"field mode with min chars",
+			5,
+			0,
+			core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "title"},
+		},
+		{
+			"record mode with min words",
+			0,
+			3,
+			core.EmbeddingRequest{CollectionId: collection.Id, Mode: core.EmbeddingModeRecord, Template: "{title}"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app.Settings().AI.EmbeddingMinChars = s.minChars
+			app.Settings().AI.EmbeddingMinWords = s.minWords
+
+			before := len(transport.Inputs())
+
+			result, err := core.GenerateEmbeddings(app, s.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Generated != 1 || result.Skipped != 2 {
+				t.Fatalf("Expected 1 generated and 2 skipped, got %+v", result)
+			}
+
+			if len(result.Errors) != 2 {
+				t.Fatalf("Expected 2 skip reasons, got %v", result.Errors)
+			}
+			for _, e := range result.Errors {
+				if !strings.Contains(e, "too short") {
+					t.Fatalf("Expected too short skip reason, got %q", e)
+				}
+			}
+
+			inputs := transport.Inputs()[before:]
+			if len(inputs) != 1 || !strings.Contains(inputs[0], "A longer meaningful title") {
+				t.Fatalf("Expected only the long text to be embedded, got %v", inputs)
+			}
+		})
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestEmbeddingTimeouts(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingTimeout = 60
+	app.Settings().AI.EmbeddingQueryTimeout = 3
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embedding_timeouts")
+
+	record := core.NewRecord(collection)
+	record.Set("title", "Hello")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "title"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := core.FindSimilarRecords(app, core.FindSimilarRequest{CollectionId: collection.Id, FieldName: "title", Text: "Hello", Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	timeouts := transport.Timeouts()
+	if len(timeouts) != 2 {
+		t.Fatalf("Expected 2 requests with deadline, got %v", timeouts)
+	}
+
+	if bulk := timeouts[0]; bulk <= 50*time.Second || bulk > 60*time.Second {
+		t.Fatalf("Expected the bulk embeddings request to use the 60s timeout, got %v", bulk)
+	}
+
+	if query := timeouts[1]; query <= 0 || query > 3*time.Second {
+		t.Fatalf("Expected the query embedding request to use the 3s timeout, got %v", query)
+	}
+}
+
+func TestIsAppFieldEmbeddable(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name     string
+		field    core.Field
+		auto     bool
+		expected bool
+	}{
+		{"unflagged text", &core.TextField{Name: "test"}, false, false},
+		{"flagged text", &core.TextField{Name: "test", Embeddable: true}, false, true},
+		{"unflagged editor", &core.EditorField{Name: "test"}, false, false},
+		{"non-text", &core.NumberField{Name: "test"}, false, false},
+		{"auto unflagged text", &core.TextField{Name: "test"}, true, true},
+		{"auto flagged text", &core.TextField{Name: "test", Embeddable: true}, true, true},
+		{"auto unflagged editor", &core.EditorField{Name: "test"}, true, true},
+		{"auto non-text", &core.NumberField{Name: "test"}, true, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app.Settings().AI.AutoEmbedTextFields = s.auto
+
+			result := core.IsAppFieldEmbeddable(app, s.field)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsAutoEmbedTextFields(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{})
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_auto_embed")
+	collection.Fields.Add(&core.TextField{Name: "summary"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("summary", "Hello")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	req := core.EmbeddingRequest{CollectionId: collection.Id, FieldName: "summary"}
+
+	if _, err := core.GenerateEmbeddings(app, req); err == nil {
+		t.Fatal("Expected the unflagged field to be rejected without the auto mode")
+	}
+
+	app.Settings().AI.AutoEmbedTextFields = true
+
+	result, err := core.GenerateEmbeddings(app, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Generated != 1 {
+		t.Fatalf("Expected 1 generated embedding, got %+v", result)
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsDegenerateVectors(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, &fakeEmbeddingsTransport{ZeroVectorFor: []string{"broken"}})
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_degenerate_vectors")
+
+	ids := map[string]string{}
+	for _, title := range []string{"valid", "broken"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		ids[title] = record.Id
+	}
+
+	result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Generated != 1 || result.Skipped != 1 {
+		t.Fatalf("Expected 1 generated and 1 skipped, got %+v", result)
+	}
+
+	if len(result.Errors) != 1 ||
+		!strings.Contains(result.Errors[0], ids["broken"]) ||
+		!strings.Contains(result.Errors[0], core.ErrDegenerateEmbedding.Error()) {
+		t.Fatalf("Expected degenerate embedding error for the broken record, got %v", result.Errors)
+	}
+
+	pending, err := core.GetPendingEmbeddingRecordIds(app, collection.Id, "title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0] != ids["broken"] {
+		t.Fatalf("Expected the degenerate embedding to not be stored, got pending %v", pending)
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsPartialBatchResponse(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{MaxVectors: 3}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingBatchSize = 5
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_partial_batch")
+
+	for _, title := range []string{"a", "b", "c", "d", "e"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("without retry", func(t *testing.T) {
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generated != 3 || result.Skipped != 2 {
+			t.Fatalf("Expected 3 generated and 2 skipped, got %+v", result)
+		}
+
+		if len(result.Errors) != 2 {
+			t.Fatalf("Expected 2 errors, got %v", result.Errors)
+		}
+		for _, e := range result.Errors {
+			if !strings.Contains(e, "missing embedding") {
+				t.Fatalf("Expected missing embedding error, got %q", e)
+			}
+		}
+	})
+
+	t.Run("with retry", func(t *testing.T) {
+		before := len(transport.Inputs())
+
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RetryMissing: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generated != 5 || result.Skipped != 0 || len(result.Errors) != 0 {
+			t.Fatalf("Expected 5 generated and no skipped, got %+v", result)
+		}
+
+		// 1 batch with 5 texts + 2 individual retries
+		if submitted := len(transport.Inputs()) - before; submitted != 7 {
+			t.Fatalf("Expected 7 submitted texts, got %d", submitted)
+		}
+	})
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsMissingMiddleBatchItem(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{OmitVectorFor: []string{"bravo"}}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingBatchSize = 3
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_missing_middle_batch_item")
+
+	titles := map[string]string{}
+	for _, title := range []string{"alpha", "bravo", "charlie"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		titles[record.Id] = title
+	}
+
+	embeddingsCollection, err := core.EnsureEmbeddingsCollection(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// checkStored checks that each stored vector belongs to its own record
+	checkStored := func(t *testing.T, expectedCount int) {
+		stored, err := app.FindAllRecords(embeddingsCollection, dbx.HashExp{"collection_id": collection.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(stored) != expectedCount {
+			t.Fatalf("Expected %d stored embeddings, got %d", expectedCount, len(stored))
+		}
+
+		for _, record := range stored {
+			title := titles[record.GetString("record_id")]
+
+			embedding, err := core.GetEmbeddingFromRecord(record)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := fakeEmbedding(title); !slices.Equal(embedding, expected) {
+				t.Fatalf("Expected the %q record embedding %v, got %v", title, expected, embedding)
+			}
+		}
+	}
+
+	t.Run("without retry", func(t *testing.T) {
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generated != 2 || result.Skipped != 1 {
+			t.Fatalf("Expected 2 generated and 1 skipped, got %+v", result)
+		}
+
+		if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "missing embedding") {
+			t.Fatalf("Expected a single missing embedding error, got %v", result.Errors)
+		}
+
+		checkStored(t, 2)
+	})
+
+	t.Run("with retry", func(t *testing.T) {
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RetryMissing: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generated != 3 || result.Skipped != 0 || len(result.Errors) != 0 {
+			t.Fatalf("Expected 3 generated and no skipped, got %+v", result)
+		}
+
+		checkStored(t, 3)
+	})
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsAdaptiveBatchSize(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{RateLimitAbove: 3}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingBatchSize = 8
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_adaptive_batch")
+
+	for i := 0; i < 20; i++ {
+		record := core.NewRecord(collection)
+		record.Set("title", fmt.Sprintf("title %d", i))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId: collection.Id,
+		FieldName:    "title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Generated != 20 || result.Skipped != 0 || len(result.Errors) != 0 {
+		t.Fatalf("Expected 20 generated and no skipped, got %+v", result)
+	}
+
+	if transport.RateLimited() == 0 {
+		t.Fatal("Expected at least one rate limited request")
+	}
+
+	sizes := transport.BatchSizes()
+
+	// the batch size should have been shrunk to 2 (8 -> 4 -> 2)
+	if sizes[0] != 2 {
+		t.Fatalf("Expected the first successful batch to have 2 texts, got %v", sizes)
+	}
+
+	// and then grown back to the max allowed by the rate limit
+	var grown bool
+	for _, size := range sizes {
+		if size > 3 {
+			t.Fatalf("Expected no successful batch with more than 3 texts, got %v", sizes)
+		}
+		if size == 3 {
+			grown = true
+		}
+	}
+	if !grown {
+		t.Fatalf("Expected the batch size to grow back after a success, got %v", sizes)
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsDebug(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+	app.Settings().AI.EmbeddingBatchSize = 2
+	app.Settings().AI.EmbeddingRetryBaseDelay = 1
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embeddings_debug")
+
+	titles := []string{"one", "two words", "three more words", "four", "five six"}
+	for _, title := range titles {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("without debug", func(t *testing.T) {
+		core.ClearEmbeddingCache()
+		app.Store().Set(core.StoreKeyAIHTTPTransport, &fakeEmbeddingsTransport{})
+
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Debug != nil {
+			t.Fatalf("Expected nil debug info, got %+v", result.Debug)
+		}
+	})
+
+	t.Run("with debug", func(t *testing.T) {
+		core.ClearEmbeddingCache()
+
+		delay := 20 * time.Millisecond
+		transport := &fakeEmbeddingsTransport{
+			FailStatus: http.StatusServiceUnavailable,
+			FailFirst:  1,
+			OnRequest:  func() { time.Sleep(delay) },
+		}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Debug:        true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Debug == nil {
+			t.Fatal("Expected the debug info to be populated")
+		}
+
+		batches := result.Debug.Batches
+		if len(batches) != 3 {
+			t.Fatalf("Expected 3 batches, got %+v", batches)
+		}
+
+		// reconstruct the batches texts from the (successful) transport inputs
+		inputs := transport.Inputs()
+		expectedTexts := []int{2, 2, 1}
+		var pos int
+		for i, b := range batches {
+			if b.Texts != expectedTexts[i] {
+				t.Fatalf("[%d] Expected %d texts, got %d", i, expectedTexts[i], b.Texts)
+			}
+
+			var expectedEstimated, expectedPrompt int
+			for _, input := range inputs[pos : pos+b.Texts] {
+				expectedEstimated += core.EstimateEmbeddingTokens(input)
+				expectedPrompt += len(strings.Fields(input))
+			}
+			pos += b.Texts
+
+			if b.EstimatedTokens != expectedEstimated {
+				t.Fatalf("[%d] Expected %d estimated tokens, got %d", i, expectedEstimated, b.EstimatedTokens)
+			}
+
+			if b.PromptTokens != expectedPrompt {
+				t.Fatalf("[%d] Expected %d prompt tokens, got %d", i, expectedPrompt, b.PromptTokens)
+			}
+
+			// the first batch request is retried once after the transient error
+			expectedAttempts := 1
+			if i == 0 {
+				expectedAttempts = 2
+			}
+			if b.Attempts != expectedAttempts {
+				t.Fatalf("[%d] Expected %d attempts, got %d", i, expectedAttempts, b.Attempts)
+			}
+
+			minDuration := float64(delay.Milliseconds())
+			if b.Duration < minDuration || b.Duration > 5000 {
+				t.Fatalf("[%d] Expected duration between %vms and 5000ms, got %vms", i, minDuration, b.Duration)
+			}
+
+			if b.Error != "" {
+				t.Fatalf("[%d] Expected no batch error, got %q", i, b.Error)
+			}
+		}
+	})
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsTokenBatching(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_token_batching")
+
+	// 5 texts with ~3000 estimated tokens and a single text exceeding the whole batch budget
+	contents := make([]string, 0, 6)
+	for i := 0; i < 5; i++ {
+		contents = append(contents, fmt.Sprintf("%d %s", i, strings.Repeat("abc ", 3000)))
+	}
+	contents = append(contents, "long "+strings.Repeat("abc ", 10000))
+
+	for _, content := range contents {
+		record := core.NewRecord(collection)
+		record.Set("content", content)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+		CollectionId: collection.Id,
+		FieldName:    "content",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Generated != 6 || result.Skipped != 0 {
+		t.Fatalf("Expected 6 generated and no skipped, got %+v", result)
+	}
+
+	sizes := transport.BatchSizes()
+	if !slices.Equal(sizes, []int{2, 2, 1, 1}) {
+		t.Fatalf("Expected batch sizes [2 2 1 1], got %v", sizes)
+	}
+
+	inputs := transport.Inputs()
+	var pos int
+	for i, size := range sizes {
+		var tokens int
+		for _, input := range inputs[pos : pos+size] {
+			tokens += core.EstimateEmbeddingTokens(input)
+		}
+		pos += size
+
+		if tokens > core.MaxTokensPerBatch {
+			t.Fatalf("[%d] Expected at most %d batch tokens, got %d", i, core.MaxTokensPerBatch, tokens)
+		}
+	}
+
+	// the too long text should have been truncated
+	last := inputs[len(inputs)-1]
+	if !strings.HasPrefix(last, "long ") || core.EstimateEmbeddingTokens(last) != core.MaxTokensPerBatch {
+		t.Fatalf("Expected the long text to be truncated to %d tokens, got %d", core.MaxTokensPerBatch, core.EstimateEmbeddingTokens(last))
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsRetryTransientErrors(t *testing.T) {
+	scenarios := []struct {
+		name              string
+		status            int
+		failFirst         int
+		maxRetries        int
+		expectedFailed    int
+		expectedGenerated int
+		expectedSkipped   int
+	}{
+		{"429 recovered", http.StatusTooManyRequests, 2, core.DefaultEmbeddingMaxRetries, 2, 5, 0},
+		{"500 recovered", http.StatusInternalServerError, 3, core.DefaultEmbeddingMaxRetries, 3, 5, 0},
+		{"502 recovered", http.StatusBadGateway, 1, core.DefaultEmbeddingMaxRetries, 1, 5, 0},
+		{"503 recovered", http.StatusServiceUnavailable, 5, core.DefaultEmbeddingMaxRetries, 5, 5, 0},
+		{"504 recovered", http.StatusGatewayTimeout, 2, 2, 2, 5, 0},
+		{"retries exhausted", http.StatusServiceUnavailable, 100, 2, 3, 0, 5},
+		{"retries disabled", http.StatusServiceUnavailable, 1, 0, 1, 0, 5},
+		{"non-retryable status", http.StatusBadRequest, 1, core.DefaultEmbeddingMaxRetries, 1, 0, 5},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			core.ClearEmbeddingCache()
+
+			transport := &fakeEmbeddingsTransport{FailStatus: s.status, FailFirst: s.failFirst}
+			app := newTestAIApp(t, transport)
+			app.Settings().AI.EmbeddingMaxRetries = s.maxRetries
+			app.Settings().AI.EmbeddingRetryBaseDelay = 1
+
+			collection := createTestEmbeddingsSourceCollection(t, app, "test_retry_transient")
+
+			for i := 0; i < 5; i++ {
+				record := core.NewRecord(collection)
+				record.Set("title", fmt.Sprintf("title %d", i))
+				if err := app.Save(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := core.GenerateEmbeddings(app, core.EmbeddingRequest{
+				CollectionId: collection.Id,
+				FieldName:    "title",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if failed := transport.Failed(); failed != s.expectedFailed {
+				t.Fatalf("Expected %d failed requests, got %d", s.expectedFailed, failed)
+			}
+
+			if result.Generated != s.expectedGenerated || result.Skipped != s.expectedSkipped {
+				t.Fatalf("Expected %d generated and %d skipped, got %+v", s.expectedGenerated, s.expectedSkipped, result)
+			}
+
+			if s.expectedSkipped == 0 && len(result.Errors) != 0 {
+				t.Fatalf("Expected no errors, got %v", result.Errors)
+			}
+		})
+	}
+}
+
+// note: not parallel because of the shared embeddings cache
+func TestGenerateEmbeddingsCanceled(t *testing.T) {
+	core.ClearEmbeddingCache()
+
+	app := newTestAIApp(t, nil)
+	app.Settings().AI.EmbeddingBatchSize = 2
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_embeddings_canceled")
+
+	for i := 0; i < 6; i++ {
+		record := core.NewRecord(collection)
+		record.Set("title", fmt.Sprintf("canceled record %d", i))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("already canceled", func(t *testing.T) {
+		transport := &fakeEmbeddingsTransport{}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := core.GenerateEmbeddingsWithContext(ctx, app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+		})
+		if !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+
+		if sizes := transport.BatchSizes(); len(sizes) != 0 {
+			t.Fatalf("Expected no embedding requests, got %v", sizes)
+		}
+	})
+
+	t.Run("canceled between batches", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// cancel right after the first batch request
+		transport := &fakeEmbeddingsTransport{OnRequest: cancel}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		_, err := core.GenerateEmbeddingsWithContext(ctx, app, core.EmbeddingRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			RunId:        "test_canceled_run",
+		})
+		if !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+
+		if sizes := transport.BatchSizes(); len(sizes) != 1 {
+			t.Fatalf("Expected a single embedding request, got %v", sizes)
+		}
+
+		status, err := core.FindEmbeddingRunStatus(app, "test_canceled_run")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Status != core.EmbeddingRunStatusCanceled {
+			t.Fatalf("Expected status %q, got %q", core.EmbeddingRunStatusCanceled, status.Status)
+		}
+	})
+
+	t.Run("canceled search", func(t *testing.T) {
+		transport := &fakeEmbeddingsTransport{}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := core.FindSimilarRecordsWithContext(ctx, app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Text:         "canceled",
+			Limit:        10,
+		})
+		if !errors.Is(err, core.ErrAIRequestCanceled) {
+			t.Fatalf("Expected ErrAIRequestCanceled, got %v", err)
+		}
+
+		if inputs := transport.Inputs(); len(inputs) != 0 {
+			t.Fatalf("Expected no embedding requests, got %v", inputs)
+		}
+	})
+}
+
+type fakeEmbeddingsTransport struct {
+	mu     sync.Mutex
+	inputs []string
+
+	// MaxVectors limits the number of the returned vectors per request
+	// to simulate incomplete batch responses (0 means no limit).
+	MaxVectors int
+
+	// OmitVectorFor lists the input texts whose vectors are omitted from the
+	// multi-text responses (to simulate non-trailing missing batch items).
+	OmitVectorFor []string
+
+	// ZeroVectorFor lists the input texts for which to return degenerate all-zero vectors.
+	ZeroVectorFor []string
+
+	// RateLimitAbove responds with 429 to the requests with
+	// more texts than the specified number (0 means no limit).
+	RateLimitAbove int
+
+	// FailStatus is the error status code returned to the first FailFirst requests.
+	FailStatus int
+
+	// FailFirst is the number of the first requests to fail with FailStatus.
+	FailFirst int
+
+	// OnRequest is an optional callback invoked before responding to each request.
+	OnRequest func()
+
+	batchSizes  []int
+	rateLimited int
+	failed      int
+	timeouts    []time.Duration
+}
+
+// Failed returns the number of the requests failed with FailStatus.
+func (f *fakeEmbeddingsTransport) Failed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.failed
+}
+
+// Timeouts returns the (approximate) remaining timeout of each request.
+func (f *fakeEmbeddingsTransport) Timeouts() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]time.Duration(nil), f.timeouts...)
+}
+
+// BatchSizes returns the number of texts of each successful request.
+func (f *fakeEmbeddingsTransport) BatchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]int(nil), f.batchSizes...)
+}
+
+// RateLimited returns the number of the rate limited requests.
+func (f *fakeEmbeddingsTransport) RateLimited() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rateLimited
+}
+
+// Inputs returns all texts that were submitted for embedding.
+func (f *fakeEmbeddingsTransport) Inputs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.inputs...)
+}
+
+func (f *fakeEmbeddingsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body struct {
+		Input []string `json:"input"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := req.Context().Deadline(); ok {
+		f.mu.Lock()
+		f.timeouts = append(f.timeouts, time.Until(deadline))
+		f.mu.Unlock()
+	}
+
+	if f.RateLimitAbove > 0 && len(body.Input) > f.RateLimitAbove {
+		f.mu.Lock()
+		f.rateLimited++
+		f.mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"rate limit reached"}}`)),
+			Request:    req,
+		}, nil
+	}
+
+	f.mu.Lock()
+	if f.failed < f.FailFirst {
+		f.failed++
+		f.mu.Unlock()
+
+		return &http.Response{
+			StatusCode: f.FailStatus,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"temporary error"}}`)),
+			Request:    req,
+		}, nil
+	}
+	f.mu.Unlock()
+
+	if f.OnRequest != nil {
+		f.OnRequest()
+	}
+
+	f.mu.Lock()
+	f.inputs = append(f.inputs, body.Input...)
+	f.batchSizes = append(f.batchSizes, len(body.Input))
+	f.mu.Unlock()
+
+	type item struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	}
+
+	total := len(body.Input)
+	if f.MaxVectors > 0 && total > f.MaxVectors {
+		total = f.MaxVectors
+	}
+
+	data := make([]item, 0, total)
+	for i, text := range body.Input[:total] {
+		if len(body.Input) > 1 && slices.Contains(f.OmitVectorFor, text) {
+			continue
+		}
+		if slices.Contains(f.ZeroVectorFor, text) {
+			data = append(data, item{Embedding: make([]float32, 27), Index: i})
+		} else {
+			data = append(data, item{Embedding: fakeEmbedding(text), Index: i})
+		}
+	}
+
+	// report the words count as the prompt tokens usage
+	var promptTokens int
+	for _, text := range body.Input {
+		promptTokens += len(strings.Fields(text))
+	}
+
+	raw, err := json.Marshal(map[string]any{
+		"data":  data,
+		"model": "test",
+		"usage": map[string]int{"prompt_tokens": promptTokens, "total_tokens": promptTokens},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(raw)),
+		Request:    req,
+	}, nil
+}
+
+// fakeEmbedding returns a deterministic letters frequency vector of the text.
+func fakeEmbedding(text string) []float32 {
+	vector := make([]float32, 27)
+	for _, r := range strings.ToLower(text) {
+		if r >= 'a' && r <= 'z' {
+			vector[r-'a']++
+		} else {
+			vector[26]++
+		}
+	}
+	return vector
+}
@@ -0,0 +1,420 @@
+package core
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// MaxSimilarityBatchQueries is the max number of query texts of a single batch similarity search.
+const MaxSimilarityBatchQueries = 500
+
+// SimilarityBatchQuery represents a single query text of [FindSimilarRecordsBatch].
+type SimilarityBatchQuery struct {
+	Text  string `json:"text"`
+	Limit int    `json:"limit"` // default to 10
+}
+
+// FindSimilarBatchRequest represents a request to find the similar
+// records of multiple query texts in the same collection embeddings.
+type FindSimilarBatchRequest struct {
+	CollectionId string        `json:"collectionId"`
+	FieldName    string        `json:"fieldName,omitempty"` // For field-level search
+	Mode         EmbeddingMode `json:"mode,omitempty"`      // "field", "record", "fields" or "combined"
+	Fields       []string      `json:"fields,omitempty"`    // For multi-field search
+
+	// Queries are the query texts with their own results limit.
+	Queries []SimilarityBatchQuery `json:"queries"`
+
+	// ScoreScale is the scale of the returned similarity scores ("raw" by default or "percent")
+	ScoreScale SimilarityScoreScale `json:"scoreScale,omitempty"`
+
+	// MinSimilarity is an optional min raw similarity score of the returned results
+	// (see [FindSimilarRequest.MinSimilarity]).
+	MinSimilarity float32 `json:"minSimilarity,omitempty"`
+
+	// Metric is the vectors comparison metric ("cosine", "dot" or "euclidean").
+	//
+	// Default to the [DefaultSimilarityMetric] of the collection embedding model.
+	Metric SimilarityMetric `json:"metric,omitempty"`
+
+	// EmbeddingsCollection is an optional custom embeddings collection name
+	// to search in (default to [EmbeddingsCollectionName]).
+	EmbeddingsCollection string `json:"embeddingsCollection,omitempty"`
+
+	// JSONPath is an optional json field path selector for the field-level and combined modes.
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Prefilter is an optional filter expression of the source collection records
+	// limiting the scored candidates of all queries (see [FindSimilarRequest.Prefilter]).
+	Prefilter string `json:"prefilter,omitempty"`
+
+	// PrefilterParams are the optional Prefilter placeholder values.
+	PrefilterParams map[string]any `json:"prefilterParams,omitempty"`
+
+	// StrictModel fails the search with [ErrEmbeddingModelMismatch] instead of
+	// returning a warning when the stored embeddings were generated with
+	// a different model than the query one.
+	StrictModel bool `json:"strictModel,omitempty"`
+}
+
+// SimilarityBatchResult represents the similar records of a single batch query text.
+type SimilarityBatchResult struct {
+	Text    string          `json:"text"`
+	Results []SimilarRecord `json:"results"`
+}
+
+// FindSimilarBatchResponse represents the response from a batch similarity search.
+type FindSimilarBatchResponse struct {
+	// Results are the results of each query in the same order as the request Queries.
+	Results []SimilarityBatchResult `json:"results"`
+
+	// Metric is the resolved vectors comparison metric of the search.
+	Metric SimilarityMetric `json:"metric"`
+
+	// Warnings lists the non-fatal search issues (ex. an embedding model mismatch).
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// FindSimilarRecordsBatch finds the records similar to each of the request query texts.
+//
+// The query texts are embedded with as few embeddings requests as the batch
+// limits allow and the collection embeddings are loaded (or streamed) only
+// once and scored against every query, which is significantly faster than
+// running a separate [FindSimilarRecords] search for each text.
+func FindSimilarRecordsBatch(ctx context.Context, app App, req FindSimilarBatchRequest) (*FindSimilarBatchResponse, error) {
+	settings := app.Settings()
+
+	if !settings.AI.Enabled {
+		return nil, errors.New("AI features are not enabled")
+	}
+
+	if len(req.Queries) == 0 {
+		return nil, errors.New("at least one query must be provided")
+	}
+	if len(req.Queries) > MaxSimilarityBatchQueries {
+		return nil, fmt.Errorf("too many queries (max %d)", MaxSimilarityBatchQueries)
+	}
+
+	maxLimit := settings.AI.SimilarityMaxLimitOrDefault()
+	for i, query := range req.Queries {
+		if query.Text == "" {
+			return nil, fmt.Errorf("queries[%d]: text is required", i)
+		}
+		if err := checkAITextLength(fmt.Sprintf("queries[%d].text", i), query.Text, MaxEmbeddingQueryTextLength); err != nil {
+			return nil, err
+		}
+		if query.Limit < 0 || query.Limit > maxLimit {
+			return nil, fmt.Errorf("queries[%d]: limit must be between 1 and %d", i, maxLimit)
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(req.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	embeddingsName, err := resolveEmbeddingsCollectionName(req.EmbeddingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldNames, err := resolveSimilarityFieldNames(req.Mode, req.FieldName, req.Fields, req.JSONPath)
+	if err != nil {
+		return nil, err
+	}
+
+	scoreScale := req.ScoreScale
+	if scoreScale == "" {
+		scoreScale = SimilarityScoreScaleRaw
+	}
+	if scoreScale != SimilarityScoreScaleRaw && scoreScale != SimilarityScoreScalePercent {
+		return nil, fmt.Errorf("invalid score scale: %s (must be 'raw' or 'percent')", scoreScale)
+	}
+
+	// Use the same model as the one of the stored collection embedding config (if any)
+	model := settings.AI.EmbeddingModel
+	if config, err := FindEmbeddingConfig(app, collection.Id); err == nil && config.Model != "" {
+		model = config.Model
+	}
+
+	metric := req.Metric
+	if metric == "" {
+		metric = DefaultSimilarityMetric(model)
+	}
+	if metric != SimilarityMetricCosine && metric != SimilarityMetricDot && metric != SimilarityMetricEuclidean {
+		return nil, fmt.Errorf("invalid metric: %s (must be 'cosine', 'dot' or 'euclidean')", metric)
+	}
+
+	// Detect the vectors from different embedding spaces (ex. after an EmbeddingModel change)
+	storedModels, err := findStoredEmbeddingModels(app, embeddingsName, collection.Id, fieldNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the stored embedding models: %w", err)
+	}
+	var warnings []string
+	if warning := embeddingModelWarning(model, storedModels, true); warning != "" {
+		if req.StrictModel {
+			return nil, fmt.Errorf("%w: %s", ErrEmbeddingModelMismatch, warning)
+		}
+		warnings = append(warnings, warning)
+	}
+
+	texts := make([]string, len(req.Queries))
+	for i, query := range req.Queries {
+		texts[i] = preprocessEmbeddingText(query.Text)
+	}
+	queryEmbeddings, err := embedBatchQueryTexts(ctx, app, model, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the query embeddings: %w", err)
+	}
+
+	// Resolve the structured prefilter candidates (if any)
+	var candidates map[string]struct{}
+	if req.Prefilter != "" {
+		candidates, err = findSimilarityCandidates(app, collection, req.Prefilter, req.PrefilterParams, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Score the embeddings of each field name against all queries and keep
+	// only the top limit records (with their max score) of each query
+	tops := make([]*similarTopK, len(req.Queries))
+	for i, query := range req.Queries {
+		limit := query.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		tops[i] = newSimilarTopK(limit)
+	}
+
+	for _, fieldName := range fieldNames {
+		err := scanEmbeddings(app, embeddingsName, collection.Id, fieldName, nil, func(chunk []CachedEmbedding) error {
+			if candidates != nil {
+				filtered := make([]CachedEmbedding, 0, min(len(candidates), len(chunk)))
+				for _, e := range chunk {
+					if _, ok := candidates[e.RecordId]; ok {
+						filtered = append(filtered, e)
+					}
+				}
+				chunk = filtered
+			}
+
+			for i, queryEmbedding := range queryEmbeddings {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				for _, result := range scoreEmbeddings(queryEmbedding, chunk, "", metric) {
+					// Drop the results below the min similarity threshold (if any)
+					if req.MinSimilarity != 0 && result.Similarity < req.MinSimilarity {
+						continue
+					}
+
+					tops[i].Add(result)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	toPercent := similarityPercentFunc(metric)
+
+	response := &FindSimilarBatchResponse{
+		Results:  make([]SimilarityBatchResult, len(req.Queries)),
+		Metric:   metric,
+		Warnings: warnings,
+	}
+
+	for i, query := range req.Queries {
+		results := tops[i].Results()
+
+		if scoreScale == SimilarityScoreScalePercent {
+			for j := range results {
+				results[j].Similarity = toPercent(results[j].Similarity)
+			}
+		}
+
+		response.Results[i] = SimilarityBatchResult{Text: query.Text, Results: results}
+	}
+
+	return response, nil
+}
+
+// embedBatchQueryTexts generates the embeddings of the batch search query texts.
+//
+// The texts are split in requests by the same batch size and tokens
+// limits as [GenerateEmbeddingsWithContext] and the transient provider
+// errors are retried with backoff (shrinking the batch size on 429).
+//
+// Unlike the embeddings generation, a batch that fails after all
+// retries fails the entire search.
+func embedBatchQueryTexts(ctx context.Context, app App, model string, texts []string) ([][]float32, error) {
+	settings := app.Settings()
+
+	batchSize := newAdaptiveBatchSize(settings.AI.EmbeddingBatchSize)
+	maxRetries := settings.AI.EmbeddingMaxRetriesOrDefault()
+	retryBaseDelay := settings.AI.EmbeddingRetryBaseDelayDuration()
+
+	result := make([][]float32, 0, len(texts))
+
+	var retries int
+
+	for pos := 0; pos < len(texts); {
+		// Close the batch when either the batch size or the tokens budget is reached
+		// (a batch has always at least one text)
+		end := pos
+		var batchTokens int
+		for end < len(texts) && end-pos < batchSize.Current() {
+			tokens := EstimateEmbeddingTokens(texts[end])
+			if end > pos && batchTokens+tokens > MaxTokensPerBatch {
+				break
+			}
+			batchTokens += tokens
+			end++
+		}
+
+		embeddings, err := callOpenAIEmbeddings(ctx, app, model, texts[pos:end], settings.AI.EmbeddingTimeoutDuration())
+
+		var retryErr *aiRetryableError
+		if errors.As(err, &retryErr) && retries < maxRetries {
+			retries++
+			if retryErr.StatusCode == http.StatusTooManyRequests {
+				batchSize.Shrink()
+			}
+			if err := sleepWithContext(ctx, retryErr.Backoff(retries, retryBaseDelay)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.IndexFunc(embeddings, func(v []float32) bool { return v == nil }); i >= 0 {
+			return nil, fmt.Errorf("missing query embedding for text %d", pos+i)
+		}
+
+		retries = 0
+		batchSize.Grow()
+
+		result = append(result, embeddings...)
+		pos = end
+	}
+
+	return result, nil
+}
+
+// similarTopK keeps the limit most similar records (with their max similarity)
+// out of a stream of scored records, using memory proportional only to the limit.
+type similarTopK struct {
+	limit int
+
+	// items is a min-heap with the least similar kept record at the top.
+	items []SimilarRecord
+
+	// positions are the current heap indexes of the kept records.
+	positions map[string]int
+}
+
+// newSimilarTopK creates a new top-k collector keeping at most limit records.
+func newSimilarTopK(limit int) *similarTopK {
+	return &similarTopK{
+		limit:     limit,
+		items:     make([]SimilarRecord, 0, limit),
+		positions: make(map[string]int, limit),
+	}
+}
+
+// Add adds a scored record to the collector.
+//
+// If the record is already kept, only its higher similarity is retained.
+//
+// A previously dropped record could be added again only with a higher
+// similarity than the least kept one at the time of its drop, which
+// is also higher than its dropped similarity, so the max per record
+// is preserved without remembering the dropped records.
+func (t *similarTopK) Add(record SimilarRecord) {
+	if t.limit <= 0 {
+		return
+	}
+
+	if i, ok := t.positions[record.RecordId]; ok {
+		if record.Similarity > t.items[i].Similarity {
+			t.items[i].Similarity = record.Similarity
+			heap.Fix(t, i)
+		}
+		return
+	}
+
+	if len(t.items) < t.limit {
+		heap.Push(t, record)
+		return
+	}
+
+	if !similarRecordLess(t.items[0], record) {
+		return
+	}
+
+	delete(t.positions, t.items[0].RecordId)
+	t.items[0] = record
+	t.positions[record.RecordId] = 0
+	heap.Fix(t, 0)
+}
+
+// Results returns the kept records sorted by their similarity in descending order
+// (see [sortSimilarRecords]).
+func (t *similarTopK) Results() []SimilarRecord {
+	results := make([]SimilarRecord, len(t.items))
+	copy(results, t.items)
+
+	sortSimilarRecords(results)
+
+	return results
+}
+
+// similarRecordLess reports whether a ranks after b in the [sortSimilarRecords] order.
+func similarRecordLess(a, b SimilarRecord) bool {
+	if a.Similarity != b.Similarity {
+		return a.Similarity < b.Similarity
+	}
+	return a.RecordId > b.RecordId
+}
+
+// Len implements [heap.Interface].
+func (t *similarTopK) Len() int {
+	return len(t.items)
+}
+
+// Less implements [heap.Interface].
+func (t *similarTopK) Less(i, j int) bool {
+	return similarRecordLess(t.items[i], t.items[j])
+}
+
+// Swap implements [heap.Interface].
+func (t *similarTopK) Swap(i, j int) {
+	t.items[i], t.items[j] = t.items[j], t.items[i]
+	t.positions[t.items[i].RecordId] = i
+	t.positions[t.items[j].RecordId] = j
+}
+
+// Push implements [heap.Interface].
+func (t *similarTopK) Push(x any) {
+	record := x.(SimilarRecord)
+	t.positions[record.RecordId] = len(t.items)
+	t.items = append(t.items, record)
+}
+
+// Pop implements [heap.Interface].
+func (t *similarTopK) Pop() any {
+	last := t.items[len(t.items)-1]
+	t.items = t.items[:len(t.items)-1]
+	delete(t.positions, last.RecordId)
+	return last
+}
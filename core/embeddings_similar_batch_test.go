@@ -0,0 +1,206 @@
+package core_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestFindSimilarRecordsBatch(t *testing.T) {
+	// note: not parallel because of the shared embeddings cache
+	core.ClearEmbeddingCache()
+	defer core.ClearEmbeddingCache()
+
+	transport := &fakeEmbeddingsTransport{}
+	app := newTestAIApp(t, transport)
+	app.Settings().AI.EmbeddingModel = "test"
+
+	collection := createTestEmbeddingsSourceCollection(t, app, "test_similarity_batch")
+
+	storeTestEmbeddings(t, app, collection.Id, "title", map[string][]float32{
+		"r1": fakeEmbedding("apple"),
+		"r2": fakeEmbedding("banana"),
+		"r3": fakeEmbedding("cherry"),
+		"r4": fakeEmbedding("apples"),
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		invalid := []core.FindSimilarBatchRequest{
+			{CollectionId: collection.Id, FieldName: "title"},
+			{CollectionId: collection.Id, FieldName: "title", Queries: []core.SimilarityBatchQuery{{Text: ""}}},
+			{CollectionId: collection.Id, FieldName: "title", Queries: []core.SimilarityBatchQuery{{Text: "apple", Limit: -1}}},
+			{CollectionId: collection.Id, Queries: []core.SimilarityBatchQuery{{Text: "apple"}}},
+			{CollectionId: "missing", FieldName: "title", Queries: []core.SimilarityBatchQuery{{Text: "apple"}}},
+			{CollectionId: collection.Id, FieldName: "title", Metric: "invalid", Queries: []core.SimilarityBatchQuery{{Text: "apple"}}},
+			{CollectionId: collection.Id, FieldName: "title", Queries: make([]core.SimilarityBatchQuery, core.MaxSimilarityBatchQueries+1)},
+		}
+
+		for i, req := range invalid {
+			if _, err := core.FindSimilarRecordsBatch(context.Background(), app, req); err == nil {
+				t.Fatalf("[%d] Expected error, got nil", i)
+			}
+		}
+	})
+
+	t.Run("aligned results", func(t *testing.T) {
+		before := len(transport.BatchSizes())
+
+		response, err := core.FindSimilarRecordsBatch(context.Background(), app, core.FindSimilarBatchRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Queries: []core.SimilarityBatchQuery{
+				{Text: "apple", Limit: 2},
+				{Text: "banana", Limit: 1},
+				{Text: "cherry"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if batches := transport.BatchSizes()[before:]; !slices.Equal(batches, []int{3}) {
+			t.Fatalf("Expected a single embeddings request with 3 texts, got %v", batches)
+		}
+
+		expected := []struct {
+			text string
+			ids  []string
+		}{
+			{"apple", []string{"r1", "r4"}},
+			{"banana", []string{"r2"}},
+			{"cherry", []string{"r3"}},
+		}
+
+		if len(response.Results) != len(expected) {
+			t.Fatalf("Expected %d results, got %v", len(expected), response.Results)
+		}
+
+		for i, e := range expected {
+			result := response.Results[i]
+
+			if result.Text != e.text {
+				t.Fatalf("[%d] Expected text %q, got %q", i, e.text, result.Text)
+			}
+
+			if i == 2 {
+				// default limit
+				if len(result.Results) != 4 || result.Results[0].RecordId != e.ids[0] {
+					t.Fatalf("[%d] Expected 4 results starting with %s, got %v", i, e.ids[0], result.Results)
+				}
+				continue
+			}
+
+			ids := make([]string, len(result.Results))
+			for j, r := range result.Results {
+				ids[j] = r.RecordId
+			}
+
+			if !slices.Equal(ids, e.ids) {
+				t.Fatalf("[%d] Expected %v, got %v", i, e.ids, ids)
+			}
+		}
+	})
+
+	t.Run("chunked and retried query embeddings", func(t *testing.T) {
+		limited := &fakeEmbeddingsTransport{RateLimitAbove: 1}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, limited)
+		defer app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		app.Settings().AI.EmbeddingBatchSize = 2
+		app.Settings().AI.EmbeddingRetryBaseDelay = 1
+		defer func() {
+			app.Settings().AI.EmbeddingBatchSize = 0
+			app.Settings().AI.EmbeddingRetryBaseDelay = 0
+		}()
+
+		response, err := core.FindSimilarRecordsBatch(context.Background(), app, core.FindSimilarBatchRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Queries: []core.SimilarityBatchQuery{
+				{Text: "apple", Limit: 1},
+				{Text: "banana", Limit: 1},
+				{Text: "cherry", Limit: 1},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if limited.RateLimited() == 0 {
+			t.Fatal("Expected at least one rate limited request")
+		}
+
+		if inputs := limited.Inputs(); !slices.Equal(inputs, []string{"apple", "banana", "cherry"}) {
+			t.Fatalf("Expected the query texts to be embedded once in order, got %v", inputs)
+		}
+
+		for i, id := range []string{"r1", "r2", "r3"} {
+			if len(response.Results[i].Results) != 1 || response.Results[i].Results[0].RecordId != id {
+				t.Fatalf("[%d] Expected %s, got %v", i, id, response.Results[i].Results)
+			}
+		}
+	})
+
+	t.Run("max score per record across the combined fields", func(t *testing.T) {
+		storeTestEmbeddings(t, app, collection.Id, core.RecordLevelFieldName, map[string][]float32{
+			"r2": fakeEmbedding("apple"),
+			"r3": fakeEmbedding("zzz"),
+		})
+
+		response, err := core.FindSimilarRecordsBatch(context.Background(), app, core.FindSimilarBatchRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			Mode:         core.EmbeddingModeCombined,
+			Queries:      []core.SimilarityBatchQuery{{Text: "apple", Limit: 2}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(response.Results[0].Results))
+		for i, r := range response.Results[0].Results {
+			ids[i] = r.RecordId
+		}
+
+		// r1 and r2 are both exact matches (r2 through its record-level embedding)
+		if !slices.Equal(ids, []string{"r1", "r2"}) {
+			t.Fatalf("Expected [r1 r2], got %v", ids)
+		}
+	})
+
+	t.Run("same scores as the single search", func(t *testing.T) {
+		batch, err := core.FindSimilarRecordsBatch(context.Background(), app, core.FindSimilarBatchRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			ScoreScale:   core.SimilarityScoreScalePercent,
+			Queries:      []core.SimilarityBatchQuery{{Text: "apple", Limit: 4}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		single, err := core.FindSimilarRecords(app, core.FindSimilarRequest{
+			CollectionId: collection.Id,
+			FieldName:    "title",
+			ScoreScale:   core.SimilarityScoreScalePercent,
+			Text:         "apple",
+			Limit:        4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(batch.Results[0].Results) != len(single.Results) {
+			t.Fatalf("Expected %d results, got %v", len(single.Results), batch.Results[0].Results)
+		}
+
+		for i, r := range single.Results {
+			got := batch.Results[0].Results[i]
+			if got.RecordId != r.RecordId || got.Similarity != r.Similarity {
+				t.Fatalf("[%d] Expected %s (%v), got %s (%v)", i, r.RecordId, r.Similarity, got.RecordId, got.Similarity)
+			}
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"strings"
+)
+
+// embeddingTemplateResolver returns the value of the named template field
+// and whether such field exists.
+type embeddingTemplateResolver func(name string) (value string, ok bool)
+
+// renderEmbeddingTemplate renders a record-level embedding text template.
+//
+// The template text is concatenated as it is with the following tags:
+//
+//	{field}               - the field value
+//	{field|other|"text"}  - the first non-empty field value or "text" literal (aka. default values)
+//	{?field}...{/field}   - the block content is rendered only if the field value is not empty
+//	{!field}...{/field}   - the block content is rendered only if the field value is empty
+//
+// For example:
+//
+//	{brand} {model|"Unknown model"}{?year} ({year}){/year}
+//
+// The blocks could be nested. Tags with unknown fields, invalid
+// expressions or unclosed blocks are rendered as plain text.
+func renderEmbeddingTemplate(template string, resolve embeddingTemplateResolver) string {
+	var sb strings.Builder
+
+	renderEmbeddingTemplateBlock(&sb, template, 0, "", resolve)
+
+	return sb.String()
+}
+
+// renderEmbeddingTemplateBlock renders the template starting at pos until
+// the {/closing} tag (or the end of the template if not found).
+//
+// Returns the position after the closing tag and whether it was found.
+func renderEmbeddingTemplateBlock(sb *strings.Builder, template string, pos int, closing string, resolve embeddingTemplateResolver) (int, bool) {
+	for pos < len(template) {
+		start := strings.IndexByte(template[pos:], '{')
+		if start < 0 {
+			sb.WriteString(template[pos:])
+			return len(template), false
+		}
+		start += pos
+		sb.WriteString(template[pos:start])
+
+		end := embeddingTemplateTagEnd(template, start+1)
+		if end < 0 {
+			// not a tag (ex. "{{field}}" or a single "{")
+			sb.WriteByte('{')
+			pos = start + 1
+			continue
+		}
+
+		tag := template[start+1 : end]
+		next := end + 1
+
+		switch {
+		case strings.HasPrefix(tag, "/"):
+			if closing != "" && strings.TrimSpace(tag[1:]) == closing {
+				return next, true
+			}
+			sb.WriteString(template[start:next])
+		case strings.HasPrefix(tag, "?"), strings.HasPrefix(tag, "!"):
+			name := strings.TrimSpace(tag[1:])
+
+			value, ok := resolve(name)
+			if !ok {
+				sb.WriteString(template[start:next])
+				break
+			}
+
+			var block strings.Builder
+			blockEnd, closed := renderEmbeddingTemplateBlock(&block, template, next, name, resolve)
+			if !closed {
+				sb.WriteString(template[start:next])
+				break
+			}
+
+			if (tag[0] == '?') == (strings.TrimSpace(value) != "") {
+				sb.WriteString(block.String())
+			}
+			next = blockEnd
+		default:
+			value, ok := evalEmbeddingTemplateExpr(tag, resolve)
+			if ok {
+				sb.WriteString(value)
+			} else {
+				sb.WriteString(template[start:next])
+			}
+		}
+
+		pos = next
+	}
+
+	return pos, false
+}
+
+// embeddingTemplateTagEnd returns the position of the "}" closing the tag
+// starting at pos (ignoring the ones in "..." literals).
+//
+// Returns -1 if the tag is not closed or contains a nested "{".
+func embeddingTemplateTagEnd(template string, pos int) int {
+	var inQuotes bool
+
+	for i := pos; i < len(template); i++ {
+		switch template[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case '{':
+			if !inQuotes {
+				return -1
+			}
+		case '}':
+			if !inQuotes {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// evalEmbeddingTemplateExpr evaluates a "|" separated list of field names
+// and "..." literals, returning the first non-empty value.
+//
+// Returns false if the expression has an unknown field or an empty term.
+func evalEmbeddingTemplateExpr(expr string, resolve embeddingTemplateResolver) (string, bool) {
+	var result string
+
+	for _, term := range splitEmbeddingTemplateExpr(expr) {
+		term = strings.TrimSpace(term)
+
+		var value string
+		if len(term) >= 2 && term[0] == '"' && term[len(term)-1] == '"' {
+			value = term[1 : len(term)-1]
+		} else {
+			v, ok := resolve(term)
+			if !ok {
+				return "", false
+			}
+			value = v
+		}
+
+		// keep validating the rest of the terms so that
+		// invalid expressions are always rendered as text
+		if result == "" && strings.TrimSpace(value) != "" {
+			result = value
+		}
+	}
+
+	return result, true
+}
+
+// splitEmbeddingTemplateExpr splits the expression by "|" outside of the "..." literals.
+func splitEmbeddingTemplateExpr(expr string) []string {
+	var terms []string
+	var inQuotes bool
+
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case '|':
+			if !inQuotes {
+				terms = append(terms, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(terms, expr[start:])
+}
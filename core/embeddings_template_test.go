@@ -0,0 +1,65 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestGenerateRecordTextTemplate(t *testing.T) {
+	t.Parallel()
+
+	collection := core.NewBaseCollection("test_template")
+	collection.Fields.Add(
+		&core.TextField{Name: "brand"},
+		&core.TextField{Name: "model"},
+		&core.NumberField{Name: "year"},
+		&core.TextField{Name: "empty"},
+		&core.EditorField{Name: "description"},
+	)
+
+	record := core.NewRecord(collection)
+	record.Set("brand", "Acme")
+	record.Set("model", "Rocket")
+	record.Set("year", 2024)
+	record.Set("empty", "  ")
+	record.Set("description", "<p>Very <b>fast</b></p>")
+
+	scenarios := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"plain placeholders", "{brand} {model} ({year})", "Acme Rocket (2024)"},
+		{"editor field", "{description}", "Very fast"},
+		{"unknown field", "{brand} {missing}", "Acme {missing}"},
+		{"non-tag braces", `{{brand}} {"a": 1} {} {brand`, `{Acme} {"a": 1} {} {brand`},
+		{"default literal", `{empty|"n/a"} {brand|"n/a"}`, "n/a Acme"},
+		{"default field", `{empty|model|"n/a"}`, "Rocket"},
+		{"default with special characters", `{empty|"a|b}"}`, "a|b}"},
+		{"default without non-empty value", `[{empty|empty}]`, "[]"},
+		{"default with unknown field", `{empty|missing|"n/a"}`, `{empty|missing|"n/a"}`},
+		{"default with empty term", `{brand|}`, `{brand|}`},
+		{"non-empty conditional", "{brand}{?year} ({year}){/year}", "Acme (2024)"},
+		{"empty conditional", "{brand}{?empty} ({empty}){/empty}", "Acme"},
+		{"negated conditional", "{!empty}no value{/empty}{!brand}no brand{/brand}", "no value"},
+		{"nested conditionals", "{?brand}{brand}{?model} {model}{?empty}!{/empty}{/model}{/brand}", "Acme Rocket"},
+		{"unclosed conditional", "{?brand}{brand}", "{?brand}Acme"},
+		{"mismatched closing tag", "{?brand}{brand}{/model}{/brand}", "Acme{/model}"},
+		{"conditional with unknown field", "{?missing}x{/missing}", "{?missing}x{/missing}"},
+		{
+			"combined",
+			`{brand} {model}{?year} ({year}){/year}{!description} - no description{/description}{?description}: {description}{/description} [{empty|"new"}]`,
+			"Acme Rocket (2024): Very fast [new]",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := core.GenerateRecordText(record, collection, s.template)
+			if result != s.expected {
+				t.Fatalf("Expected\n%q\ngot\n%q", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// This file exposes some of the unexported package helpers
+// so that they could be tested from the core_test package.
+
+func BatchTexts(texts []string, batchSize int) [][]string {
+	return batchTexts(texts, batchSize)
+}
+
+func SimilarityToPercent(similarity float32) float32 {
+	return similarityToPercent(similarity)
+}
+
+func StripHTMLWithLimits(s string, maxSize int, maxTags int) string {
+	return stripHTMLWithLimits(s, maxSize, maxTags)
+}
+
+func MultiplyArchetypes(archetypes []map[string]any, fields []SeedFieldInfo, count int) []map[string]any {
+	return multiplyArchetypes(archetypes, fields, count)
+}
+
+func MultiplyArchetypesWithRand(archetypes []map[string]any, fields []SeedFieldInfo, count int, localRand *rand.Rand) []map[string]any {
+	return multiplyArchetypesWithRand(archetypes, fields, count, localRand, ArchetypeSelectionRandom, nil)
+}
+
+func MultiplyArchetypesWithSelection(archetypes []map[string]any, fields []SeedFieldInfo, count int, localRand *rand.Rand, selection string) []map[string]any {
+	return multiplyArchetypesWithRand(archetypes, fields, count, localRand, selection, nil)
+}
+
+func EncodeEmbedding(embedding []float32, compression string) (any, error) {
+	return encodeEmbedding(embedding, compression)
+}
+
+func GetEmbeddingFromRecord(record *Record) ([]float32, error) {
+	return getEmbeddingFromRecord(record)
+}
+
+func ApplySeedTimeSeries(records []map[string]any, ts SeedTimeSeries, localRand *rand.Rand) {
+	applySeedTimeSeries(records, ts, localRand)
+}
+
+// CacheArchetypes stores the provided archetypes in the global archetypes cache
+// so that the hybrid seed generation could be tested without calling the AI provider.
+func CacheArchetypes(collection *Collection, archetypes []map[string]any) {
+	fields := extractSeedFieldsInfo(collection)
+
+	globalArchetypeCache.Set(collection.Id, &CachedArchetypes{
+		SchemaHash: computeSchemaHash(fields),
+		Archetypes: archetypes,
+		Fields:     fields,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// SetEmbeddingsLoadLimits temporarily changes the embeddings cache per entry
+// and load page size limits and returns a function to restore them.
+func SetEmbeddingsLoadLimits(maxPerEntry int, pageSize int) (restore func()) {
+	oldMaxPerEntry, oldPageSize := embeddingCacheMaxPerEntry, embeddingsLoadPageSize
+
+	embeddingCacheMaxPerEntry, embeddingsLoadPageSize = maxPerEntry, pageSize
+
+	return func() {
+		embeddingCacheMaxPerEntry, embeddingsLoadPageSize = oldMaxPerEntry, oldPageSize
+	}
+}
+
+// CheckAIConnection is the same as [TestAIConnection] but with a custom http transport.
+func CheckAIConnection(transport http.RoundTripper, config AIConfig) error {
+	return testAIConnection(&http.Client{Transport: transport}, config)
+}
+
+func ExtractEmbeddingJSONPathText(raw string, path string) (string, error) {
+	segments, err := parseEmbeddingJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+	return extractJSONPathText([]byte(raw), segments), nil
+}
+
+// SetEmbeddingCacheEntry stores the embeddings in the global embeddings cache.
+func SetEmbeddingCacheEntry(collectionId, fieldName string, embeddings []CachedEmbedding) bool {
+	return embeddingCache.Set(collectionId, fieldName, embeddings)
+}
+
+// GetEmbeddingCacheEntry returns the global embeddings cache entry embeddings.
+func GetEmbeddingCacheEntry(collectionId, fieldName string) ([]CachedEmbedding, bool) {
+	return embeddingCache.Get(collectionId, fieldName)
+}
+
+// EmbeddingMemoryOverhead is the estimated memory overhead per cached embedding.
+const EmbeddingMemoryOverhead = embeddingMemoryOverhead
+
+// GetEmbeddingCacheInfo returns the global embeddings cache info.
+func GetEmbeddingCacheInfo() *CacheInfo {
+	return embeddingCache.Info()
+}
+
+// InvalidateEmbeddingCacheEntry removes the global embeddings cache entry.
+func InvalidateEmbeddingCacheEntry(collectionId, fieldName string) {
+	embeddingCache.Invalidate(collectionId, fieldName)
+}
+
+// ExpireEmbeddingCacheEntry moves the last access time of the
+// global embeddings cache entry before the cache TTL.
+func ExpireEmbeddingCacheEntry(collectionId, fieldName string) {
+	embeddingCache.mu.Lock()
+	defer embeddingCache.mu.Unlock()
+
+	if entry, ok := embeddingCache.cache[cacheKey(collectionId, fieldName)]; ok {
+		entry.accessedAt = time.Now().Add(-EmbeddingCacheTTL - time.Second)
+	}
+}
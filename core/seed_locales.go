@@ -0,0 +1,128 @@
+package core
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// seedLocale holds the localized values of the identity seed placeholders
+// (gofakeit itself generates only English/US-centric values).
+type seedLocale struct {
+	// Name is the human readable locale name used in the AI prompts (ex. "German").
+	Name string
+
+	FirstNames []string
+	LastNames  []string
+	Cities     []string
+	Country    string
+
+	// PhoneFormat is the phone number format where each "#" is replaced with a random digit.
+	PhoneFormat string
+
+	// FamilyNameFirst indicates whether the full name starts with the family name.
+	FamilyNameFirst bool
+}
+
+// seedLocales lists the supported seed data locales by their language code.
+var seedLocales = map[string]*seedLocale{
+	"de": {
+		Name: "German",
+		FirstNames: []string{
+			"Lukas", "Leon", "Finn", "Jonas", "Paul", "Felix", "Maximilian", "Elias", "Noah", "Ben",
+			"Mia", "Emma", "Hannah", "Sophia", "Lena", "Lea", "Marie", "Anna", "Laura", "Johanna",
+		},
+		LastNames: []string{
+			"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann",
+			"Schäfer", "Koch", "Bauer", "Richter", "Klein", "Wolf", "Schröder", "Neumann", "Schwarz", "Zimmermann",
+		},
+		Cities: []string{
+			"Berlin", "Hamburg", "München", "Köln", "Frankfurt am Main", "Stuttgart", "Düsseldorf", "Leipzig",
+			"Dortmund", "Essen", "Bremen", "Dresden", "Hannover", "Nürnberg", "Freiburg im Breisgau",
+		},
+		Country:     "Deutschland",
+		PhoneFormat: "+49 1## #######",
+	},
+	"ja": {
+		Name: "Japanese",
+		FirstNames: []string{
+			"翔太", "蓮", "大翔", "悠真", "陽翔", "湊", "樹", "健太", "拓海", "颯太",
+			"陽菜", "結衣", "さくら", "美咲", "葵", "凛", "花子", "愛", "優奈", "芽依",
+		},
+		LastNames: []string{
+			"佐藤", "鈴木", "高橋", "田中", "伊藤", "渡辺", "山本", "中村", "小林", "加藤",
+			"吉田", "山田", "佐々木", "山口", "松本", "井上", "木村", "林", "斎藤", "清水",
+		},
+		Cities: []string{
+			"東京", "横浜", "大阪", "名古屋", "札幌", "福岡", "神戸", "京都",
+			"川崎", "さいたま", "広島", "仙台", "千葉", "北九州", "静岡",
+		},
+		Country:         "日本",
+		PhoneFormat:     "+81 90-####-####",
+		FamilyNameFirst: true,
+	},
+}
+
+// findSeedLocale returns the seed locale matching the specified locale code
+// (ex. "de", "de-DE", "de_AT", "JA").
+//
+// Returns nil for empty or unknown locale (aka. the default gofakeit values are used).
+func findSeedLocale(locale string) *seedLocale {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+
+	return seedLocales[lang]
+}
+
+// describe appends the locale instructions to the AI seed data description
+// (the description is returned as it is if l is nil).
+func (l *seedLocale) describe(description string) string {
+	if l == nil {
+		return description
+	}
+
+	hint := "Generate the text values in the " + l.Name + " language."
+	if description == "" {
+		return hint
+	}
+
+	return description + "\n\n" + hint
+}
+
+// value returns a random localized value of the specified placeholder key
+// and whether the key is localized.
+func (l *seedLocale) value(key string, localRand *rand.Rand) (string, bool) {
+	switch key {
+	case "NAME":
+		first := randomSeedItem(l.FirstNames, localRand)
+		last := randomSeedItem(l.LastNames, localRand)
+		if l.FamilyNameFirst {
+			return last + " " + first, true
+		}
+		return first + " " + last, true
+	case "FIRSTNAME":
+		return randomSeedItem(l.FirstNames, localRand), true
+	case "LASTNAME":
+		return randomSeedItem(l.LastNames, localRand), true
+	case "CITY":
+		return randomSeedItem(l.Cities, localRand), true
+	case "COUNTRY":
+		return l.Country, true
+	case "PHONE":
+		var sb strings.Builder
+		for _, r := range l.PhoneFormat {
+			if r == '#' {
+				sb.WriteByte(byte('0' + localRand.Intn(10)))
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String(), true
+	}
+
+	return "", false
+}
+
+// randomSeedItem returns a random item of the non-empty items slice.
+func randomSeedItem(items []string, localRand *rand.Rand) string {
+	return items[localRand.Intn(len(items))]
+}
@@ -0,0 +1,99 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestGenerateSeedDataLocale(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, nil)
+
+	collection := core.NewBaseCollection("test_seed_locale")
+	collection.Fields.Add(&core.TextField{Name: "name"})
+	collection.Fields.Add(&core.TextField{Name: "phone"})
+	collection.Fields.Add(&core.TextField{Name: "country"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"name": "{{NAME}}", "phone": "{{PHONE}}", "country": "{{COUNTRY}}"},
+	})
+
+	hasHan := func(str string) bool {
+		return strings.IndexFunc(str, func(r rune) bool { return unicode.Is(unicode.Han, r) }) != -1
+	}
+
+	scenarios := []struct {
+		locale      string
+		phonePrefix string
+		country     string
+		han         bool
+	}{
+		{"de", "+49 1", "Deutschland", false},
+		{"de_AT", "+49 1", "Deutschland", false},
+		{"ja-JP", "+81 90-", "日本", true},
+		{"unknown", "", "", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.locale, func(t *testing.T) {
+			records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+				Count:  30,
+				Locale: s.locale,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, record := range records {
+				name := record["name"].(string)
+				phone := record["phone"].(string)
+				country := record["country"].(string)
+
+				if s.country == "" {
+					// default values
+					if strings.HasPrefix(phone, "+") || country == "Deutschland" || country == "日本" || hasHan(name) {
+						t.Fatalf("[%d] Expected the default values, got %v", i, record)
+					}
+					continue
+				}
+
+				if !strings.HasPrefix(phone, s.phonePrefix) || strings.Contains(phone, "#") {
+					t.Fatalf("[%d] Expected phone with prefix %q, got %q", i, s.phonePrefix, phone)
+				}
+
+				if country != s.country {
+					t.Fatalf("[%d] Expected country %q, got %q", i, s.country, country)
+				}
+
+				if hasHan(name) != s.han || len(strings.Fields(name)) != 2 {
+					t.Fatalf("[%d] Unexpected localized name %q", i, name)
+				}
+			}
+		})
+	}
+
+	t.Run("AI prompt", func(t *testing.T) {
+		transport := &fakeChatTransport{content: `{"records":[{"name":"a"}]}`}
+		app.Store().Set(core.StoreKeyAIHTTPTransport, transport)
+
+		_, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count:  1,
+			Locale: "de",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prompts := transport.Prompts()
+		if len(prompts) != 1 || !strings.Contains(prompts[0], "in the German language") {
+			t.Fatalf("Expected the locale hint in the prompt, got %v", prompts)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// MaxSeedRelationCandidates is the max number of randomly sampled record ids
+// of the referenced collection used as seed relation values.
+const MaxSeedRelationCandidates = 1000
+
+// seedRelation holds the sampled record ids of a single relation field referenced collection.
+type seedRelation struct {
+	field      *RelationField
+	candidates []string
+}
+
+// loadSeedRelations samples up to limit record ids of the referenced collection
+// of each relation field that is not part of the request fixed fields (or the tree field).
+//
+// Returns the relations with candidates and the names of the skipped relation
+// fields (aka. the ones with an empty or missing referenced collection).
+func loadSeedRelations(app App, collection *Collection, req GenerateSeedDataRequest, limit int) ([]seedRelation, []string, error) {
+	var relations []seedRelation
+	var skipped []string
+
+	for _, field := range collection.Fields {
+		relField, ok := field.(*RelationField)
+		if !ok {
+			continue
+		}
+
+		if _, ok := req.FixedFields[relField.Name]; ok {
+			continue // the fixed value is set anyway
+		}
+
+		if req.Tree != nil && req.Tree.Field == relField.Name {
+			continue // the tree parent is set anyway
+		}
+
+		refCollection, err := app.FindCachedCollectionByNameOrId(relField.CollectionId)
+		if err != nil {
+			skipped = append(skipped, relField.Name)
+			continue
+		}
+
+		// with a fixed seed the candidates are loaded in a stable order
+		// (they are still randomly picked later with the seeded random source)
+		orderBy := "RANDOM()"
+		if req.Seed != nil {
+			orderBy = "id ASC"
+		}
+
+		var candidates []string
+		err = app.DB().
+			Select("id").
+			From(refCollection.Name).
+			OrderBy(orderBy).
+			Limit(int64(limit)).
+			Column(&candidates)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(candidates) == 0 {
+			skipped = append(skipped, relField.Name)
+			continue
+		}
+
+		relations = append(relations, seedRelation{field: relField, candidates: candidates})
+	}
+
+	sort.Strings(skipped)
+
+	return relations, skipped, nil
+}
+
+// SkippedSeedRelationFields returns the names of the collection relation fields
+// that can't be populated with [GenerateSeedDataRequest.PopulateRelations]
+// because their referenced collection has no records.
+func SkippedSeedRelationFields(app App, collection *Collection, req GenerateSeedDataRequest) ([]string, error) {
+	_, skipped, err := loadSeedRelations(app, collection, req, 1)
+
+	return skipped, err
+}
+
+// applySeedRelations sets on every record random values from the relation candidates.
+//
+// Single relations get a single id and the multiple ones a random number
+// of unique ids within the field MinSelect and MaxSelect limits (and the available candidates).
+func applySeedRelations(records []map[string]any, relations []seedRelation, localRand *rand.Rand) {
+	for _, record := range records {
+		for _, rel := range relations {
+			if !rel.field.IsMultiple() {
+				record[rel.field.Name] = rel.candidates[localRand.Intn(len(rel.candidates))]
+				continue
+			}
+
+			maxCount := min(rel.field.MaxSelect, len(rel.candidates))
+			minCount := min(max(rel.field.MinSelect, 1), maxCount)
+			count := minCount + localRand.Intn(maxCount-minCount+1)
+
+			// the candidates are capped with MaxSeedRelationCandidates so it is OK to permute all of them
+			indexes := localRand.Perm(len(rel.candidates))[:count]
+
+			ids := make([]string, count)
+			for i, idx := range indexes {
+				ids[i] = rel.candidates[idx]
+			}
+
+			record[rel.field.Name] = ids
+		}
+	}
+}
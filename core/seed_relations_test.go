@@ -0,0 +1,134 @@
+package core_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestGenerateSeedDataPopulateRelations(t *testing.T) {
+	t.Parallel()
+
+	app := newTestAIApp(t, &fakeChatTransport{content: `{"archetypes":[{"title":"a"},{"title":"b"}]}`})
+
+	authors := core.NewBaseCollection("test_seed_rel_authors")
+	authors.Fields.Add(&core.TextField{Name: "name"})
+	if err := app.Save(authors); err != nil {
+		t.Fatal(err)
+	}
+
+	var authorIds []string
+	for i := 0; i < 4; i++ {
+		record := core.NewRecord(authors)
+		record.Set("name", "test")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		authorIds = append(authorIds, record.Id)
+	}
+
+	empty := core.NewBaseCollection("test_seed_rel_empty")
+	empty.Fields.Add(&core.TextField{Name: "name"})
+	if err := app.Save(empty); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := core.NewBaseCollection("test_seed_rel_posts")
+	posts.Fields.Add(&core.TextField{Name: "title"})
+	posts.Fields.Add(&core.RelationField{Name: "author", CollectionId: authors.Id, MaxSelect: 1})
+	posts.Fields.Add(&core.RelationField{Name: "coauthors", CollectionId: authors.Id, MinSelect: 2, MaxSelect: 3})
+	posts.Fields.Add(&core.RelationField{Name: "editors", CollectionId: authors.Id, MaxSelect: 10})
+	posts.Fields.Add(&core.RelationField{Name: "category", CollectionId: empty.Id, MaxSelect: 1})
+	posts.Fields.Add(&core.RelationField{Name: "reviewer", CollectionId: authors.Id, MaxSelect: 1})
+	if err := app.Save(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("skipped fields", func(t *testing.T) {
+		skipped, err := core.SkippedSeedRelationFields(app, posts, core.GenerateSeedDataRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(skipped, []string{"category"}) {
+			t.Fatalf("Expected only the category field to be skipped, got %v", skipped)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, posts, core.GenerateSeedDataRequest{Count: 30})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, record := range records {
+			if _, ok := record["author"]; ok {
+				t.Fatalf("[%d] Expected no relation values, got %v", i, record)
+			}
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		records, err := core.GenerateSeedData(app, posts, core.GenerateSeedDataRequest{
+			Count:             30,
+			PopulateRelations: true,
+			FixedFields:       map[string]any{"reviewer": authorIds[0]},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 30 {
+			t.Fatalf("Expected 30 records, got %d", len(records))
+		}
+
+		for i, record := range records {
+			author, ok := record["author"].(string)
+			if !ok || !slices.Contains(authorIds, author) {
+				t.Fatalf("[%d] Expected single author id, got %#v", i, record["author"])
+			}
+
+			scenarios := []struct {
+				field    string
+				minCount int
+				maxCount int
+			}{
+				{"coauthors", 2, 3},
+				{"editors", 1, len(authorIds)}, // limited by the available records
+			}
+			for _, s := range scenarios {
+				ids, ok := record[s.field].([]string)
+				if !ok || len(ids) < s.minCount || len(ids) > s.maxCount {
+					t.Fatalf("[%d] Expected %d-%d %s ids, got %#v", i, s.minCount, s.maxCount, s.field, record[s.field])
+				}
+
+				unique := map[string]struct{}{}
+				for _, id := range ids {
+					if !slices.Contains(authorIds, id) {
+						t.Fatalf("[%d] Unexpected %s id %q", i, s.field, id)
+					}
+					unique[id] = struct{}{}
+				}
+				if len(unique) != len(ids) {
+					t.Fatalf("[%d] Expected unique %s ids, got %v", i, s.field, ids)
+				}
+			}
+
+			if _, ok := record["category"]; ok {
+				t.Fatalf("[%d] Expected the empty collection relation to be skipped, got %v", i, record["category"])
+			}
+
+			if record["reviewer"] != authorIds[0] {
+				t.Fatalf("[%d] Expected the fixed reviewer value, got %v", i, record["reviewer"])
+			}
+		}
+
+		// ensure that the records could be saved
+		record := core.NewRecord(posts)
+		record.Load(records[0])
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save the seed record: %v", err)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// SeedRunsCollectionName is the name of the system collection for
+// tracking the records inserted as part of a seed data run
+const SeedRunsCollectionName = "_seed_runs"
+
+// NewSeedRunId generates a new random seed run identifier.
+func NewSeedRunId() string {
+	return security.RandomStringWithAlphabet(DefaultIdLength, DefaultIdAlphabet)
+}
+
+// EnsureSeedRunsCollection creates the _seed_runs system collection if it doesn't exist.
+// Returns the seed runs collection.
+func EnsureSeedRunsCollection(app App) (*Collection, error) {
+	collection, err := app.FindCollectionByNameOrId(SeedRunsCollectionName)
+	if err == nil {
+		return collection, nil
+	}
+
+	collection = NewCollection(CollectionTypeBase, SeedRunsCollectionName)
+	collection.System = true
+
+	collection.Fields.Add(&TextField{
+		Name:     "run_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "collection_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Fields.Add(&TextField{
+		Name:     "record_id",
+		Required: true,
+		System:   true,
+	})
+
+	collection.Indexes = []string{
+		"CREATE INDEX idx_seed_runs_run ON _seed_runs (run_id)",
+	}
+
+	if err := app.Save(collection); err != nil {
+		// another goroutine could have created the collection in the meantime
+		errStr := err.Error()
+		if strings.Contains(errStr, "UNIQUE constraint failed") ||
+			strings.Contains(errStr, "already exists") ||
+			strings.Contains(errStr, "must be unique") {
+			collection, findErr := app.FindCollectionByNameOrId(SeedRunsCollectionName)
+			if findErr == nil {
+				return collection, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create seed runs collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// TrackSeedRunRecord tags the specified (already inserted) record as part of the runId seed run.
+//
+// The tracked records of a run could be later removed with [CleanupSeedRun].
+func TrackSeedRunRecord(app App, runId string, record *Record) error {
+	if runId == "" {
+		return errors.New("missing seed run id")
+	}
+
+	seedRunsCollection, err := EnsureSeedRunsCollection(app)
+	if err != nil {
+		return err
+	}
+
+	entry := NewRecord(seedRunsCollection)
+	entry.Set("run_id", runId)
+	entry.Set("collection_id", record.Collection().Id)
+	entry.Set("record_id", record.Id)
+
+	if err := app.Save(entry); err != nil {
+		return fmt.Errorf("failed to track seed run record: %w", err)
+	}
+
+	return nil
+}
+
+// CleanupSeedRun deletes all tracked records of the runId seed run
+// (including the tracking entries) and returns the number of the deleted records.
+//
+// Already deleted records are silently skipped.
+func CleanupSeedRun(app App, runId string) (int, error) {
+	if runId == "" {
+		return 0, errors.New("missing seed run id")
+	}
+
+	seedRunsCollection, err := app.FindCollectionByNameOrId(SeedRunsCollectionName)
+	if err != nil {
+		return 0, nil // no tracked runs
+	}
+
+	entries, err := app.FindRecordsByFilter(
+		seedRunsCollection.Id,
+		"run_id = {:runId}",
+		"",
+		0,
+		0,
+		map[string]any{"runId": runId},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch the seed run records: %w", err)
+	}
+
+	// group the record ids by their collection
+	grouped := map[string][]string{}
+	for _, entry := range entries {
+		collectionId := entry.GetString("collection_id")
+		grouped[collectionId] = append(grouped[collectionId], entry.GetString("record_id"))
+	}
+
+	var deleted int
+
+	err = app.RunInTransaction(func(txApp App) error {
+		for collectionId, recordIds := range grouped {
+			collection, err := txApp.FindCachedCollectionByNameOrId(collectionId)
+			if err != nil {
+				continue // the collection was deleted
+			}
+
+			// fetch in chunks to avoid exceeding the max query parameters limit
+			for _, chunk := range batchTexts(recordIds, 500) {
+				records, err := txApp.FindRecordsByIds(collection, chunk)
+				if err != nil {
+					return fmt.Errorf("failed to fetch the seed run records: %w", err)
+				}
+
+				for _, record := range records {
+					if err := txApp.Delete(record); err != nil {
+						return fmt.Errorf("failed to delete seed run record %s: %w", record.Id, err)
+					}
+					deleted++
+				}
+			}
+		}
+
+		for _, entry := range entries {
+			if err := txApp.Delete(entry); err != nil {
+				return fmt.Errorf("failed to delete seed run entry: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
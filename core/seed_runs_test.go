@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestCleanupSeedRun(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collectionA := core.NewBaseCollection("test_seed_run_a")
+	collectionA.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collectionA); err != nil {
+		t.Fatal(err)
+	}
+
+	collectionB := core.NewBaseCollection("test_seed_run_b")
+	collectionB.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collectionB); err != nil {
+		t.Fatal(err)
+	}
+
+	runId := core.NewSeedRunId()
+	otherRunId := core.NewSeedRunId()
+	if runId == otherRunId {
+		t.Fatalf("Expected unique run ids, got %q twice", runId)
+	}
+
+	create := func(collection *core.Collection, runId string) *core.Record {
+		record := core.NewRecord(collection)
+		record.Set("title", "test")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if runId != "" {
+			if err := core.TrackSeedRunRecord(app, runId, record); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return record
+	}
+
+	var runRecords []*core.Record
+	for i := 0; i < 3; i++ {
+		runRecords = append(runRecords, create(collectionA, runId), create(collectionB, runId))
+	}
+	untracked := create(collectionA, "")
+	otherRun := create(collectionB, otherRunId)
+
+	// manually deleted run record
+	if err := app.Delete(runRecords[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := core.CleanupSeedRun(app, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != len(runRecords)-1 {
+		t.Fatalf("Expected %d deleted records, got %d", len(runRecords)-1, deleted)
+	}
+
+	for _, r := range runRecords {
+		if _, err := app.FindRecordById(r.Collection(), r.Id); err == nil {
+			t.Fatalf("Expected run record %q to be deleted", r.Id)
+		}
+	}
+
+	for _, r := range []*core.Record{untracked, otherRun} {
+		if _, err := app.FindRecordById(r.Collection(), r.Id); err != nil {
+			t.Fatalf("Expected record %q to remain: %v", r.Id, err)
+		}
+	}
+
+	entries, err := app.CountRecords(core.SeedRunsCollectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries != 1 {
+		t.Fatalf("Expected only the other run tracking entry to remain, got %d", entries)
+	}
+
+	// cleanup of an already cleaned run
+	deleted, err = core.CleanupSeedRun(app, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 0 {
+		t.Fatalf("Expected 0 deleted records on repeated cleanup, got %d", deleted)
+	}
+
+	if _, err := core.CleanupSeedRun(app, ""); err == nil {
+		t.Fatal("Expected error for empty run id")
+	}
+}
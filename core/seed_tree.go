@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	// MaxSeedTreeDepth is the max allowed number of levels of a seed tree.
+	MaxSeedTreeDepth = 10
+
+	// MaxSeedTreeBranching is the max allowed number of children per seed tree node.
+	MaxSeedTreeBranching = 100
+
+	// MaxSeedTreeRecords is the max allowed total number of records of a seed tree.
+	MaxSeedTreeRecords = 1000000
+)
+
+// SeedTree defines the hierarchical (tree) generation options of a collection
+// with a self-referencing parent relation (ex. category trees, org charts).
+//
+// The records are generated level by level (aka. breadth-first), starting with
+// the root records (without parent), and each record of the next levels gets
+// as parent a record from the previous level. Every non-leaf record has exactly
+// Branching children, so the generated records count is determined by the tree
+// options and the request Count is ignored.
+//
+// Note that the children of a record that failed to be inserted are skipped too.
+type SeedTree struct {
+	// Field is the name of the self-referencing relation field holding the parent id.
+	Field string `json:"field"`
+
+	// Depth is the number of the tree levels (including the roots level).
+	Depth int `json:"depth"`
+
+	// Branching is the number of children of each non-leaf record.
+	Branching int `json:"branching"`
+
+	// Roots is the number of the root records (default to 1).
+	Roots int `json:"roots,omitempty"`
+}
+
+// rootsCount returns the number of the root records.
+func (t SeedTree) rootsCount() int {
+	if t.Roots <= 0 {
+		return 1
+	}
+
+	return t.Roots
+}
+
+// Size returns the total number of the tree records.
+//
+// Returns MaxSeedTreeRecords+1 if the tree exceeds [MaxSeedTreeRecords].
+func (t SeedTree) Size() int {
+	var total int
+
+	level := t.rootsCount()
+	for i := 0; i < t.Depth; i++ {
+		total += level
+		if total > MaxSeedTreeRecords {
+			return MaxSeedTreeRecords + 1
+		}
+		level *= max(t.Branching, 1)
+	}
+
+	return total
+}
+
+// parentIndex returns the index of the parent of the i-th tree record
+// in the breadth-first order (or -1 for the root records).
+func (t SeedTree) parentIndex(i int) int {
+	roots := t.rootsCount()
+	if i < roots {
+		return -1
+	}
+
+	return (i - roots) / max(t.Branching, 1)
+}
+
+// validateSeedTree validates the tree options against the collection schema.
+func validateSeedTree(collection *Collection, tree *SeedTree, fixedFields map[string]any) error {
+	if tree == nil {
+		return nil
+	}
+
+	errs := validation.Errors{}
+
+	if tree.Field == "" {
+		errs["field"] = validation.ErrRequired
+	} else if field, ok := collection.Fields.GetByName(tree.Field).(*RelationField); !ok || field.CollectionId != collection.Id {
+		errs["field"] = validation.NewError("validation_invalid_tree_field", "The tree field must be a self-referencing relation field.")
+	} else if field.Required || field.MinSelect > 0 {
+		errs["field"] = validation.NewError("validation_required_tree_field", "The tree field must not be required (the root records have no parent).")
+	} else if _, ok := fixedFields[tree.Field]; ok {
+		errs["field"] = validation.NewError("validation_fixed_tree_field", "The tree field cannot be a fixed field.")
+	}
+
+	if tree.Depth < 1 || tree.Depth > MaxSeedTreeDepth {
+		errs["depth"] = validation.NewError("validation_invalid_tree_depth", fmt.Sprintf("Must be between 1 and %d.", MaxSeedTreeDepth))
+	}
+
+	if tree.Branching < 1 || tree.Branching > MaxSeedTreeBranching {
+		errs["branching"] = validation.NewError("validation_invalid_tree_branching", fmt.Sprintf("Must be between 1 and %d.", MaxSeedTreeBranching))
+	}
+
+	if tree.Roots < 0 {
+		errs["roots"] = validation.NewError("validation_invalid_tree_roots", "Must be a positive number.")
+	}
+
+	if len(errs) == 0 && tree.Size() > MaxSeedTreeRecords {
+		errs["depth"] = validation.NewError("validation_tree_too_large", fmt.Sprintf("The tree must have at most %d records.", MaxSeedTreeRecords))
+	}
+
+	if len(errs) > 0 {
+		return validation.Errors{"tree": errs}
+	}
+
+	return nil
+}
+
+// generateSeedTreeIds generates the ids of the count tree records
+// (they are assigned upfront so that the children could reference their parent before its insert).
+//
+// The ids are always cryptographically random (even for seeded requests)
+// so that the repeated runs of the same seeded request don't collide
+// with the records inserted by the previous runs.
+func generateSeedTreeIds(count int) []string {
+	ids := make([]string, count)
+	unique := make(map[string]struct{}, count)
+
+	for i := range ids {
+		for {
+			id := security.RandomStringWithAlphabet(DefaultIdLength, DefaultIdAlphabet)
+			if _, ok := unique[id]; !ok {
+				unique[id] = struct{}{}
+				ids[i] = id
+				break
+			}
+		}
+	}
+
+	return ids
+}
+
+// applySeedTree sets the id and the parent of the records
+// starting at the offset position of the tree records.
+func applySeedTree(records []map[string]any, tree SeedTree, ids []string, offset int) {
+	for j, record := range records {
+		i := offset + j
+		if i >= len(ids) {
+			break
+		}
+
+		record[FieldNameId] = ids[i]
+
+		if p := tree.parentIndex(i); p >= 0 {
+			record[tree.Field] = ids[p]
+		} else {
+			record[tree.Field] = ""
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package core_test
+
+import (
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSeedTreeSize(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		tree     core.SeedTree
+		expected int
+	}{
+		{core.SeedTree{Depth: 1, Branching: 5}, 1},
+		{core.SeedTree{Depth: 3, Branching: 2}, 7},
+		{core.SeedTree{Depth: 3, Branching: 3, Roots: 2}, 26},
+		{core.SeedTree{Depth: 4, Branching: 1, Roots: 3}, 12},
+		{core.SeedTree{Depth: 10, Branching: 100}, core.MaxSeedTreeRecords + 1},
+	}
+
+	for i, s := range scenarios {
+		if size := s.tree.Size(); size != s.expected {
+			t.Errorf("[%d] Expected size %d, got %d", i, s.expected, size)
+		}
+	}
+}
+
+func TestGenerateSeedDataTree(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	other := core.NewBaseCollection("test_seed_tree_other")
+	if err := app.Save(other); err != nil {
+		t.Fatal(err)
+	}
+
+	collection := core.NewBaseCollection("test_seed_tree")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Fields.Add(&core.RelationField{Name: "parent", CollectionId: collection.Id, MaxSelect: 1})
+	collection.Fields.Add(&core.RelationField{Name: "other", CollectionId: other.Id, MaxSelect: 1})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// the root records can't have a required parent
+	requiredCollection := core.NewBaseCollection("test_seed_tree_required")
+	if err := app.Save(requiredCollection); err != nil {
+		t.Fatal(err)
+	}
+	requiredCollection.Fields.Add(&core.RelationField{Name: "parent", CollectionId: requiredCollection.Id, MaxSelect: 1, Required: true})
+	if err := app.Save(requiredCollection); err != nil {
+		t.Fatal(err)
+	}
+
+	core.CacheArchetypes(collection, []map[string]any{
+		{"title": "{{NAME}}"},
+	})
+
+	t.Run("invalid trees", func(t *testing.T) {
+		scenarios := []struct {
+			name       string
+			collection *core.Collection
+			req        core.GenerateSeedDataRequest
+		}{
+			{"missing field", collection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Depth: 2, Branching: 2}}},
+			{"non-relation field", collection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Field: "title", Depth: 2, Branching: 2}}},
+			{"non self-relation field", collection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Field: "other", Depth: 2, Branching: 2}}},
+			{"required field", requiredCollection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Field: "parent", Depth: 2, Branching: 2}}},
+			{"zero depth", collection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Field: "parent", Branching: 2}}},
+			{"zero branching", collection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Field: "parent", Depth: 2}}},
+			{"too large", collection, core.GenerateSeedDataRequest{Tree: &core.SeedTree{Field: "parent", Depth: 10, Branching: 100}}},
+			{
+				"fixed tree field",
+				collection,
+				core.GenerateSeedDataRequest{
+					Tree:        &core.SeedTree{Field: "parent", Depth: 2, Branching: 2},
+					FixedFields: map[string]any{"parent": ""},
+				},
+			},
+		}
+
+		for _, s := range scenarios {
+			t.Run(s.name, func(t *testing.T) {
+				_, err := core.GenerateSeedData(app, s.collection, s.req)
+
+				errs, ok := err.(validation.Errors)
+				if !ok {
+					t.Fatalf("Expected validation.Errors, got %v", err)
+				}
+
+				if _, ok := errs["tree"]; !ok {
+					t.Fatalf("Expected tree validation error, got %v", errs)
+				}
+			})
+		}
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		tree := &core.SeedTree{Field: "parent", Depth: 3, Branching: 3, Roots: 2}
+
+		seed := int64(1)
+		records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+			Count: 5, // ignored
+			Tree:  tree,
+			Seed:  &seed,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != tree.Size() {
+			t.Fatalf("Expected %d records, got %d", tree.Size(), len(records))
+		}
+
+		parents := make(map[string]string, len(records))
+		children := map[string]int{}
+		var roots int
+
+		for i, record := range records {
+			id, _ := record["id"].(string)
+			if id == "" {
+				t.Fatalf("[%d] Expected the record id to be set", i)
+			}
+			if _, ok := parents[id]; ok {
+				t.Fatalf("[%d] Duplicated record id %q", i, id)
+			}
+
+			// the parents are always generated (and inserted) before their children
+			parent, _ := record["parent"].(string)
+			if parent == "" {
+				roots++
+			} else if _, ok := parents[parent]; !ok {
+				t.Fatalf("[%d] Expected the parent %q to be generated before the record", i, parent)
+			}
+
+			parents[id] = parent
+			children[parent]++
+		}
+
+		if roots != 2 {
+			t.Fatalf("Expected 2 root records, got %d", roots)
+		}
+
+		for id, parent := range parents {
+			// no cycles
+			depth := 1
+			for current := parent; current != ""; current = parents[current] {
+				depth++
+				if depth > tree.Depth {
+					t.Fatalf("Expected the record %q to reach a root within %d levels", id, tree.Depth)
+				}
+			}
+
+			// every non-leaf record has exactly Branching children
+			if depth < tree.Depth && children[id] != tree.Branching {
+				t.Fatalf("Expected %d children of the record %q, got %d", tree.Branching, id, children[id])
+			}
+		}
+
+		// ensure that the records could be saved in their generation order
+		for i, data := range records {
+			record := core.NewRecord(collection)
+			record.Load(data)
+			if err := app.Save(record); err != nil {
+				t.Fatalf("[%d] Failed to save the seed record %v: %v", i, data, err)
+			}
+		}
+
+		total, err := app.CountRecords(collection, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(total) != len(records) {
+			t.Fatalf("Expected %d saved records, got %d", len(records), total)
+		}
+	})
+	t.Run("repeated seeded run", func(t *testing.T) {
+		tree := &core.SeedTree{Field: "parent", Depth: 2, Branching: 2}
+
+		// the same seeded request should be insertable multiple times
+		for run := 0; run < 2; run++ {
+			seed := int64(1)
+			records, err := core.GenerateSeedData(app, collection, core.GenerateSeedDataRequest{
+				Tree: tree,
+				Seed: &seed,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, data := range records {
+				record := core.NewRecord(collection)
+				record.Load(data)
+				if err := app.Save(record); err != nil {
+					t.Fatalf("[%d:%d] Failed to save the seed record %v: %v", run, i, data, err)
+				}
+			}
+		}
+	})
+}
@@ -180,11 +180,21 @@ func newDefaultSettings() *Settings {
 				},
 			},
 			AI: AIConfig{
-				Enabled:             false,
-				Provider:            "openai",
-				Model:               "gpt-4o-mini",
-				EmbeddingModel:      "text-embedding-3-small",
-				EmbeddingDimensions: 1536,
+				Enabled:                 false,
+				Provider:                "openai",
+				Model:                   "gpt-4o-mini",
+				EmbeddingModel:          "text-embedding-3-small",
+				EmbeddingDimensions:     1536,
+				EmbeddingBatchSize:      MaxTextsPerBatch,
+				EmbeddingTimeout:        DefaultEmbeddingTimeout,
+				EmbeddingQueryTimeout:   DefaultEmbeddingQueryTimeout,
+				EmbeddingMaxRetries:     DefaultEmbeddingMaxRetries,
+				EmbeddingRetryBaseDelay: DefaultEmbeddingRetryBaseDelay,
+				SimilarityMaxLimit:      DefaultSimilarityMaxLimit,
+				MaxResponseSize:         DefaultAIMaxResponseSize,
+				GenerationParseRetries:  DefaultAIGenerationParseRetries,
+				HTMLStripMaxSize:        DefaultHTMLStripMaxSize,
+				HTMLStripMaxTags:        DefaultHTMLStripMaxTags,
 			},
 		},
 	}
@@ -661,6 +671,19 @@ func checkUniqueRuleLabel(value any) error {
 	return nil
 }
 
+// aiEndpointPathRegex validates the custom OpenAI endpoint paths
+// (must start with a slash and must not contain a query or fragment).
+var aiEndpointPathRegex = regexp.MustCompile(`^\/[^\s?#]*$`)
+
+// ValidateAIEndpointPath validates a single custom OpenAI endpoint path value
+// (e.g. [AIConfig.ChatPath]).
+//
+// It is exported so that it can be reused when validating unsaved
+// AI settings (e.g. during a test connection request).
+func ValidateAIEndpointPath(value any) error {
+	return validation.Validate(value, validation.Length(0, 255), validation.Match(aiEndpointPathRegex))
+}
+
 var rateLimitRuleLabelRegex = regexp.MustCompile(`^(\w+\ \/[\w\/-]*|\/[\w\/-]*|^\w+\:\w+|\*\:\w+|\w+)$`)
 
 // The allowed RateLimitRule.Audience values
@@ -734,6 +757,199 @@ type AIConfig struct {
 	Model               string `form:"model" json:"model"`
 	EmbeddingModel      string `form:"embeddingModel" json:"embeddingModel"`
 	EmbeddingDimensions int    `form:"embeddingDimensions" json:"embeddingDimensions"`
+
+	// BaseURL is an optional OpenAI compatible API base url replacing the default
+	// [DefaultOpenAIBaseURL] prefix of the chat and embeddings requests
+	// (ex. an internal gateway or an Azure OpenAI deployment url).
+	BaseURL string `form:"baseURL" json:"baseURL"`
+
+	// ChatPath is an optional OpenAI compatible chat completions endpoint path
+	// appended to the BaseURL (fallbacks to [DefaultOpenAIChatPath] if not set).
+	ChatPath string `form:"chatPath" json:"chatPath"`
+
+	// EmbeddingsPath is an optional OpenAI compatible embeddings endpoint path
+	// appended to the BaseURL (fallbacks to [DefaultOpenAIEmbeddingsPath] if not set).
+	EmbeddingsPath string `form:"embeddingsPath" json:"embeddingsPath"`
+
+	// APIVersion is an optional "api-version" query parameter
+	// appended to the OpenAI requests (required by Azure OpenAI).
+	APIVersion string `form:"apiVersion" json:"apiVersion"`
+
+	// AuthHeader is the OpenAI requests auth header style
+	// ("" for the default "Authorization: Bearer" header or
+	// [AIAuthHeaderAPIKey] for the Azure OpenAI "api-key" header).
+	AuthHeader string `form:"authHeader" json:"authHeader"`
+
+	// SchemaModel is an optional model used for the schema generation
+	// (fallbacks to Model if not set).
+	SchemaModel string `form:"schemaModel" json:"schemaModel"`
+
+	// SeedModel is an optional model used for the seed data and archetypes generation
+	// (fallbacks to Model if not set).
+	SeedModel string `form:"seedModel" json:"seedModel"`
+
+	// MaxResponseSize is the max size (in bytes) of a single AI provider response
+	// (0 or not set fallbacks to [DefaultAIMaxResponseSize]).
+	MaxResponseSize int64 `form:"maxResponseSize" json:"maxResponseSize"`
+
+	// GenerationParseRetries is the max number of full regenerations of a schema,
+	// seed data or archetypes response that couldn't be parsed as valid JSON
+	// (default to [DefaultAIGenerationParseRetries], 0 disables the regeneration).
+	//
+	// Note that this is different from the embeddings HTTP requests retry
+	// and each retry is a new (billed) completion request.
+	GenerationParseRetries int `form:"generationParseRetries" json:"generationParseRetries"`
+
+	// EmbeddingBatchSize is the max number of texts sent in a single
+	// embeddings request (0 or not set fallbacks to [MaxTextsPerBatch]).
+	EmbeddingBatchSize int `form:"embeddingBatchSize" json:"embeddingBatchSize"`
+
+	// EmbeddingTombstoneDays is the grace period (in days) during which the embeddings
+	// of deleted records are only tombstoned (excluded from search) before being hard deleted
+	// (0 or not set disables the tombstones and hard deletes the embeddings right away).
+	EmbeddingTombstoneDays int `form:"embeddingTombstoneDays" json:"embeddingTombstoneDays"`
+
+	// EmbeddingTimeout is the max duration in seconds to wait for a bulk embeddings request
+	// (0 or not set fallbacks to [DefaultEmbeddingTimeout]).
+	EmbeddingTimeout int `form:"embeddingTimeout" json:"embeddingTimeout"`
+
+	// EmbeddingQueryTimeout is the max duration in seconds to wait for a search query embedding request
+	// (0 or not set fallbacks to [DefaultEmbeddingQueryTimeout]).
+	EmbeddingQueryTimeout int `form:"embeddingQueryTimeout" json:"embeddingQueryTimeout"`
+
+	// EmbeddingMaxRetries is the max number of retries of an embeddings batch request
+	// failed with 429, 500, 502, 503 or 504 status code before skipping the batch
+	// (default to [DefaultEmbeddingMaxRetries], 0 disables the retries).
+	EmbeddingMaxRetries int `form:"embeddingMaxRetries" json:"embeddingMaxRetries"`
+
+	// EmbeddingRetryBaseDelay is the initial wait time in milliseconds before retrying
+	// a failed embeddings batch request, doubled on each consecutive retry
+	// (0 or not set fallbacks to [DefaultEmbeddingRetryBaseDelay]).
+	//
+	// The Retry-After response header, if present, takes precedence.
+	EmbeddingRetryBaseDelay int `form:"embeddingRetryBaseDelay" json:"embeddingRetryBaseDelay"`
+
+	// EmbeddingMinChars is the min number of characters of the text to embed
+	// (records with shorter text are skipped; 0 or not set disables the check).
+	EmbeddingMinChars int `form:"embeddingMinChars" json:"embeddingMinChars"`
+
+	// EmbeddingMinWords is the min number of words of the text to embed
+	// (records with fewer words are skipped; 0 or not set disables the check).
+	EmbeddingMinWords int `form:"embeddingMinWords" json:"embeddingMinWords"`
+
+	// EmbeddingCompression is the storage format of the embedding vectors
+	// ("" for plain JSON arrays or "gzip" for compressed blobs).
+	//
+	// Changing it affects only the newly stored embeddings
+	// (the existing ones remain readable in their original format).
+	EmbeddingCompression string `form:"embeddingCompression" json:"embeddingCompression"`
+
+	// AutoEmbedTextFields treats all text and editor fields as embeddable
+	// without requiring their explicit Embeddable flag.
+	AutoEmbedTextFields bool `form:"autoEmbedTextFields" json:"autoEmbedTextFields"`
+
+	// EmbeddingCacheDebug enables the embedding cache internal state dump endpoint.
+	EmbeddingCacheDebug bool `form:"embeddingCacheDebug" json:"embeddingCacheDebug"`
+
+	// EmbeddingCacheQuantization stores the loaded embedding vectors as int8 values
+	// with a per-vector scale factor instead of float32 (~4x less cache memory
+	// at the cost of a small similarity scores precision loss).
+	//
+	// Changing it affects only the newly loaded cache entries.
+	EmbeddingCacheQuantization bool `form:"embeddingCacheQuantization" json:"embeddingCacheQuantization"`
+
+	// SimilarityMaxLimit is the max number of results of a single similarity search,
+	// enforced both by the core search functions and the API endpoints
+	// (0 or not set fallbacks to [DefaultSimilarityMaxLimit]).
+	SimilarityMaxLimit int `form:"similarityMaxLimit" json:"similarityMaxLimit"`
+
+	// HTMLStripMaxSize is the max size (in bytes) of an editor field value
+	// before stripping its HTML tags for embedding (0 or not set fallbacks to [DefaultHTMLStripMaxSize]).
+	HTMLStripMaxSize int `form:"htmlStripMaxSize" json:"htmlStripMaxSize"`
+
+	// HTMLStripMaxTags is the max number of HTML tags to strip from a single editor field value
+	// (0 or not set fallbacks to [DefaultHTMLStripMaxTags]).
+	HTMLStripMaxTags int `form:"htmlStripMaxTags" json:"htmlStripMaxTags"`
+}
+
+// SchemaModelOrDefault returns the model to use for the schema generation.
+func (c AIConfig) SchemaModelOrDefault() string {
+	if c.SchemaModel != "" {
+		return c.SchemaModel
+	}
+	return c.Model
+}
+
+// SeedModelOrDefault returns the model to use for the seed data generation.
+func (c AIConfig) SeedModelOrDefault() string {
+	if c.SeedModel != "" {
+		return c.SeedModel
+	}
+	return c.Model
+}
+
+// GenerationParseRetriesOrDefault returns the max number of regenerations of an unparsable AI response.
+//
+// The default is assigned with the settings initialization so 0 is returned
+// as it is (aka. no regeneration) and only the negative values are normalized.
+func (c AIConfig) GenerationParseRetriesOrDefault() int {
+	return max(0, c.GenerationParseRetries)
+}
+
+// EmbeddingTimeoutDuration returns the bulk embeddings request timeout.
+func (c AIConfig) EmbeddingTimeoutDuration() time.Duration {
+	if c.EmbeddingTimeout > 0 {
+		return time.Duration(c.EmbeddingTimeout) * time.Second
+	}
+	return DefaultEmbeddingTimeout * time.Second
+}
+
+// EmbeddingMaxRetriesOrDefault returns the max number of retries of a failed embeddings batch request.
+//
+// The default is assigned with the settings initialization so 0 is
+// returned as it is (aka. no retries) and only the negative values are normalized.
+func (c AIConfig) EmbeddingMaxRetriesOrDefault() int {
+	return max(0, c.EmbeddingMaxRetries)
+}
+
+// EmbeddingRetryBaseDelayDuration returns the initial wait time before retrying a failed embeddings batch request.
+func (c AIConfig) EmbeddingRetryBaseDelayDuration() time.Duration {
+	if c.EmbeddingRetryBaseDelay > 0 {
+		return time.Duration(c.EmbeddingRetryBaseDelay) * time.Millisecond
+	}
+	return DefaultEmbeddingRetryBaseDelay * time.Millisecond
+}
+
+// EmbeddingQueryTimeoutDuration returns the search query embedding request timeout.
+func (c AIConfig) EmbeddingQueryTimeoutDuration() time.Duration {
+	if c.EmbeddingQueryTimeout > 0 {
+		return time.Duration(c.EmbeddingQueryTimeout) * time.Second
+	}
+	return DefaultEmbeddingQueryTimeout * time.Second
+}
+
+// SimilarityMaxLimitOrDefault returns the max number of results of a single similarity search.
+func (c AIConfig) SimilarityMaxLimitOrDefault() int {
+	if c.SimilarityMaxLimit > 0 {
+		return c.SimilarityMaxLimit
+	}
+	return DefaultSimilarityMaxLimit
+}
+
+// ChatPathOrDefault returns the OpenAI chat completions endpoint path.
+func (c AIConfig) ChatPathOrDefault() string {
+	if c.ChatPath != "" {
+		return c.ChatPath
+	}
+	return DefaultOpenAIChatPath
+}
+
+// EmbeddingsPathOrDefault returns the OpenAI embeddings endpoint path.
+func (c AIConfig) EmbeddingsPathOrDefault() string {
+	if c.EmbeddingsPath != "" {
+		return c.EmbeddingsPath
+	}
+	return DefaultOpenAIEmbeddingsPath
 }
 
 // Validate makes AIConfig validatable by implementing [validation.Validatable] interface.
@@ -742,12 +958,17 @@ func (c AIConfig) Validate() error {
 		validation.Field(
 			&c.Provider,
 			validation.When(c.Enabled, validation.Required),
-			validation.In("openai"),
+			validation.In(AIProviderOpenAI, AIProviderAnthropic),
 		),
 		validation.Field(
 			&c.APIKey,
 			validation.When(c.Enabled, validation.Required),
 		),
+		validation.Field(&c.BaseURL, is.URL),
+		validation.Field(&c.ChatPath, validation.By(ValidateAIEndpointPath)),
+		validation.Field(&c.EmbeddingsPath, validation.By(ValidateAIEndpointPath)),
+		validation.Field(&c.APIVersion, validation.Length(0, 50)),
+		validation.Field(&c.AuthHeader, validation.In(AIAuthHeaderAPIKey)),
 		validation.Field(
 			&c.Model,
 			validation.When(c.Enabled, validation.Required),
@@ -760,5 +981,19 @@ func (c AIConfig) Validate() error {
 			&c.EmbeddingDimensions,
 			validation.When(c.Enabled, validation.Required, validation.Min(1), validation.Max(4096)),
 		),
+		validation.Field(&c.EmbeddingBatchSize, validation.Min(0), validation.Max(MaxTextsPerBatch)),
+		validation.Field(&c.EmbeddingTombstoneDays, validation.Min(0)),
+		validation.Field(&c.EmbeddingTimeout, validation.Min(0)),
+		validation.Field(&c.EmbeddingQueryTimeout, validation.Min(0)),
+		validation.Field(&c.EmbeddingMaxRetries, validation.Min(0), validation.Max(MaxEmbeddingRetries)),
+		validation.Field(&c.EmbeddingRetryBaseDelay, validation.Min(0)),
+		validation.Field(&c.SimilarityMaxLimit, validation.Min(0)),
+		validation.Field(&c.EmbeddingMinChars, validation.Min(0)),
+		validation.Field(&c.EmbeddingMinWords, validation.Min(0)),
+		validation.Field(&c.EmbeddingCompression, validation.In(EmbeddingCompressionNone, EmbeddingCompressionGzip)),
+		validation.Field(&c.MaxResponseSize, validation.Min(0)),
+		validation.Field(&c.GenerationParseRetries, validation.Min(0), validation.Max(MaxAIGenerationParseRetries)),
+		validation.Field(&c.HTMLStripMaxSize, validation.Min(0)),
+		validation.Field(&c.HTMLStripMaxTags, validation.Min(0)),
 	)
 }
@@ -0,0 +1,88 @@
+package inflector
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// slugifyReplacements contains the ASCII transliterations of the common characters
+// that don't decompose to a base latin letter with NFKD normalization
+// (special latin letters, cyrillic and greek).
+var slugifyReplacements = map[rune]string{
+	// latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'ł': "l", 'þ': "th", 'ı': "i", 'ĳ': "ij", 'ŋ': "ng", 'ħ': "h",
+
+	// cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sht", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i",
+	'ї': "yi", 'ґ': "g", 'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c",
+	'џ': "dz", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz", 'ў': "u",
+
+	// greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Slugify converts str into a lowercased ASCII slug with words separated by separator
+// (ex. "Café crème" -> "cafe_creme" with "_" separator).
+//
+// The accented latin characters are folded to their base letter and the cyrillic
+// and greek characters are transliterated. All other characters (including the
+// characters of scripts without transliteration, ex. CJK) are treated as separators.
+//
+// Returns an empty string if str doesn't have any transliterable characters.
+func Slugify(str string, separator string) string {
+	var result strings.Builder
+
+	var pendingSeparator bool
+
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if pendingSeparator && result.Len() > 0 {
+			result.WriteString(separator)
+		}
+		pendingSeparator = false
+		result.WriteString(s)
+	}
+
+	for _, r := range str {
+		r = unicode.ToLower(r)
+
+		// transliterate before the decomposition (ex. "й" -> "y" instead of "и" + breve)
+		if replacement, ok := slugifyReplacements[r]; ok {
+			write(replacement) // could be empty for the ignored letters (ex. the cyrillic soft sign)
+			continue
+		}
+
+		// the compatibility decomposed form splits the accented characters into their
+		// base letter followed by the combining marks (and also folds the
+		// compatibility characters, ex. the fullwidth letters and the ligatures)
+		for _, d := range norm.NFKD.String(string(r)) {
+			if unicode.Is(unicode.Mn, d) {
+				continue // combining mark
+			}
+
+			d = unicode.ToLower(d)
+
+			if d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)) {
+				write(string(d))
+			} else if replacement, ok := slugifyReplacements[d]; ok {
+				write(replacement) // accented base letter (ex. the greek "ή")
+			} else {
+				pendingSeparator = true
+			}
+		}
+	}
+
+	return result.String()
+}
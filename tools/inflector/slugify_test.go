@@ -0,0 +1,44 @@
+package inflector_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/inflector"
+)
+
+func TestSlugify(t *testing.T) {
+	scenarios := []struct {
+		val       string
+		separator string
+		expected  string
+	}{
+		{"", "_", ""},
+		{"  ", "_", ""},
+		{"!@#$%^", "_", ""},
+		{"Blog posts", "_", "blog_posts"},
+		{"  Blog -- posts!  ", "_", "blog_posts"},
+		{"Blog posts 2024", "-", "blog-posts-2024"},
+		{"snake_case_name", "_", "snake_case_name"},
+		{"Café crème recettes", "_", "cafe_creme_recettes"},
+		{"Ångström über Straße", "_", "angstrom_uber_strasse"},
+		{"Łódź Øresund Þór", "_", "lodz_oresund_thor"},
+		{"Список книг", "_", "spisok_knig"},
+		{"Об'єкти", "_", "ob_yekti"},
+		{"Объявления", "_", "obyavleniya"},
+		{"Мой ёж", "_", "moy_yozh"},
+		{"Βιβλιοθήκη", "_", "vivliothiki"},
+		{"图书列表", "_", ""},
+		{"图书 books 列表", "_", "books"},
+		{"ＡＢＣ ﬁles", "_", "abc_files"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.val), func(t *testing.T) {
+			result := inflector.Slugify(s.val, s.separator)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}